@@ -1,42 +1,239 @@
 package main
 
 import (
+	"context"
+	"database/sql"
+	"errors"
 	"log"
+	"net"
 	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
 	"github.com/getkin/kin-openapi/openapi3"
 	"github.com/gin-gonic/gin"
+	goredis "github.com/redis/go-redis/v9"
 	swaggerFiles "github.com/swaggo/files"
 	ginSwagger "github.com/swaggo/gin-swagger"
+	"go.uber.org/dig"
 
+	"task-api/internal/auth"
+	"task-api/internal/certs"
 	"task-api/internal/config"
+	"task-api/internal/domain/audit"
+	"task-api/internal/domain/task"
+	"task-api/internal/encryption"
+	"task-api/internal/events"
 	"task-api/internal/handlers"
+	"task-api/internal/health"
+	"task-api/internal/idempotency"
+	"task-api/internal/infrastructure/blob"
 	"task-api/internal/infrastructure/persistence/memory"
 	"task-api/internal/infrastructure/persistence/mysql"
+	"task-api/internal/infrastructure/redis"
+	"task-api/internal/ratelimit"
+	"task-api/internal/requestid"
+	apikeyService "task-api/internal/services/apikey"
+	attachmentService "task-api/internal/services/attachment"
+	auditService "task-api/internal/services/audit"
+	commentService "task-api/internal/services/comment"
+	labelService "task-api/internal/services/label"
+	projectService "task-api/internal/services/project"
+	reminderService "task-api/internal/services/reminder"
+	roleService "task-api/internal/services/role"
 	taskService "task-api/internal/services/task"
+	userService "task-api/internal/services/user"
+	webhookService "task-api/internal/services/webhook"
+	"task-api/internal/tenant"
 )
 
+type server struct {
+	dig.In
+
+	Router     *gin.Engine
+	Handlers   []handlers.Handler `group:"handlers"`
+	Dispatcher *webhookService.Dispatcher
+	Scheduler  *reminderService.Scheduler
+	Broker     *events.Broker
+	Monitor    *health.Monitor
+	Config     config.ServerConfig
+	// Certs is nil when the API is served over plain HTTP.
+	Certs *certs.Reloader
+	// Reencryptor only exists with MySQL storage.
+	Reencryptor *mysql.Reencryptor `optional:"true"`
+}
+
 func main() {
 	injector := config.NewInjector()
 
 	// Define in-memory and relational repository constructors
 	inMemoryRepos := []interface{}{
 		memory.NewInMemoryTaskRepository,
+		memory.NewInMemoryWebhookRepository,
+		memory.NewInMemoryLabelRepository,
+		memory.NewInMemoryCommentRepository,
+		memory.NewInMemoryAttachmentRepository,
+		memory.NewInMemoryUserRepository,
+		memory.NewInMemoryProjectRepository,
+		memory.NewInMemoryReminderRepository,
+		memory.NewInMemoryAPIKeyRepository,
+		memory.NewInMemoryRoleRepository,
+		memory.NewInMemoryAuditRepository,
+		memory.NewInMemoryTransactor,
 	}
 
 	relationRepos := []interface{}{
+		func(cfg config.MySQLConfig, lc *config.Lifecycle) (*sql.DB, error) {
+			db, err := mysql.NewDB(cfg)
+			if err != nil {
+				return nil, err
+			}
+			lc.Append(config.Hook{Name: "MySQL", OnStop: func(context.Context) error { return db.Close() }})
+			return db, nil
+		},
+		// Task names are sealed at rest when there is a keyring.
+		func(cfg config.EncryptionConfig) (*encryption.Keyring, error) {
+			if cfg.KeyringFile == "" {
+				return nil, nil
+			}
+			return encryption.LoadKeyring(cfg.KeyringFile)
+		},
+		mysql.NewReencryptor,
 		mysql.NewMySQLTaskRepository,
+		mysql.NewMySQLWebhookRepository,
+		mysql.NewMySQLLabelRepository,
+		mysql.NewMySQLCommentRepository,
+		mysql.NewMySQLAttachmentRepository,
+		mysql.NewMySQLUserRepository,
+		mysql.NewMySQLProjectRepository,
+		mysql.NewMySQLReminderRepository,
+		mysql.NewMySQLAPIKeyRepository,
+		mysql.NewMySQLRoleRepository,
+		mysql.NewMySQLAuditRepository,
+		mysql.NewMySQLTransactor,
 	}
 
 	services := []interface{}{
+		roleService.NewRoleService,
+		roleService.NewPolicy,
+		auditService.NewAuditService,
+		func(records auditService.Service) audit.Recorder {
+			return records
+		},
 		taskService.NewTaskService,
+		labelService.NewLabelService,
+		userService.NewUserService,
+		projectService.NewProjectService,
+		commentService.NewCommentService,
+		attachmentService.NewAttachmentService,
+		blob.NewFilesystemStore,
+		// Data kept outside the task repository is cleaned up when a task
+		// is deleted.
+		func(comments commentService.Service, attachments attachmentService.Service, reminders *reminderService.Scheduler) []task.DeleteHook {
+			return []task.DeleteHook{comments, attachments, reminders}
+		},
+		webhookService.NewDispatcher,
+		webhookService.NewWebhookService,
+		reminderService.NewLogNotifier,
+		reminderService.NewScheduler,
+		apikeyService.NewAPIKeyService,
+		func(keys apikeyService.Service) auth.KeyVerifier {
+			return keys
+		},
+		auth.NewAuthenticator,
+		auth.NewMiddleware,
+		auth.NewIdentify,
+		// Rate limits and idempotency keys are kept in Redis when there is
+		// one, so that they hold across API instances.
+		func(cfg config.RedisConfig, lc *config.Lifecycle) *goredis.Client {
+			if cfg.Addr == "" {
+				return nil
+			}
+			client := redis.NewClient(cfg)
+			lc.Append(config.Hook{Name: "Redis", OnStop: func(context.Context) error { return client.Close() }})
+			return client
+		},
+		func(client *goredis.Client) ratelimit.Store {
+			if client == nil {
+				return ratelimit.NewMemoryStore()
+			}
+			return redis.NewRateLimitStore(client)
+		},
+		ratelimit.NewMiddleware,
+		func(client *goredis.Client) idempotency.Store {
+			if client == nil {
+				return idempotency.NewMemoryStore()
+			}
+			return redis.NewIdempotencyStore(client)
+		},
+		idempotency.NewMiddleware,
+		// Readiness checks the storage and Redis, through the rate limit
+		// store, when they are outside the process.
+		func(tasks task.Repository, limits ratelimit.Store, cfg config.HealthConfig) *health.Monitor {
+			return health.NewMonitor(health.Checkers(tasks, limits), cfg)
+		},
+		func(cfg config.TLSConfig) (*certs.Reloader, error) {
+			if !cfg.Enabled() {
+				return nil, nil
+			}
+			return certs.NewReloader(cfg)
+		},
+	}
+
+	configs := []interface{}{
+		config.NewGraphQLConfig,
+		config.NewEventsConfig,
+		config.NewAuthConfig,
+		config.NewWebSocketConfig,
+		config.NewMySQLConfig,
+		config.NewWebhookConfig,
+		config.NewTaskConfig,
+		config.NewAttachmentConfig,
+		config.NewReminderConfig,
+		config.NewRoleConfig,
+		config.NewRateLimitConfig,
+		config.NewRedisConfig,
+		config.NewIdempotencyConfig,
+		config.NewTLSConfig,
+		config.NewEncryptionConfig,
+		config.NewServerConfig,
+		config.NewHealthConfig,
+	}
+
+	eventBus := []interface{}{
+		func(cfg config.EventsConfig) *events.Broker {
+			return events.NewBroker(cfg.BufferSize)
+		},
+		events.NewPublisher,
 	}
 
 	handler := []interface{}{
 		handlers.NewTaskHandler,
+		handlers.NewGraphQLHandler,
+		handlers.NewEventHandler,
+		handlers.NewWebSocketHandler,
+		handlers.NewWebhookHandler,
+		handlers.NewLabelHandler,
+		handlers.NewCommentHandler,
+		handlers.NewAttachmentHandler,
+		handlers.NewUserHandler,
+		handlers.NewProjectHandler,
+		handlers.NewAPIKeyHandler,
+		handlers.NewRoleHandler,
+		handlers.NewAuditHandler,
 	}
 
-	err := injector.Provide(func() *gin.Engine {
+	// Swagger goes through the authentication middleware too, which lets it
+	// through as long as its paths are in AUTH_PUBLIC_PATHS. Every route is
+	// rate limited, by caller when the request carries a valid token, and
+	// POST and PATCH requests with an Idempotency-Key are only handled once.
+	// Both tell anonymous callers apart by their address, which is only taken
+	// from X-Forwarded-For when the connection comes from a trusted proxy.
+	// The health probes are the exception: they are registered ahead of both,
+	// so that a load balancer or orchestrator never gets a 429 from them.
+	err := injector.Provide(func(cfg config.ServerConfig, authCfg config.AuthConfig, authMiddleware auth.Middleware, identify auth.Identify, limiter ratelimit.Middleware, idem idempotency.Middleware, probes *handlers.HealthHandler) *gin.Engine {
 		swagger, err := openapi3.NewLoader().LoadFromFile("./cmd/api/api_doc.yaml")
 		if err != nil {
 			log.Fatal(err)
@@ -44,15 +241,35 @@ func main() {
 
 		swagger.Servers = nil
 		router := gin.Default()
-		router.GET("/openapi.json", func(c *gin.Context) {
+		if err := router.SetTrustedProxies(cfg.TrustedProxies); err != nil {
+			log.Fatalf("Invalid HTTP_TRUSTED_PROXIES: %v", err)
+		}
+		router.Use(requestid.Middleware())
+		probes.RegisterRoutes(router)
+		router.Use(gin.HandlerFunc(identify), tenant.Middleware(authCfg), gin.HandlerFunc(limiter), gin.HandlerFunc(idem))
+		docs := router.Group("", gin.HandlerFunc(authMiddleware))
+		docs.GET("/openapi.json", func(c *gin.Context) {
 			c.JSONP(http.StatusOK, swagger)
 		})
-		router.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler, ginSwagger.URL("/openapi.json")))
+		docs.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler, ginSwagger.URL("/openapi.json")))
 		return router
 	})
 	if err != nil {
 		log.Fatal(err)
 	}
+	if err := injector.Provide(handlers.NewHealthHandler); err != nil {
+		log.Fatal(err)
+	}
+
+	// Provide configuration injection
+	if err := injector.ProvideMulti(configs); err != nil {
+		log.Fatalf("Failed to invoke config: %v", err)
+	}
+
+	// Provide event bus injection
+	if err := injector.ProvideMulti(eventBus); err != nil {
+		log.Fatalf("Failed to invoke event bus: %v", err)
+	}
 
 	// Provide storage injection
 	if err := injector.ProvideStorage(inMemoryRepos, relationRepos); err != nil {
@@ -65,18 +282,113 @@ func main() {
 	}
 
 	// Provide Handler injection
-	if err := injector.ProvideMulti(handler); err != nil {
+	if err := injector.ProvideGroup("handlers", new(handlers.Handler), handler); err != nil {
 		log.Fatalf("Failed to invoke service: %v", err)
 	}
 
-	err = injector.Invoke(func(router *gin.Engine, taskHandler *handlers.TaskHandler) {
-		taskHandler.RegisterRoutes(router)
-		if err := router.Run(":8080"); err != nil {
-			log.Fatalf("Failed to run server: %v", err)
+	// Components are stopped in the reverse order of their hooks: readiness
+	// first, so that load balancers stop sending requests, then the webhook
+	// dispatcher, before the server closes the broker it consumes, then the
+	// server, so that it stops accepting connections and drains the
+	// requests in flight, then the other workers and finally the storage
+	// they all use.
+	serveErr := make(chan error, 1)
+	var shutdownTimeout time.Duration
+	err = injector.Invoke(func(s server, lc *config.Lifecycle) {
+		for _, h := range s.Handlers {
+			h.RegisterRoutes(s.Router)
 		}
-	})
 
+		lc.Go("reminder scheduler", s.Scheduler.Run)
+		if s.Reencryptor != nil {
+			lc.Go("re-encryption", s.Reencryptor.Run)
+		}
+		if s.Certs != nil {
+			// Renewed certificates are picked up by new connections.
+			lc.Go("certificate reloader", s.Certs.Run)
+		}
+		lc.Append(serverHook(newHTTPServer(s), serveErr))
+		lc.Go("webhook dispatcher", s.Dispatcher.Run)
+		lc.Append(config.Hook{Name: "readiness", OnStop: s.Monitor.Drain})
+		shutdownTimeout = s.Config.ShutdownTimeout
+	})
 	if err != nil {
 		log.Fatalf("Failed to invoke dependencies: %v", err)
 	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	if err := injector.Start(ctx); err != nil {
+		log.Fatalf("Failed to start: %v", err)
+	}
+
+	select {
+	case <-ctx.Done():
+		log.Print("Shutting down")
+	case err := <-serveErr:
+		log.Printf("Failed to run server: %v", err)
+	}
+	// A second signal ends the process at once.
+	stop()
+
+	ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	if err := injector.Stop(ctx); err != nil {
+		log.Printf("Failed to shut down cleanly: %v", err)
+		os.Exit(1)
+	}
+}
+
+func newHTTPServer(s server) *http.Server {
+	srv := &http.Server{
+		Addr:              s.Config.Addr,
+		Handler:           s.Router,
+		ReadTimeout:       s.Config.ReadTimeout,
+		ReadHeaderTimeout: s.Config.ReadHeaderTimeout,
+		WriteTimeout:      s.Config.WriteTimeout,
+		IdleTimeout:       s.Config.IdleTimeout,
+	}
+	if s.Certs != nil {
+		srv.TLSConfig = s.Certs.TLSConfig()
+	}
+	// Event streams and WebSockets would otherwise keep the server from
+	// draining; closing the broker ends them, and clients reconnect to
+	// another instance.
+	srv.RegisterOnShutdown(s.Broker.Close)
+	return srv
+}
+
+// serverHook listens when started, so that a port in use fails the start,
+// and reports errors serving later on to serveErr. Connections still open
+// when the shutdown deadline passes are closed.
+func serverHook(srv *http.Server, serveErr chan<- error) config.Hook {
+	return config.Hook{
+		Name: "HTTP server",
+		OnStart: func(context.Context) error {
+			ln, err := net.Listen("tcp", srv.Addr)
+			if err != nil {
+				return err
+			}
+			go func() {
+				var err error
+				if srv.TLSConfig != nil {
+					err = srv.ServeTLS(ln, "", "")
+				} else {
+					err = srv.Serve(ln)
+				}
+				if !errors.Is(err, http.ErrServerClosed) {
+					serveErr <- err
+				}
+			}()
+			log.Printf("Listening on %s", srv.Addr)
+			return nil
+		},
+		OnStop: func(ctx context.Context) error {
+			if err := srv.Shutdown(ctx); err != nil {
+				srv.Close()
+				return err
+			}
+			return nil
+		},
+	}
 }
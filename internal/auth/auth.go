@@ -0,0 +1,138 @@
+package auth
+
+import (
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"task-api/internal/config"
+	"task-api/internal/domain/apikey"
+)
+
+var ErrUnauthorized = errors.New("unauthorized")
+
+type Identity struct {
+	Subject string `json:"subject"`
+	// Scopes limits what an API key may do. Users, identified by a static
+	// token or a JWT, have no scopes and are not limited.
+	Scopes []string `json:"scopes,omitempty"`
+	// Tenant is the tenant a JWT is bound to by its tenant claim, or an
+	// API key by the tenant it was issued in. Static tokens and client
+	// certificates are bound to one by AUTH_SUBJECT_TENANTS instead.
+	Tenant string `json:"tenant,omitempty"`
+	// Certificate is the subject of the verified client certificate the
+	// request came with, as a distinguished name.
+	Certificate string `json:"certificate,omitempty"`
+}
+
+// Allows reports whether the identity may act within scope.
+func (i Identity) Allows(scope string) bool {
+	if i.Scopes == nil {
+		return true
+	}
+	for _, s := range i.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+type Authenticator interface {
+	Authenticate(token string) (Identity, error)
+}
+
+type staticTokenAuthenticator struct {
+	tokens map[string]string
+}
+
+func NewStaticTokenAuthenticator(cfg config.AuthConfig) Authenticator {
+	return &staticTokenAuthenticator{tokens: cfg.StaticTokens}
+}
+
+// KeyVerifier looks up the API key for a secret, as the API key service does.
+type KeyVerifier interface {
+	Verify(secret string) (apikey.Key, error)
+}
+
+// NewAuthenticator accepts the static tokens, API keys and, when a JWT
+// secret or key is configured, JWTs.
+func NewAuthenticator(cfg config.AuthConfig, keys KeyVerifier) (Authenticator, error) {
+	a := &tokenAuthenticator{
+		keys:   &keyAuthenticator{keys: keys},
+		static: NewStaticTokenAuthenticator(cfg),
+	}
+	if cfg.JWTSecret != "" || cfg.JWTPublicKeyFile != "" || cfg.JWKSFile != "" {
+		jwt, err := NewJWTAuthenticator(cfg)
+		if err != nil {
+			return nil, err
+		}
+		a.jwt = jwt
+	}
+	return a, nil
+}
+
+// tokenAuthenticator verifies tokens shaped like a JWT as one, tokens with
+// the API key prefix as API keys and looks up all others as static tokens.
+type tokenAuthenticator struct {
+	jwt    Authenticator
+	keys   Authenticator
+	static Authenticator
+}
+
+func (a *tokenAuthenticator) Authenticate(token string) (Identity, error) {
+	switch {
+	case a.jwt != nil && strings.Count(token, ".") == 2:
+		return a.jwt.Authenticate(token)
+	case strings.HasPrefix(token, apikey.SecretPrefix):
+		return a.keys.Authenticate(token)
+	default:
+		return a.static.Authenticate(token)
+	}
+}
+
+// keyAuthenticator identifies API keys as "apikey:<id>", limited to the
+// scopes and tenant of the key.
+type keyAuthenticator struct {
+	keys KeyVerifier
+}
+
+func (a *keyAuthenticator) Authenticate(token string) (Identity, error) {
+	k, err := a.keys.Verify(token)
+	if err != nil {
+		return Identity{}, fmt.Errorf("%w: %v", ErrUnauthorized, err)
+	}
+	scopes := k.Scopes
+	if scopes == nil {
+		scopes = []string{}
+	}
+	return Identity{Subject: fmt.Sprintf("apikey:%d", k.ID), Scopes: scopes, Tenant: k.TenantID}, nil
+}
+
+func (a *staticTokenAuthenticator) Authenticate(token string) (Identity, error) {
+	if token == "" {
+		return Identity{}, ErrUnauthorized
+	}
+	for candidate, subject := range a.tokens {
+		if subtle.ConstantTimeCompare([]byte(candidate), []byte(token)) == 1 {
+			return Identity{Subject: subject}, nil
+		}
+	}
+	return Identity{}, ErrUnauthorized
+}
+
+// BearerToken extracts the token from the Authorization header, falling back
+// to the access_token query parameter for clients such as browser WebSockets
+// that cannot set headers.
+func BearerToken(r *http.Request) string {
+	if header := r.Header.Get("Authorization"); header != "" {
+		scheme, token, ok := strings.Cut(header, " ")
+		if ok && strings.EqualFold(scheme, "Bearer") {
+			return strings.TrimSpace(token)
+		}
+		return ""
+	}
+	return r.URL.Query().Get("access_token")
+}
@@ -0,0 +1,110 @@
+package auth
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"task-api/internal/config"
+	"task-api/internal/domain/apikey"
+)
+
+// fakeKeys accepts the API key "tk_valid" and knows "tk_revoked" as revoked.
+type fakeKeys struct{}
+
+func (fakeKeys) Verify(secret string) (apikey.Key, error) {
+	switch secret {
+	case "tk_valid":
+		return apikey.Key{ID: 7, Scopes: []string{apikey.ScopeTasksRead}}, nil
+	case "tk_revoked":
+		return apikey.Key{}, apikey.ErrRevoked
+	default:
+		return apikey.Key{}, apikey.ErrNotFound
+	}
+}
+
+func TestStaticTokenAuthenticator(t *testing.T) {
+	authenticator := NewStaticTokenAuthenticator(config.AuthConfig{
+		StaticTokens: map[string]string{"secret": "board"},
+	})
+
+	tests := []struct {
+		TestCase string
+		Token    string
+		Expected Identity
+		Error    error
+	}{
+		{TestCase: "Known token", Token: "secret", Expected: Identity{Subject: "board"}},
+		{TestCase: "Unknown token", Token: "guess", Error: ErrUnauthorized},
+		{TestCase: "Missing token", Token: "", Error: ErrUnauthorized},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.TestCase, func(t *testing.T) {
+			identity, err := authenticator.Authenticate(tc.Token)
+			assert.Equal(t, tc.Error, err)
+			assert.Equal(t, tc.Expected, identity)
+		})
+	}
+}
+
+func TestNewAuthenticator(t *testing.T) {
+	static, err := NewAuthenticator(config.AuthConfig{StaticTokens: map[string]string{"secret": "board"}}, fakeKeys{})
+	assert.NoError(t, err)
+	identity, err := static.Authenticate("secret")
+	assert.NoError(t, err)
+	assert.Equal(t, Identity{Subject: "board"}, identity)
+
+	combined, err := NewAuthenticator(config.AuthConfig{StaticTokens: map[string]string{"secret": "board"}, JWTSecret: "key"}, fakeKeys{})
+	assert.NoError(t, err)
+	identity, err = combined.Authenticate("secret")
+	assert.NoError(t, err)
+	assert.Equal(t, Identity{Subject: "board"}, identity)
+	_, err = combined.Authenticate("not.a.jwt")
+	assert.ErrorIs(t, err, ErrUnauthorized)
+
+	identity, err = combined.Authenticate("tk_valid")
+	assert.NoError(t, err)
+	assert.Equal(t, Identity{Subject: "apikey:7", Scopes: []string{apikey.ScopeTasksRead}}, identity)
+	_, err = combined.Authenticate("tk_revoked")
+	assert.ErrorIs(t, err, ErrUnauthorized)
+	assert.EqualError(t, err, "unauthorized: api key is revoked")
+
+	_, err = NewAuthenticator(config.AuthConfig{JWKSFile: "/nonexistent/jwks.json"}, fakeKeys{})
+	assert.Error(t, err)
+}
+
+func TestIdentityAllows(t *testing.T) {
+	user := Identity{Subject: "board"}
+	assert.True(t, user.Allows(apikey.ScopeTasksWrite))
+
+	key := Identity{Subject: "apikey:7", Scopes: []string{apikey.ScopeTasksRead}}
+	assert.True(t, key.Allows(apikey.ScopeTasksRead))
+	assert.False(t, key.Allows(apikey.ScopeTasksWrite))
+	assert.False(t, Identity{Subject: "apikey:8", Scopes: []string{}}.Allows(apikey.ScopeTasksRead))
+}
+
+func TestBearerToken(t *testing.T) {
+	tests := []struct {
+		TestCase string
+		URL      string
+		Header   string
+		Expected string
+	}{
+		{TestCase: "Authorization header", URL: "/ws", Header: "Bearer abc", Expected: "abc"},
+		{TestCase: "Scheme is case insensitive", URL: "/ws", Header: "bearer abc", Expected: "abc"},
+		{TestCase: "Other schemes are ignored", URL: "/ws?access_token=abc", Header: "Basic abc", Expected: ""},
+		{TestCase: "Query parameter fallback", URL: "/ws?access_token=abc", Expected: "abc"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.TestCase, func(t *testing.T) {
+			req, _ := http.NewRequest(http.MethodGet, tc.URL, nil)
+			if tc.Header != "" {
+				req.Header.Set("Authorization", tc.Header)
+			}
+			assert.Equal(t, tc.Expected, BearerToken(req))
+		})
+	}
+}
@@ -0,0 +1,43 @@
+package auth
+
+import (
+	"crypto/x509"
+	"net/http"
+)
+
+// CertificateSubjectPrefix starts the subject of callers identified by their
+// client certificate, so that they cannot pass for a user.
+const CertificateSubjectPrefix = "cert:"
+
+// ClientCertificate returns the client certificate r came with, as verified
+// against the client CAs during the TLS handshake. Certificates the server
+// did not verify are not returned.
+func ClientCertificate(r *http.Request) (*x509.Certificate, bool) {
+	if r.TLS == nil || len(r.TLS.VerifiedChains) == 0 || len(r.TLS.VerifiedChains[0]) == 0 {
+		return nil, false
+	}
+	return r.TLS.VerifiedChains[0][0], true
+}
+
+// authenticate identifies the caller of r by its bearer token. Callers
+// without one but with a verified client certificate, such as other
+// services, are identified as "cert:<common name>". Either way the identity
+// carries the subject of the certificate.
+func authenticate(authenticator Authenticator, r *http.Request) (Identity, error) {
+	cert, hasCert := ClientCertificate(r)
+	token := BearerToken(r)
+	if token == "" && hasCert && cert.Subject.CommonName != "" {
+		return Identity{
+			Subject:     CertificateSubjectPrefix + cert.Subject.CommonName,
+			Certificate: cert.Subject.String(),
+		}, nil
+	}
+	identity, err := authenticator.Authenticate(token)
+	if err != nil {
+		return Identity{}, err
+	}
+	if hasCert {
+		identity.Certificate = cert.Subject.String()
+	}
+	return identity, nil
+}
@@ -0,0 +1,92 @@
+package auth
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+
+	"task-api/internal/config"
+)
+
+func TestClientCertificate(t *testing.T) {
+	cfg := config.AuthConfig{StaticTokens: map[string]string{"secret": "board"}}
+	authenticator := NewStaticTokenAuthenticator(cfg)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(gin.HandlerFunc(NewIdentify(authenticator)))
+	router.GET("/tasks", gin.HandlerFunc(NewMiddleware(authenticator, cfg)), func(c *gin.Context) {
+		identity, _ := FromContext(c.Request.Context())
+		c.JSON(http.StatusOK, identity)
+	})
+
+	billing := &x509.Certificate{Subject: pkix.Name{CommonName: "billing", Organization: []string{"Acme"}}}
+	verified := func(cert *x509.Certificate) *tls.ConnectionState {
+		return &tls.ConnectionState{
+			PeerCertificates: []*x509.Certificate{cert},
+			VerifiedChains:   [][]*x509.Certificate{{cert}},
+		}
+	}
+
+	tests := []struct {
+		TestCase string
+		Header   string
+		TLS      *tls.ConnectionState
+		Status   int
+		Expected string
+	}{
+		{
+			TestCase: "Verified certificate identifies the caller",
+			TLS:      verified(billing),
+			Status:   http.StatusOK,
+			Expected: `{"subject":"cert:billing","certificate":"CN=billing,O=Acme"}`,
+		},
+		{
+			TestCase: "Token takes precedence over the certificate",
+			Header:   "Bearer secret",
+			TLS:      verified(billing),
+			Status:   http.StatusOK,
+			Expected: `{"subject":"board","certificate":"CN=billing,O=Acme"}`,
+		},
+		{
+			TestCase: "Invalid token is rejected despite the certificate",
+			Header:   "Bearer guess",
+			TLS:      verified(billing),
+			Status:   http.StatusUnauthorized,
+			Expected: `{"error":"unauthorized"}`,
+		},
+		{
+			TestCase: "Unverified certificate is ignored",
+			TLS:      &tls.ConnectionState{PeerCertificates: []*x509.Certificate{billing}},
+			Status:   http.StatusUnauthorized,
+			Expected: `{"error":"unauthorized"}`,
+		},
+		{
+			TestCase: "Certificate without a common name",
+			TLS:      verified(&x509.Certificate{Subject: pkix.Name{Organization: []string{"Acme"}}}),
+			Status:   http.StatusUnauthorized,
+			Expected: `{"error":"unauthorized"}`,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.TestCase, func(t *testing.T) {
+			req, _ := http.NewRequest(http.MethodGet, "/tasks", nil)
+			req.TLS = tc.TLS
+			if tc.Header != "" {
+				req.Header.Set("Authorization", tc.Header)
+			}
+			rr := httptest.NewRecorder()
+			router.ServeHTTP(rr, req)
+
+			assert.Equal(t, tc.Status, rr.Code)
+			assert.JSONEq(t, tc.Expected, rr.Body.String())
+		})
+	}
+}
@@ -0,0 +1,153 @@
+package auth
+
+import (
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+
+	"github.com/golang-jwt/jwt/v5"
+
+	"task-api/internal/config"
+)
+
+// jwtAuthenticator verifies HS256 tokens with a shared secret and RS256
+// tokens with RSA keys from a PEM file or a JWKS file. Tokens must carry an
+// expiry and a subject, and may carry a tenant.
+type jwtAuthenticator struct {
+	secret []byte
+	// key verifies RS256 tokens without a kid, or whose kid is not in keys.
+	key    *rsa.PublicKey
+	keys   map[string]*rsa.PublicKey
+	parser *jwt.Parser
+}
+
+// NewJWTAuthenticator loads the configured keys. It fails when a key file
+// cannot be read or no key is configured at all.
+func NewJWTAuthenticator(cfg config.AuthConfig) (Authenticator, error) {
+	a := &jwtAuthenticator{keys: make(map[string]*rsa.PublicKey)}
+	var methods []string
+	if cfg.JWTSecret != "" {
+		a.secret = []byte(cfg.JWTSecret)
+		methods = append(methods, jwt.SigningMethodHS256.Alg())
+	}
+	if cfg.JWTPublicKeyFile != "" {
+		data, err := os.ReadFile(cfg.JWTPublicKeyFile)
+		if err != nil {
+			return nil, err
+		}
+		if a.key, err = jwt.ParseRSAPublicKeyFromPEM(data); err != nil {
+			return nil, fmt.Errorf("%s: %w", cfg.JWTPublicKeyFile, err)
+		}
+	}
+	if cfg.JWKSFile != "" {
+		data, err := os.ReadFile(cfg.JWKSFile)
+		if err != nil {
+			return nil, err
+		}
+		if a.keys, err = parseJWKS(data); err != nil {
+			return nil, fmt.Errorf("%s: %w", cfg.JWKSFile, err)
+		}
+	}
+	if a.key != nil || len(a.keys) > 0 {
+		methods = append(methods, jwt.SigningMethodRS256.Alg())
+	}
+	if len(methods) == 0 {
+		return nil, errors.New("no JWT secret or public key configured")
+	}
+
+	options := []jwt.ParserOption{
+		jwt.WithValidMethods(methods),
+		jwt.WithExpirationRequired(),
+		jwt.WithLeeway(cfg.JWTLeeway),
+	}
+	if cfg.JWTIssuer != "" {
+		options = append(options, jwt.WithIssuer(cfg.JWTIssuer))
+	}
+	if cfg.JWTAudience != "" {
+		options = append(options, jwt.WithAudience(cfg.JWTAudience))
+	}
+	a.parser = jwt.NewParser(options...)
+	return a, nil
+}
+
+type claims struct {
+	jwt.RegisteredClaims
+	Tenant string `json:"tenant,omitempty"`
+}
+
+func (a *jwtAuthenticator) Authenticate(token string) (Identity, error) {
+	var c claims
+	if _, err := a.parser.ParseWithClaims(token, &c, a.keyFor); err != nil {
+		return Identity{}, fmt.Errorf("%w: %v", ErrUnauthorized, err)
+	}
+	if c.Subject == "" {
+		return Identity{}, fmt.Errorf("%w: token has no subject", ErrUnauthorized)
+	}
+	return Identity{Subject: c.Subject, Tenant: c.Tenant}, nil
+}
+
+// keyFor picks the key for the algorithm of a token, so that an RSA public
+// key is never used as an HMAC secret.
+func (a *jwtAuthenticator) keyFor(token *jwt.Token) (interface{}, error) {
+	switch token.Method {
+	case jwt.SigningMethodHS256:
+		return a.secret, nil
+	case jwt.SigningMethodRS256:
+		if kid, ok := token.Header["kid"].(string); ok {
+			if key, ok := a.keys[kid]; ok {
+				return key, nil
+			}
+		}
+		if a.key != nil {
+			return a.key, nil
+		}
+		return nil, errors.New("unknown signing key")
+	default:
+		return nil, fmt.Errorf("unexpected signing method %s", token.Method.Alg())
+	}
+}
+
+type jsonWebKey struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+}
+
+// parseJWKS reads the RSA signing keys of a JSON Web Key Set by kid. Keys of
+// other types or uses are skipped.
+func parseJWKS(data []byte) (map[string]*rsa.PublicKey, error) {
+	var set struct {
+		Keys []jsonWebKey `json:"keys"`
+	}
+	if err := json.Unmarshal(data, &set); err != nil {
+		return nil, err
+	}
+	keys := make(map[string]*rsa.PublicKey)
+	for _, k := range set.Keys {
+		if k.Kty != "RSA" || k.Use != "" && k.Use != "sig" {
+			continue
+		}
+		n, err := base64.RawURLEncoding.DecodeString(k.N)
+		if err != nil {
+			return nil, fmt.Errorf("key %q: invalid modulus", k.Kid)
+		}
+		e, err := base64.RawURLEncoding.DecodeString(k.E)
+		if err != nil || len(e) == 0 || len(e) > 4 {
+			return nil, fmt.Errorf("key %q: invalid exponent", k.Kid)
+		}
+		keys[k.Kid] = &rsa.PublicKey{
+			N: new(big.Int).SetBytes(n),
+			E: int(new(big.Int).SetBytes(e).Int64()),
+		}
+	}
+	if len(keys) == 0 {
+		return nil, errors.New("no RSA signing keys")
+	}
+	return keys, nil
+}
@@ -0,0 +1,159 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"task-api/internal/config"
+)
+
+func TestJWTAuthenticator(t *testing.T) {
+	dir := t.TempDir()
+	pemKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	jwksKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	der, err := x509.MarshalPKIXPublicKey(&pemKey.PublicKey)
+	require.NoError(t, err)
+	pemFile := filepath.Join(dir, "key.pem")
+	require.NoError(t, os.WriteFile(pemFile, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}), 0o600))
+	jwksFile := filepath.Join(dir, "jwks.json")
+	require.NoError(t, os.WriteFile(jwksFile, []byte(fmt.Sprintf(`{"keys": [
+		{"kty": "EC", "kid": "ec", "crv": "P-256"},
+		{"kty": "RSA", "kid": "main", "use": "sig", "n": %q, "e": %q}
+	]}`, base64.RawURLEncoding.EncodeToString(jwksKey.N.Bytes()), base64.RawURLEncoding.EncodeToString(big.NewInt(int64(jwksKey.E)).Bytes()))), 0o600))
+
+	authenticator, err := NewJWTAuthenticator(config.AuthConfig{
+		JWTSecret:        "secret",
+		JWTPublicKeyFile: pemFile,
+		JWKSFile:         jwksFile,
+		JWTIssuer:        "https://issuer.example",
+		JWTAudience:      "task-api",
+	})
+	require.NoError(t, err)
+
+	now := time.Now()
+	valid := jwt.MapClaims{
+		"sub": "alice",
+		"iss": "https://issuer.example",
+		"aud": "task-api",
+		"exp": now.Add(time.Hour).Unix(),
+	}
+	with := func(key string, value interface{}) jwt.MapClaims {
+		claims := jwt.MapClaims{}
+		for k, v := range valid {
+			claims[k] = v
+		}
+		if value == nil {
+			delete(claims, key)
+		} else {
+			claims[key] = value
+		}
+		return claims
+	}
+	sign := func(method jwt.SigningMethod, claims jwt.MapClaims, key interface{}, kid string) string {
+		token := jwt.NewWithClaims(method, claims)
+		if kid != "" {
+			token.Header["kid"] = kid
+		}
+		signed, err := token.SignedString(key)
+		require.NoError(t, err)
+		return signed
+	}
+
+	tests := []struct {
+		TestCase string
+		Token    string
+		Expected Identity
+		Error    bool
+	}{
+		{TestCase: "HS256", Token: sign(jwt.SigningMethodHS256, valid, []byte("secret"), ""), Expected: Identity{Subject: "alice"}},
+		{TestCase: "RS256 with the PEM key", Token: sign(jwt.SigningMethodRS256, valid, pemKey, ""), Expected: Identity{Subject: "alice"}},
+		{TestCase: "RS256 with a JWKS key", Token: sign(jwt.SigningMethodRS256, valid, jwksKey, "main"), Expected: Identity{Subject: "alice"}},
+		{TestCase: "Unknown kid falls back to the PEM key", Token: sign(jwt.SigningMethodRS256, valid, pemKey, "old"), Expected: Identity{Subject: "alice"}},
+		{TestCase: "Tenant claim", Token: sign(jwt.SigningMethodHS256, with("tenant", "acme"), []byte("secret"), ""), Expected: Identity{Subject: "alice", Tenant: "acme"}},
+		{TestCase: "Audience list", Token: sign(jwt.SigningMethodHS256, with("aud", []string{"other", "task-api"}), []byte("secret"), ""), Expected: Identity{Subject: "alice"}},
+		{TestCase: "Expired", Token: sign(jwt.SigningMethodHS256, with("exp", now.Add(-time.Hour).Unix()), []byte("secret"), ""), Error: true},
+		{TestCase: "Without expiry", Token: sign(jwt.SigningMethodHS256, with("exp", nil), []byte("secret"), ""), Error: true},
+		{TestCase: "Not valid yet", Token: sign(jwt.SigningMethodHS256, with("nbf", now.Add(time.Hour).Unix()), []byte("secret"), ""), Error: true},
+		{TestCase: "Wrong issuer", Token: sign(jwt.SigningMethodHS256, with("iss", "https://evil.example"), []byte("secret"), ""), Error: true},
+		{TestCase: "Wrong audience", Token: sign(jwt.SigningMethodHS256, with("aud", "other"), []byte("secret"), ""), Error: true},
+		{TestCase: "Without subject", Token: sign(jwt.SigningMethodHS256, with("sub", nil), []byte("secret"), ""), Error: true},
+		{TestCase: "Wrong secret", Token: sign(jwt.SigningMethodHS256, valid, []byte("guess"), ""), Error: true},
+		{TestCase: "Wrong RSA key", Token: sign(jwt.SigningMethodRS256, valid, jwksKey, ""), Error: true},
+		{TestCase: "Unlisted algorithm", Token: sign(jwt.SigningMethodHS512, valid, []byte("secret"), ""), Error: true},
+		{TestCase: "Unsigned", Token: sign(jwt.SigningMethodNone, valid, jwt.UnsafeAllowNoneSignatureType, ""), Error: true},
+		{TestCase: "Malformed", Token: "a.b.c", Error: true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.TestCase, func(t *testing.T) {
+			identity, err := authenticator.Authenticate(tc.Token)
+			if tc.Error {
+				assert.ErrorIs(t, err, ErrUnauthorized)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.Expected, identity)
+		})
+	}
+}
+
+func TestJWTAuthenticatorRejectsPublicKeyAsSecret(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	der, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	require.NoError(t, err)
+	pemBytes := pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})
+	pemFile := filepath.Join(t.TempDir(), "key.pem")
+	require.NoError(t, os.WriteFile(pemFile, pemBytes, 0o600))
+
+	authenticator, err := NewJWTAuthenticator(config.AuthConfig{JWTPublicKeyFile: pemFile})
+	require.NoError(t, err)
+
+	// Without a secret configured, HS256 is not accepted at all.
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"sub": "mallory",
+		"exp": time.Now().Add(time.Hour).Unix(),
+	}).SignedString(pemBytes)
+	require.NoError(t, err)
+	_, err = authenticator.Authenticate(token)
+	assert.ErrorIs(t, err, ErrUnauthorized)
+}
+
+func TestNewJWTAuthenticatorErrors(t *testing.T) {
+	dir := t.TempDir()
+	invalid := filepath.Join(dir, "invalid.json")
+	require.NoError(t, os.WriteFile(invalid, []byte(`{"keys": [{"kty": "EC", "kid": "ec"}]}`), 0o600))
+
+	tests := []struct {
+		TestCase string
+		Config   config.AuthConfig
+	}{
+		{TestCase: "No key", Config: config.AuthConfig{JWTIssuer: "https://issuer.example"}},
+		{TestCase: "Missing PEM file", Config: config.AuthConfig{JWTPublicKeyFile: filepath.Join(dir, "missing.pem")}},
+		{TestCase: "Invalid PEM file", Config: config.AuthConfig{JWTPublicKeyFile: invalid}},
+		{TestCase: "JWKS without RSA keys", Config: config.AuthConfig{JWKSFile: invalid}},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.TestCase, func(t *testing.T) {
+			_, err := NewJWTAuthenticator(tc.Config)
+			assert.Error(t, err)
+		})
+	}
+}
@@ -0,0 +1,101 @@
+package auth
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"task-api/internal/config"
+)
+
+type contextKey struct{}
+
+// NewContext returns a copy of ctx carrying the identity of the caller.
+func NewContext(ctx context.Context, identity Identity) context.Context {
+	return context.WithValue(ctx, contextKey{}, identity)
+}
+
+// FromContext returns the identity the middleware put into ctx.
+func FromContext(ctx context.Context) (Identity, bool) {
+	identity, ok := ctx.Value(contextKey{}).(Identity)
+	return identity, ok
+}
+
+// Middleware rejects requests without a valid bearer token or verified client
+// certificate with a 401 and puts the identity of the caller into the
+// request context.
+type Middleware gin.HandlerFunc
+
+// NewMiddleware lets requests for the configured public paths through
+// without a token. Paths are matched against the route pattern, such as
+// /swagger/*any. An identity already put into the context by Identify is
+// accepted as is.
+func NewMiddleware(authenticator Authenticator, cfg config.AuthConfig) Middleware {
+	public := make(map[string]bool, len(cfg.PublicPaths))
+	for _, path := range cfg.PublicPaths {
+		public[path] = true
+	}
+	return func(c *gin.Context) {
+		if _, ok := FromContext(c.Request.Context()); ok || public[c.FullPath()] {
+			c.Next()
+			return
+		}
+		identity, err := authenticate(authenticator, c.Request)
+		if err != nil {
+			c.Header("WWW-Authenticate", `Bearer realm="task-api"`)
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+			return
+		}
+		c.Request = c.Request.WithContext(NewContext(c.Request.Context(), identity))
+		c.Next()
+	}
+}
+
+// Identify puts the identity of callers with a valid bearer token or verified
+// client certificate into the request context and lets every request through. It runs ahead of the
+// middleware that treat callers differently without requiring a token, such
+// as the rate limiter.
+type Identify gin.HandlerFunc
+
+func NewIdentify(authenticator Authenticator) Identify {
+	return func(c *gin.Context) {
+		_, hasCert := ClientCertificate(c.Request)
+		if hasCert || BearerToken(c.Request) != "" {
+			if identity, err := authenticate(authenticator, c.Request); err == nil {
+				c.Request = c.Request.WithContext(NewContext(c.Request.Context(), identity))
+			}
+		}
+		c.Next()
+	}
+}
+
+// RequireScope rejects requests whose caller may not act within scope with a
+// 403. It runs after the middleware.
+func RequireScope(scope string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		identity, _ := FromContext(c.Request.Context())
+		if !identity.Allows(scope) {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "api key lacks scope " + scope})
+			return
+		}
+		c.Next()
+	}
+}
+
+// RequireAdmin rejects requests from anyone but the configured admin
+// subjects with a 403. API keys are never admins, whatever their scopes.
+func RequireAdmin(cfg config.AuthConfig) gin.HandlerFunc {
+	admins := make(map[string]bool, len(cfg.AdminSubjects))
+	for _, subject := range cfg.AdminSubjects {
+		admins[subject] = true
+	}
+	return func(c *gin.Context) {
+		identity, ok := FromContext(c.Request.Context())
+		if !ok || identity.Scopes != nil || !admins[identity.Subject] {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "admin access required"})
+			return
+		}
+		c.Next()
+	}
+}
@@ -0,0 +1,192 @@
+package auth
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+
+	"task-api/internal/config"
+	"task-api/internal/domain/apikey"
+)
+
+func TestMiddleware(t *testing.T) {
+	cfg := config.AuthConfig{
+		StaticTokens: map[string]string{"secret": "board"},
+		PublicPaths:  []string{"/docs/*any"},
+	}
+	middleware := gin.HandlerFunc(NewMiddleware(NewStaticTokenAuthenticator(cfg), cfg))
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	whoami := func(c *gin.Context) {
+		identity, ok := FromContext(c.Request.Context())
+		c.JSON(http.StatusOK, gin.H{"subject": identity.Subject, "authenticated": ok})
+	}
+	router.GET("/tasks", middleware, whoami)
+	router.GET("/docs/*any", middleware, whoami)
+
+	tests := []struct {
+		TestCase string
+		URL      string
+		Header   string
+		Status   int
+		Expected string
+	}{
+		{
+			TestCase: "Valid token",
+			URL:      "/tasks",
+			Header:   "Bearer secret",
+			Status:   http.StatusOK,
+			Expected: `{"subject":"board","authenticated":true}`,
+		},
+		{
+			TestCase: "Missing token",
+			URL:      "/tasks",
+			Status:   http.StatusUnauthorized,
+			Expected: `{"error":"unauthorized"}`,
+		},
+		{
+			TestCase: "Invalid token",
+			URL:      "/tasks",
+			Header:   "Bearer guess",
+			Status:   http.StatusUnauthorized,
+			Expected: `{"error":"unauthorized"}`,
+		},
+		{
+			TestCase: "Public path",
+			URL:      "/docs/index.html",
+			Status:   http.StatusOK,
+			Expected: `{"subject":"","authenticated":false}`,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.TestCase, func(t *testing.T) {
+			req, _ := http.NewRequest(http.MethodGet, tc.URL, nil)
+			if tc.Header != "" {
+				req.Header.Set("Authorization", tc.Header)
+			}
+			rr := httptest.NewRecorder()
+			router.ServeHTTP(rr, req)
+
+			assert.Equal(t, tc.Status, rr.Code)
+			assert.JSONEq(t, tc.Expected, rr.Body.String())
+			if tc.Status == http.StatusUnauthorized {
+				assert.Equal(t, `Bearer realm="task-api"`, rr.Header().Get("WWW-Authenticate"))
+			}
+		})
+	}
+}
+
+func TestRequireScopeAndAdmin(t *testing.T) {
+	cfg := config.AuthConfig{
+		StaticTokens:  map[string]string{"root": "alice", "secret": "board"},
+		AdminSubjects: []string{"alice", "apikey:7"},
+	}
+	authenticator, err := NewAuthenticator(cfg, fakeKeys{})
+	assert.NoError(t, err)
+	middleware := gin.HandlerFunc(NewMiddleware(authenticator, cfg))
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	ok := func(c *gin.Context) { c.Status(http.StatusNoContent) }
+	router.GET("/read", middleware, RequireScope(apikey.ScopeTasksRead), ok)
+	router.GET("/write", middleware, RequireScope(apikey.ScopeTasksWrite), ok)
+	router.GET("/admin", middleware, RequireAdmin(cfg), ok)
+
+	tests := []struct {
+		TestCase string
+		URL      string
+		Token    string
+		Status   int
+	}{
+		{TestCase: "User reads", URL: "/read", Token: "secret", Status: http.StatusNoContent},
+		{TestCase: "User writes", URL: "/write", Token: "secret", Status: http.StatusNoContent},
+		{TestCase: "Key reads within its scope", URL: "/read", Token: "tk_valid", Status: http.StatusNoContent},
+		{TestCase: "Key writes outside its scope", URL: "/write", Token: "tk_valid", Status: http.StatusForbidden},
+		{TestCase: "Revoked key", URL: "/read", Token: "tk_revoked", Status: http.StatusUnauthorized},
+		{TestCase: "Admin", URL: "/admin", Token: "root", Status: http.StatusNoContent},
+		{TestCase: "Other user is no admin", URL: "/admin", Token: "secret", Status: http.StatusForbidden},
+		{TestCase: "Key is never an admin", URL: "/admin", Token: "tk_valid", Status: http.StatusForbidden},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.TestCase, func(t *testing.T) {
+			req, _ := http.NewRequest(http.MethodGet, tc.URL, nil)
+			req.Header.Set("Authorization", "Bearer "+tc.Token)
+			rr := httptest.NewRecorder()
+			router.ServeHTTP(rr, req)
+			assert.Equal(t, tc.Status, rr.Code)
+		})
+	}
+}
+
+// countingAuthenticator counts the tokens it checks.
+type countingAuthenticator struct {
+	Authenticator
+	calls int
+}
+
+func (a *countingAuthenticator) Authenticate(token string) (Identity, error) {
+	a.calls++
+	return a.Authenticator.Authenticate(token)
+}
+
+func TestIdentify(t *testing.T) {
+	cfg := config.AuthConfig{StaticTokens: map[string]string{"secret": "board"}}
+
+	tests := []struct {
+		TestCase string
+		Header   string
+		Status   int
+		Expected string
+		Calls    int
+	}{
+		{
+			TestCase: "Valid token is checked once",
+			Header:   "Bearer secret",
+			Status:   http.StatusOK,
+			Expected: `{"subject":"board","authenticated":true}`,
+			Calls:    1,
+		},
+		{
+			TestCase: "Invalid token is still rejected",
+			Header:   "Bearer guess",
+			Status:   http.StatusUnauthorized,
+			Expected: `{"error":"unauthorized"}`,
+			Calls:    2,
+		},
+		{
+			TestCase: "Missing token is still rejected",
+			Status:   http.StatusUnauthorized,
+			Expected: `{"error":"unauthorized"}`,
+			Calls:    1,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.TestCase, func(t *testing.T) {
+			authenticator := &countingAuthenticator{Authenticator: NewStaticTokenAuthenticator(cfg)}
+			router := gin.New()
+			router.Use(gin.HandlerFunc(NewIdentify(authenticator)))
+			router.GET("/tasks", gin.HandlerFunc(NewMiddleware(authenticator, cfg)), func(c *gin.Context) {
+				identity, ok := FromContext(c.Request.Context())
+				c.JSON(http.StatusOK, gin.H{"subject": identity.Subject, "authenticated": ok})
+			})
+
+			req, _ := http.NewRequest(http.MethodGet, "/tasks", nil)
+			if tc.Header != "" {
+				req.Header.Set("Authorization", tc.Header)
+			}
+			rr := httptest.NewRecorder()
+			router.ServeHTTP(rr, req)
+
+			assert.Equal(t, tc.Status, rr.Code)
+			assert.JSONEq(t, tc.Expected, rr.Body.String())
+			assert.Equal(t, tc.Calls, authenticator.calls)
+		})
+	}
+}
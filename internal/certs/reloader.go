@@ -0,0 +1,151 @@
+// Package certs serves the TLS certificate, and the CAs client certificates
+// are verified against, from files that may be replaced while the API runs.
+package certs
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"sync/atomic"
+	"time"
+
+	"task-api/internal/config"
+)
+
+var ErrNoCertificates = errors.New("no certificates found")
+
+// Reloader hands out a TLS configuration built from the files in
+// config.TLSConfig and rebuilds it when they change, so that renewed
+// certificates are picked up by new connections without a restart.
+type Reloader struct {
+	cfg     config.TLSConfig
+	current atomic.Pointer[tls.Config]
+	// modTimes are those of the files the current configuration was built
+	// from.
+	modTimes []time.Time
+}
+
+// NewReloader loads the files once, failing when they are unusable.
+func NewReloader(cfg config.TLSConfig) (*Reloader, error) {
+	r := &Reloader{cfg: cfg}
+	modTimes, err := r.stat()
+	if err != nil {
+		return nil, err
+	}
+	if err := r.load(); err != nil {
+		return nil, err
+	}
+	r.modTimes = modTimes
+	return r, nil
+}
+
+// TLSConfig returns the configuration to serve with. It asks for the
+// current configuration on every handshake.
+func (r *Reloader) TLSConfig() *tls.Config {
+	return &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		GetConfigForClient: func(*tls.ClientHelloInfo) (*tls.Config, error) {
+			return r.current.Load(), nil
+		},
+		GetCertificate: func(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+			return &r.current.Load().Certificates[0], nil
+		},
+	}
+}
+
+// Run checks the files for changes until ctx is done.
+func (r *Reloader) Run(ctx context.Context) {
+	ticker := time.NewTicker(r.cfg.ReloadInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			r.Reload()
+		}
+	}
+}
+
+// Reload rebuilds the configuration when a file changed since it was last
+// loaded. When the new files are unusable, for instance because the key was
+// replaced but not yet the certificate, the current configuration is kept
+// and the files are tried again on the next check.
+func (r *Reloader) Reload() {
+	modTimes, err := r.stat()
+	if err != nil {
+		log.Printf("certs: %v", err)
+		return
+	}
+	if equal(modTimes, r.modTimes) {
+		return
+	}
+	if err := r.load(); err != nil {
+		log.Printf("certs: keeping the current certificates: %v", err)
+		return
+	}
+	r.modTimes = modTimes
+	log.Printf("certs: reloaded %s", r.cfg.CertFile)
+}
+
+func (r *Reloader) load() error {
+	cert, err := tls.LoadX509KeyPair(r.cfg.CertFile, r.cfg.KeyFile)
+	if err != nil {
+		return fmt.Errorf("failed to load key pair: %w", err)
+	}
+	c := &tls.Config{
+		MinVersion:   tls.VersionTLS12,
+		Certificates: []tls.Certificate{cert},
+		NextProtos:   []string{"h2", "http/1.1"},
+	}
+	if r.cfg.ClientCAFile != "" {
+		pem, err := os.ReadFile(r.cfg.ClientCAFile)
+		if err != nil {
+			return fmt.Errorf("failed to read client CAs: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return fmt.Errorf("%w in %s", ErrNoCertificates, r.cfg.ClientCAFile)
+		}
+		c.ClientCAs = pool
+		c.ClientAuth = tls.VerifyClientCertIfGiven
+		if r.cfg.RequireClientCert {
+			c.ClientAuth = tls.RequireAndVerifyClientCert
+		}
+	}
+	r.current.Store(c)
+	return nil
+}
+
+func (r *Reloader) stat() ([]time.Time, error) {
+	files := []string{r.cfg.CertFile, r.cfg.KeyFile}
+	if r.cfg.ClientCAFile != "" {
+		files = append(files, r.cfg.ClientCAFile)
+	}
+	modTimes := make([]time.Time, len(files))
+	for i, file := range files {
+		info, err := os.Stat(file)
+		if err != nil {
+			return nil, err
+		}
+		modTimes[i] = info.ModTime()
+	}
+	return modTimes, nil
+}
+
+func equal(a, b []time.Time) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if !a[i].Equal(b[i]) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,183 @@
+package certs
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"task-api/internal/config"
+)
+
+type authority struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+	pem  []byte
+}
+
+func newAuthority(t *testing.T, name string) authority {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: name},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+	return authority{cert: cert, key: key, pem: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})}
+}
+
+// issue returns a PEM certificate and key for name, signed by the authority.
+func (a authority) issue(t *testing.T, name string, serial int64, usage x509.ExtKeyUsage) ([]byte, []byte) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(serial),
+		Subject:      pkix.Name{CommonName: name},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{usage},
+		IPAddresses:  []net.IP{net.IPv4(127, 0, 0, 1)},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, a.cert, &key.PublicKey, a.key)
+	require.NoError(t, err)
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+}
+
+// write replaces the file and moves its modification time on, as file
+// systems with a coarse clock may not.
+func write(t *testing.T, file string, data []byte, modTime time.Time) {
+	require.NoError(t, os.WriteFile(file, data, 0o600))
+	require.NoError(t, os.Chtimes(file, modTime, modTime))
+}
+
+func serial(t *testing.T, r *Reloader) int64 {
+	c, err := r.TLSConfig().GetConfigForClient(&tls.ClientHelloInfo{})
+	require.NoError(t, err)
+	leaf, err := x509.ParseCertificate(c.Certificates[0].Certificate[0])
+	require.NoError(t, err)
+	return leaf.SerialNumber.Int64()
+}
+
+func TestReloader(t *testing.T) {
+	dir := t.TempDir()
+	ca := newAuthority(t, "Test CA")
+	cfg := config.TLSConfig{
+		CertFile:       filepath.Join(dir, "tls.crt"),
+		KeyFile:        filepath.Join(dir, "tls.key"),
+		ReloadInterval: time.Minute,
+	}
+	modTime := time.Now().Add(-time.Hour)
+
+	_, err := NewReloader(cfg)
+	assert.Error(t, err)
+
+	cert, key := ca.issue(t, "localhost", 2, x509.ExtKeyUsageServerAuth)
+	write(t, cfg.CertFile, cert, modTime)
+	write(t, cfg.KeyFile, key, modTime)
+	r, err := NewReloader(cfg)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), serial(t, r))
+
+	// Unchanged files are not loaded again.
+	write(t, cfg.KeyFile, []byte("garbage"), modTime)
+	r.Reload()
+	assert.Equal(t, int64(2), serial(t, r))
+
+	// A certificate that does not match the key yet is not picked up.
+	cert, key = ca.issue(t, "localhost", 3, x509.ExtKeyUsageServerAuth)
+	modTime = modTime.Add(time.Minute)
+	write(t, cfg.CertFile, cert, modTime)
+	r.Reload()
+	assert.Equal(t, int64(2), serial(t, r))
+
+	// Once the key is renewed too, the new certificate is served.
+	modTime = modTime.Add(time.Minute)
+	write(t, cfg.KeyFile, key, modTime)
+	r.Reload()
+	assert.Equal(t, int64(3), serial(t, r))
+}
+
+func TestReloaderVerifiesClientCertificates(t *testing.T) {
+	dir := t.TempDir()
+	ca := newAuthority(t, "Test CA")
+	other := newAuthority(t, "Other CA")
+	cfg := config.TLSConfig{
+		CertFile:          filepath.Join(dir, "tls.crt"),
+		KeyFile:           filepath.Join(dir, "tls.key"),
+		ClientCAFile:      filepath.Join(dir, "ca.crt"),
+		RequireClientCert: true,
+		ReloadInterval:    time.Minute,
+	}
+	modTime := time.Now().Add(-time.Hour)
+	cert, key := ca.issue(t, "localhost", 2, x509.ExtKeyUsageServerAuth)
+	write(t, cfg.CertFile, cert, modTime)
+	write(t, cfg.KeyFile, key, modTime)
+	write(t, cfg.ClientCAFile, []byte("not a certificate"), modTime)
+
+	_, err := NewReloader(cfg)
+	assert.ErrorIs(t, err, ErrNoCertificates)
+
+	write(t, cfg.ClientCAFile, ca.pem, modTime)
+	r, err := NewReloader(cfg)
+	require.NoError(t, err)
+
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.Write([]byte(req.TLS.VerifiedChains[0][0].Subject.CommonName))
+	}))
+	server.TLS = r.TLSConfig()
+	server.StartTLS()
+	defer server.Close()
+
+	roots := x509.NewCertPool()
+	roots.AddCert(ca.cert)
+	get := func(issuer authority) error {
+		clientCert, clientKey := issuer.issue(t, "billing", 4, x509.ExtKeyUsageClientAuth)
+		pair, err := tls.X509KeyPair(clientCert, clientKey)
+		require.NoError(t, err)
+		client := &http.Client{Transport: &http.Transport{TLSClientConfig: &tls.Config{
+			RootCAs:      roots,
+			Certificates: []tls.Certificate{pair},
+		}}}
+		resp, err := client.Get(server.URL)
+		if err != nil {
+			return err
+		}
+		resp.Body.Close()
+		return nil
+	}
+
+	assert.NoError(t, get(ca))
+	assert.Error(t, get(other))
+
+	// The client CAs are reloaded like the certificate.
+	write(t, cfg.ClientCAFile, other.pem, modTime.Add(time.Minute))
+	r.Reload()
+	assert.Error(t, get(ca))
+	assert.NoError(t, get(other))
+}
@@ -0,0 +1,17 @@
+package config
+
+type AttachmentConfig struct {
+	Dir     string
+	MaxSize int64
+	// AllowedTypes lists the sniffed content types accepted for upload;
+	// entries like "image/*" match a whole family. Empty allows any type.
+	AllowedTypes []string
+}
+
+func NewAttachmentConfig() AttachmentConfig {
+	return AttachmentConfig{
+		Dir:          getEnv("ATTACHMENT_DIR", "./data/attachments"),
+		MaxSize:      int64(getEnvInt("ATTACHMENT_MAX_SIZE", 10<<20)),
+		AllowedTypes: getEnvList("ATTACHMENT_ALLOWED_TYPES"),
+	}
+}
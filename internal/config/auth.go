@@ -0,0 +1,69 @@
+package config
+
+import (
+	"os"
+	"strings"
+	"time"
+)
+
+type AuthConfig struct {
+	// StaticTokens maps opaque bearer tokens to the subject they identify.
+	StaticTokens map[string]string
+	// JWTSecret verifies HS256 tokens.
+	JWTSecret string
+	// JWTPublicKeyFile is a PEM file with the RSA key verifying RS256
+	// tokens; JWKSFile is a JSON Web Key Set with keys picked by kid.
+	JWTPublicKeyFile string
+	JWKSFile         string
+	// JWTIssuer and JWTAudience are checked when set.
+	JWTIssuer   string
+	JWTAudience string
+	// JWTLeeway absorbs clock skew when checking exp and nbf.
+	JWTLeeway time.Duration
+	// PublicPaths are route patterns, as registered with gin, that the
+	// authentication middleware lets through without a token.
+	PublicPaths []string
+	// AdminSubjects are the users, by subject, allowed to manage API keys.
+	AdminSubjects []string
+	// SubjectTenants binds the callers identified by a static token or a
+	// client certificate, by subject, to a tenant. Those not listed belong
+	// to the default tenant.
+	SubjectTenants map[string]string
+}
+
+func NewAuthConfig() AuthConfig {
+	cfg := AuthConfig{
+		StaticTokens:     parsePairs(os.Getenv("AUTH_STATIC_TOKENS"), ":"),
+		JWTSecret:        os.Getenv("AUTH_JWT_SECRET"),
+		JWTPublicKeyFile: os.Getenv("AUTH_JWT_PUBLIC_KEY_FILE"),
+		JWKSFile:         os.Getenv("AUTH_JWKS_FILE"),
+		JWTIssuer:        os.Getenv("AUTH_JWT_ISSUER"),
+		JWTAudience:      os.Getenv("AUTH_JWT_AUDIENCE"),
+		JWTLeeway:        getEnvDuration("AUTH_JWT_LEEWAY", 30*time.Second),
+		PublicPaths:      []string{"/swagger/*any", "/openapi.json", "/healthz", "/readyz"},
+		AdminSubjects:    getEnvList("AUTH_ADMIN_SUBJECTS"),
+		// Subjects such as cert:billing contain colons.
+		SubjectTenants: parsePairs(os.Getenv("AUTH_SUBJECT_TENANTS"), "="),
+	}
+	switch value := os.Getenv("AUTH_PUBLIC_PATHS"); value {
+	case "":
+	case "none":
+		cfg.PublicPaths = nil
+	default:
+		cfg.PublicPaths = getEnvList("AUTH_PUBLIC_PATHS")
+	}
+	return cfg
+}
+
+// parsePairs parses "key:value,key:value" lists, with sep between keys and
+// values.
+func parsePairs(value, sep string) map[string]string {
+	pairs := make(map[string]string)
+	for _, item := range strings.Split(value, ",") {
+		key, val, ok := strings.Cut(strings.TrimSpace(item), sep)
+		if ok && key != "" && val != "" {
+			pairs[key] = val
+		}
+	}
+	return pairs
+}
@@ -0,0 +1,32 @@
+package config
+
+import (
+	"os"
+	"time"
+)
+
+type EncryptionConfig struct {
+	// KeyringFile is a JSON keyring whose current key seals task names in
+	// MySQL. Without it, names are stored in plain text.
+	KeyringFile string
+	// ReencryptInterval is how often names stored in plain text or under a
+	// key other than the current one are sealed again, ReencryptBatchSize
+	// at a time.
+	ReencryptInterval  time.Duration
+	ReencryptBatchSize int
+}
+
+func NewEncryptionConfig() EncryptionConfig {
+	cfg := EncryptionConfig{
+		KeyringFile:        os.Getenv("ENCRYPTION_KEYRING_FILE"),
+		ReencryptInterval:  getEnvDuration("ENCRYPTION_REENCRYPT_INTERVAL", time.Hour),
+		ReencryptBatchSize: getEnvInt("ENCRYPTION_REENCRYPT_BATCH_SIZE", 100),
+	}
+	if cfg.ReencryptInterval <= 0 {
+		cfg.ReencryptInterval = time.Hour
+	}
+	if cfg.ReencryptBatchSize <= 0 {
+		cfg.ReencryptBatchSize = 100
+	}
+	return cfg
+}
@@ -0,0 +1,15 @@
+package config
+
+import "time"
+
+type EventsConfig struct {
+	BufferSize int
+	Heartbeat  time.Duration
+}
+
+func NewEventsConfig() EventsConfig {
+	return EventsConfig{
+		BufferSize: getEnvInt("EVENTS_BUFFER_SIZE", 1024),
+		Heartbeat:  getEnvDuration("EVENTS_HEARTBEAT_INTERVAL", 15*time.Second),
+	}
+}
@@ -0,0 +1,15 @@
+package config
+
+type GraphQLConfig struct {
+	MaxDepth      int
+	MaxComplexity int
+	GraphiQL      bool
+}
+
+func NewGraphQLConfig() GraphQLConfig {
+	return GraphQLConfig{
+		MaxDepth:      getEnvInt("GRAPHQL_MAX_DEPTH", 5),
+		MaxComplexity: getEnvInt("GRAPHQL_MAX_COMPLEXITY", 1000),
+		GraphiQL:      getEnvBool("GRAPHQL_GRAPHIQL", IsDevelopment()),
+	}
+}
@@ -0,0 +1,23 @@
+package config
+
+import "time"
+
+type HealthConfig struct {
+	// CheckTimeout bounds each dependency check of a readiness probe.
+	CheckTimeout time.Duration
+	// ShutdownDelay is how long readiness fails before the server stops
+	// accepting connections on shutdown, so that load balancers stop
+	// sending requests first.
+	ShutdownDelay time.Duration
+}
+
+func NewHealthConfig() HealthConfig {
+	cfg := HealthConfig{
+		CheckTimeout:  getEnvDuration("HEALTH_CHECK_TIMEOUT", 2*time.Second),
+		ShutdownDelay: getEnvDuration("HEALTH_SHUTDOWN_DELAY", 0),
+	}
+	if cfg.CheckTimeout <= 0 {
+		cfg.CheckTimeout = 2 * time.Second
+	}
+	return cfg
+}
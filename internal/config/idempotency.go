@@ -0,0 +1,25 @@
+package config
+
+import "time"
+
+type IdempotencyConfig struct {
+	// TTL is how long the response to a request with an Idempotency-Key is
+	// replayed to retries. Zero turns idempotency keys off.
+	TTL time.Duration
+	// LockTimeout bounds how long a request holds its key before it has a
+	// response. A concurrent duplicate waits for it that long at most, and
+	// then handles the request itself, so it should exceed the time the
+	// slowest request takes.
+	LockTimeout time.Duration
+}
+
+func NewIdempotencyConfig() IdempotencyConfig {
+	cfg := IdempotencyConfig{
+		TTL:         getEnvDuration("IDEMPOTENCY_TTL", 24*time.Hour),
+		LockTimeout: getEnvDuration("IDEMPOTENCY_LOCK_TIMEOUT", time.Minute),
+	}
+	if cfg.LockTimeout <= 0 {
+		cfg.LockTimeout = time.Minute
+	}
+	return cfg
+}
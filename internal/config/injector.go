@@ -1,11 +1,10 @@
 package config
 
 import (
+	"context"
 	"os"
 
 	"go.uber.org/dig"
-
-	"task-api/internal/domain/task"
 )
 
 const (
@@ -14,12 +13,29 @@ const (
 
 type Injector struct {
 	container *dig.Container
+	lifecycle *Lifecycle
 }
 
+// NewInjector provides the *Lifecycle of the injector, so that constructors
+// can append the hooks starting and stopping what they build.
 func NewInjector() *Injector {
-	return &Injector{
+	i := &Injector{
 		container: dig.New(),
+		lifecycle: &Lifecycle{},
 	}
+	// Providing a constant value cannot fail.
+	_ = i.container.Provide(func() *Lifecycle { return i.lifecycle })
+	return i
+}
+
+// Start runs the start hooks appended while the values were built.
+func (i *Injector) Start(ctx context.Context) error {
+	return i.lifecycle.Start(ctx)
+}
+
+// Stop runs the stop hooks of everything started, in reverse order.
+func (i *Injector) Stop(ctx context.Context) error {
+	return i.lifecycle.Stop(ctx)
 }
 
 func (i *Injector) Invoke(function interface{}) error {
@@ -29,22 +45,9 @@ func (i *Injector) Invoke(function interface{}) error {
 func (i *Injector) ProvideStorage(inMemoryRepos []interface{}, relationRepos []interface{}) error {
 	storageType := os.Getenv("STORAGE_TYPE")
 	if storageType == Mysql {
-		dsn := os.Getenv("MYSQL_DSN")
-		for _, repoConstructor := range relationRepos {
-			if err := i.container.Provide(func() (task.Repository, error) {
-				return repoConstructor.(func(string) (task.Repository, error))(dsn)
-			}); err != nil {
-				return err
-			}
-		}
-	} else {
-		for _, repoConstructor := range inMemoryRepos {
-			if err := i.container.Provide(repoConstructor.(func() task.Repository)); err != nil {
-				return err
-			}
-		}
+		return i.ProvideMulti(relationRepos)
 	}
-	return nil
+	return i.ProvideMulti(inMemoryRepos)
 }
 
 func (i *Injector) ProvideMulti(constructors []interface{}) error {
@@ -59,3 +62,14 @@ func (i *Injector) ProvideMulti(constructors []interface{}) error {
 func (i *Injector) Provide(constructor interface{}) error {
 	return i.container.Provide(constructor)
 }
+
+// ProvideGroup registers every constructor as a member of the named value
+// group, exposed as the interface pointed to by as.
+func (i *Injector) ProvideGroup(group string, as interface{}, constructors []interface{}) error {
+	for _, constructor := range constructors {
+		if err := i.container.Provide(constructor, dig.Group(group), dig.As(as)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
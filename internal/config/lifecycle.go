@@ -0,0 +1,101 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// Hook starts and stops a component. Either function may be nil.
+type Hook struct {
+	Name    string
+	OnStart func(ctx context.Context) error
+	OnStop  func(ctx context.Context) error
+}
+
+// Lifecycle starts components in the order their hooks were appended and
+// stops them in reverse, so that a component stops before the ones it was
+// built from: the server before the workers, the workers before the
+// database.
+type Lifecycle struct {
+	mu      sync.Mutex
+	hooks   []Hook
+	started int
+}
+
+func (l *Lifecycle) Append(hook Hook) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.hooks = append(l.hooks, hook)
+}
+
+// Go appends a hook that runs run in its own goroutine once started. Stop
+// cancels the context passed to run and waits for it to return.
+func (l *Lifecycle) Go(name string, run func(ctx context.Context)) {
+	var cancel context.CancelFunc
+	done := make(chan struct{})
+	l.Append(Hook{
+		Name: name,
+		OnStart: func(context.Context) error {
+			var ctx context.Context
+			ctx, cancel = context.WithCancel(context.Background())
+			go func() {
+				defer close(done)
+				run(ctx)
+			}()
+			return nil
+		},
+		OnStop: func(ctx context.Context) error {
+			cancel()
+			select {
+			case <-done:
+				return nil
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		},
+	})
+}
+
+// Start runs the OnStart hooks in order. When one fails, the components
+// started before it are stopped again and its error is returned.
+func (l *Lifecycle) Start(ctx context.Context) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	for l.started < len(l.hooks) {
+		hook := l.hooks[l.started]
+		if hook.OnStart != nil {
+			if err := hook.OnStart(ctx); err != nil {
+				err = fmt.Errorf("failed to start %s: %w", hook.Name, err)
+				return errors.Join(err, l.stop(ctx))
+			}
+		}
+		l.started++
+	}
+	return nil
+}
+
+// Stop runs the OnStop hooks of the started components in reverse order.
+// Every hook runs, even after others failed or ctx is done, and their
+// errors are joined.
+func (l *Lifecycle) Stop(ctx context.Context) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.stop(ctx)
+}
+
+func (l *Lifecycle) stop(ctx context.Context) error {
+	var errs []error
+	for ; l.started > 0; l.started-- {
+		hook := l.hooks[l.started-1]
+		if hook.OnStop == nil {
+			continue
+		}
+		if err := hook.OnStop(ctx); err != nil {
+			errs = append(errs, fmt.Errorf("failed to stop %s: %w", hook.Name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,123 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLifecycle(t *testing.T) {
+	var calls []string
+	hook := func(name string, startErr, stopErr error) Hook {
+		return Hook{
+			Name: name,
+			OnStart: func(context.Context) error {
+				calls = append(calls, "start "+name)
+				return startErr
+			},
+			OnStop: func(context.Context) error {
+				calls = append(calls, "stop "+name)
+				return stopErr
+			},
+		}
+	}
+	failed := errors.New("failed")
+
+	type testCase struct {
+		TestCase      string
+		Hooks         []Hook
+		ExpectedStart string
+		ExpectedStop  string
+		Expected      []string
+	}
+
+	testCases := []testCase{
+		{
+			TestCase: "stops in reverse order",
+			Hooks:    []Hook{hook("db", nil, nil), hook("workers", nil, nil), hook("server", nil, nil)},
+			Expected: []string{"start db", "start workers", "start server", "stop server", "stop workers", "stop db"},
+		},
+		{
+			TestCase:      "a failed start stops what started before",
+			Hooks:         []Hook{hook("db", nil, nil), hook("server", failed, nil), hook("workers", nil, nil)},
+			ExpectedStart: "failed to start server: failed",
+			Expected:      []string{"start db", "start server", "stop db"},
+		},
+		{
+			TestCase:     "a failed stop does not keep the others from stopping",
+			Hooks:        []Hook{hook("db", nil, failed), hook("server", nil, failed), {Name: "no-op"}},
+			ExpectedStop: "failed to stop server: failed\nfailed to stop db: failed",
+			Expected:     []string{"start db", "start server", "stop server", "stop db"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.TestCase, func(t *testing.T) {
+			calls = nil
+			l := &Lifecycle{}
+			for _, h := range tc.Hooks {
+				l.Append(h)
+			}
+
+			err := l.Start(context.Background())
+			if tc.ExpectedStart != "" {
+				assert.EqualError(t, err, tc.ExpectedStart)
+			} else {
+				assert.NoError(t, err)
+				err = l.Stop(context.Background())
+				if tc.ExpectedStop != "" {
+					assert.EqualError(t, err, tc.ExpectedStop)
+				} else {
+					assert.NoError(t, err)
+				}
+			}
+			assert.Equal(t, tc.Expected, calls)
+
+			// Stopped components are not stopped again.
+			calls = nil
+			assert.NoError(t, l.Stop(context.Background()))
+			assert.Empty(t, calls)
+		})
+	}
+}
+
+func TestLifecycleGo(t *testing.T) {
+	l := &Lifecycle{}
+	stopped := make(chan struct{})
+	l.Go("stuck", func(ctx context.Context) {
+		select {}
+	})
+	l.Go("worker", func(ctx context.Context) {
+		<-ctx.Done()
+		close(stopped)
+	})
+
+	assert.NoError(t, l.Start(context.Background()))
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	err := l.Stop(ctx)
+	assert.EqualError(t, err, "failed to stop stuck: context deadline exceeded")
+	select {
+	case <-stopped:
+	default:
+		t.Fatal("worker was not stopped")
+	}
+}
+
+func TestInjectorProvidesLifecycle(t *testing.T) {
+	injector := NewInjector()
+	started := false
+	assert.NoError(t, injector.Provide(func(l *Lifecycle) string {
+		l.Append(Hook{Name: "greeting", OnStart: func(context.Context) error {
+			started = true
+			return nil
+		}})
+		return "hello"
+	}))
+	assert.NoError(t, injector.Invoke(func(string) {}))
+	assert.NoError(t, injector.Start(context.Background()))
+	assert.True(t, started)
+}
@@ -0,0 +1,11 @@
+package config
+
+import "os"
+
+type MySQLConfig struct {
+	DSN string
+}
+
+func NewMySQLConfig() MySQLConfig {
+	return MySQLConfig{DSN: os.Getenv("MYSQL_DSN")}
+}
@@ -0,0 +1,24 @@
+package config
+
+import "time"
+
+type RateLimitConfig struct {
+	// Reads and Writes are the requests a client may make per Period with
+	// GET, HEAD and OPTIONS and with other methods. Unused requests carry
+	// over up to one period's worth. Zero turns the limit off.
+	Reads  int
+	Writes int
+	Period time.Duration
+}
+
+func NewRateLimitConfig() RateLimitConfig {
+	cfg := RateLimitConfig{
+		Reads:  getEnvInt("RATE_LIMIT_READS", 300),
+		Writes: getEnvInt("RATE_LIMIT_WRITES", 60),
+		Period: getEnvDuration("RATE_LIMIT_PERIOD", time.Minute),
+	}
+	if cfg.Period <= 0 {
+		cfg.Period = time.Minute
+	}
+	return cfg
+}
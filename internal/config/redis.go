@@ -0,0 +1,19 @@
+package config
+
+import "os"
+
+// RedisConfig points at the Redis server state shared between API instances
+// is kept in. Without an address, that state stays in each process.
+type RedisConfig struct {
+	Addr     string
+	Password string
+	DB       int
+}
+
+func NewRedisConfig() RedisConfig {
+	return RedisConfig{
+		Addr:     os.Getenv("REDIS_ADDR"),
+		Password: os.Getenv("REDIS_PASSWORD"),
+		DB:       getEnvInt("REDIS_DB", 0),
+	}
+}
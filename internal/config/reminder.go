@@ -0,0 +1,34 @@
+package config
+
+import (
+	"os"
+	"time"
+)
+
+type ReminderConfig struct {
+	// Windows are offsets from the due date at which reminders go out;
+	// negative ones remind of overdue tasks. An empty list disables
+	// reminders.
+	Windows      []time.Duration
+	PollInterval time.Duration
+}
+
+func NewReminderConfig() ReminderConfig {
+	cfg := ReminderConfig{
+		Windows:      []time.Duration{24 * time.Hour, 0, -24 * time.Hour},
+		PollInterval: getEnvDuration("REMINDER_POLL_INTERVAL", time.Minute),
+	}
+	switch value := os.Getenv("REMINDER_WINDOWS"); value {
+	case "":
+	case "none":
+		cfg.Windows = nil
+	default:
+		cfg.Windows = nil
+		for _, item := range getEnvList("REMINDER_WINDOWS") {
+			if window, err := time.ParseDuration(item); err == nil {
+				cfg.Windows = append(cfg.Windows, window)
+			}
+		}
+	}
+	return cfg
+}
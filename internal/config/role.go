@@ -0,0 +1,17 @@
+package config
+
+import "task-api/internal/domain/role"
+
+type RoleConfig struct {
+	// DefaultRole is the role of callers without an assignment, including
+	// subjects that are not users.
+	DefaultRole role.Role
+}
+
+func NewRoleConfig() RoleConfig {
+	defaultRole := role.Role(getEnv("ROLE_DEFAULT", string(role.Viewer)))
+	if !defaultRole.Valid() {
+		defaultRole = role.Viewer
+	}
+	return RoleConfig{DefaultRole: defaultRole}
+}
@@ -0,0 +1,33 @@
+package config
+
+import "time"
+
+type ServerConfig struct {
+	Addr string
+	// ReadTimeout and WriteTimeout bound reading a whole request and
+	// writing its response; event streams are exempt from WriteTimeout.
+	ReadTimeout       time.Duration
+	ReadHeaderTimeout time.Duration
+	WriteTimeout      time.Duration
+	// IdleTimeout closes keep-alive connections waiting for a request.
+	IdleTimeout time.Duration
+	// ShutdownTimeout bounds draining in-flight requests and stopping the
+	// workers and storage on SIGINT or SIGTERM.
+	ShutdownTimeout time.Duration
+	// TrustedProxies lists the addresses and CIDR ranges of the proxies
+	// whose X-Forwarded-For headers are believed. Without any, clients are
+	// told apart by the address of the connection.
+	TrustedProxies []string
+}
+
+func NewServerConfig() ServerConfig {
+	return ServerConfig{
+		Addr:              getEnv("HTTP_ADDR", ":8080"),
+		ReadTimeout:       getEnvDuration("HTTP_READ_TIMEOUT", time.Minute),
+		ReadHeaderTimeout: getEnvDuration("HTTP_READ_HEADER_TIMEOUT", 10*time.Second),
+		WriteTimeout:      getEnvDuration("HTTP_WRITE_TIMEOUT", time.Minute),
+		IdleTimeout:       getEnvDuration("HTTP_IDLE_TIMEOUT", 2*time.Minute),
+		ShutdownTimeout:   getEnvDuration("HTTP_SHUTDOWN_TIMEOUT", 30*time.Second),
+		TrustedProxies:    getEnvList("HTTP_TRUSTED_PROXIES"),
+	}
+}
@@ -0,0 +1,15 @@
+package config
+
+import "task-api/internal/domain/task"
+
+type TaskConfig struct {
+	DeleteChildren task.DeleteMode
+}
+
+func NewTaskConfig() TaskConfig {
+	mode := task.DeleteMode(getEnv("TASK_DELETE_CHILDREN", string(task.DeleteReparent)))
+	if mode != task.DeleteCascade {
+		mode = task.DeleteReparent
+	}
+	return TaskConfig{DeleteChildren: mode}
+}
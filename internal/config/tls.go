@@ -0,0 +1,39 @@
+package config
+
+import (
+	"os"
+	"time"
+)
+
+// TLSConfig turns on HTTPS when a certificate and key are given.
+type TLSConfig struct {
+	CertFile string
+	KeyFile  string
+	// ClientCAFile is a PEM bundle of the CAs client certificates are
+	// verified against. Without it, clients are not asked for one.
+	ClientCAFile string
+	// RequireClientCert refuses connections without a valid client
+	// certificate; otherwise one is only verified when a client sends it.
+	RequireClientCert bool
+	// ReloadInterval is how often the files are checked for changes.
+	ReloadInterval time.Duration
+}
+
+func NewTLSConfig() TLSConfig {
+	cfg := TLSConfig{
+		CertFile:          os.Getenv("TLS_CERT_FILE"),
+		KeyFile:           os.Getenv("TLS_KEY_FILE"),
+		ClientCAFile:      os.Getenv("TLS_CLIENT_CA_FILE"),
+		RequireClientCert: getEnvBool("TLS_REQUIRE_CLIENT_CERT", true),
+		ReloadInterval:    getEnvDuration("TLS_RELOAD_INTERVAL", time.Minute),
+	}
+	if cfg.ReloadInterval <= 0 {
+		cfg.ReloadInterval = time.Minute
+	}
+	return cfg
+}
+
+// Enabled reports whether the API is served over HTTPS.
+func (c TLSConfig) Enabled() bool {
+	return c.CertFile != "" && c.KeyFile != ""
+}
@@ -0,0 +1,23 @@
+package config
+
+import "time"
+
+type WebhookConfig struct {
+	MaxAttempts  int
+	BackoffBase  time.Duration
+	BackoffMax   time.Duration
+	Timeout      time.Duration
+	Workers      int
+	PollInterval time.Duration
+}
+
+func NewWebhookConfig() WebhookConfig {
+	return WebhookConfig{
+		MaxAttempts:  getEnvInt("WEBHOOK_MAX_ATTEMPTS", 8),
+		BackoffBase:  getEnvDuration("WEBHOOK_BACKOFF_BASE", 2*time.Second),
+		BackoffMax:   getEnvDuration("WEBHOOK_BACKOFF_MAX", time.Hour),
+		Timeout:      getEnvDuration("WEBHOOK_TIMEOUT", 10*time.Second),
+		Workers:      getEnvInt("WEBHOOK_WORKERS", 4),
+		PollInterval: getEnvDuration("WEBHOOK_POLL_INTERVAL", time.Second),
+	}
+}
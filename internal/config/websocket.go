@@ -0,0 +1,19 @@
+package config
+
+import "time"
+
+type WebSocketConfig struct {
+	SendBuffer     int
+	PingInterval   time.Duration
+	WriteTimeout   time.Duration
+	AllowedOrigins []string
+}
+
+func NewWebSocketConfig() WebSocketConfig {
+	return WebSocketConfig{
+		SendBuffer:     getEnvInt("WS_SEND_BUFFER", 256),
+		PingInterval:   getEnvDuration("WS_PING_INTERVAL", 30*time.Second),
+		WriteTimeout:   getEnvDuration("WS_WRITE_TIMEOUT", 10*time.Second),
+		AllowedOrigins: getEnvList("WS_ALLOWED_ORIGINS"),
+	}
+}
@@ -0,0 +1,43 @@
+package apikey
+
+import "time"
+
+// Scopes grant API keys access to parts of the API.
+const (
+	ScopeTasksRead  = "tasks:read"
+	ScopeTasksWrite = "tasks:write"
+)
+
+// Scopes lists every scope a key can carry.
+var Scopes = []string{ScopeTasksRead, ScopeTasksWrite}
+
+// SecretPrefix starts every secret, which tells API keys apart from other
+// bearer tokens.
+const SecretPrefix = "tk_"
+
+// Key is a machine credential. Only the SHA-256 hash of the secret is
+// stored; the secret itself is shown once, when the key is issued.
+type Key struct {
+	ID int `json:"id"`
+	// TenantID is the tenant the key was issued in, the only one its
+	// callers reach.
+	TenantID string `json:"tenant_id"`
+	Name     string `json:"name"`
+	// Prefix is the start of the secret, to tell keys apart.
+	Prefix     string     `json:"prefix"`
+	Hash       string     `json:"-"`
+	Scopes     []string   `json:"scopes"`
+	CreatedAt  time.Time  `json:"created_at"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+	RevokedAt  *time.Time `json:"revoked_at,omitempty"`
+}
+
+func (k Key) Revoked() bool {
+	return k.RevokedAt != nil
+}
+
+// Issued is a newly created key together with its secret.
+type Issued struct {
+	Key    Key    `json:"key"`
+	Secret string `json:"secret"`
+}
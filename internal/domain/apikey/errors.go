@@ -0,0 +1,11 @@
+package apikey
+
+import "errors"
+
+var (
+	ErrNotFound = errors.New("api key not found")
+	// ErrInvalidKey wraps problems with the name or scopes of a new key.
+	ErrInvalidKey = errors.New("invalid api key")
+	// ErrRevoked is returned when a revoked key is used.
+	ErrRevoked = errors.New("api key is revoked")
+)
@@ -0,0 +1,14 @@
+package apikey
+
+import "time"
+
+type Repository interface {
+	// GetAll returns the keys ordered by ID, revoked ones included.
+	GetAll() ([]Key, error)
+	GetByID(id int) (Key, error)
+	GetByHash(hash string) (Key, error)
+	Create(k Key) (Key, error)
+	// Touch records when a key was last used.
+	Touch(id int, at time.Time) error
+	Revoke(id int, at time.Time) error
+}
@@ -0,0 +1,31 @@
+package attachment
+
+import (
+	"io"
+	"time"
+)
+
+type Attachment struct {
+	ID          int    `json:"id"`
+	TaskID      int    `json:"task_id"`
+	Filename    string `json:"filename"`
+	ContentType string `json:"content_type"`
+	Size        int64  `json:"size"`
+	// Checksum is the hex SHA-256 of the content.
+	Checksum  string    `json:"checksum"`
+	Uploader  string    `json:"uploader"`
+	CreatedAt time.Time `json:"created_at"`
+	// StorageKey locates the content in the blob store.
+	StorageKey string `json:"-"`
+}
+
+// BlobStore keeps the content of attachments. Keys are generated by the
+// service and only contain letters, digits, dashes and slashes.
+type BlobStore interface {
+	// Put stores everything read from r under key and returns the number of
+	// bytes written.
+	Put(key string, r io.Reader) (int64, error)
+	Open(key string) (io.ReadSeekCloser, error)
+	// Delete removes the blob; deleting a missing blob is not an error.
+	Delete(key string) error
+}
@@ -0,0 +1,11 @@
+package attachment
+
+import "errors"
+
+var (
+	ErrNotFound       = errors.New("attachment not found")
+	ErrBlobNotFound   = errors.New("attachment content not found")
+	ErrTooLarge       = errors.New("attachment is too large")
+	ErrTypeNotAllowed = errors.New("attachment type is not allowed")
+	ErrEmpty          = errors.New("attachment is empty")
+)
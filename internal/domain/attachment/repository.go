@@ -0,0 +1,8 @@
+package attachment
+
+type Repository interface {
+	ListByTask(taskID int) ([]Attachment, error)
+	GetByID(id int) (Attachment, error)
+	Create(a Attachment) (Attachment, error)
+	Delete(id int) error
+}
@@ -0,0 +1,50 @@
+package audit
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Operation is the kind of change a record describes.
+type Operation string
+
+const (
+	OperationCreate Operation = "create"
+	OperationUpdate Operation = "update"
+	OperationDelete Operation = "delete"
+)
+
+// Record describes one change of a task. Records are only ever appended.
+type Record struct {
+	ID     int `json:"id"`
+	TaskID int `json:"task_id"`
+	// TenantID is the tenant of the caller who made the change.
+	TenantID string `json:"-"`
+	// Actor is the subject of the caller who made the change.
+	Actor     string    `json:"actor"`
+	Time      time.Time `json:"time"`
+	RequestID string    `json:"request_id,omitempty"`
+	Operation Operation `json:"operation"`
+	// Changes holds the fields of the task that changed, by their JSON
+	// name. Creations list every field that was set and deletions every
+	// field the task had.
+	Changes map[string]Change `json:"changes"`
+}
+
+// Change is the JSON value of a field before and after a change; null when
+// the field was not set.
+type Change struct {
+	Before json.RawMessage `json:"before"`
+	After  json.RawMessage `json:"after"`
+}
+
+// Filter selects records. Zero fields match every record, except TenantID,
+// which only ever matches the records of that tenant; From and To bound the
+// time of a record, inclusively.
+type Filter struct {
+	TenantID string
+	TaskID   int
+	Actor    string
+	From     time.Time
+	To       time.Time
+}
@@ -0,0 +1,6 @@
+package audit
+
+import "errors"
+
+// ErrInvalidFilter wraps problems with the filter of a query.
+var ErrInvalidFilter = errors.New("invalid audit filter")
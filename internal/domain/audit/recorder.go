@@ -0,0 +1,13 @@
+package audit
+
+import (
+	"context"
+
+	"task-api/internal/domain/task"
+)
+
+// Recorder writes the record of a change of a task, made by the caller
+// carried by ctx. Before is nil for creations and after for deletions.
+type Recorder interface {
+	Record(ctx context.Context, op Operation, before, after *task.Info) error
+}
@@ -0,0 +1,12 @@
+package audit
+
+import "context"
+
+// Repository stores records. There is no way to change or remove one.
+type Repository interface {
+	// Append writes a record along with the change made through ctx, when
+	// the storage has transactions.
+	Append(ctx context.Context, r Record) (Record, error)
+	// Find returns the records matching f, oldest first.
+	Find(f Filter) ([]Record, error)
+}
@@ -0,0 +1,23 @@
+package comment
+
+import "time"
+
+type Comment struct {
+	ID     int `json:"id"`
+	TaskID int `json:"task_id"`
+	// ParentID is set on replies and points at the comment replied to.
+	ParentID   *int      `json:"parent_id,omitempty"`
+	Author     string    `json:"author"`
+	Body       string    `json:"body"`
+	ReplyCount int       `json:"reply_count"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+// Page is one page of the comments at a single level of a thread.
+type Page struct {
+	Items  []Comment `json:"items"`
+	Total  int       `json:"total"`
+	Offset int       `json:"offset"`
+	Limit  int       `json:"limit"`
+}
@@ -0,0 +1,12 @@
+package comment
+
+import "errors"
+
+var (
+	ErrNotFound       = errors.New("comment not found")
+	ErrInvalidComment = errors.New("invalid comment")
+	ErrParentNotFound = errors.New("parent comment not found")
+	// ErrForbidden is returned when someone other than the author tries to
+	// change a comment.
+	ErrForbidden = errors.New("only the author can change a comment")
+)
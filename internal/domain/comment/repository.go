@@ -0,0 +1,15 @@
+package comment
+
+type Repository interface {
+	// List returns a page of the comments of a task that reply to parentID,
+	// or of its top-level comments when parentID is nil, oldest first,
+	// together with the total number of such comments.
+	List(taskID int, parentID *int, offset, limit int) ([]Comment, int, error)
+	GetByID(id int) (Comment, error)
+	Create(c Comment) (Comment, error)
+	Update(c Comment) (Comment, error)
+	// Delete removes a comment together with all of its replies.
+	Delete(id int) error
+	// DeleteByTask removes every comment of a task.
+	DeleteByTask(taskID int) error
+}
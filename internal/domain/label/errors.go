@@ -0,0 +1,11 @@
+package label
+
+import "errors"
+
+var (
+	ErrNotFound      = errors.New("label not found")
+	ErrInvalidLabel  = errors.New("invalid label")
+	ErrDuplicateName = errors.New("label name already exists")
+	ErrNotAttached   = errors.New("label is not attached to the task")
+	ErrInvalidMatch  = errors.New("match must be any or all")
+)
@@ -0,0 +1,19 @@
+package label
+
+type Label struct {
+	ID    int    `json:"id"`
+	Name  string `json:"name"`
+	Color string `json:"color,omitempty"`
+	// TenantID is the tenant owning the label. The repositories set it from
+	// the context, and it is never shown to clients.
+	TenantID string `json:"-"`
+}
+
+// Match decides whether a task filtered by several labels needs any or all
+// of them.
+type Match string
+
+const (
+	MatchAny Match = "any"
+	MatchAll Match = "all"
+)
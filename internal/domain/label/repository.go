@@ -0,0 +1,28 @@
+package label
+
+import "context"
+
+// Repository only reaches the labels of the tenant carried by ctx, as
+// tenant.FromContext returns it, and Create puts labels into that tenant.
+// Names are unique within a tenant.
+type Repository interface {
+	GetAll(ctx context.Context) ([]Label, error)
+	GetByID(ctx context.Context, id int) (Label, error)
+	GetByName(ctx context.Context, name string) (Label, error)
+	Create(ctx context.Context, l Label) (Label, error)
+	Update(ctx context.Context, l Label) (Label, error)
+	// Delete removes the label and detaches it from every task.
+	Delete(ctx context.Context, id int) error
+
+	// Attach links a label to a task. Attaching it twice is a no-op.
+	Attach(ctx context.Context, taskID, labelID int) error
+	Detach(ctx context.Context, taskID, labelID int) error
+	// DetachAll removes every label from a task.
+	DetachAll(ctx context.Context, taskID int) error
+	// GetTaskLabels returns the labels of each of the given tasks, sorted by
+	// name. Tasks without labels are left out of the map.
+	GetTaskLabels(ctx context.Context, taskIDs []int) (map[int][]Label, error)
+	// FindTasks returns the sorted IDs of the tasks carrying any or all of
+	// the given labels.
+	FindTasks(ctx context.Context, labelIDs []int, match Match) ([]int, error)
+}
@@ -0,0 +1,12 @@
+package project
+
+import "errors"
+
+var (
+	ErrNotFound       = errors.New("project not found")
+	ErrInvalidProject = errors.New("invalid project")
+	// ErrArchived is returned when tasks of an archived project are changed
+	// or tasks are added to it.
+	ErrArchived       = errors.New("project is archived")
+	ErrDefaultProject = errors.New("the default project cannot be archived")
+)
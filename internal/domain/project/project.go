@@ -0,0 +1,22 @@
+package project
+
+import "time"
+
+// DefaultProjectID is the project tasks are created in when none is given.
+// It always exists and cannot be archived.
+const DefaultProjectID = 1
+
+type Project struct {
+	ID          int    `json:"id"`
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	// ArchivedAt is set while the project and its tasks are archived.
+	ArchivedAt *time.Time `json:"archived_at,omitempty"`
+	// TaskCount and DoneCount are computed on read.
+	TaskCount int `json:"task_count"`
+	DoneCount int `json:"done_count"`
+}
+
+func (p Project) Archived() bool {
+	return p.ArchivedAt != nil
+}
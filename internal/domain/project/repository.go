@@ -0,0 +1,11 @@
+package project
+
+type Repository interface {
+	// GetAll returns the projects ordered by ID. Archived projects are only
+	// included when includeArchived is set.
+	GetAll(includeArchived bool) ([]Project, error)
+	GetByID(id int) (Project, error)
+	Create(p Project) (Project, error)
+	// Update stores the name, description and archive state of a project.
+	Update(p Project) (Project, error)
+}
@@ -0,0 +1,7 @@
+package reminder
+
+import "errors"
+
+// ErrAlreadySent is returned when a reminder for the same task, due date
+// and window has been claimed before.
+var ErrAlreadySent = errors.New("reminder already sent")
@@ -0,0 +1,15 @@
+package reminder
+
+import "time"
+
+// Reminder records that a task was reminded of for one window. Windows are
+// offsets from the due date: 24h fires a day before it, 0 when the task is
+// due and -24h a day after, once it is overdue.
+type Reminder struct {
+	TaskID int           `json:"task_id"`
+	Window time.Duration `json:"window"`
+	// DueAt is the due date the reminder was sent for, so moving the due
+	// date makes the task due for reminders again.
+	DueAt  time.Time `json:"due_at"`
+	SentAt time.Time `json:"sent_at"`
+}
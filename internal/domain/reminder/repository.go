@@ -0,0 +1,12 @@
+package reminder
+
+type Repository interface {
+	// Claim records a reminder before it is sent and fails with
+	// ErrAlreadySent when it was recorded before, which keeps a reminder
+	// from going out twice, also across restarts and instances.
+	Claim(r Reminder) error
+	// Release removes a claimed reminder that could not be sent, so it is
+	// tried again.
+	Release(r Reminder) error
+	DeleteByTask(taskID int) error
+}
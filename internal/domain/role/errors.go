@@ -0,0 +1,10 @@
+package role
+
+import "errors"
+
+var (
+	// ErrForbidden is wrapped with the reason an action was denied.
+	ErrForbidden   = errors.New("forbidden")
+	ErrInvalidRole = errors.New("invalid role")
+	ErrNotFound    = errors.New("role assignment not found")
+)
@@ -0,0 +1,10 @@
+package role
+
+import "context"
+
+// Policy decides whether the caller carried by ctx may take an action on the
+// tasks of a project. Project 0 stands for actions across projects, such as
+// listing all tasks. Denials wrap ErrForbidden.
+type Policy interface {
+	Authorize(ctx context.Context, action Action, projectID int) error
+}
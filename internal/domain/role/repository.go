@@ -0,0 +1,10 @@
+package role
+
+type Repository interface {
+	// GetByUser returns the assignments of a user, the global one first.
+	GetByUser(userID int) ([]Assignment, error)
+	// Set assigns a role, replacing the one the user had for the same
+	// project, or globally.
+	Set(a Assignment) error
+	Delete(userID, projectID int) error
+}
@@ -0,0 +1,48 @@
+package role
+
+// Role grants a user actions on tasks, either everywhere or within one
+// project.
+type Role string
+
+const (
+	Viewer Role = "viewer"
+	Editor Role = "editor"
+	Admin  Role = "admin"
+)
+
+// Action is something done to a task.
+type Action string
+
+const (
+	ActionRead   Action = "read"
+	ActionCreate Action = "create"
+	ActionUpdate Action = "update"
+	ActionDelete Action = "delete"
+)
+
+func (r Role) Valid() bool {
+	return r == Viewer || r == Editor || r == Admin
+}
+
+// Allows reports whether the role may take action: viewers read, editors
+// also create and update, and admins also delete.
+func (r Role) Allows(action Action) bool {
+	switch action {
+	case ActionRead:
+		return r.Valid()
+	case ActionCreate, ActionUpdate:
+		return r == Editor || r == Admin
+	case ActionDelete:
+		return r == Admin
+	default:
+		return false
+	}
+}
+
+// Assignment gives a user a role. Without a project it applies to every
+// project the user has no role of its own in.
+type Assignment struct {
+	UserID    int  `json:"user_id"`
+	ProjectID int  `json:"project_id,omitempty"`
+	Role      Role `json:"role"`
+}
@@ -0,0 +1,84 @@
+package task
+
+import "sort"
+
+// Dependency records that BlockerID has to be done before BlockedID.
+type Dependency struct {
+	BlockerID int `json:"blocker_id"`
+	BlockedID int `json:"blocked_id"`
+}
+
+// Blocks reports whether from reaches to by following dependencies, i.e.
+// whether from directly or transitively blocks to.
+func Blocks(deps []Dependency, from, to int) bool {
+	next := make(map[int][]int)
+	for _, d := range deps {
+		next[d.BlockerID] = append(next[d.BlockerID], d.BlockedID)
+	}
+
+	queue := []int{from}
+	visited := map[int]bool{from: true}
+	for len(queue) > 0 {
+		for _, id := range next[queue[0]] {
+			if id == to {
+				return true
+			}
+			if !visited[id] {
+				visited[id] = true
+				queue = append(queue, id)
+			}
+		}
+		queue = queue[1:]
+	}
+	return false
+}
+
+// TopologicalOrder sorts tasks so every task comes after all of its
+// blockers. Among tasks that are ready at the same time the lower ID goes
+// first, which keeps the order stable. Dependencies on tasks outside the
+// list are ignored.
+func TopologicalOrder(tasks []Info, deps []Dependency) ([]Info, error) {
+	byID := make(map[int]Info, len(tasks))
+	for _, t := range tasks {
+		byID[t.ID] = t
+	}
+
+	indegree := make(map[int]int, len(tasks))
+	next := make(map[int][]int)
+	for _, d := range deps {
+		if _, ok := byID[d.BlockerID]; !ok {
+			continue
+		}
+		if _, ok := byID[d.BlockedID]; !ok {
+			continue
+		}
+		next[d.BlockerID] = append(next[d.BlockerID], d.BlockedID)
+		indegree[d.BlockedID]++
+	}
+
+	var ready []int
+	for id := range byID {
+		if indegree[id] == 0 {
+			ready = append(ready, id)
+		}
+	}
+
+	order := make([]Info, 0, len(tasks))
+	for len(ready) > 0 {
+		sort.Ints(ready)
+		id := ready[0]
+		ready = ready[1:]
+		order = append(order, byID[id])
+		for _, blocked := range next[id] {
+			indegree[blocked]--
+			if indegree[blocked] == 0 {
+				ready = append(ready, blocked)
+			}
+		}
+	}
+
+	if len(order) != len(byID) {
+		return nil, ErrDependencyCycle
+	}
+	return order, nil
+}
@@ -0,0 +1,64 @@
+package task
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTopologicalOrder(t *testing.T) {
+	tasks := []Info{{ID: 1, Name: "Ship"}, {ID: 2, Name: "Build"}, {ID: 3, Name: "Design"}, {ID: 4, Name: "Docs"}}
+
+	tests := []struct {
+		TestCase string
+		Deps     []Dependency
+		Expected []int
+		Error    error
+	}{
+		{
+			TestCase: "No dependencies keeps ID order",
+			Deps:     nil,
+			Expected: []int{1, 2, 3, 4},
+		},
+		{
+			TestCase: "Blockers come first",
+			Deps:     []Dependency{{BlockerID: 3, BlockedID: 2}, {BlockerID: 2, BlockedID: 1}, {BlockerID: 3, BlockedID: 4}},
+			Expected: []int{3, 2, 1, 4},
+		},
+		{
+			TestCase: "Dependencies on unknown tasks are ignored",
+			Deps:     []Dependency{{BlockerID: 9, BlockedID: 1}},
+			Expected: []int{1, 2, 3, 4},
+		},
+		{
+			TestCase: "Cycle",
+			Deps:     []Dependency{{BlockerID: 1, BlockedID: 2}, {BlockerID: 2, BlockedID: 1}},
+			Error:    ErrDependencyCycle,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.TestCase, func(t *testing.T) {
+			order, err := TopologicalOrder(tasks, tc.Deps)
+			if tc.Error != nil {
+				assert.Equal(t, tc.Error, err)
+				return
+			}
+			assert.NoError(t, err)
+			var ids []int
+			for _, task := range order {
+				ids = append(ids, task.ID)
+			}
+			assert.Equal(t, tc.Expected, ids)
+		})
+	}
+}
+
+func TestBlocks(t *testing.T) {
+	deps := []Dependency{{BlockerID: 1, BlockedID: 2}, {BlockerID: 2, BlockedID: 3}}
+
+	assert.True(t, Blocks(deps, 1, 3))
+	assert.True(t, Blocks(deps, 2, 3))
+	assert.False(t, Blocks(deps, 3, 1))
+	assert.False(t, Blocks(deps, 4, 1))
+}
@@ -0,0 +1,45 @@
+package task
+
+import "errors"
+
+var (
+	ErrNotFound       = errors.New("task not found")
+	ErrNameRequired   = errors.New("task name is required")
+	ErrInvalidStatus  = errors.New("invalid task status")
+	ErrParentNotFound = errors.New("parent task not found")
+	ErrParentCycle    = errors.New("task cannot be moved under itself or one of its subtasks")
+	// ErrAssigneeNotFound is returned when a task is assigned to an unknown
+	// user.
+	ErrAssigneeNotFound = errors.New("assignee not found")
+	// ErrProjectNotFound is returned when a task is created in or moved to
+	// an unknown project.
+	ErrProjectNotFound = errors.New("project not found")
+	// ErrInvalidRecurrence wraps problems with a recurrence rule, its time
+	// zone or the due date it starts from.
+	ErrInvalidRecurrence = errors.New("invalid recurrence")
+	// ErrNotRecurring is returned when the occurrences of a task without a
+	// recurrence are requested.
+	ErrNotRecurring = errors.New("task does not recur")
+	// ErrInvalidSearch wraps problems with a search query.
+	ErrInvalidSearch = errors.New("invalid search")
+	// ErrEncrypted is returned when tasks are searched or filtered by a
+	// field that is stored encrypted, which the storage cannot match.
+	ErrEncrypted = errors.New("task names are stored encrypted and cannot be searched")
+
+	ErrDependencyCycle    = errors.New("dependency would create a cycle")
+	ErrDependencyNotFound = errors.New("dependency not found")
+	// ErrBlocked is returned when a task is marked done while at least one
+	// of its blockers is still open.
+	ErrBlocked = errors.New("task has open blockers")
+)
+
+// IsInvalid reports whether err was caused by invalid task input rather
+// than a missing task or a storage failure.
+func IsInvalid(err error) bool {
+	for _, invalid := range []error{ErrNameRequired, ErrInvalidStatus, ErrParentNotFound, ErrParentCycle, ErrDependencyCycle, ErrAssigneeNotFound, ErrProjectNotFound, ErrInvalidRecurrence, ErrInvalidSearch} {
+		if errors.Is(err, invalid) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,7 @@
+package task
+
+// DeleteHook is told about every deleted task so that data kept outside the
+// task repository, such as comments and attachment blobs, can be removed.
+type DeleteHook interface {
+	TaskDeleted(id int) error
+}
@@ -1,9 +1,49 @@
 package task
 
+import (
+	"context"
+	"time"
+
+	"task-api/internal/search"
+)
+
+// Repository only reaches the tasks of the tenant carried by ctx, as
+// tenant.FromContext returns it: tasks of other tenants are not found, not
+// listed and not counted. Create puts tasks into that tenant.
 type Repository interface {
-	GetAll() ([]Info, error)
-	GetByID(id int) (Info, error)
-	Create(taskInfo Info) (Info, error)
-	Update(taskInfo Info) (Info, error)
-	Delete(id int) error
+	GetAll(ctx context.Context) ([]Info, error)
+	GetByID(ctx context.Context, id int) (Info, error)
+	// Create and Update put tasks without a project into the default
+	// project.
+	Create(ctx context.Context, taskInfo Info) (Info, error)
+	Update(ctx context.Context, taskInfo Info) (Info, error)
+	Delete(ctx context.Context, id int) error
+	// GetChildren returns the direct children of a task.
+	GetChildren(ctx context.Context, parentID int) ([]Info, error)
+	// GetDescendants returns every task below the given one, breadth first.
+	GetDescendants(ctx context.Context, id int) ([]Info, error)
+	// AddDependency records that blockerID blocks blockedID. Adding an
+	// existing dependency is a no-op.
+	AddDependency(ctx context.Context, blockerID, blockedID int) error
+	RemoveDependency(ctx context.Context, blockerID, blockedID int) error
+	// GetBlockers returns the tasks that directly block the given one.
+	GetBlockers(ctx context.Context, id int) ([]Info, error)
+	GetDependencies(ctx context.Context) ([]Dependency, error)
+	// GetByProject returns the tasks of a project, ordered by ID.
+	GetByProject(ctx context.Context, projectID int) ([]Info, error)
+	// CountByProject returns the number of tasks and done tasks of every
+	// project that has tasks.
+	CountByProject(ctx context.Context) (map[int]Progress, error)
+	// GetByAssignee returns the tasks assigned to a user, ordered by ID.
+	GetByAssignee(ctx context.Context, userID int) ([]Info, error)
+	// GetDueBefore returns the pending tasks due at or before the given
+	// time, ordered by due date.
+	GetDueBefore(ctx context.Context, before time.Time) ([]Info, error)
+	// Search returns the tasks whose names match the query, most relevant
+	// first. Scores are only comparable within one result.
+	Search(ctx context.Context, query search.Query) ([]SearchResult, error)
+	// Tenants returns the tenants that have tasks, in order, for work that
+	// goes through every tenant in turn. It is the only method that looks
+	// past the tenant of ctx.
+	Tenants(ctx context.Context) ([]string, error)
 }
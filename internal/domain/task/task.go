@@ -1,7 +1,65 @@
 package task
 
+import "time"
+
+const (
+	StatusPending = 0
+	StatusDone    = 1
+)
+
 type Info struct {
-	ID     int    `json:"id"`
-	Name   string `json:"name"`
-	Status int    `json:"status"`
+	ID         int         `json:"id"`
+	Name       string      `json:"name"`
+	Status     int         `json:"status"`
+	ProjectID  int         `json:"project_id,omitempty"`
+	ParentID   *int        `json:"parent_id,omitempty"`
+	AssigneeID *int        `json:"assignee_id,omitempty"`
+	DueAt      *time.Time  `json:"due_at,omitempty"`
+	Recurrence *Recurrence `json:"recurrence,omitempty"`
+	Progress   *Progress   `json:"progress,omitempty"`
+	// Labels holds the names of the attached labels. Like Progress it is
+	// filled in on read; labels are attached through their own endpoints.
+	Labels []string `json:"labels,omitempty"`
+	// TenantID is the tenant owning the task. The repositories set it from
+	// the context, and it is never shown to clients.
+	TenantID string `json:"-"`
+}
+
+// Progress rolls up the completion of a task's direct children. It is
+// computed on read and never stored.
+type Progress struct {
+	Done  int `json:"done"`
+	Total int `json:"total"`
+}
+
+// Recurrence repeats a task on the dates of an RFC 5545 RRULE. The series is
+// carried from instance to instance: completing one creates the next, due
+// at the following occurrence, and leaves the completed one without it.
+type Recurrence struct {
+	// Rule is an RRULE such as "FREQ=WEEKLY;BYDAY=MO".
+	Rule string `json:"rule"`
+	// Timezone is the IANA time zone the rule is expanded in; dates keep
+	// their local time of day across DST changes. It defaults to UTC.
+	Timezone string `json:"timezone,omitempty"`
+	// Start is the due date of the first instance, which COUNT and
+	// INTERVAL count from. It is set when the recurrence is created.
+	Start time.Time `json:"start"`
+}
+
+// SearchResult is a task found by a full-text search. Snippet is the task
+// name as HTML with the matching words in <mark> tags.
+type SearchResult struct {
+	Task    Info    `json:"task"`
+	Score   float64 `json:"score"`
+	Snippet string  `json:"snippet"`
 }
+
+// DeleteMode decides what happens to the children of a deleted task.
+type DeleteMode string
+
+const (
+	// DeleteCascade deletes the whole subtree.
+	DeleteCascade DeleteMode = "cascade"
+	// DeleteReparent moves the children up to the deleted task's parent.
+	DeleteReparent DeleteMode = "reparent"
+)
@@ -0,0 +1,10 @@
+package task
+
+import "context"
+
+// Transactor runs fn so that the changes it makes through the task and audit
+// repositories with the context it is given are kept together or not at all.
+// Calls made within fn join the transaction fn runs in.
+type Transactor interface {
+	WithinTx(ctx context.Context, fn func(ctx context.Context) error) error
+}
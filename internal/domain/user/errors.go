@@ -0,0 +1,9 @@
+package user
+
+import "errors"
+
+var (
+	ErrNotFound          = errors.New("user not found")
+	ErrInvalidUser       = errors.New("invalid user")
+	ErrDuplicateUsername = errors.New("username already exists")
+)
@@ -0,0 +1,8 @@
+package user
+
+type Repository interface {
+	GetAll() ([]User, error)
+	GetByID(id int) (User, error)
+	GetByUsername(username string) (User, error)
+	Create(u User) (User, error)
+}
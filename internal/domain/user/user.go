@@ -0,0 +1,10 @@
+package user
+
+// User is someone tasks can be assigned to. Username is the subject of the
+// user's bearer token, which is how requests are tied to a user.
+type User struct {
+	ID       int    `json:"id"`
+	Username string `json:"username"`
+	Name     string `json:"name,omitempty"`
+	Email    string `json:"email,omitempty"`
+}
@@ -0,0 +1,19 @@
+package webhook
+
+import "time"
+
+type Repository interface {
+	GetAll() ([]Subscription, error)
+	GetByID(id int) (Subscription, error)
+	Create(subscription Subscription) (Subscription, error)
+	Update(subscription Subscription) (Subscription, error)
+	Delete(id int) error
+
+	CreateDelivery(delivery Delivery) (Delivery, error)
+	UpdateDelivery(delivery Delivery) (Delivery, error)
+	GetDelivery(id int) (Delivery, error)
+	ListDeliveries(subscriptionID int) ([]Delivery, error)
+	// ListDueDeliveries returns pending deliveries whose next attempt is due
+	// at or before the given time.
+	ListDueDeliveries(before time.Time) ([]Delivery, error)
+}
@@ -0,0 +1,65 @@
+package webhook
+
+import (
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+var (
+	ErrNotFound            = errors.New("webhook subscription not found")
+	ErrDeliveryNotFound    = errors.New("webhook delivery not found")
+	ErrInvalidSubscription = errors.New("invalid webhook subscription")
+)
+
+type DeliveryStatus string
+
+const (
+	DeliveryPending   DeliveryStatus = "pending"
+	DeliverySucceeded DeliveryStatus = "succeeded"
+	DeliveryFailed    DeliveryStatus = "failed"
+)
+
+type Subscription struct {
+	ID         int       `json:"id"`
+	URL        string    `json:"url"`
+	EventTypes []string  `json:"event_types"`
+	Secret     string    `json:"secret,omitempty"`
+	Active     bool      `json:"active"`
+	CreatedAt  time.Time `json:"created_at"`
+	// TenantID is the tenant whose task events the subscription receives.
+	// The service sets it from the context, and it is never shown to
+	// clients.
+	TenantID string `json:"-"`
+}
+
+// Accepts reports whether the subscription wants events of the given type.
+// A subscription without event types receives every event.
+func (s Subscription) Accepts(eventType string) bool {
+	if !s.Active {
+		return false
+	}
+	if len(s.EventTypes) == 0 {
+		return true
+	}
+	for _, t := range s.EventTypes {
+		if t == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+type Delivery struct {
+	ID             int             `json:"id"`
+	SubscriptionID int             `json:"subscription_id"`
+	EventID        uint64          `json:"event_id"`
+	EventType      string          `json:"event_type"`
+	Payload        json.RawMessage `json:"payload"`
+	Status         DeliveryStatus  `json:"status"`
+	Attempts       int             `json:"attempts"`
+	ResponseCode   int             `json:"response_code,omitempty"`
+	LastError      string          `json:"last_error,omitempty"`
+	NextAttemptAt  time.Time       `json:"next_attempt_at"`
+	CreatedAt      time.Time       `json:"created_at"`
+}
@@ -0,0 +1,176 @@
+// Package encryption seals values with AES-GCM under a data key of their
+// own, which is in turn sealed under a key of a keyring. The ID of that key
+// is kept next to each value, so that keys can be rotated: new values are
+// sealed under the current key, and values under older ones stay readable
+// until they are sealed again.
+package encryption
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+)
+
+var (
+	ErrInvalidKeyring = errors.New("invalid keyring")
+	// ErrUnknownKey is returned for values sealed under a key that is not
+	// in the keyring.
+	ErrUnknownKey = errors.New("unknown encryption key")
+	// ErrDecrypt is returned for values that were tampered with, or that
+	// are read back under other additional data than they were sealed with.
+	ErrDecrypt = errors.New("failed to decrypt value")
+)
+
+const (
+	// version starts every sealed value, so that the layout can change.
+	version     = 1
+	keySize     = 32
+	maxKeyIDLen = 64
+)
+
+// Keyring holds the AES-256 keys that seal data keys, by ID.
+type Keyring struct {
+	current string
+	keys    map[string]cipher.AEAD
+}
+
+// keyringFile is the layout of a keyring file:
+//
+//	{"current": "2024-05", "keys": {"2024-05": "<base64>", "2023-11": "<base64>"}}
+type keyringFile struct {
+	Current string            `json:"current"`
+	Keys    map[string]string `json:"keys"`
+}
+
+// LoadKeyring reads a keyring file, whose keys are 32 random bytes in
+// standard base64.
+func LoadKeyring(file string) (*Keyring, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	var f keyringFile
+	if err := json.Unmarshal(data, &f); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidKeyring, err)
+	}
+	keys := make(map[string][]byte, len(f.Keys))
+	for id, encoded := range f.Keys {
+		key, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			return nil, fmt.Errorf("%w: key %q is not base64", ErrInvalidKeyring, id)
+		}
+		keys[id] = key
+	}
+	return NewKeyring(f.Current, keys)
+}
+
+// NewKeyring seals new values under the key with ID current.
+func NewKeyring(current string, keys map[string][]byte) (*Keyring, error) {
+	k := &Keyring{current: current, keys: make(map[string]cipher.AEAD, len(keys))}
+	for id, key := range keys {
+		if id == "" || len(id) > maxKeyIDLen {
+			return nil, fmt.Errorf("%w: key IDs must have 1 to %d characters", ErrInvalidKeyring, maxKeyIDLen)
+		}
+		if len(key) != keySize {
+			return nil, fmt.Errorf("%w: key %q must be %d bytes", ErrInvalidKeyring, id, keySize)
+		}
+		aead, err := newAEAD(key)
+		if err != nil {
+			return nil, err
+		}
+		k.keys[id] = aead
+	}
+	if _, ok := k.keys[current]; !ok {
+		return nil, fmt.Errorf("%w: current key %q is not in the keyring", ErrInvalidKeyring, current)
+	}
+	return k, nil
+}
+
+// CurrentKeyID returns the ID of the key new values are sealed under.
+func (k *Keyring) CurrentKeyID() string {
+	return k.current
+}
+
+// Encrypt seals plaintext under a new data key, sealed under the current
+// key, and returns the ID of that key with the sealed value. The value only
+// opens with the same additional data, which binds it to where it is kept.
+func (k *Keyring) Encrypt(plaintext, additionalData []byte) (string, []byte, error) {
+	dataKey := make([]byte, keySize)
+	if _, err := rand.Read(dataKey); err != nil {
+		return "", nil, err
+	}
+	data, err := newAEAD(dataKey)
+	if err != nil {
+		return "", nil, err
+	}
+	// The data key is bound to the ID of the key sealing it.
+	sealed := []byte{version}
+	sealed, err = seal(k.keys[k.current], sealed, dataKey, []byte(k.current))
+	if err != nil {
+		return "", nil, err
+	}
+	sealed, err = seal(data, sealed, plaintext, additionalData)
+	if err != nil {
+		return "", nil, err
+	}
+	return k.current, sealed, nil
+}
+
+// Decrypt opens a value sealed by Encrypt under the key with ID keyID.
+func (k *Keyring) Decrypt(keyID string, sealed, additionalData []byte) ([]byte, error) {
+	master, ok := k.keys[keyID]
+	if !ok {
+		return nil, fmt.Errorf("%w %q", ErrUnknownKey, keyID)
+	}
+	if len(sealed) == 0 || sealed[0] != version {
+		return nil, ErrDecrypt
+	}
+	dataKey, rest, err := open(master, sealed[1:], keySize, []byte(keyID))
+	if err != nil {
+		return nil, err
+	}
+	data, err := newAEAD(dataKey)
+	if err != nil {
+		return nil, err
+	}
+	plaintext, _, err := open(data, rest, len(rest)-data.NonceSize()-data.Overhead(), additionalData)
+	return plaintext, err
+}
+
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// seal appends a random nonce and the sealed plaintext to dst.
+func seal(aead cipher.AEAD, dst, plaintext, additionalData []byte) ([]byte, error) {
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	dst = append(dst, nonce...)
+	return aead.Seal(dst, nonce, plaintext, additionalData), nil
+}
+
+// open opens the plaintext of size bytes sealed at the start of src and
+// returns it with what follows.
+func open(aead cipher.AEAD, src []byte, size int, additionalData []byte) ([]byte, []byte, error) {
+	n := aead.NonceSize() + size + aead.Overhead()
+	if size < 0 || len(src) < n {
+		return nil, nil, ErrDecrypt
+	}
+	nonce, ciphertext := src[:aead.NonceSize()], src[aead.NonceSize():n]
+	plaintext, err := aead.Open(nil, nonce, ciphertext, additionalData)
+	if err != nil {
+		return nil, nil, ErrDecrypt
+	}
+	return plaintext, src[n:], nil
+}
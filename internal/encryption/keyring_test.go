@@ -0,0 +1,173 @@
+package encryption
+
+import (
+	"bytes"
+	"encoding/base64"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func key(b byte) []byte {
+	return bytes.Repeat([]byte{b}, keySize)
+}
+
+func TestKeyring(t *testing.T) {
+	old, err := NewKeyring("2023-11", map[string][]byte{"2023-11": key(1)})
+	require.NoError(t, err)
+	rotated, err := NewKeyring("2024-05", map[string][]byte{"2023-11": key(1), "2024-05": key(2)})
+	require.NoError(t, err)
+
+	keyID, sealed, err := old.Encrypt([]byte("Call ACME about invoice 42"), []byte("tasks.name:acme"))
+	require.NoError(t, err)
+	assert.Equal(t, "2023-11", keyID)
+	assert.NotContains(t, string(sealed), "ACME")
+
+	// Values are sealed under data keys of their own.
+	_, again, err := old.Encrypt([]byte("Call ACME about invoice 42"), []byte("tasks.name:acme"))
+	require.NoError(t, err)
+	assert.NotEqual(t, sealed, again)
+
+	tampered := append([]byte{}, sealed...)
+	tampered[len(tampered)-1] ^= 1
+
+	type testCase struct {
+		TestCase       string
+		Keyring        *Keyring
+		KeyID          string
+		Sealed         []byte
+		AdditionalData string
+		Expected       string
+		Error          error
+	}
+
+	testCases := []testCase{
+		{
+			TestCase:       "opens with the same key",
+			Keyring:        old,
+			KeyID:          keyID,
+			Sealed:         sealed,
+			AdditionalData: "tasks.name:acme",
+			Expected:       "Call ACME about invoice 42",
+		},
+		{
+			TestCase:       "older keys stay readable after a rotation",
+			Keyring:        rotated,
+			KeyID:          keyID,
+			Sealed:         sealed,
+			AdditionalData: "tasks.name:acme",
+			Expected:       "Call ACME about invoice 42",
+		},
+		{
+			TestCase:       "other additional data",
+			Keyring:        old,
+			KeyID:          keyID,
+			Sealed:         sealed,
+			AdditionalData: "tasks.name:globex",
+			Error:          ErrDecrypt,
+		},
+		{
+			TestCase:       "other key ID",
+			Keyring:        rotated,
+			KeyID:          "2024-05",
+			Sealed:         sealed,
+			AdditionalData: "tasks.name:acme",
+			Error:          ErrDecrypt,
+		},
+		{
+			TestCase:       "unknown key",
+			Keyring:        old,
+			KeyID:          "2024-05",
+			Sealed:         sealed,
+			AdditionalData: "tasks.name:acme",
+			Error:          ErrUnknownKey,
+		},
+		{
+			TestCase:       "tampered value",
+			Keyring:        old,
+			KeyID:          keyID,
+			Sealed:         tampered,
+			AdditionalData: "tasks.name:acme",
+			Error:          ErrDecrypt,
+		},
+		{
+			TestCase:       "truncated value",
+			Keyring:        old,
+			KeyID:          keyID,
+			Sealed:         sealed[:20],
+			AdditionalData: "tasks.name:acme",
+			Error:          ErrDecrypt,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.TestCase, func(t *testing.T) {
+			plaintext, err := tc.Keyring.Decrypt(tc.KeyID, tc.Sealed, []byte(tc.AdditionalData))
+			if tc.Error != nil {
+				assert.ErrorIs(t, err, tc.Error)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.Expected, string(plaintext))
+		})
+	}
+
+	keyID, _, err = rotated.Encrypt([]byte("Report"), nil)
+	require.NoError(t, err)
+	assert.Equal(t, "2024-05", keyID)
+	assert.Equal(t, "2024-05", rotated.CurrentKeyID())
+}
+
+func TestLoadKeyring(t *testing.T) {
+	encoded := base64.StdEncoding.EncodeToString(key(1))
+
+	type testCase struct {
+		TestCase string
+		Content  string
+		Error    bool
+	}
+
+	testCases := []testCase{
+		{
+			TestCase: "valid keyring",
+			Content:  `{"current": "k1", "keys": {"k1": "` + encoded + `"}}`,
+		},
+		{
+			TestCase: "current key missing",
+			Content:  `{"current": "k2", "keys": {"k1": "` + encoded + `"}}`,
+			Error:    true,
+		},
+		{
+			TestCase: "short key",
+			Content:  `{"current": "k1", "keys": {"k1": "c2hvcnQ="}}`,
+			Error:    true,
+		},
+		{
+			TestCase: "key not base64",
+			Content:  `{"current": "k1", "keys": {"k1": "not base64!"}}`,
+			Error:    true,
+		},
+		{
+			TestCase: "not JSON",
+			Content:  `current = k1`,
+			Error:    true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.TestCase, func(t *testing.T) {
+			file := filepath.Join(t.TempDir(), "keyring.json")
+			require.NoError(t, os.WriteFile(file, []byte(tc.Content), 0o600))
+			k, err := LoadKeyring(file)
+			if tc.Error {
+				assert.ErrorIs(t, err, ErrInvalidKeyring)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, "k1", k.CurrentKeyID())
+		})
+	}
+}
@@ -0,0 +1,143 @@
+package events
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"task-api/internal/domain/task"
+)
+
+const subscriberBuffer = 64
+
+var (
+	// ErrSlowConsumer ends subscriptions that could not keep up.
+	ErrSlowConsumer = errors.New("subscriber could not keep up")
+	// ErrClosed ends subscriptions when the broker is closed.
+	ErrClosed = errors.New("broker closed")
+)
+
+// Broker fans task events out to live subscribers and keeps the most recent
+// ones in a bounded ring buffer so reconnecting clients can resume.
+type Broker struct {
+	mu          sync.Mutex
+	buffer      []Event
+	start       int
+	lastID      uint64
+	subscribers map[*Subscription]struct{}
+	closed      bool
+}
+
+type Subscription struct {
+	C <-chan Event
+	// Missed tells that some of the events published after the
+	// lastEventID given to Subscribe are gone from the buffer, or were
+	// published before a restart, so the backlog does not hold them all.
+	Missed bool
+
+	ch     chan Event
+	broker *Broker
+	once   sync.Once
+	err    error
+}
+
+func NewBroker(bufferSize int) *Broker {
+	if bufferSize <= 0 {
+		bufferSize = 1
+	}
+	return &Broker{
+		buffer:      make([]Event, 0, bufferSize),
+		subscribers: make(map[*Subscription]struct{}),
+	}
+}
+
+func NewPublisher(b *Broker) Publisher {
+	return b
+}
+
+func (b *Broker) Publish(eventType Type, t task.Info) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.lastID++
+	event := Event{ID: b.lastID, Type: eventType, Task: t, OccurredAt: time.Now().UTC()}
+
+	if len(b.buffer) < cap(b.buffer) {
+		b.buffer = append(b.buffer, event)
+	} else {
+		b.buffer[b.start] = event
+		b.start = (b.start + 1) % len(b.buffer)
+	}
+
+	for sub := range b.subscribers {
+		select {
+		case sub.ch <- event:
+		default:
+			// The subscriber is not keeping up; drop it so it can resume
+			// from the buffer instead of silently missing events.
+			b.remove(sub, ErrSlowConsumer)
+		}
+	}
+}
+
+// Subscribe registers a live subscription and returns the buffered events
+// published after lastEventID. A lastEventID of zero skips the replay.
+func (b *Broker) Subscribe(lastEventID uint64) ([]Event, *Subscription) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	var backlog []Event
+	missed := lastEventID > b.lastID ||
+		lastEventID > 0 && len(b.buffer) > 0 && b.buffer[b.start].ID > lastEventID+1
+	if lastEventID > 0 && lastEventID < b.lastID {
+		for i := 0; i < len(b.buffer); i++ {
+			event := b.buffer[(b.start+i)%len(b.buffer)]
+			if event.ID > lastEventID {
+				backlog = append(backlog, event)
+			}
+		}
+	}
+
+	ch := make(chan Event, subscriberBuffer)
+	sub := &Subscription{C: ch, Missed: missed, ch: ch, broker: b}
+	b.subscribers[sub] = struct{}{}
+	if b.closed {
+		b.remove(sub, ErrClosed)
+	}
+	return backlog, sub
+}
+
+// Close ends every subscription, and those made later at once, so that the
+// handlers streaming them return when the server shuts down.
+func (b *Broker) Close() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.closed = true
+	for sub := range b.subscribers {
+		b.remove(sub, ErrClosed)
+	}
+}
+
+func (s *Subscription) Close() {
+	s.broker.mu.Lock()
+	defer s.broker.mu.Unlock()
+	s.broker.remove(s, nil)
+}
+
+// Err tells why C was closed by the broker: ErrSlowConsumer or ErrClosed.
+// It is nil while C is open and after Close.
+func (s *Subscription) Err() error {
+	s.broker.mu.Lock()
+	defer s.broker.mu.Unlock()
+	return s.err
+}
+
+// remove must be called with the broker lock held.
+func (b *Broker) remove(sub *Subscription, err error) {
+	sub.once.Do(func() {
+		sub.err = err
+		delete(b.subscribers, sub)
+		close(sub.ch)
+	})
+}
@@ -0,0 +1,138 @@
+package events
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"task-api/internal/domain/task"
+)
+
+func eventIDs(events []Event) []uint64 {
+	var ids []uint64
+	for _, e := range events {
+		ids = append(ids, e.ID)
+	}
+	return ids
+}
+
+func TestBroker_Subscribe(t *testing.T) {
+	tests := []struct {
+		TestCase    string
+		BufferSize  int
+		Published   int
+		LastEventID uint64
+		Expected    []uint64
+		Missed      bool
+	}{
+		{
+			TestCase:    "No replay without Last-Event-ID",
+			BufferSize:  4,
+			Published:   3,
+			LastEventID: 0,
+			Expected:    nil,
+		},
+		{
+			TestCase:    "Replay events after Last-Event-ID",
+			BufferSize:  4,
+			Published:   3,
+			LastEventID: 1,
+			Expected:    []uint64{2, 3},
+		},
+		{
+			TestCase:    "Replay is bounded by the buffer",
+			BufferSize:  2,
+			Published:   5,
+			LastEventID: 1,
+			Expected:    []uint64{4, 5},
+			Missed:      true,
+		},
+		{
+			TestCase:    "Nothing missed right before the buffer",
+			BufferSize:  2,
+			Published:   5,
+			LastEventID: 3,
+			Expected:    []uint64{4, 5},
+		},
+		{
+			TestCase:    "Last-Event-ID from before a restart",
+			BufferSize:  4,
+			Published:   3,
+			LastEventID: 7,
+			Expected:    nil,
+			Missed:      true,
+		},
+		{
+			TestCase:    "Up to date client gets nothing",
+			BufferSize:  4,
+			Published:   3,
+			LastEventID: 3,
+			Expected:    nil,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.TestCase, func(t *testing.T) {
+			broker := NewBroker(tc.BufferSize)
+			for i := 0; i < tc.Published; i++ {
+				broker.Publish(TaskCreated, task.Info{ID: i + 1})
+			}
+
+			backlog, sub := broker.Subscribe(tc.LastEventID)
+			defer sub.Close()
+			assert.Equal(t, tc.Expected, eventIDs(backlog))
+			assert.Equal(t, tc.Missed, sub.Missed)
+		})
+	}
+}
+
+func TestBroker_Publish(t *testing.T) {
+	broker := NewBroker(4)
+	_, sub := broker.Subscribe(0)
+
+	broker.Publish(TaskUpdated, task.Info{ID: 1, Name: "Task", Status: 1})
+	event := <-sub.C
+	assert.Equal(t, uint64(1), event.ID)
+	assert.Equal(t, TaskUpdated, event.Type)
+	assert.Equal(t, task.Info{ID: 1, Name: "Task", Status: 1}, event.Task)
+
+	sub.Close()
+	_, ok := <-sub.C
+	assert.False(t, ok)
+	sub.Close()
+}
+
+func TestBroker_DropsSlowSubscriber(t *testing.T) {
+	broker := NewBroker(4)
+	_, sub := broker.Subscribe(0)
+
+	for i := 0; i <= subscriberBuffer; i++ {
+		broker.Publish(TaskCreated, task.Info{ID: i + 1})
+	}
+
+	received := 0
+	for range sub.C {
+		received++
+	}
+	assert.Equal(t, subscriberBuffer, received)
+	assert.ErrorIs(t, sub.Err(), ErrSlowConsumer)
+}
+
+func TestBroker_Close(t *testing.T) {
+	broker := NewBroker(4)
+	_, open := broker.Subscribe(0)
+	_, closed := broker.Subscribe(0)
+	closed.Close()
+
+	broker.Close()
+	_, ok := <-open.C
+	assert.False(t, ok)
+	assert.ErrorIs(t, open.Err(), ErrClosed)
+	assert.NoError(t, closed.Err())
+
+	// Later subscriptions end at once.
+	_, late := broker.Subscribe(0)
+	_, ok = <-late.C
+	assert.False(t, ok)
+	assert.ErrorIs(t, late.Err(), ErrClosed)
+}
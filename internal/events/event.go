@@ -0,0 +1,26 @@
+package events
+
+import (
+	"time"
+
+	"task-api/internal/domain/task"
+)
+
+type Type string
+
+const (
+	TaskCreated Type = "task.created"
+	TaskUpdated Type = "task.updated"
+	TaskDeleted Type = "task.deleted"
+)
+
+type Event struct {
+	ID         uint64    `json:"id"`
+	Type       Type      `json:"type"`
+	Task       task.Info `json:"task"`
+	OccurredAt time.Time `json:"occurred_at"`
+}
+
+type Publisher interface {
+	Publish(eventType Type, t task.Info)
+}
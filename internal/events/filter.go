@@ -0,0 +1,93 @@
+package events
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"task-api/internal/domain/task"
+)
+
+// statusNames are the statuses a task can have; there is no in-between
+// status such as in_progress.
+var statusNames = map[string]int{
+	"pending": task.StatusPending,
+	"done":    task.StatusDone,
+}
+
+// Filter selects the events a subscriber is interested in. Empty fields
+// match everything.
+type Filter struct {
+	Types  []Type
+	TaskID *int
+	Status *int
+	// Tenant is set by the server from the request rather than parsed, so
+	// subscribers only ever see the events of their own tenant.
+	Tenant string
+}
+
+// ParseFilter parses expressions such as "status=done&type=task.updated".
+// Conditions are separated by "&" or "," and must all match; a key may be
+// repeated to accept several event types. A status is pending or done, by
+// name or numeric value, and any other is refused.
+func ParseFilter(expr string) (Filter, error) {
+	var f Filter
+	for _, cond := range strings.FieldsFunc(expr, func(r rune) bool { return r == '&' || r == ',' }) {
+		key, value, ok := strings.Cut(strings.TrimSpace(cond), "=")
+		if !ok || value == "" {
+			return Filter{}, fmt.Errorf("invalid filter condition %q", cond)
+		}
+		switch key {
+		case "type":
+			eventType := Type(value)
+			if eventType != TaskCreated && eventType != TaskUpdated && eventType != TaskDeleted {
+				return Filter{}, fmt.Errorf("unknown event type %q", value)
+			}
+			f.Types = append(f.Types, eventType)
+		case "id":
+			id, err := strconv.Atoi(value)
+			if err != nil {
+				return Filter{}, fmt.Errorf("invalid task id %q", value)
+			}
+			f.TaskID = &id
+		case "status":
+			status, ok := statusNames[value]
+			if !ok {
+				n, err := strconv.Atoi(value)
+				if err != nil || n != task.StatusPending && n != task.StatusDone {
+					return Filter{}, fmt.Errorf("invalid status %q: must be pending or done", value)
+				}
+				status = n
+			}
+			f.Status = &status
+		default:
+			return Filter{}, fmt.Errorf("unknown filter field %q", key)
+		}
+	}
+	return f, nil
+}
+
+func (f Filter) Matches(e Event) bool {
+	if len(f.Types) > 0 {
+		matched := false
+		for _, t := range f.Types {
+			if t == e.Type {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+	if f.TaskID != nil && *f.TaskID != e.Task.ID {
+		return false
+	}
+	if f.Status != nil && *f.Status != e.Task.Status {
+		return false
+	}
+	if f.Tenant != "" && f.Tenant != e.Task.TenantID {
+		return false
+	}
+	return true
+}
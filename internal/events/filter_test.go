@@ -0,0 +1,99 @@
+package events
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"task-api/internal/domain/task"
+)
+
+func TestParseFilter(t *testing.T) {
+	tests := []struct {
+		TestCase string
+		Expr     string
+		Matches  []Event
+		Rejects  []Event
+		Error    string
+	}{
+		{
+			TestCase: "Empty filter matches everything",
+			Expr:     "",
+			Matches: []Event{
+				{Type: TaskCreated, Task: task.Info{ID: 1}},
+				{Type: TaskDeleted, Task: task.Info{ID: 2, Status: task.StatusDone}},
+			},
+		},
+		{
+			TestCase: "Status by name",
+			Expr:     "status=done",
+			Matches:  []Event{{Type: TaskUpdated, Task: task.Info{ID: 1, Status: task.StatusDone}}},
+			Rejects:  []Event{{Type: TaskUpdated, Task: task.Info{ID: 1, Status: task.StatusPending}}},
+		},
+		{
+			TestCase: "Combined conditions",
+			Expr:     "status=0&type=task.created,type=task.deleted",
+			Matches: []Event{
+				{Type: TaskCreated, Task: task.Info{ID: 1}},
+				{Type: TaskDeleted, Task: task.Info{ID: 2}},
+			},
+			Rejects: []Event{
+				{Type: TaskUpdated, Task: task.Info{ID: 1}},
+				{Type: TaskCreated, Task: task.Info{ID: 1, Status: task.StatusDone}},
+			},
+		},
+		{
+			TestCase: "Single task",
+			Expr:     "id=7",
+			Matches:  []Event{{Type: TaskUpdated, Task: task.Info{ID: 7}}},
+			Rejects:  []Event{{Type: TaskUpdated, Task: task.Info{ID: 8}}},
+		},
+		{
+			TestCase: "Unknown field",
+			Expr:     "owner=me",
+			Error:    `unknown filter field "owner"`,
+		},
+		{
+			TestCase: "The tenant cannot be chosen",
+			Expr:     "tenant=acme",
+			Error:    `unknown filter field "tenant"`,
+		},
+		{
+			TestCase: "Invalid status",
+			Expr:     "status=later",
+			Error:    `invalid status "later": must be pending or done`,
+		},
+		{
+			TestCase: "There is no in-progress status",
+			Expr:     "status=in_progress",
+			Error:    `invalid status "in_progress": must be pending or done`,
+		},
+		{
+			TestCase: "Unknown numeric status",
+			Expr:     "status=2",
+			Error:    `invalid status "2": must be pending or done`,
+		},
+		{
+			TestCase: "Missing value",
+			Expr:     "status",
+			Error:    `invalid filter condition "status"`,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.TestCase, func(t *testing.T) {
+			filter, err := ParseFilter(tc.Expr)
+			if tc.Error != "" {
+				assert.EqualError(t, err, tc.Error)
+				return
+			}
+			assert.NoError(t, err)
+			for _, e := range tc.Matches {
+				assert.True(t, filter.Matches(e), "expected %+v to match", e)
+			}
+			for _, e := range tc.Rejects {
+				assert.False(t, filter.Matches(e), "expected %+v not to match", e)
+			}
+		})
+	}
+}
@@ -0,0 +1,136 @@
+package graph
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/graphql-go/graphql/language/ast"
+	"github.com/graphql-go/graphql/language/parser"
+)
+
+type Limits struct {
+	MaxDepth      int
+	MaxComplexity int
+}
+
+// Check parses the query and rejects it before execution if any operation
+// nests deeper than MaxDepth or costs more than MaxComplexity. Every field
+// costs one point and list fields multiply the cost of their selection by
+// the requested page size.
+func (l Limits) Check(query string, variables map[string]interface{}) error {
+	doc, err := parser.Parse(parser.ParseParams{Source: query})
+	if err != nil {
+		// Syntax errors are reported by the executor with proper locations.
+		return nil
+	}
+
+	fragments := make(map[string]*ast.FragmentDefinition)
+	for _, def := range doc.Definitions {
+		if fragment, ok := def.(*ast.FragmentDefinition); ok {
+			fragments[fragment.Name.Value] = fragment
+		}
+	}
+
+	w := walker{fragments: fragments, variables: variables}
+	for _, def := range doc.Definitions {
+		op, ok := def.(*ast.OperationDefinition)
+		if !ok {
+			continue
+		}
+		depth, complexity := w.selectionSet(op.SelectionSet, map[string]bool{})
+		if l.MaxDepth > 0 && depth > l.MaxDepth {
+			return fmt.Errorf("query depth %d exceeds the limit of %d", depth, l.MaxDepth)
+		}
+		if l.MaxComplexity > 0 && complexity > l.MaxComplexity {
+			return fmt.Errorf("query complexity %d exceeds the limit of %d", complexity, l.MaxComplexity)
+		}
+	}
+	return nil
+}
+
+type walker struct {
+	fragments map[string]*ast.FragmentDefinition
+	variables map[string]interface{}
+}
+
+func (w walker) selectionSet(set *ast.SelectionSet, visiting map[string]bool) (int, int) {
+	if set == nil {
+		return 0, 0
+	}
+
+	maxDepth, complexity := 0, 0
+	for _, selection := range set.Selections {
+		var depth, cost int
+		switch s := selection.(type) {
+		case *ast.Field:
+			depth, cost = w.field(s, visiting)
+		case *ast.InlineFragment:
+			depth, cost = w.selectionSet(s.SelectionSet, visiting)
+		case *ast.FragmentSpread:
+			name := s.Name.Value
+			fragment, ok := w.fragments[name]
+			if !ok || visiting[name] {
+				// Unknown and cyclic fragments are rejected by validation.
+				continue
+			}
+			visiting[name] = true
+			depth, cost = w.selectionSet(fragment.SelectionSet, visiting)
+			delete(visiting, name)
+		}
+		if depth > maxDepth {
+			maxDepth = depth
+		}
+		complexity += cost
+	}
+	return maxDepth, complexity
+}
+
+func (w walker) field(field *ast.Field, visiting map[string]bool) (int, int) {
+	if field.SelectionSet == nil {
+		return 1, 1
+	}
+	depth, cost := w.selectionSet(field.SelectionSet, visiting)
+	return depth + 1, 1 + cost*w.multiplier(field)
+}
+
+// multiplier estimates how many items a field returns from its "limit"
+// argument, the only way this schema exposes lists.
+func (w walker) multiplier(field *ast.Field) int {
+	for _, arg := range field.Arguments {
+		if arg.Name.Value != "limit" {
+			continue
+		}
+		switch v := arg.Value.(type) {
+		case *ast.IntValue:
+			if n, err := strconv.Atoi(v.Value); err == nil {
+				return clampPageSize(n)
+			}
+		case *ast.Variable:
+			if n, ok := toInt(w.variables[v.Name.Value]); ok {
+				return clampPageSize(n)
+			}
+		}
+		return MaxPageSize
+	}
+	if field.Name.Value == "tasks" {
+		return DefaultPageSize
+	}
+	return 1
+}
+
+func clampPageSize(n int) int {
+	if n <= 0 || n > MaxPageSize {
+		return MaxPageSize
+	}
+	return n
+}
+
+func toInt(value interface{}) (int, bool) {
+	switch n := value.(type) {
+	case int:
+		return n, true
+	case float64:
+		return int(n), true
+	}
+	return 0, false
+}
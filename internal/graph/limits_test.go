@@ -0,0 +1,58 @@
+package graph
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLimits_Check(t *testing.T) {
+	limits := Limits{MaxDepth: 3, MaxComplexity: 50}
+
+	tests := []struct {
+		TestCase  string
+		Query     string
+		Variables map[string]interface{}
+		Error     string
+	}{
+		{
+			TestCase: "Single task within limits",
+			Query:    `{ task(id: 1) { id name status } }`,
+		},
+		{
+			TestCase: "Small page within limits",
+			Query:    `{ tasks(limit: 5) { total items { id name } } }`,
+		},
+		{
+			TestCase: "Default page size exceeds complexity",
+			Query:    `{ tasks { items { id name status } } }`,
+			Error:    "query complexity 81 exceeds the limit of 50",
+		},
+		{
+			TestCase:  "Page size from variables",
+			Query:     `query Q($n: Int) { tasks(limit: $n) { items { id name status } } }`,
+			Variables: map[string]interface{}{"n": float64(30)},
+			Error:     "query complexity 121 exceeds the limit of 50",
+		},
+		{
+			TestCase: "Depth through fragments",
+			Query:    `{ tasks(limit: 1) { ...Page } } fragment Page on TaskPage { items { ... on Task { id } } }`,
+		},
+		{
+			TestCase: "Depth exceeded",
+			Query:    `{ a { b { c { d } } } }`,
+			Error:    "query depth 4 exceeds the limit of 3",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.TestCase, func(t *testing.T) {
+			err := limits.Check(tc.Query, tc.Variables)
+			if tc.Error != "" {
+				assert.EqualError(t, err, tc.Error)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
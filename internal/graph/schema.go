@@ -0,0 +1,192 @@
+package graph
+
+import (
+	"sort"
+
+	"github.com/graphql-go/graphql"
+
+	"task-api/internal/domain/task"
+	taskService "task-api/internal/services/task"
+)
+
+const (
+	DefaultPageSize = 20
+	MaxPageSize     = 100
+)
+
+var taskType = graphql.NewObject(graphql.ObjectConfig{
+	Name: "Task",
+	Fields: graphql.Fields{
+		"id":     &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+		"name":   &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+		"status": &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+	},
+})
+
+var taskPageType = graphql.NewObject(graphql.ObjectConfig{
+	Name: "TaskPage",
+	Fields: graphql.Fields{
+		"items":  &graphql.Field{Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(taskType)))},
+		"total":  &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+		"offset": &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+		"limit":  &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+	},
+})
+
+var taskInputType = graphql.NewInputObject(graphql.InputObjectConfig{
+	Name: "TaskInput",
+	Fields: graphql.InputObjectConfigFieldMap{
+		"name": &graphql.InputObjectFieldConfig{Type: graphql.NewNonNull(graphql.String)},
+		// status has no default, so that updates leaving it out keep it.
+		"status": &graphql.InputObjectFieldConfig{Type: graphql.Int},
+	},
+})
+
+type TaskPage struct {
+	Items  []task.Info `json:"items"`
+	Total  int         `json:"total"`
+	Offset int         `json:"offset"`
+	Limit  int         `json:"limit"`
+}
+
+type resolver struct {
+	service taskService.Service
+}
+
+func NewSchema(service taskService.Service) (graphql.Schema, error) {
+	r := &resolver{service: service}
+
+	query := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Query",
+		Fields: graphql.Fields{
+			"task": &graphql.Field{
+				Type: taskType,
+				Args: graphql.FieldConfigArgument{
+					"id": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.Int)},
+				},
+				Resolve: r.task,
+			},
+			"tasks": &graphql.Field{
+				Type: graphql.NewNonNull(taskPageType),
+				Args: graphql.FieldConfigArgument{
+					"offset": &graphql.ArgumentConfig{Type: graphql.Int, DefaultValue: 0},
+					"limit":  &graphql.ArgumentConfig{Type: graphql.Int, DefaultValue: DefaultPageSize},
+					"status": &graphql.ArgumentConfig{Type: graphql.Int},
+				},
+				Resolve: r.tasks,
+			},
+		},
+	})
+
+	mutation := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Mutation",
+		Fields: graphql.Fields{
+			"createTask": &graphql.Field{
+				Type: graphql.NewNonNull(taskType),
+				Args: graphql.FieldConfigArgument{
+					"input": &graphql.ArgumentConfig{Type: graphql.NewNonNull(taskInputType)},
+				},
+				Resolve: r.createTask,
+			},
+			"updateTask": &graphql.Field{
+				Type: graphql.NewNonNull(taskType),
+				Args: graphql.FieldConfigArgument{
+					"id":    &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.Int)},
+					"input": &graphql.ArgumentConfig{Type: graphql.NewNonNull(taskInputType)},
+				},
+				Resolve: r.updateTask,
+			},
+			"deleteTask": &graphql.Field{
+				Type: graphql.NewNonNull(graphql.Boolean),
+				Args: graphql.FieldConfigArgument{
+					"id": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.Int)},
+				},
+				Resolve: r.deleteTask,
+			},
+		},
+	})
+
+	return graphql.NewSchema(graphql.SchemaConfig{
+		Query:    query,
+		Mutation: mutation,
+	})
+}
+
+func (r *resolver) task(p graphql.ResolveParams) (interface{}, error) {
+	return r.service.GetTaskByID(p.Context, p.Args["id"].(int))
+}
+
+func (r *resolver) tasks(p graphql.ResolveParams) (interface{}, error) {
+	offset := p.Args["offset"].(int)
+	limit := p.Args["limit"].(int)
+	if offset < 0 {
+		offset = 0
+	}
+	if limit <= 0 || limit > MaxPageSize {
+		limit = MaxPageSize
+	}
+
+	tasks, err := r.service.GetAllTasks(p.Context)
+	if err != nil {
+		return nil, err
+	}
+
+	filtered := make([]task.Info, 0, len(tasks))
+	status, hasStatus := p.Args["status"].(int)
+	for _, t := range tasks {
+		if hasStatus && t.Status != status {
+			continue
+		}
+		filtered = append(filtered, t)
+	}
+	sort.Slice(filtered, func(i, j int) bool { return filtered[i].ID < filtered[j].ID })
+
+	page := TaskPage{Items: []task.Info{}, Total: len(filtered), Offset: offset, Limit: limit}
+	if offset < len(filtered) {
+		end := offset + limit
+		if end > len(filtered) {
+			end = len(filtered)
+		}
+		page.Items = filtered[offset:end]
+	}
+	return page, nil
+}
+
+func (r *resolver) createTask(p graphql.ResolveParams) (interface{}, error) {
+	return r.service.CreateTask(p.Context, taskFromInput(p.Args["input"]))
+}
+
+// updateTask only changes the fields of the input, as UpdateTask replaces the
+// whole task: the project, parent, assignee, due date and recurrence of the
+// task stay as they are.
+func (r *resolver) updateTask(p graphql.ResolveParams) (interface{}, error) {
+	t, err := r.service.GetTaskByID(p.Context, p.Args["id"].(int))
+	if err != nil {
+		return nil, err
+	}
+	fields, _ := p.Args["input"].(map[string]interface{})
+	if name, ok := fields["name"].(string); ok {
+		t.Name = name
+	}
+	if status, ok := fields["status"].(int); ok {
+		t.Status = status
+	}
+	return r.service.UpdateTask(p.Context, t)
+}
+
+func (r *resolver) deleteTask(p graphql.ResolveParams) (interface{}, error) {
+	if err := r.service.DeleteTask(p.Context, p.Args["id"].(int)); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// taskFromInput makes a new task of the input, pending unless it says
+// otherwise.
+func taskFromInput(input interface{}) task.Info {
+	fields, _ := input.(map[string]interface{})
+	var t task.Info
+	t.Name, _ = fields["name"].(string)
+	t.Status, _ = fields["status"].(int)
+	return t
+}
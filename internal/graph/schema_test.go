@@ -0,0 +1,116 @@
+package graph
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/graphql-go/graphql"
+	"github.com/stretchr/testify/assert"
+
+	"task-api/internal/domain/task"
+	"task-api/internal/mocks"
+)
+
+func TestSchema(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	dueAt := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+
+	mockService := mocks.NewMockService(ctrl)
+	schema, err := NewSchema(mockService)
+	assert.NoError(t, err)
+
+	tests := []struct {
+		TestCase string
+		Query    string
+		Expected map[string]interface{}
+		Setup    func()
+	}{
+		{
+			TestCase: "Query task by ID",
+			Query:    `{ task(id: 1) { id name } }`,
+			Expected: map[string]interface{}{
+				"task": map[string]interface{}{"id": 1, "name": "Test Task"},
+			},
+			Setup: func() {
+				mockService.EXPECT().GetTaskByID(gomock.Any(), 1).Return(task.Info{ID: 1, Name: "Test Task", Status: 0}, nil)
+			},
+		},
+		{
+			TestCase: "Query filtered page",
+			Query:    `{ tasks(offset: 1, limit: 1, status: 0) { total items { id } } }`,
+			Expected: map[string]interface{}{
+				"tasks": map[string]interface{}{
+					"total": 2,
+					"items": []interface{}{map[string]interface{}{"id": 3}},
+				},
+			},
+			Setup: func() {
+				mockService.EXPECT().GetAllTasks(gomock.Any()).Return([]task.Info{
+					{ID: 3, Name: "Test Task 3", Status: 0},
+					{ID: 2, Name: "Test Task 2", Status: 1},
+					{ID: 1, Name: "Test Task 1", Status: 0},
+				}, nil)
+			},
+		},
+		{
+			TestCase: "Create task",
+			Query:    `mutation { createTask(input: {name: "New Task"}) { id status } }`,
+			Expected: map[string]interface{}{
+				"createTask": map[string]interface{}{"id": 1, "status": 0},
+			},
+			Setup: func() {
+				mockService.EXPECT().CreateTask(gomock.Any(), task.Info{Name: "New Task", Status: 0}).Return(task.Info{ID: 1, Name: "New Task", Status: 0}, nil)
+			},
+		},
+		{
+			TestCase: "Update task",
+			Query:    `mutation { updateTask(id: 1, input: {name: "Updated Task", status: 1}) { status } }`,
+			Expected: map[string]interface{}{
+				"updateTask": map[string]interface{}{"status": 1},
+			},
+			Setup: func() {
+				mockService.EXPECT().GetTaskByID(gomock.Any(), 1).Return(task.Info{ID: 1, Name: "Test Task", Status: 0}, nil)
+				mockService.EXPECT().UpdateTask(gomock.Any(), task.Info{ID: 1, Name: "Updated Task", Status: 1}).Return(task.Info{ID: 1, Name: "Updated Task", Status: 1}, nil)
+			},
+		},
+		{
+			TestCase: "Update task name keeps the other fields",
+			Query:    `mutation { updateTask(id: 1, input: {name: "Renamed"}) { name status } }`,
+			Expected: map[string]interface{}{
+				"updateTask": map[string]interface{}{"name": "Renamed", "status": 1},
+			},
+			Setup: func() {
+				existing := task.Info{ID: 1, Name: "Test Task", Status: 1, ProjectID: 2, ParentID: intPtr(3), AssigneeID: intPtr(4),
+					DueAt: &dueAt, Recurrence: &task.Recurrence{Rule: "FREQ=WEEKLY", Timezone: "UTC", Start: dueAt}}
+				renamed := existing
+				renamed.Name = "Renamed"
+				mockService.EXPECT().GetTaskByID(gomock.Any(), 1).Return(existing, nil)
+				mockService.EXPECT().UpdateTask(gomock.Any(), renamed).Return(renamed, nil)
+			},
+		},
+		{
+			TestCase: "Delete task",
+			Query:    `mutation { deleteTask(id: 1) }`,
+			Expected: map[string]interface{}{"deleteTask": true},
+			Setup: func() {
+				mockService.EXPECT().DeleteTask(gomock.Any(), 1).Return(nil)
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.TestCase, func(t *testing.T) {
+			tc.Setup()
+			result := graphql.Do(graphql.Params{Schema: schema, RequestString: tc.Query})
+			assert.Empty(t, result.Errors)
+			assert.Equal(t, tc.Expected, result.Data)
+		})
+	}
+}
+
+func intPtr(v int) *int {
+	return &v
+}
@@ -0,0 +1,91 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"task-api/internal/auth"
+	"task-api/internal/config"
+	"task-api/internal/domain/apikey"
+	apikeyService "task-api/internal/services/apikey"
+)
+
+type APIKeyHandler struct {
+	Service apikeyService.Service
+	// Auth and Admin guard every route: only admin users manage keys.
+	Auth  auth.Middleware
+	Admin gin.HandlerFunc
+}
+
+type apiKeyRequest struct {
+	Name   string   `json:"name" binding:"required"`
+	Scopes []string `json:"scopes"`
+}
+
+func NewAPIKeyHandler(service apikeyService.Service, authMiddleware auth.Middleware, cfg config.AuthConfig) *APIKeyHandler {
+	return &APIKeyHandler{Service: service, Auth: authMiddleware, Admin: auth.RequireAdmin(cfg)}
+}
+
+func (h *APIKeyHandler) RegisterRoutes(router *gin.Engine) {
+	keys := router.Group("/admin/api-keys", gin.HandlerFunc(h.Auth), h.Admin)
+	keys.GET("", h.GetKeys)
+	keys.POST("", h.IssueKey)
+	keys.DELETE("/:id", h.RevokeKey)
+}
+
+func (h *APIKeyHandler) GetKeys(c *gin.Context) {
+	keys, err := h.Service.ListKeys(c.Request.Context())
+	if err != nil {
+		apiKeyError(c, err)
+		return
+	}
+	if keys == nil {
+		keys = []apikey.Key{}
+	}
+	c.JSON(http.StatusOK, keys)
+}
+
+// IssueKey is the only response that includes the secret of a key.
+func (h *APIKeyHandler) IssueKey(c *gin.Context) {
+	var req apiKeyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	issued, err := h.Service.IssueKey(c.Request.Context(), req.Name, req.Scopes)
+	if err != nil {
+		apiKeyError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, issued)
+}
+
+// RevokeKey keeps the key listed, with its revocation time, so that its
+// last use stays visible.
+func (h *APIKeyHandler) RevokeKey(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+	k, err := h.Service.RevokeKey(c.Request.Context(), id)
+	if err != nil {
+		apiKeyError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, k)
+}
+
+func apiKeyError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, apikey.ErrInvalidKey):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, apikey.ErrNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
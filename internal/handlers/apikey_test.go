@@ -0,0 +1,143 @@
+package handlers
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"task-api/internal/auth"
+	"task-api/internal/config"
+	"task-api/internal/domain/apikey"
+	"task-api/internal/mocks"
+)
+
+func TestAPIKeyHandler(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mocks.NewMockAPIKeyService(ctrl)
+	cfg := config.AuthConfig{
+		StaticTokens:  map[string]string{"root": "alice", "secret": "board"},
+		AdminSubjects: []string{"alice"},
+	}
+	handler := NewAPIKeyHandler(mockService, auth.NewMiddleware(auth.NewStaticTokenAuthenticator(cfg), cfg), cfg)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.Default()
+	handler.RegisterRoutes(router)
+
+	created := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	key := apikey.Key{ID: 1, TenantID: "default", Name: "CI", Prefix: "tk_abcdefgh", Hash: "hash", Scopes: []string{apikey.ScopeTasksRead}, CreatedAt: created}
+
+	tests := []struct {
+		TestCase string
+		Method   string
+		URL      string
+		Token    string
+		Body     string
+		Expected string
+		Setup    func()
+		Status   int
+	}{
+		{
+			TestCase: "Issue returns the secret once",
+			Method:   http.MethodPost,
+			URL:      "/admin/api-keys",
+			Token:    "root",
+			Body:     `{"name":"CI","scopes":["tasks:read"]}`,
+			Expected: `{"key":{"id":1,"tenant_id":"default","name":"CI","prefix":"tk_abcdefgh","scopes":["tasks:read"],"created_at":"2026-03-01T09:00:00Z"},"secret":"tk_abcdefgh_rest"}`,
+			Setup: func() {
+				mockService.EXPECT().IssueKey(gomock.Any(), "CI", []string{apikey.ScopeTasksRead}).Return(apikey.Issued{Key: key, Secret: "tk_abcdefgh_rest"}, nil)
+			},
+			Status: http.StatusCreated,
+		},
+		{
+			TestCase: "Issue with an unknown scope",
+			Method:   http.MethodPost,
+			URL:      "/admin/api-keys",
+			Token:    "root",
+			Body:     `{"name":"CI","scopes":["tasks:admin"]}`,
+			Expected: `{"error":"invalid api key: unknown scope \"tasks:admin\""}`,
+			Setup: func() {
+				mockService.EXPECT().IssueKey(gomock.Any(), "CI", []string{"tasks:admin"}).
+					Return(apikey.Issued{}, fmt.Errorf("%w: unknown scope %q", apikey.ErrInvalidKey, "tasks:admin"))
+			},
+			Status: http.StatusBadRequest,
+		},
+		{
+			TestCase: "List keys without secrets",
+			Method:   http.MethodGet,
+			URL:      "/admin/api-keys",
+			Token:    "root",
+			Expected: `[{"id":1,"tenant_id":"default","name":"CI","prefix":"tk_abcdefgh","scopes":["tasks:read"],"created_at":"2026-03-01T09:00:00Z"}]`,
+			Setup: func() {
+				mockService.EXPECT().ListKeys(gomock.Any()).Return([]apikey.Key{key}, nil)
+			},
+			Status: http.StatusOK,
+		},
+		{
+			TestCase: "Revoke key",
+			Method:   http.MethodDelete,
+			URL:      "/admin/api-keys/1",
+			Token:    "root",
+			Expected: `{"id":1,"tenant_id":"default","name":"CI","prefix":"tk_abcdefgh","scopes":["tasks:read"],"created_at":"2026-03-01T09:00:00Z","revoked_at":"2026-03-01T09:00:00Z"}`,
+			Setup: func() {
+				revoked := key
+				revoked.RevokedAt = &created
+				mockService.EXPECT().RevokeKey(gomock.Any(), 1).Return(revoked, nil)
+			},
+			Status: http.StatusOK,
+		},
+		{
+			TestCase: "Revoke missing key",
+			Method:   http.MethodDelete,
+			URL:      "/admin/api-keys/2",
+			Token:    "root",
+			Expected: `{"error":"api key not found"}`,
+			Setup: func() {
+				mockService.EXPECT().RevokeKey(gomock.Any(), 2).Return(apikey.Key{}, apikey.ErrNotFound)
+			},
+			Status: http.StatusNotFound,
+		},
+		{
+			TestCase: "Users who are no admins are refused",
+			Method:   http.MethodGet,
+			URL:      "/admin/api-keys",
+			Token:    "secret",
+			Expected: `{"error":"admin access required"}`,
+			Setup:    func() {},
+			Status:   http.StatusForbidden,
+		},
+		{
+			TestCase: "Anonymous callers are refused",
+			Method:   http.MethodGet,
+			URL:      "/admin/api-keys",
+			Expected: `{"error":"unauthorized"}`,
+			Setup:    func() {},
+			Status:   http.StatusUnauthorized,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.TestCase, func(t *testing.T) {
+			tc.Setup()
+			req, _ := http.NewRequest(tc.Method, tc.URL, bytes.NewBufferString(tc.Body))
+			req.Header.Set("Content-Type", "application/json")
+			if tc.Token != "" {
+				req.Header.Set("Authorization", "Bearer "+tc.Token)
+			}
+			rr := httptest.NewRecorder()
+			router.ServeHTTP(rr, req)
+
+			assert.Equal(t, tc.Status, rr.Code)
+			assert.JSONEq(t, tc.Expected, rr.Body.String())
+		})
+	}
+}
@@ -0,0 +1,172 @@
+package handlers
+
+import (
+	"errors"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"task-api/internal/auth"
+	"task-api/internal/config"
+	"task-api/internal/domain/apikey"
+	"task-api/internal/domain/attachment"
+	"task-api/internal/domain/role"
+	"task-api/internal/domain/task"
+	attachmentService "task-api/internal/services/attachment"
+)
+
+// multipartOverhead is allowed on top of the maximum attachment size for the
+// multipart boundaries, part headers and any other form fields.
+const multipartOverhead = 1 << 20
+
+type AttachmentHandler struct {
+	Service attachmentService.Service
+	// Auth guards the attachments like the task routes, and identifies the
+	// uploader.
+	Auth    auth.Middleware
+	MaxSize int64
+}
+
+func NewAttachmentHandler(service attachmentService.Service, authMiddleware auth.Middleware, cfg config.AttachmentConfig) *AttachmentHandler {
+	return &AttachmentHandler{Service: service, Auth: authMiddleware, MaxSize: cfg.MaxSize}
+}
+
+// RegisterRoutes guards the attachments with the auth middleware. API keys
+// need the tasks:read scope to list and download and tasks:write to upload
+// and delete attachments.
+func (h *AttachmentHandler) RegisterRoutes(router *gin.Engine) {
+	attachments := router.Group("/tasks/:id/attachments", gin.HandlerFunc(h.Auth))
+
+	read := attachments.Group("", auth.RequireScope(apikey.ScopeTasksRead))
+	read.GET("", h.GetAttachments)
+	read.GET("/:attachmentId", h.DownloadAttachment)
+
+	write := attachments.Group("", auth.RequireScope(apikey.ScopeTasksWrite))
+	write.POST("", h.UploadAttachment)
+	write.DELETE("/:attachmentId", h.DeleteAttachment)
+}
+
+func (h *AttachmentHandler) GetAttachments(c *gin.Context) {
+	taskID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+	attachments, err := h.Service.ListAttachments(c.Request.Context(), taskID)
+	if err != nil {
+		attachmentError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, attachments)
+}
+
+// UploadAttachment streams the "file" part of a multipart form into the
+// service instead of parsing the whole form up front.
+func (h *AttachmentHandler) UploadAttachment(c *gin.Context) {
+	identity, _ := auth.FromContext(c.Request.Context())
+	taskID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, h.MaxSize+multipartOverhead)
+	reader, err := c.Request.MultipartReader()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "file is required"})
+			return
+		}
+		if err != nil {
+			attachmentError(c, err)
+			return
+		}
+		if part.FormName() != "file" {
+			part.Close()
+			continue
+		}
+
+		created, err := h.Service.Upload(c.Request.Context(), taskID, identity.Subject, part.FileName(), part)
+		part.Close()
+		if err != nil {
+			attachmentError(c, err)
+			return
+		}
+		c.JSON(http.StatusCreated, created)
+		return
+	}
+}
+
+// DownloadAttachment serves the content with the type detected at upload
+// time. http.ServeContent answers Range and conditional requests.
+func (h *AttachmentHandler) DownloadAttachment(c *gin.Context) {
+	taskID, id, ok := attachmentIDs(c)
+	if !ok {
+		return
+	}
+	a, content, err := h.Service.Open(c.Request.Context(), taskID, id)
+	if err != nil {
+		attachmentError(c, err)
+		return
+	}
+	defer content.Close()
+
+	header := c.Writer.Header()
+	header.Set("Content-Type", a.ContentType)
+	header.Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": a.Filename}))
+	header.Set("X-Content-Type-Options", "nosniff")
+	header.Set("ETag", `"`+a.Checksum+`"`)
+	http.ServeContent(c.Writer, c.Request, a.Filename, a.CreatedAt, content)
+}
+
+func (h *AttachmentHandler) DeleteAttachment(c *gin.Context) {
+	taskID, id, ok := attachmentIDs(c)
+	if !ok {
+		return
+	}
+	if err := h.Service.DeleteAttachment(c.Request.Context(), taskID, id); err != nil {
+		attachmentError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Attachment deleted successfully"})
+}
+
+func attachmentIDs(c *gin.Context) (int, int, bool) {
+	taskID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return 0, 0, false
+	}
+	id, err := strconv.Atoi(c.Param("attachmentId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid attachment ID"})
+		return 0, 0, false
+	}
+	return taskID, id, true
+}
+
+func attachmentError(c *gin.Context, err error) {
+	var maxBytes *http.MaxBytesError
+	switch {
+	case errors.Is(err, attachment.ErrTooLarge), errors.As(err, &maxBytes):
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": attachment.ErrTooLarge.Error()})
+	case errors.Is(err, attachment.ErrTypeNotAllowed):
+		c.JSON(http.StatusUnsupportedMediaType, gin.H{"error": err.Error()})
+	case errors.Is(err, attachment.ErrEmpty):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, role.ErrForbidden):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	case errors.Is(err, attachment.ErrNotFound), errors.Is(err, attachment.ErrBlobNotFound), errors.Is(err, task.ErrNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
@@ -0,0 +1,255 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"task-api/internal/auth"
+	"task-api/internal/config"
+	"task-api/internal/domain/apikey"
+	"task-api/internal/domain/attachment"
+	"task-api/internal/domain/task"
+	"task-api/internal/mocks"
+)
+
+type nopSeekCloser struct {
+	io.ReadSeeker
+}
+
+func (nopSeekCloser) Close() error { return nil }
+
+func multipartBody(t *testing.T, field, filename, content string) (*bytes.Buffer, string) {
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	part, err := writer.CreateFormFile(field, filename)
+	assert.NoError(t, err)
+	_, _ = part.Write([]byte(content))
+	assert.NoError(t, writer.Close())
+	return &body, writer.FormDataContentType()
+}
+
+func TestAttachmentHandler(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mocks.NewMockAttachmentService(ctrl)
+	mockKeys := mocks.NewMockAPIKeyService(ctrl)
+	mockKeys.EXPECT().Verify("tk_read").Return(apikey.Key{ID: 3, Scopes: []string{apikey.ScopeTasksRead}}, nil).AnyTimes()
+	cfg := config.AuthConfig{StaticTokens: map[string]string{"alice-token": "alice"}}
+	authenticator, err := auth.NewAuthenticator(cfg, mockKeys)
+	assert.NoError(t, err)
+	handler := NewAttachmentHandler(mockService, auth.NewMiddleware(authenticator, cfg), config.AttachmentConfig{MaxSize: 1024})
+
+	gin.SetMode(gin.TestMode)
+	router := gin.Default()
+	handler.RegisterRoutes(router)
+
+	createdAt := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	notes := attachment.Attachment{ID: 2, TaskID: 1, Filename: "notes.txt", ContentType: "text/plain; charset=utf-8", Size: 11,
+		Checksum: "b94d27b9934d3e08a52e52d7da7dabfac484efe37a5380ee9088f7ace2efcde9", Uploader: "alice", CreatedAt: createdAt}
+
+	tests := []struct {
+		TestCase string
+		Request  func() *http.Request
+		Setup    func()
+		Status   int
+		Headers  map[string]string
+		Expected string
+	}{
+		{
+			TestCase: "Upload needs a token",
+			Request: func() *http.Request {
+				body, contentType := multipartBody(t, "file", "notes.txt", "hello world")
+				req, _ := http.NewRequest(http.MethodPost, "/tasks/1/attachments", body)
+				req.Header.Set("Content-Type", contentType)
+				return req
+			},
+			Setup:    func() {},
+			Status:   http.StatusUnauthorized,
+			Expected: `{"error":"unauthorized"}`,
+		},
+		{
+			TestCase: "Upload needs the tasks:write scope",
+			Request: func() *http.Request {
+				body, contentType := multipartBody(t, "file", "notes.txt", "hello world")
+				req, _ := http.NewRequest(http.MethodPost, "/tasks/1/attachments", body)
+				req.Header.Set("Content-Type", contentType)
+				req.Header.Set("Authorization", "Bearer tk_read")
+				return req
+			},
+			Setup:    func() {},
+			Status:   http.StatusForbidden,
+			Expected: `{"error":"api key lacks scope tasks:write"}`,
+		},
+		{
+			TestCase: "Upload as the token subject",
+			Request: func() *http.Request {
+				body, contentType := multipartBody(t, "file", "notes.txt", "hello world")
+				req, _ := http.NewRequest(http.MethodPost, "/tasks/1/attachments", body)
+				req.Header.Set("Content-Type", contentType)
+				req.Header.Set("Authorization", "Bearer alice-token")
+				return req
+			},
+			Setup: func() {
+				mockService.EXPECT().Upload(gomock.Any(), 1, "alice", "notes.txt", gomock.Any()).
+					DoAndReturn(func(_ context.Context, _ int, _, _ string, r io.Reader) (attachment.Attachment, error) {
+						content, _ := io.ReadAll(r)
+						assert.Equal(t, "hello world", string(content))
+						return notes, nil
+					})
+			},
+			Status: http.StatusCreated,
+			Expected: `{"id":2,"task_id":1,"filename":"notes.txt","content_type":"text/plain; charset=utf-8","size":11,` +
+				`"checksum":"b94d27b9934d3e08a52e52d7da7dabfac484efe37a5380ee9088f7ace2efcde9","uploader":"alice","created_at":"2026-03-01T09:00:00Z"}`,
+		},
+		{
+			TestCase: "Upload without a file part",
+			Request: func() *http.Request {
+				body, contentType := multipartBody(t, "other", "notes.txt", "hello world")
+				req, _ := http.NewRequest(http.MethodPost, "/tasks/1/attachments", body)
+				req.Header.Set("Content-Type", contentType)
+				req.Header.Set("Authorization", "Bearer alice-token")
+				return req
+			},
+			Setup:    func() {},
+			Status:   http.StatusBadRequest,
+			Expected: `{"error":"file is required"}`,
+		},
+		{
+			TestCase: "Upload of a type that is not allowed",
+			Request: func() *http.Request {
+				body, contentType := multipartBody(t, "file", "doc.pdf", "%PDF-1.7")
+				req, _ := http.NewRequest(http.MethodPost, "/tasks/1/attachments", body)
+				req.Header.Set("Content-Type", contentType)
+				req.Header.Set("Authorization", "Bearer alice-token")
+				return req
+			},
+			Setup: func() {
+				mockService.EXPECT().Upload(gomock.Any(), 1, "alice", "doc.pdf", gomock.Any()).Return(attachment.Attachment{}, attachment.ErrTypeNotAllowed)
+			},
+			Status:   http.StatusUnsupportedMediaType,
+			Expected: `{"error":"attachment type is not allowed"}`,
+		},
+		{
+			TestCase: "Upload over the request limit",
+			Request: func() *http.Request {
+				body, contentType := multipartBody(t, "file", "big.txt", strings.Repeat("a", 1024+multipartOverhead))
+				req, _ := http.NewRequest(http.MethodPost, "/tasks/1/attachments", body)
+				req.Header.Set("Content-Type", contentType)
+				req.Header.Set("Authorization", "Bearer alice-token")
+				return req
+			},
+			Setup: func() {
+				mockService.EXPECT().Upload(gomock.Any(), 1, "alice", "big.txt", gomock.Any()).
+					DoAndReturn(func(_ context.Context, _ int, _, _ string, r io.Reader) (attachment.Attachment, error) {
+						_, err := io.ReadAll(r)
+						return attachment.Attachment{}, err
+					})
+			},
+			Status:   http.StatusRequestEntityTooLarge,
+			Expected: `{"error":"attachment is too large"}`,
+		},
+		{
+			TestCase: "List attachments of a missing task",
+			Request: func() *http.Request {
+				req, _ := http.NewRequest(http.MethodGet, "/tasks/9/attachments", nil)
+				req.Header.Set("Authorization", "Bearer alice-token")
+				return req
+			},
+			Setup: func() {
+				mockService.EXPECT().ListAttachments(gomock.Any(), 9).Return(nil, task.ErrNotFound)
+			},
+			Status:   http.StatusNotFound,
+			Expected: `{"error":"task not found"}`,
+		},
+		{
+			TestCase: "Download",
+			Request: func() *http.Request {
+				req, _ := http.NewRequest(http.MethodGet, "/tasks/1/attachments/2", nil)
+				req.Header.Set("Authorization", "Bearer tk_read")
+				return req
+			},
+			Setup: func() {
+				mockService.EXPECT().Open(gomock.Any(), 1, 2).Return(notes, nopSeekCloser{strings.NewReader("hello world")}, nil)
+			},
+			Status: http.StatusOK,
+			Headers: map[string]string{
+				"Content-Type":           "text/plain; charset=utf-8",
+				"Content-Disposition":    `attachment; filename=notes.txt`,
+				"X-Content-Type-Options": "nosniff",
+				"ETag":                   `"b94d27b9934d3e08a52e52d7da7dabfac484efe37a5380ee9088f7ace2efcde9"`,
+				"Accept-Ranges":          "bytes",
+			},
+			Expected: "hello world",
+		},
+		{
+			TestCase: "Download a range",
+			Request: func() *http.Request {
+				req, _ := http.NewRequest(http.MethodGet, "/tasks/1/attachments/2", nil)
+				req.Header.Set("Authorization", "Bearer alice-token")
+				req.Header.Set("Range", "bytes=6-")
+				return req
+			},
+			Setup: func() {
+				mockService.EXPECT().Open(gomock.Any(), 1, 2).Return(notes, nopSeekCloser{strings.NewReader("hello world")}, nil)
+			},
+			Status: http.StatusPartialContent,
+			Headers: map[string]string{
+				"Content-Range": "bytes 6-10/11",
+			},
+			Expected: "world",
+		},
+		{
+			TestCase: "Download needs a token",
+			Request: func() *http.Request {
+				req, _ := http.NewRequest(http.MethodGet, "/tasks/1/attachments/2", nil)
+				return req
+			},
+			Setup:    func() {},
+			Status:   http.StatusUnauthorized,
+			Expected: `{"error":"unauthorized"}`,
+		},
+		{
+			TestCase: "Delete",
+			Request: func() *http.Request {
+				req, _ := http.NewRequest(http.MethodDelete, "/tasks/1/attachments/2", nil)
+				req.Header.Set("Authorization", "Bearer alice-token")
+				return req
+			},
+			Setup: func() {
+				mockService.EXPECT().DeleteAttachment(gomock.Any(), 1, 2).Return(nil)
+			},
+			Status:   http.StatusOK,
+			Expected: `{"message":"Attachment deleted successfully"}`,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.TestCase, func(t *testing.T) {
+			tc.Setup()
+			rr := httptest.NewRecorder()
+			router.ServeHTTP(rr, tc.Request())
+
+			assert.Equal(t, tc.Status, rr.Code)
+			for name, value := range tc.Headers {
+				assert.Equal(t, value, rr.Header().Get(name), name)
+			}
+			if strings.HasPrefix(tc.Expected, "{") {
+				assert.JSONEq(t, tc.Expected, rr.Body.String())
+			} else {
+				assert.Equal(t, tc.Expected, rr.Body.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,75 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"task-api/internal/auth"
+	"task-api/internal/config"
+	"task-api/internal/domain/audit"
+	auditService "task-api/internal/services/audit"
+)
+
+type AuditHandler struct {
+	Service auditService.Service
+	// Auth and Admin guard the log: only admin users read it.
+	Auth  auth.Middleware
+	Admin gin.HandlerFunc
+}
+
+func NewAuditHandler(service auditService.Service, authMiddleware auth.Middleware, cfg config.AuthConfig) *AuditHandler {
+	return &AuditHandler{Service: service, Auth: authMiddleware, Admin: auth.RequireAdmin(cfg)}
+}
+
+func (h *AuditHandler) RegisterRoutes(router *gin.Engine) {
+	router.GET("/audit", gin.HandlerFunc(h.Auth), h.Admin, h.GetRecords)
+}
+
+// GetRecords filters by ?task_id=, ?actor= and the RFC 3339 times ?from=
+// and ?to=, all optional.
+func (h *AuditHandler) GetRecords(c *gin.Context) {
+	var f audit.Filter
+	var err error
+	if raw := c.Query("task_id"); raw != "" {
+		if f.TaskID, err = strconv.Atoi(raw); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid task_id"})
+			return
+		}
+	}
+	f.Actor = c.Query("actor")
+	if raw := c.Query("from"); raw != "" {
+		if f.From, err = time.Parse(time.RFC3339, raw); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid from"})
+			return
+		}
+	}
+	if raw := c.Query("to"); raw != "" {
+		if f.To, err = time.Parse(time.RFC3339, raw); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid to"})
+			return
+		}
+	}
+
+	records, err := h.Service.GetRecords(c.Request.Context(), f)
+	if err != nil {
+		auditError(c, err)
+		return
+	}
+	if records == nil {
+		records = []audit.Record{}
+	}
+	c.JSON(http.StatusOK, records)
+}
+
+func auditError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, audit.ErrInvalidFilter):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
@@ -0,0 +1,120 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"task-api/internal/auth"
+	"task-api/internal/config"
+	"task-api/internal/domain/audit"
+	"task-api/internal/mocks"
+)
+
+func TestAuditHandler(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mocks.NewMockAuditService(ctrl)
+	cfg := config.AuthConfig{
+		StaticTokens:  map[string]string{"root": "alice", "secret": "board"},
+		AdminSubjects: []string{"alice"},
+	}
+	handler := NewAuditHandler(mockService, auth.NewMiddleware(auth.NewStaticTokenAuthenticator(cfg), cfg), cfg)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.Default()
+	handler.RegisterRoutes(router)
+
+	at := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	record := audit.Record{ID: 1, TaskID: 7, Actor: "bob", Time: at, RequestID: "req-1", Operation: audit.OperationUpdate,
+		Changes: map[string]audit.Change{"name": {Before: json.RawMessage(`"Draft"`), After: json.RawMessage(`"Final"`)}}}
+
+	tests := []struct {
+		TestCase string
+		URL      string
+		Token    string
+		Expected string
+		Setup    func()
+		Status   int
+	}{
+		{
+			TestCase: "Filter records",
+			URL:      "/audit?task_id=7&actor=bob&from=2026-03-01T00:00:00Z&to=2026-03-02T00:00:00Z",
+			Token:    "root",
+			Expected: `[{"id":1,"task_id":7,"actor":"bob","time":"2026-03-01T09:00:00Z","request_id":"req-1","operation":"update",
+				"changes":{"name":{"before":"Draft","after":"Final"}}}]`,
+			Setup: func() {
+				mockService.EXPECT().GetRecords(gomock.Any(), audit.Filter{TaskID: 7, Actor: "bob", From: at.Add(-9 * time.Hour), To: at.Add(15 * time.Hour)}).
+					Return([]audit.Record{record}, nil)
+			},
+			Status: http.StatusOK,
+		},
+		{
+			TestCase: "No records",
+			URL:      "/audit",
+			Token:    "root",
+			Expected: `[]`,
+			Setup: func() {
+				mockService.EXPECT().GetRecords(gomock.Any(), audit.Filter{}).Return(nil, nil)
+			},
+			Status: http.StatusOK,
+		},
+		{
+			TestCase: "Invalid task_id",
+			URL:      "/audit?task_id=seven",
+			Token:    "root",
+			Expected: `{"error":"Invalid task_id"}`,
+			Setup:    func() {},
+			Status:   http.StatusBadRequest,
+		},
+		{
+			TestCase: "Invalid from",
+			URL:      "/audit?from=yesterday",
+			Token:    "root",
+			Expected: `{"error":"Invalid from"}`,
+			Setup:    func() {},
+			Status:   http.StatusBadRequest,
+		},
+		{
+			TestCase: "Invalid filter",
+			URL:      "/audit?from=2026-03-02T00:00:00Z&to=2026-03-01T00:00:00Z",
+			Token:    "root",
+			Expected: `{"error":"invalid audit filter: from is after to"}`,
+			Setup: func() {
+				mockService.EXPECT().GetRecords(gomock.Any(), gomock.Any()).Return(nil, fmt.Errorf("%w: from is after to", audit.ErrInvalidFilter))
+			},
+			Status: http.StatusBadRequest,
+		},
+		{
+			TestCase: "Users who are no admins are refused",
+			URL:      "/audit",
+			Token:    "secret",
+			Expected: `{"error":"admin access required"}`,
+			Setup:    func() {},
+			Status:   http.StatusForbidden,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.TestCase, func(t *testing.T) {
+			tc.Setup()
+			req, _ := http.NewRequest(http.MethodGet, tc.URL, nil)
+			if tc.Token != "" {
+				req.Header.Set("Authorization", "Bearer "+tc.Token)
+			}
+			rr := httptest.NewRecorder()
+			router.ServeHTTP(rr, req)
+
+			assert.Equal(t, tc.Status, rr.Code)
+			assert.JSONEq(t, tc.Expected, rr.Body.String())
+		})
+	}
+}
@@ -0,0 +1,164 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"task-api/internal/auth"
+	"task-api/internal/domain/apikey"
+	"task-api/internal/domain/comment"
+	"task-api/internal/domain/role"
+	"task-api/internal/domain/task"
+	commentService "task-api/internal/services/comment"
+)
+
+type CommentHandler struct {
+	Service commentService.Service
+	// Auth guards the comments like the task routes, and identifies the
+	// author of the comments written.
+	Auth auth.Middleware
+}
+
+type commentRequest struct {
+	Body     string `json:"body"`
+	ParentID *int   `json:"parent_id"`
+}
+
+func NewCommentHandler(service commentService.Service, authMiddleware auth.Middleware) *CommentHandler {
+	return &CommentHandler{Service: service, Auth: authMiddleware}
+}
+
+// RegisterRoutes guards the comments with the auth middleware. API keys need
+// the tasks:read scope to read and tasks:write to write comments.
+func (h *CommentHandler) RegisterRoutes(router *gin.Engine) {
+	comments := router.Group("/tasks/:id/comments", gin.HandlerFunc(h.Auth))
+
+	read := comments.Group("", auth.RequireScope(apikey.ScopeTasksRead))
+	read.GET("", h.GetComments)
+
+	write := comments.Group("", auth.RequireScope(apikey.ScopeTasksWrite))
+	write.POST("", h.CreateComment)
+	write.PUT("/:commentId", h.UpdateComment)
+	write.DELETE("/:commentId", h.DeleteComment)
+}
+
+// GetComments lists one page of top-level comments, or of the replies to
+// ?parent_id= when given.
+func (h *CommentHandler) GetComments(c *gin.Context) {
+	taskID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+
+	var parentID *int
+	if raw := c.Query("parent_id"); raw != "" {
+		id, err := strconv.Atoi(raw)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid parent ID"})
+			return
+		}
+		parentID = &id
+	}
+	offset, err := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid offset"})
+		return
+	}
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "0"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid limit"})
+		return
+	}
+
+	page, err := h.Service.ListComments(c.Request.Context(), taskID, parentID, offset, limit)
+	if err != nil {
+		commentError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, page)
+}
+
+func (h *CommentHandler) CreateComment(c *gin.Context) {
+	identity, _ := auth.FromContext(c.Request.Context())
+	taskID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+	var req commentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	created, err := h.Service.CreateComment(c.Request.Context(), comment.Comment{TaskID: taskID, ParentID: req.ParentID, Author: identity.Subject, Body: req.Body})
+	if err != nil {
+		commentError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, created)
+}
+
+func (h *CommentHandler) UpdateComment(c *gin.Context) {
+	identity, _ := auth.FromContext(c.Request.Context())
+	taskID, id, ok := commentIDs(c)
+	if !ok {
+		return
+	}
+	var req commentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	updated, err := h.Service.UpdateComment(c.Request.Context(), identity.Subject, comment.Comment{ID: id, TaskID: taskID, Body: req.Body})
+	if err != nil {
+		commentError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, updated)
+}
+
+func (h *CommentHandler) DeleteComment(c *gin.Context) {
+	identity, _ := auth.FromContext(c.Request.Context())
+	taskID, id, ok := commentIDs(c)
+	if !ok {
+		return
+	}
+	if err := h.Service.DeleteComment(c.Request.Context(), identity.Subject, taskID, id); err != nil {
+		commentError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Comment deleted successfully"})
+}
+
+func commentIDs(c *gin.Context) (int, int, bool) {
+	taskID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return 0, 0, false
+	}
+	id, err := strconv.Atoi(c.Param("commentId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid comment ID"})
+		return 0, 0, false
+	}
+	return taskID, id, true
+}
+
+func commentError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, comment.ErrInvalidComment), errors.Is(err, comment.ErrParentNotFound):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, comment.ErrForbidden), errors.Is(err, role.ErrForbidden):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	case errors.Is(err, comment.ErrNotFound), errors.Is(err, task.ErrNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
@@ -0,0 +1,150 @@
+package handlers
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"task-api/internal/auth"
+	"task-api/internal/config"
+	"task-api/internal/domain/apikey"
+	"task-api/internal/domain/comment"
+	"task-api/internal/domain/task"
+	"task-api/internal/mocks"
+)
+
+func TestCommentHandler(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mocks.NewMockCommentService(ctrl)
+	mockKeys := mocks.NewMockAPIKeyService(ctrl)
+	mockKeys.EXPECT().Verify("tk_read").Return(apikey.Key{ID: 3, Scopes: []string{apikey.ScopeTasksRead}}, nil).AnyTimes()
+	cfg := config.AuthConfig{StaticTokens: map[string]string{"alice-token": "alice"}}
+	authenticator, err := auth.NewAuthenticator(cfg, mockKeys)
+	assert.NoError(t, err)
+	handler := NewCommentHandler(mockService, auth.NewMiddleware(authenticator, cfg))
+
+	gin.SetMode(gin.TestMode)
+	router := gin.Default()
+	handler.RegisterRoutes(router)
+
+	parentID := 3
+	tests := []struct {
+		TestCase string
+		Method   string
+		URL      string
+		Token    string
+		Body     string
+		Expected string
+		Setup    func()
+		Status   int
+	}{
+		{
+			TestCase: "List replies",
+			Method:   http.MethodGet,
+			URL:      "/tasks/1/comments?parent_id=3&offset=0&limit=10",
+			Token:    "tk_read",
+			Expected: `{"items":[],"total":0,"offset":0,"limit":10}`,
+			Setup: func() {
+				mockService.EXPECT().ListComments(gomock.Any(), 1, &parentID, 0, 10).Return(comment.Page{Items: []comment.Comment{}, Limit: 10}, nil)
+			},
+			Status: http.StatusOK,
+		},
+		{
+			TestCase: "List comments of a missing task",
+			Method:   http.MethodGet,
+			URL:      "/tasks/9/comments",
+			Token:    "alice-token",
+			Expected: `{"error":"task not found"}`,
+			Setup: func() {
+				mockService.EXPECT().ListComments(gomock.Any(), 9, nil, 0, 0).Return(comment.Page{}, task.ErrNotFound)
+			},
+			Status: http.StatusNotFound,
+		},
+		{
+			TestCase: "List needs a token",
+			Method:   http.MethodGet,
+			URL:      "/tasks/1/comments",
+			Expected: `{"error":"unauthorized"}`,
+			Setup:    func() {},
+			Status:   http.StatusUnauthorized,
+		},
+		{
+			TestCase: "Create needs the tasks:write scope",
+			Method:   http.MethodPost,
+			URL:      "/tasks/1/comments",
+			Token:    "tk_read",
+			Body:     `{"body":"Hello"}`,
+			Expected: `{"error":"api key lacks scope tasks:write"}`,
+			Setup:    func() {},
+			Status:   http.StatusForbidden,
+		},
+		{
+			TestCase: "Create needs a token",
+			Method:   http.MethodPost,
+			URL:      "/tasks/1/comments",
+			Body:     `{"body":"Hello"}`,
+			Expected: `{"error":"unauthorized"}`,
+			Setup:    func() {},
+			Status:   http.StatusUnauthorized,
+		},
+		{
+			TestCase: "Create reply as the token subject",
+			Method:   http.MethodPost,
+			URL:      "/tasks/1/comments",
+			Token:    "alice-token",
+			Body:     `{"body":"Hello","parent_id":3,"author":"mallory"}`,
+			Expected: `{"id":4,"task_id":1,"parent_id":3,"author":"alice","body":"Hello","reply_count":0,"created_at":"0001-01-01T00:00:00Z","updated_at":"0001-01-01T00:00:00Z"}`,
+			Setup: func() {
+				mockService.EXPECT().CreateComment(gomock.Any(), comment.Comment{TaskID: 1, ParentID: &parentID, Author: "alice", Body: "Hello"}).
+					Return(comment.Comment{ID: 4, TaskID: 1, ParentID: &parentID, Author: "alice", Body: "Hello"}, nil)
+			},
+			Status: http.StatusCreated,
+		},
+		{
+			TestCase: "Edit someone else's comment",
+			Method:   http.MethodPut,
+			URL:      "/tasks/1/comments/5",
+			Token:    "alice-token",
+			Body:     `{"body":"Edited"}`,
+			Expected: `{"error":"only the author can change a comment"}`,
+			Setup: func() {
+				mockService.EXPECT().UpdateComment(gomock.Any(), "alice", comment.Comment{ID: 5, TaskID: 1, Body: "Edited"}).Return(comment.Comment{}, comment.ErrForbidden)
+			},
+			Status: http.StatusForbidden,
+		},
+		{
+			TestCase: "Delete own comment",
+			Method:   http.MethodDelete,
+			URL:      "/tasks/1/comments/5",
+			Token:    "alice-token",
+			Expected: `{"message":"Comment deleted successfully"}`,
+			Setup: func() {
+				mockService.EXPECT().DeleteComment(gomock.Any(), "alice", 1, 5).Return(nil)
+			},
+			Status: http.StatusOK,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.TestCase, func(t *testing.T) {
+			tc.Setup()
+			req, _ := http.NewRequest(tc.Method, tc.URL, bytes.NewBufferString(tc.Body))
+			req.Header.Set("Content-Type", "application/json")
+			if tc.Token != "" {
+				req.Header.Set("Authorization", "Bearer "+tc.Token)
+			}
+			rr := httptest.NewRecorder()
+			router.ServeHTTP(rr, req)
+
+			assert.Equal(t, tc.Status, rr.Code)
+			assert.JSONEq(t, tc.Expected, rr.Body.String())
+		})
+	}
+}
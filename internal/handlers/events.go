@@ -0,0 +1,133 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"task-api/internal/auth"
+	"task-api/internal/config"
+	"task-api/internal/domain/apikey"
+	"task-api/internal/events"
+	"task-api/internal/tenant"
+)
+
+type EventHandler struct {
+	Broker    *events.Broker
+	Heartbeat time.Duration
+	// Auth guards the stream like the task routes, as it carries the
+	// tasks of the caller's tenant.
+	Auth auth.Middleware
+}
+
+const defaultHeartbeat = 15 * time.Second
+
+func NewEventHandler(broker *events.Broker, authMiddleware auth.Middleware, cfg config.EventsConfig) *EventHandler {
+	heartbeat := cfg.Heartbeat
+	if heartbeat <= 0 {
+		heartbeat = defaultHeartbeat
+	}
+	return &EventHandler{Broker: broker, Heartbeat: heartbeat, Auth: authMiddleware}
+}
+
+func (h *EventHandler) RegisterRoutes(router *gin.Engine) {
+	router.GET("/tasks/events", gin.HandlerFunc(h.Auth), auth.RequireScope(apikey.ScopeTasksRead), h.StreamEvents)
+}
+
+// StreamEvents streams task changes as Server-Sent Events. Clients resume
+// with the Last-Event-ID header, or the last_event_id query parameter for
+// EventSource implementations that cannot set headers. When some of the
+// events after it are no longer buffered, the stream starts with a reset
+// event, after which clients fetch the tasks again rather than trust the
+// replay.
+func (h *EventHandler) StreamEvents(c *gin.Context) {
+	lastEventID := c.GetHeader("Last-Event-ID")
+	if lastEventID == "" {
+		lastEventID = c.Query("last_event_id")
+	}
+	var after uint64
+	if lastEventID != "" {
+		id, err := strconv.ParseUint(lastEventID, 10, 64)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid Last-Event-ID"})
+			return
+		}
+		after = id
+	}
+
+	backlog, sub := h.Broker.Subscribe(after)
+	defer sub.Close()
+	filter := events.Filter{Tenant: tenant.FromContext(c.Request.Context())}
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no")
+	c.Status(http.StatusOK)
+	// The stream outlives the server's write timeout; heartbeats that fail
+	// to write end it when the client is gone.
+	_ = http.NewResponseController(c.Writer).SetWriteDeadline(time.Time{})
+
+	if sub.Missed {
+		if err := writeReset(c.Writer, after); err != nil {
+			return
+		}
+	}
+	for _, event := range backlog {
+		if !filter.Matches(event) {
+			continue
+		}
+		if err := writeEvent(c.Writer, event); err != nil {
+			return
+		}
+	}
+	c.Writer.Flush()
+
+	heartbeat := time.NewTicker(h.Heartbeat)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-c.Request.Context().Done():
+			return
+		case event, ok := <-sub.C:
+			if !ok {
+				// Dropped as a slow consumer or shutting down; the client
+				// reconnects and resumes.
+				return
+			}
+			if !filter.Matches(event) {
+				continue
+			}
+			if err := writeEvent(c.Writer, event); err != nil {
+				return
+			}
+		case <-heartbeat.C:
+			if _, err := io.WriteString(c.Writer, ": heartbeat\n\n"); err != nil {
+				return
+			}
+		}
+		c.Writer.Flush()
+	}
+}
+
+func writeEvent(w io.Writer, event events.Event) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", event.ID, event.Type, data)
+	return err
+}
+
+// writeReset leaves the id out, so that a client that reconnects before
+// the next event is told again.
+func writeReset(w io.Writer, lastEventID uint64) error {
+	_, err := fmt.Fprintf(w, "event: reset\ndata: {\"last_event_id\":%d}\n\n", lastEventID)
+	return err
+}
@@ -0,0 +1,155 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+
+	"task-api/internal/auth"
+	"task-api/internal/config"
+	"task-api/internal/domain/task"
+	"task-api/internal/events"
+	"task-api/internal/tenant"
+)
+
+func TestEventHandler_StreamEvents(t *testing.T) {
+	broker := events.NewBroker(16)
+	broker.Publish(events.TaskCreated, task.Info{ID: 1, Name: "Test Task", Status: 0, TenantID: tenant.Default})
+	broker.Publish(events.TaskDeleted, task.Info{ID: 1, Name: "Test Task", Status: 0, TenantID: tenant.Default})
+	broker.Publish(events.TaskCreated, task.Info{ID: 2, Name: "Other Task", Status: 0, TenantID: "acme"})
+	cfg := config.AuthConfig{
+		StaticTokens:   map[string]string{"secret": "board", "acme-secret": "acme-board"},
+		SubjectTenants: map[string]string{"acme-board": "acme"},
+	}
+	authenticator := auth.NewStaticTokenAuthenticator(cfg)
+	handler := NewEventHandler(broker, auth.NewMiddleware(authenticator, cfg), config.EventsConfig{Heartbeat: 10 * time.Millisecond})
+
+	gin.SetMode(gin.TestMode)
+	router := gin.Default()
+	router.Use(gin.HandlerFunc(auth.NewIdentify(authenticator)), tenant.Middleware(cfg))
+	handler.RegisterRoutes(router)
+
+	tests := []struct {
+		TestCase    string
+		LastEventID string
+		Token       string
+		Tenant      string
+		Contains    []string
+		NotContains []string
+		Status      int
+	}{
+		{
+			TestCase:    "Resume from Last-Event-ID",
+			LastEventID: "1",
+			Token:       "secret",
+			Contains:    []string{"id: 2\nevent: task.deleted\n", ": heartbeat\n\n"},
+			NotContains: []string{"id: 1\n", "id: 3\n"},
+			Status:      http.StatusOK,
+		},
+		{
+			TestCase:    "Only the events of the tenant",
+			LastEventID: "1",
+			Token:       "acme-secret",
+			Tenant:      "acme",
+			Contains:    []string{"id: 3\nevent: task.created\n"},
+			NotContains: []string{"id: 2\n"},
+			Status:      http.StatusOK,
+		},
+		{
+			TestCase:    "Invalid Last-Event-ID",
+			LastEventID: "abc",
+			Token:       "secret",
+			Status:      http.StatusBadRequest,
+		},
+		{
+			TestCase:    "Missing token",
+			LastEventID: "1",
+			NotContains: []string{"id: 2\n"},
+			Status:      http.StatusUnauthorized,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.TestCase, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+			defer cancel()
+			req, _ := http.NewRequestWithContext(ctx, http.MethodGet, "/tasks/events", nil)
+			req.Header.Set("Last-Event-ID", tc.LastEventID)
+			if tc.Token != "" {
+				req.Header.Set("Authorization", "Bearer "+tc.Token)
+			}
+			if tc.Tenant != "" {
+				req.Header.Set(tenant.Header, tc.Tenant)
+			}
+			rr := httptest.NewRecorder()
+			router.ServeHTTP(rr, req)
+
+			assert.Equal(t, tc.Status, rr.Code)
+			for _, s := range tc.Contains {
+				assert.Contains(t, rr.Body.String(), s)
+			}
+			for _, s := range tc.NotContains {
+				assert.NotContains(t, rr.Body.String(), s)
+			}
+		})
+	}
+}
+
+func TestEventHandler_StreamEventsAfterOverflow(t *testing.T) {
+	broker := events.NewBroker(2)
+	for i := 1; i <= 5; i++ {
+		broker.Publish(events.TaskUpdated, task.Info{ID: 1, Name: "Test Task", TenantID: tenant.Default})
+	}
+	cfg := config.AuthConfig{StaticTokens: map[string]string{"secret": "board"}}
+	authenticator := auth.NewStaticTokenAuthenticator(cfg)
+	handler := NewEventHandler(broker, auth.NewMiddleware(authenticator, cfg), config.EventsConfig{Heartbeat: time.Second})
+
+	gin.SetMode(gin.TestMode)
+	router := gin.Default()
+	router.Use(gin.HandlerFunc(auth.NewIdentify(authenticator)), tenant.Middleware(cfg))
+	handler.RegisterRoutes(router)
+
+	tests := []struct {
+		TestCase    string
+		LastEventID string
+		Expected    string
+	}{
+		{
+			TestCase:    "Reset before the replay when events were evicted",
+			LastEventID: "1",
+			Expected:    "event: reset\ndata: {\"last_event_id\":1}\n\nid: 4\n",
+		},
+		{
+			TestCase:    "Reset when the ID is from before a restart",
+			LastEventID: "9",
+			Expected:    "event: reset\ndata: {\"last_event_id\":9}\n\n",
+		},
+		{
+			TestCase:    "No reset when nothing was evicted",
+			LastEventID: "3",
+			Expected:    "id: 4\n",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.TestCase, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+			defer cancel()
+			req, _ := http.NewRequestWithContext(ctx, http.MethodGet, "/tasks/events", nil)
+			req.Header.Set("Last-Event-ID", tc.LastEventID)
+			req.Header.Set("Authorization", "Bearer secret")
+			rr := httptest.NewRecorder()
+			router.ServeHTTP(rr, req)
+
+			assert.Equal(t, http.StatusOK, rr.Code)
+			assert.True(t, strings.HasPrefix(rr.Body.String(), tc.Expected), rr.Body.String())
+			assert.NotContains(t, rr.Body.String(), "id: 3\n")
+		})
+	}
+}
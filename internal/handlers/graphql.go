@@ -0,0 +1,93 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/graphql-go/graphql"
+
+	"task-api/internal/auth"
+	"task-api/internal/config"
+	"task-api/internal/graph"
+	taskService "task-api/internal/services/task"
+)
+
+type GraphQLHandler struct {
+	Schema graphql.Schema
+	// Auth guards queries, which the task service authorizes like any
+	// other call.
+	Auth     auth.Middleware
+	Limits   graph.Limits
+	GraphiQL bool
+}
+
+type graphQLRequest struct {
+	Query         string                 `json:"query" binding:"required"`
+	OperationName string                 `json:"operationName"`
+	Variables     map[string]interface{} `json:"variables"`
+}
+
+func NewGraphQLHandler(service taskService.Service, authMiddleware auth.Middleware, cfg config.GraphQLConfig) (*GraphQLHandler, error) {
+	schema, err := graph.NewSchema(service)
+	if err != nil {
+		return nil, err
+	}
+	return &GraphQLHandler{
+		Schema:   schema,
+		Auth:     authMiddleware,
+		Limits:   graph.Limits{MaxDepth: cfg.MaxDepth, MaxComplexity: cfg.MaxComplexity},
+		GraphiQL: cfg.GraphiQL,
+	}, nil
+}
+
+func (h *GraphQLHandler) RegisterRoutes(router *gin.Engine) {
+	router.POST("/graphql", gin.HandlerFunc(h.Auth), h.Query)
+	if h.GraphiQL {
+		router.GET("/graphql", h.Playground)
+	}
+}
+
+func (h *GraphQLHandler) Query(c *gin.Context) {
+	var req graphQLRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.Limits.Check(req.Query, req.Variables); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"errors": []gin.H{{"message": err.Error()}}})
+		return
+	}
+
+	result := graphql.Do(graphql.Params{
+		Schema:         h.Schema,
+		RequestString:  req.Query,
+		OperationName:  req.OperationName,
+		VariableValues: req.Variables,
+		Context:        c.Request.Context(),
+	})
+	c.JSON(http.StatusOK, result)
+}
+
+func (h *GraphQLHandler) Playground(c *gin.Context) {
+	c.Data(http.StatusOK, "text/html; charset=utf-8", []byte(graphiQLPage))
+}
+
+const graphiQLPage = `<!DOCTYPE html>
+<html>
+<head>
+  <title>Task API GraphiQL</title>
+  <link rel="stylesheet" href="https://unpkg.com/graphiql@3/graphiql.min.css" />
+</head>
+<body style="margin: 0;">
+  <div id="graphiql" style="height: 100vh;"></div>
+  <script crossorigin src="https://unpkg.com/react@18/umd/react.production.min.js"></script>
+  <script crossorigin src="https://unpkg.com/react-dom@18/umd/react-dom.production.min.js"></script>
+  <script crossorigin src="https://unpkg.com/graphiql@3/graphiql.min.js"></script>
+  <script>
+    const fetcher = GraphiQL.createFetcher({ url: '/graphql' });
+    ReactDOM.createRoot(document.getElementById('graphiql')).render(React.createElement(GraphiQL, { fetcher }));
+  </script>
+</body>
+</html>
+`
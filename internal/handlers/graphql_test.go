@@ -0,0 +1,121 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"task-api/internal/auth"
+	"task-api/internal/config"
+	"task-api/internal/domain/task"
+	"task-api/internal/mocks"
+)
+
+func TestGraphQLHandler_Query(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mocks.NewMockService(ctrl)
+	handler, err := NewGraphQLHandler(mockService, noAuth, config.GraphQLConfig{MaxDepth: 3, MaxComplexity: 50})
+	assert.NoError(t, err)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.Default()
+	handler.RegisterRoutes(router)
+
+	tests := []struct {
+		TestCase string
+		Query    string
+		Expected string
+		Setup    func()
+		Status   int
+	}{
+		{
+			TestCase: "Query task",
+			Query:    `{ task(id: 1) { id name } }`,
+			Expected: `{"data":{"task":{"id":1,"name":"Test Task"}}}`,
+			Setup: func() {
+				mockService.EXPECT().GetTaskByID(gomock.Any(), 1).Return(task.Info{ID: 1, Name: "Test Task", Status: 0}, nil)
+			},
+			Status: http.StatusOK,
+		},
+		{
+			TestCase: "Query too complex",
+			Query:    `{ tasks { items { id } total } }`,
+			Expected: `{"errors":[{"message":"query complexity 61 exceeds the limit of 50"}]}`,
+			Setup:    func() {},
+			Status:   http.StatusBadRequest,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.TestCase, func(t *testing.T) {
+			tc.Setup()
+			body, _ := json.Marshal(map[string]string{"query": tc.Query})
+			req, _ := http.NewRequest(http.MethodPost, "/graphql", bytes.NewBuffer(body))
+			req.Header.Set("Content-Type", "application/json")
+			rr := httptest.NewRecorder()
+			router.ServeHTTP(rr, req)
+
+			assert.Equal(t, tc.Status, rr.Code)
+			assert.JSONEq(t, tc.Expected, rr.Body.String())
+		})
+	}
+}
+
+func TestGraphQLHandler_RequiresAuth(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	cfg := config.AuthConfig{StaticTokens: map[string]string{"secret": "board"}}
+	handler, err := NewGraphQLHandler(mocks.NewMockService(ctrl), auth.NewMiddleware(auth.NewStaticTokenAuthenticator(cfg), cfg), config.GraphQLConfig{MaxDepth: 3, MaxComplexity: 50})
+	assert.NoError(t, err)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.Default()
+	handler.RegisterRoutes(router)
+
+	body, _ := json.Marshal(map[string]string{"query": `mutation { deleteTask(id: 1) }`})
+	req, _ := http.NewRequest(http.MethodPost, "/graphql", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusUnauthorized, rr.Code)
+}
+
+func TestGraphQLHandler_Playground(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		TestCase string
+		GraphiQL bool
+		Status   int
+	}{
+		{TestCase: "GraphiQL enabled in development", GraphiQL: true, Status: http.StatusOK},
+		{TestCase: "GraphiQL disabled otherwise", GraphiQL: false, Status: http.StatusNotFound},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.TestCase, func(t *testing.T) {
+			handler, err := NewGraphQLHandler(mocks.NewMockService(ctrl), noAuth, config.GraphQLConfig{GraphiQL: tc.GraphiQL})
+			assert.NoError(t, err)
+			router := gin.Default()
+			handler.RegisterRoutes(router)
+
+			req, _ := http.NewRequest(http.MethodGet, "/graphql", nil)
+			rr := httptest.NewRecorder()
+			router.ServeHTTP(rr, req)
+
+			assert.Equal(t, tc.Status, rr.Code)
+		})
+	}
+}
@@ -0,0 +1,44 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"task-api/internal/auth"
+	"task-api/internal/health"
+)
+
+type HealthHandler struct {
+	Monitor *health.Monitor
+	// Auth lets the probes through as long as their paths are in
+	// AUTH_PUBLIC_PATHS, as they are by default.
+	Auth auth.Middleware
+}
+
+func NewHealthHandler(monitor *health.Monitor, authMiddleware auth.Middleware) *HealthHandler {
+	return &HealthHandler{Monitor: monitor, Auth: authMiddleware}
+}
+
+func (h *HealthHandler) RegisterRoutes(router *gin.Engine) {
+	probes := router.Group("", gin.HandlerFunc(h.Auth))
+	probes.GET("/healthz", h.Live)
+	probes.GET("/readyz", h.Ready)
+}
+
+// Live answers as long as the process serves requests; it does not check
+// the dependencies, so that an outage of one does not get the API restarted.
+func (h *HealthHandler) Live(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"status": health.StatusOK})
+}
+
+// Ready reports on every dependency, with a 503 when one of them fails or
+// the API is shutting down.
+func (h *HealthHandler) Ready(c *gin.Context) {
+	report := h.Monitor.Ready(c.Request.Context())
+	status := http.StatusOK
+	if report.Status != health.StatusOK {
+		status = http.StatusServiceUnavailable
+	}
+	c.JSON(status, report)
+}
@@ -0,0 +1,94 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+
+	"task-api/internal/auth"
+	"task-api/internal/config"
+	"task-api/internal/health"
+)
+
+type stubChecker struct {
+	name string
+	err  error
+}
+
+func (s *stubChecker) Component() string { return s.name }
+
+func (s *stubChecker) CheckHealth(context.Context) error { return s.err }
+
+func TestHealthHandler(t *testing.T) {
+	redis := &stubChecker{name: "redis"}
+	monitor := health.NewMonitor([]health.Checker{redis}, config.HealthConfig{CheckTimeout: time.Second})
+	cfg := config.AuthConfig{StaticTokens: map[string]string{"secret": "board"}, PublicPaths: []string{"/healthz", "/readyz"}}
+	handler := NewHealthHandler(monitor, auth.NewMiddleware(auth.NewStaticTokenAuthenticator(cfg), cfg))
+
+	gin.SetMode(gin.TestMode)
+	router := gin.Default()
+	handler.RegisterRoutes(router)
+
+	tests := []struct {
+		TestCase string
+		URL      string
+		Expected string
+		Setup    func()
+		Status   int
+	}{
+		{
+			TestCase: "Live without a token",
+			URL:      "/healthz",
+			Expected: `{"status":"ok"}`,
+			Status:   http.StatusOK,
+		},
+		{
+			TestCase: "Ready",
+			URL:      "/readyz",
+			Expected: `{"status":"ok","components":{"redis":{"status":"ok","latency_ms":0}}}`,
+			Status:   http.StatusOK,
+		},
+		{
+			TestCase: "Not ready while a dependency fails",
+			URL:      "/readyz",
+			Expected: `{"status":"failing","components":{"redis":{"status":"failing","error":"connection refused","latency_ms":0}}}`,
+			Setup:    func() { redis.err = errors.New("connection refused") },
+			Status:   http.StatusServiceUnavailable,
+		},
+		{
+			TestCase: "Not ready while shutting down",
+			URL:      "/readyz",
+			Expected: `{"status":"failing","error":"shutting down","components":{}}`,
+			Setup: func() {
+				redis.err = nil
+				assert.NoError(t, monitor.Drain(context.Background()))
+			},
+			Status: http.StatusServiceUnavailable,
+		},
+		{
+			TestCase: "Still live while shutting down",
+			URL:      "/healthz",
+			Expected: `{"status":"ok"}`,
+			Status:   http.StatusOK,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.TestCase, func(t *testing.T) {
+			if tc.Setup != nil {
+				tc.Setup()
+			}
+			req, _ := http.NewRequest(http.MethodGet, tc.URL, nil)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+			assert.Equal(t, tc.Status, w.Code)
+			assert.JSONEq(t, tc.Expected, w.Body.String())
+		})
+	}
+}
@@ -0,0 +1,129 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"task-api/internal/auth"
+	"task-api/internal/config"
+	"task-api/internal/domain/apikey"
+	"task-api/internal/domain/label"
+	labelService "task-api/internal/services/label"
+)
+
+type LabelHandler struct {
+	Service labelService.Service
+	// Auth guards every route, and Admin the ones that change labels, as
+	// they are shared by every project.
+	Auth  auth.Middleware
+	Admin gin.HandlerFunc
+}
+
+func NewLabelHandler(service labelService.Service, authMiddleware auth.Middleware, cfg config.AuthConfig) *LabelHandler {
+	return &LabelHandler{Service: service, Auth: authMiddleware, Admin: auth.RequireAdmin(cfg)}
+}
+
+func (h *LabelHandler) RegisterRoutes(router *gin.Engine) {
+	labels := router.Group("/labels", gin.HandlerFunc(h.Auth))
+
+	read := labels.Group("", auth.RequireScope(apikey.ScopeTasksRead))
+	read.GET("", h.GetLabels)
+	read.GET("/:id", h.GetLabel)
+
+	write := labels.Group("", h.Admin)
+	write.POST("", h.CreateLabel)
+	write.PUT("/:id", h.UpdateLabel)
+	write.DELETE("/:id", h.DeleteLabel)
+}
+
+func (h *LabelHandler) GetLabels(c *gin.Context) {
+	labels, err := h.Service.GetAllLabels(c.Request.Context())
+	if err != nil {
+		labelError(c, err)
+		return
+	}
+	if labels == nil {
+		labels = []label.Label{}
+	}
+	c.JSON(http.StatusOK, labels)
+}
+
+func (h *LabelHandler) GetLabel(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+	l, err := h.Service.GetLabel(c.Request.Context(), id)
+	if err != nil {
+		labelError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, l)
+}
+
+func (h *LabelHandler) CreateLabel(c *gin.Context) {
+	var l label.Label
+	if err := c.ShouldBindJSON(&l); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	created, err := h.Service.CreateLabel(c.Request.Context(), l)
+	if err != nil {
+		labelError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, created)
+}
+
+func (h *LabelHandler) UpdateLabel(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+
+	var l label.Label
+	if err := c.ShouldBindJSON(&l); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	l.ID = id
+	updated, err := h.Service.UpdateLabel(c.Request.Context(), l)
+	if err != nil {
+		labelError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, updated)
+}
+
+func (h *LabelHandler) DeleteLabel(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+	if err := h.Service.DeleteLabel(c.Request.Context(), id); err != nil {
+		labelError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Label deleted successfully"})
+}
+
+func labelError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, label.ErrInvalidLabel):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, label.ErrNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, label.ErrDuplicateName):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
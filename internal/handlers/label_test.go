@@ -0,0 +1,148 @@
+package handlers
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"task-api/internal/auth"
+	"task-api/internal/config"
+	"task-api/internal/domain/label"
+	"task-api/internal/mocks"
+)
+
+func TestLabelHandler(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mocks.NewMockLabelService(ctrl)
+	cfg := config.AuthConfig{
+		StaticTokens:  map[string]string{"root": "alice", "secret": "board"},
+		AdminSubjects: []string{"alice"},
+	}
+	handler := NewLabelHandler(mockService, auth.NewMiddleware(auth.NewStaticTokenAuthenticator(cfg), cfg), cfg)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.Default()
+	handler.RegisterRoutes(router)
+
+	tests := []struct {
+		TestCase string
+		Method   string
+		URL      string
+		Token    string
+		Body     string
+		Expected string
+		Setup    func()
+		Status   int
+	}{
+		{
+			TestCase: "Create label",
+			Method:   http.MethodPost,
+			URL:      "/labels",
+			Token:    "root",
+			Body:     `{"name":"urgent","color":"#ff0000"}`,
+			Expected: `{"id":1,"name":"urgent","color":"#ff0000"}`,
+			Setup: func() {
+				mockService.EXPECT().CreateLabel(gomock.Any(), label.Label{Name: "urgent", Color: "#ff0000"}).Return(label.Label{ID: 1, Name: "urgent", Color: "#ff0000"}, nil)
+			},
+			Status: http.StatusCreated,
+		},
+		{
+			TestCase: "Create invalid label",
+			Method:   http.MethodPost,
+			URL:      "/labels",
+			Token:    "root",
+			Body:     `{"name":""}`,
+			Expected: `{"error":"invalid label: name is required"}`,
+			Setup: func() {
+				mockService.EXPECT().CreateLabel(gomock.Any(), label.Label{}).Return(label.Label{}, fmt.Errorf("%w: name is required", label.ErrInvalidLabel))
+			},
+			Status: http.StatusBadRequest,
+		},
+		{
+			TestCase: "Rename onto an existing label",
+			Method:   http.MethodPut,
+			URL:      "/labels/2",
+			Token:    "root",
+			Body:     `{"name":"urgent"}`,
+			Expected: `{"error":"label name already exists"}`,
+			Setup: func() {
+				mockService.EXPECT().UpdateLabel(gomock.Any(), label.Label{ID: 2, Name: "urgent"}).Return(label.Label{}, label.ErrDuplicateName)
+			},
+			Status: http.StatusConflict,
+		},
+		{
+			TestCase: "List labels",
+			Method:   http.MethodGet,
+			URL:      "/labels",
+			Token:    "root",
+			Expected: `[]`,
+			Setup: func() {
+				mockService.EXPECT().GetAllLabels(gomock.Any()).Return(nil, nil)
+			},
+			Status: http.StatusOK,
+		},
+		{
+			TestCase: "Delete missing label",
+			Method:   http.MethodDelete,
+			URL:      "/labels/9",
+			Token:    "root",
+			Expected: `{"error":"label not found"}`,
+			Setup: func() {
+				mockService.EXPECT().DeleteLabel(gomock.Any(), 9).Return(label.ErrNotFound)
+			},
+			Status: http.StatusNotFound,
+		},
+		{
+			TestCase: "Labels need a token",
+			Method:   http.MethodGet,
+			URL:      "/labels",
+			Expected: `{"error":"unauthorized"}`,
+			Setup:    func() {},
+			Status:   http.StatusUnauthorized,
+		},
+		{
+			TestCase: "Users who are no admins list labels",
+			Method:   http.MethodGet,
+			URL:      "/labels",
+			Token:    "secret",
+			Expected: `[]`,
+			Setup: func() {
+				mockService.EXPECT().GetAllLabels(gomock.Any()).Return(nil, nil)
+			},
+			Status: http.StatusOK,
+		},
+		{
+			TestCase: "Users who are no admins may not delete labels",
+			Method:   http.MethodDelete,
+			URL:      "/labels/2",
+			Token:    "secret",
+			Expected: `{"error":"admin access required"}`,
+			Setup:    func() {},
+			Status:   http.StatusForbidden,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.TestCase, func(t *testing.T) {
+			tc.Setup()
+			req, _ := http.NewRequest(tc.Method, tc.URL, bytes.NewBufferString(tc.Body))
+			req.Header.Set("Content-Type", "application/json")
+			if tc.Token != "" {
+				req.Header.Set("Authorization", "Bearer "+tc.Token)
+			}
+			rr := httptest.NewRecorder()
+			router.ServeHTTP(rr, req)
+
+			assert.Equal(t, tc.Status, rr.Code)
+			assert.JSONEq(t, tc.Expected, rr.Body.String())
+		})
+	}
+}
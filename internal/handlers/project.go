@@ -0,0 +1,194 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"task-api/internal/auth"
+	"task-api/internal/domain/apikey"
+	"task-api/internal/domain/project"
+	"task-api/internal/domain/role"
+	"task-api/internal/domain/task"
+	projectService "task-api/internal/services/project"
+	taskService "task-api/internal/services/task"
+)
+
+type ProjectHandler struct {
+	Service projectService.Service
+	Tasks   taskService.Service
+	// Auth guards every project route.
+	Auth auth.Middleware
+}
+
+func NewProjectHandler(service projectService.Service, tasks taskService.Service, authMiddleware auth.Middleware) *ProjectHandler {
+	return &ProjectHandler{Service: service, Tasks: tasks, Auth: authMiddleware}
+}
+
+func (h *ProjectHandler) RegisterRoutes(router *gin.Engine) {
+	projects := router.Group("/projects", gin.HandlerFunc(h.Auth))
+
+	read := projects.Group("", auth.RequireScope(apikey.ScopeTasksRead))
+	read.GET("", h.GetProjects)
+	read.GET("/:id", h.GetProject)
+	read.GET("/:id/tasks", h.GetProjectTasks)
+
+	write := projects.Group("", auth.RequireScope(apikey.ScopeTasksWrite))
+	write.POST("", h.CreateProject)
+	write.PUT("/:id", h.UpdateProject)
+	write.POST("/:id/archive", h.ArchiveProject)
+	write.POST("/:id/unarchive", h.UnarchiveProject)
+	write.POST("/:id/tasks", h.CreateProjectTask)
+}
+
+// GetProjects lists the active projects, and archived ones too with
+// ?archived=true.
+func (h *ProjectHandler) GetProjects(c *gin.Context) {
+	includeArchived, _ := strconv.ParseBool(c.Query("archived"))
+	projects, err := h.Service.GetAllProjects(c.Request.Context(), includeArchived)
+	if err != nil {
+		projectError(c, err)
+		return
+	}
+	if projects == nil {
+		projects = []project.Project{}
+	}
+	c.JSON(http.StatusOK, projects)
+}
+
+func (h *ProjectHandler) GetProject(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+	p, err := h.Service.GetProject(c.Request.Context(), id)
+	if err != nil {
+		projectError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, p)
+}
+
+func (h *ProjectHandler) CreateProject(c *gin.Context) {
+	var p project.Project
+	if err := c.ShouldBindJSON(&p); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	created, err := h.Service.CreateProject(c.Request.Context(), p)
+	if err != nil {
+		projectError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, created)
+}
+
+func (h *ProjectHandler) UpdateProject(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+	var p project.Project
+	if err := c.ShouldBindJSON(&p); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	p.ID = id
+	updated, err := h.Service.UpdateProject(c.Request.Context(), p)
+	if err != nil {
+		projectError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, updated)
+}
+
+func (h *ProjectHandler) ArchiveProject(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+	p, err := h.Service.ArchiveProject(c.Request.Context(), id)
+	if err != nil {
+		projectError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, p)
+}
+
+func (h *ProjectHandler) UnarchiveProject(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+	p, err := h.Service.UnarchiveProject(c.Request.Context(), id)
+	if err != nil {
+		projectError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, p)
+}
+
+// GetProjectTasks lists the tasks of a project, including archived ones.
+func (h *ProjectHandler) GetProjectTasks(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+	tasks, err := h.Tasks.GetTasksByProject(c.Request.Context(), id)
+	if err != nil {
+		projectError(c, err)
+		return
+	}
+	if tasks == nil {
+		tasks = []task.Info{}
+	}
+	c.JSON(http.StatusOK, tasks)
+}
+
+// CreateProjectTask creates a task in the project of the path, whatever
+// project_id the body names.
+func (h *ProjectHandler) CreateProjectTask(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+	var t task.Info
+	if err := c.ShouldBindJSON(&t); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if _, err := h.Service.GetProject(c.Request.Context(), id); err != nil {
+		projectError(c, err)
+		return
+	}
+	t.ProjectID = id
+	created, err := h.Tasks.CreateTask(c.Request.Context(), t)
+	if err != nil {
+		taskError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, created)
+}
+
+func projectError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, project.ErrInvalidProject):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, project.ErrNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, project.ErrArchived), errors.Is(err, project.ErrDefaultProject):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case errors.Is(err, role.ErrForbidden):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
@@ -0,0 +1,208 @@
+package handlers
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"task-api/internal/auth"
+	"task-api/internal/config"
+	"task-api/internal/domain/apikey"
+	"task-api/internal/domain/project"
+	"task-api/internal/domain/task"
+	"task-api/internal/mocks"
+)
+
+func TestProjectHandler(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mocks.NewMockProjectService(ctrl)
+	mockTasks := mocks.NewMockService(ctrl)
+	handler := NewProjectHandler(mockService, mockTasks, noAuth)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.Default()
+	handler.RegisterRoutes(router)
+
+	archivedAt := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	website := project.Project{ID: 2, Name: "Website", TaskCount: 3, DoneCount: 1}
+	tests := []struct {
+		TestCase string
+		Method   string
+		URL      string
+		Body     string
+		Setup    func()
+		Status   int
+		Expected string
+	}{
+		{
+			TestCase: "List projects with archived ones",
+			Method:   http.MethodGet,
+			URL:      "/projects?archived=true",
+			Setup: func() {
+				mockService.EXPECT().GetAllProjects(gomock.Any(), true).Return([]project.Project{website}, nil)
+			},
+			Status:   http.StatusOK,
+			Expected: `[{"id":2,"name":"Website","task_count":3,"done_count":1}]`,
+		},
+		{
+			TestCase: "Create project",
+			Method:   http.MethodPost,
+			URL:      "/projects",
+			Body:     `{"name":"Website"}`,
+			Setup: func() {
+				mockService.EXPECT().CreateProject(gomock.Any(), project.Project{Name: "Website"}).Return(project.Project{ID: 2, Name: "Website"}, nil)
+			},
+			Status:   http.StatusCreated,
+			Expected: `{"id":2,"name":"Website","task_count":0,"done_count":0}`,
+		},
+		{
+			TestCase: "Create invalid project",
+			Method:   http.MethodPost,
+			URL:      "/projects",
+			Body:     `{"name":""}`,
+			Setup: func() {
+				mockService.EXPECT().CreateProject(gomock.Any(), project.Project{}).Return(project.Project{}, project.ErrInvalidProject)
+			},
+			Status:   http.StatusBadRequest,
+			Expected: `{"error":"invalid project"}`,
+		},
+		{
+			TestCase: "Get unknown project",
+			Method:   http.MethodGet,
+			URL:      "/projects/9",
+			Setup: func() {
+				mockService.EXPECT().GetProject(gomock.Any(), 9).Return(project.Project{}, project.ErrNotFound)
+			},
+			Status:   http.StatusNotFound,
+			Expected: `{"error":"project not found"}`,
+		},
+		{
+			TestCase: "Archive project",
+			Method:   http.MethodPost,
+			URL:      "/projects/2/archive",
+			Setup: func() {
+				mockService.EXPECT().ArchiveProject(gomock.Any(), 2).Return(project.Project{ID: 2, Name: "Website", ArchivedAt: &archivedAt}, nil)
+			},
+			Status:   http.StatusOK,
+			Expected: `{"id":2,"name":"Website","archived_at":"2024-03-01T12:00:00Z","task_count":0,"done_count":0}`,
+		},
+		{
+			TestCase: "Archive default project",
+			Method:   http.MethodPost,
+			URL:      "/projects/1/archive",
+			Setup: func() {
+				mockService.EXPECT().ArchiveProject(gomock.Any(), 1).Return(project.Project{}, project.ErrDefaultProject)
+			},
+			Status:   http.StatusConflict,
+			Expected: `{"error":"the default project cannot be archived"}`,
+		},
+		{
+			TestCase: "List project tasks",
+			Method:   http.MethodGet,
+			URL:      "/projects/2/tasks",
+			Setup: func() {
+				mockTasks.EXPECT().GetTasksByProject(gomock.Any(), 2).Return([]task.Info{{ID: 1, Name: "Landing page", ProjectID: 2}}, nil)
+			},
+			Status:   http.StatusOK,
+			Expected: `[{"id":1,"name":"Landing page","status":0,"project_id":2}]`,
+		},
+		{
+			TestCase: "Create task in project",
+			Method:   http.MethodPost,
+			URL:      "/projects/2/tasks",
+			Body:     `{"name":"Landing page","project_id":5}`,
+			Setup: func() {
+				mockService.EXPECT().GetProject(gomock.Any(), 2).Return(website, nil)
+				mockTasks.EXPECT().CreateTask(gomock.Any(), task.Info{Name: "Landing page", ProjectID: 2}).Return(task.Info{ID: 1, Name: "Landing page", ProjectID: 2}, nil)
+			},
+			Status:   http.StatusCreated,
+			Expected: `{"id":1,"name":"Landing page","status":0,"project_id":2}`,
+		},
+		{
+			TestCase: "Create task in unknown project",
+			Method:   http.MethodPost,
+			URL:      "/projects/9/tasks",
+			Body:     `{"name":"Landing page"}`,
+			Setup: func() {
+				mockService.EXPECT().GetProject(gomock.Any(), 9).Return(project.Project{}, project.ErrNotFound)
+			},
+			Status:   http.StatusNotFound,
+			Expected: `{"error":"project not found"}`,
+		},
+		{
+			TestCase: "Create task in archived project",
+			Method:   http.MethodPost,
+			URL:      "/projects/2/tasks",
+			Body:     `{"name":"Landing page"}`,
+			Setup: func() {
+				mockService.EXPECT().GetProject(gomock.Any(), 2).Return(project.Project{ID: 2, Name: "Website", ArchivedAt: &archivedAt}, nil)
+				mockTasks.EXPECT().CreateTask(gomock.Any(), task.Info{Name: "Landing page", ProjectID: 2}).Return(task.Info{}, project.ErrArchived)
+			},
+			Status:   http.StatusConflict,
+			Expected: `{"error":"project is archived"}`,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.TestCase, func(t *testing.T) {
+			tc.Setup()
+			req, _ := http.NewRequest(tc.Method, tc.URL, bytes.NewBufferString(tc.Body))
+			req.Header.Set("Content-Type", "application/json")
+			rr := httptest.NewRecorder()
+			router.ServeHTTP(rr, req)
+
+			assert.Equal(t, tc.Status, rr.Code)
+			assert.JSONEq(t, tc.Expected, rr.Body.String())
+		})
+	}
+}
+
+func TestProjectHandler_RequiresAuth(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockKeys := mocks.NewMockAPIKeyService(ctrl)
+	mockKeys.EXPECT().Verify("tk_read").Return(apikey.Key{ID: 3, Scopes: []string{apikey.ScopeTasksRead}}, nil).AnyTimes()
+	cfg := config.AuthConfig{StaticTokens: map[string]string{"secret": "alice"}}
+	authenticator, err := auth.NewAuthenticator(cfg, mockKeys)
+	assert.NoError(t, err)
+	handler := NewProjectHandler(mocks.NewMockProjectService(ctrl), mocks.NewMockService(ctrl), auth.NewMiddleware(authenticator, cfg))
+
+	gin.SetMode(gin.TestMode)
+	router := gin.Default()
+	handler.RegisterRoutes(router)
+
+	tests := []struct {
+		TestCase string
+		Method   string
+		URL      string
+		Token    string
+		Status   int
+	}{
+		{TestCase: "List without a token", Method: http.MethodGet, URL: "/projects", Status: http.StatusUnauthorized},
+		{TestCase: "Archive without a token", Method: http.MethodPost, URL: "/projects/2/archive", Status: http.StatusUnauthorized},
+		{TestCase: "Create with a read-only API key", Method: http.MethodPost, URL: "/projects", Token: "tk_read", Status: http.StatusForbidden},
+		{TestCase: "Archive with a read-only API key", Method: http.MethodPost, URL: "/projects/2/archive", Token: "tk_read", Status: http.StatusForbidden},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.TestCase, func(t *testing.T) {
+			req, _ := http.NewRequest(tc.Method, tc.URL, bytes.NewBufferString(`{"name":"Website"}`))
+			req.Header.Set("Content-Type", "application/json")
+			if tc.Token != "" {
+				req.Header.Set("Authorization", "Bearer "+tc.Token)
+			}
+			rr := httptest.NewRecorder()
+			router.ServeHTTP(rr, req)
+			assert.Equal(t, tc.Status, rr.Code)
+		})
+	}
+}
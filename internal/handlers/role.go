@@ -0,0 +1,107 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"task-api/internal/auth"
+	"task-api/internal/config"
+	"task-api/internal/domain/project"
+	"task-api/internal/domain/role"
+	"task-api/internal/domain/user"
+	roleService "task-api/internal/services/role"
+)
+
+type RoleHandler struct {
+	Service roleService.Service
+	// Auth and Admin guard every route: only admin users assign roles.
+	Auth  auth.Middleware
+	Admin gin.HandlerFunc
+}
+
+// roleRequest assigns a role globally when project_id is missing.
+type roleRequest struct {
+	Role      role.Role `json:"role" binding:"required"`
+	ProjectID int       `json:"project_id"`
+}
+
+func NewRoleHandler(service roleService.Service, authMiddleware auth.Middleware, cfg config.AuthConfig) *RoleHandler {
+	return &RoleHandler{Service: service, Auth: authMiddleware, Admin: auth.RequireAdmin(cfg)}
+}
+
+func (h *RoleHandler) RegisterRoutes(router *gin.Engine) {
+	roles := router.Group("/users/:id/roles", gin.HandlerFunc(h.Auth), h.Admin)
+	roles.GET("", h.GetRoles)
+	roles.PUT("", h.AssignRole)
+	roles.DELETE("", h.UnassignRole)
+}
+
+func (h *RoleHandler) GetRoles(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+	assignments, err := h.Service.GetRoles(id)
+	if err != nil {
+		roleError(c, err)
+		return
+	}
+	if assignments == nil {
+		assignments = []role.Assignment{}
+	}
+	c.JSON(http.StatusOK, assignments)
+}
+
+func (h *RoleHandler) AssignRole(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+	var req roleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	assignment, err := h.Service.AssignRole(role.Assignment{UserID: id, ProjectID: req.ProjectID, Role: req.Role})
+	if err != nil {
+		roleError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, assignment)
+}
+
+// UnassignRole removes the global role of a user, or with ?project_id= the
+// role in that project.
+func (h *RoleHandler) UnassignRole(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+	projectID, err := strconv.Atoi(c.DefaultQuery("project_id", "0"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid project ID"})
+		return
+	}
+	if err := h.Service.UnassignRole(id, projectID); err != nil {
+		roleError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Role removed successfully"})
+}
+
+func roleError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, role.ErrInvalidRole):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, role.ErrNotFound), errors.Is(err, user.ErrNotFound), errors.Is(err, project.ErrNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
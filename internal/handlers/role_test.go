@@ -0,0 +1,143 @@
+package handlers
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"task-api/internal/auth"
+	"task-api/internal/config"
+	"task-api/internal/domain/role"
+	"task-api/internal/domain/user"
+	"task-api/internal/mocks"
+)
+
+func TestRoleHandler(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mocks.NewMockRoleService(ctrl)
+	cfg := config.AuthConfig{
+		StaticTokens:  map[string]string{"root": "alice", "secret": "board"},
+		AdminSubjects: []string{"alice"},
+	}
+	handler := NewRoleHandler(mockService, auth.NewMiddleware(auth.NewStaticTokenAuthenticator(cfg), cfg), cfg)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.Default()
+	handler.RegisterRoutes(router)
+
+	tests := []struct {
+		TestCase string
+		Method   string
+		URL      string
+		Token    string
+		Body     string
+		Expected string
+		Setup    func()
+		Status   int
+	}{
+		{
+			TestCase: "Assign a project role",
+			Method:   http.MethodPut,
+			URL:      "/users/2/roles",
+			Token:    "root",
+			Body:     `{"role":"editor","project_id":3}`,
+			Expected: `{"user_id":2,"project_id":3,"role":"editor"}`,
+			Setup: func() {
+				mockService.EXPECT().AssignRole(role.Assignment{UserID: 2, ProjectID: 3, Role: role.Editor}).
+					Return(role.Assignment{UserID: 2, ProjectID: 3, Role: role.Editor}, nil)
+			},
+			Status: http.StatusOK,
+		},
+		{
+			TestCase: "Assign an unknown role",
+			Method:   http.MethodPut,
+			URL:      "/users/2/roles",
+			Token:    "root",
+			Body:     `{"role":"owner"}`,
+			Expected: `{"error":"invalid role: \"owner\" is not one of viewer, editor and admin"}`,
+			Setup: func() {
+				mockService.EXPECT().AssignRole(role.Assignment{UserID: 2, Role: "owner"}).
+					Return(role.Assignment{}, fmt.Errorf("%w: %q is not one of viewer, editor and admin", role.ErrInvalidRole, "owner"))
+			},
+			Status: http.StatusBadRequest,
+		},
+		{
+			TestCase: "List roles",
+			Method:   http.MethodGet,
+			URL:      "/users/2/roles",
+			Token:    "root",
+			Expected: `[{"user_id":2,"role":"viewer"}]`,
+			Setup: func() {
+				mockService.EXPECT().GetRoles(2).Return([]role.Assignment{{UserID: 2, Role: role.Viewer}}, nil)
+			},
+			Status: http.StatusOK,
+		},
+		{
+			TestCase: "List roles of a missing user",
+			Method:   http.MethodGet,
+			URL:      "/users/9/roles",
+			Token:    "root",
+			Expected: `{"error":"user not found"}`,
+			Setup: func() {
+				mockService.EXPECT().GetRoles(9).Return(nil, user.ErrNotFound)
+			},
+			Status: http.StatusNotFound,
+		},
+		{
+			TestCase: "Remove a project role",
+			Method:   http.MethodDelete,
+			URL:      "/users/2/roles?project_id=3",
+			Token:    "root",
+			Expected: `{"message":"Role removed successfully"}`,
+			Setup: func() {
+				mockService.EXPECT().UnassignRole(2, 3).Return(nil)
+			},
+			Status: http.StatusOK,
+		},
+		{
+			TestCase: "Remove a missing global role",
+			Method:   http.MethodDelete,
+			URL:      "/users/2/roles",
+			Token:    "root",
+			Expected: `{"error":"role assignment not found"}`,
+			Setup: func() {
+				mockService.EXPECT().UnassignRole(2, 0).Return(role.ErrNotFound)
+			},
+			Status: http.StatusNotFound,
+		},
+		{
+			TestCase: "Users who are no admins are refused",
+			Method:   http.MethodPut,
+			URL:      "/users/2/roles",
+			Token:    "secret",
+			Body:     `{"role":"admin"}`,
+			Expected: `{"error":"admin access required"}`,
+			Setup:    func() {},
+			Status:   http.StatusForbidden,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.TestCase, func(t *testing.T) {
+			tc.Setup()
+			req, _ := http.NewRequest(tc.Method, tc.URL, bytes.NewBufferString(tc.Body))
+			req.Header.Set("Content-Type", "application/json")
+			if tc.Token != "" {
+				req.Header.Set("Authorization", "Bearer "+tc.Token)
+			}
+			rr := httptest.NewRecorder()
+			router.ServeHTTP(rr, req)
+
+			assert.Equal(t, tc.Status, rr.Code)
+			assert.JSONEq(t, tc.Expected, rr.Body.String())
+		})
+	}
+}
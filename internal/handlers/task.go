@@ -1,54 +1,297 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 
+	"task-api/internal/auth"
+	"task-api/internal/domain/apikey"
+	"task-api/internal/domain/label"
+	"task-api/internal/domain/project"
+	"task-api/internal/domain/role"
 	"task-api/internal/domain/task"
 	taskService "task-api/internal/services/task"
 )
 
 type TaskHandler struct {
 	Service taskService.Service
+	// Auth guards every task route.
+	Auth auth.Middleware
 }
 
-func NewTaskHandler(service taskService.Service) *TaskHandler {
-	return &TaskHandler{Service: service}
+type blockerRequest struct {
+	BlockerID int `json:"blocker_id" binding:"required"`
 }
 
+type labelRequest struct {
+	LabelID int `json:"label_id" binding:"required"`
+}
+
+// assignRequest unassigns the task when assignee_id is null or missing.
+type assignRequest struct {
+	AssigneeID *int `json:"assignee_id"`
+}
+
+func NewTaskHandler(service taskService.Service, authMiddleware auth.Middleware) *TaskHandler {
+	return &TaskHandler{Service: service, Auth: authMiddleware}
+}
+
+// RegisterRoutes guards the task routes with the auth middleware. API keys
+// need the tasks:read scope to read and tasks:write to change tasks.
 func (h *TaskHandler) RegisterRoutes(router *gin.Engine) {
-	router.GET("/tasks", h.GetTasks)
-	router.GET("/tasks/:id", h.GetTask)
-	router.POST("/tasks", h.CreateTask)
-	router.PUT("/tasks/:id", h.UpdateTask)
-	router.DELETE("/tasks/:id", h.DeleteTask)
+	tasks := router.Group("/tasks", gin.HandlerFunc(h.Auth))
+
+	read := tasks.Group("", auth.RequireScope(apikey.ScopeTasksRead))
+	read.GET("", h.GetTasks)
+	read.GET("/:id", h.GetTask)
+	read.GET("/order", h.GetExecutionOrder)
+	read.GET("/search", h.SearchTasks)
+	read.GET("/:id/children", h.GetChildren)
+	read.GET("/:id/occurrences", h.GetOccurrences)
+	read.GET("/:id/blockers", h.GetBlockers)
+
+	write := tasks.Group("", auth.RequireScope(apikey.ScopeTasksWrite))
+	write.POST("/:id/blockers", h.AddBlocker)
+	write.DELETE("/:id/blockers/:blockerId", h.RemoveBlocker)
+	write.POST("/:id/labels", h.AttachLabel)
+	write.DELETE("/:id/labels/:labelId", h.DetachLabel)
+	write.POST("/:id/assign", h.AssignTask)
+	write.POST("", h.CreateTask)
+	write.PUT("/:id", h.UpdateTask)
+	write.DELETE("/:id", h.DeleteTask)
 }
 
+// GetTasks lists all tasks, or with ?labels=a,b only those carrying any
+// (match=any, the default) or all (match=all) of the labels.
 func (h *TaskHandler) GetTasks(c *gin.Context) {
-	tasks, err := h.Service.GetAllTasks()
+	var tasks []task.Info
+	var err error
+	if names := splitList(c.Query("labels")); len(names) > 0 {
+		match := label.Match(c.DefaultQuery("match", string(label.MatchAny)))
+		tasks, err = h.Service.GetTasksByLabels(c.Request.Context(), names, match)
+	} else {
+		tasks, err = h.Service.GetAllTasks(c.Request.Context())
+	}
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		taskError(c, err)
 		return
 	}
+	if tasks == nil {
+		tasks = []task.Info{}
+	}
 	c.JSON(http.StatusOK, tasks)
 }
 
+// SearchTasks finds tasks by words in their names with ?q=, which takes
+// words, prefixes such as deploy* and "quoted phrases".
+func (h *TaskHandler) SearchTasks(c *gin.Context) {
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "0"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid limit"})
+		return
+	}
+	results, err := h.Service.SearchTasks(c.Request.Context(), c.Query("q"), limit)
+	if err != nil {
+		taskError(c, err)
+		return
+	}
+	if results == nil {
+		results = []task.SearchResult{}
+	}
+	c.JSON(http.StatusOK, results)
+}
+
 func (h *TaskHandler) GetTask(c *gin.Context) {
 	id, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
 		return
 	}
-	task, err := h.Service.GetTaskByID(id)
+	task, err := h.Service.GetTaskByID(c.Request.Context(), id)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		taskError(c, err)
 		return
 	}
 	c.JSON(http.StatusOK, task)
 }
 
+// GetChildren lists direct subtasks, or the whole subtree breadth-first when
+// recursive=true.
+func (h *TaskHandler) GetChildren(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+	recursive, _ := strconv.ParseBool(c.Query("recursive"))
+	children, err := h.Service.GetChildren(c.Request.Context(), id, recursive)
+	if err != nil {
+		taskError(c, err)
+		return
+	}
+	if children == nil {
+		children = []task.Info{}
+	}
+	c.JSON(http.StatusOK, children)
+}
+
+// GetOccurrences previews the due dates of a recurring task between the
+// RFC 3339 times ?from= (default now) and ?to= (default open).
+func (h *TaskHandler) GetOccurrences(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+	from := time.Now()
+	if raw := c.Query("from"); raw != "" {
+		if from, err = time.Parse(time.RFC3339, raw); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid from"})
+			return
+		}
+	}
+	var to time.Time
+	if raw := c.Query("to"); raw != "" {
+		if to, err = time.Parse(time.RFC3339, raw); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid to"})
+			return
+		}
+	}
+	occurrences, err := h.Service.GetOccurrences(c.Request.Context(), id, from, to)
+	if err != nil {
+		taskError(c, err)
+		return
+	}
+	if occurrences == nil {
+		occurrences = []time.Time{}
+	}
+	c.JSON(http.StatusOK, occurrences)
+}
+
+func (h *TaskHandler) GetBlockers(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+	blockers, err := h.Service.GetBlockers(c.Request.Context(), id)
+	if err != nil {
+		taskError(c, err)
+		return
+	}
+	if blockers == nil {
+		blockers = []task.Info{}
+	}
+	c.JSON(http.StatusOK, blockers)
+}
+
+func (h *TaskHandler) AddBlocker(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+	var req blockerRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err := h.Service.AddBlocker(c.Request.Context(), id, req.BlockerID); err != nil {
+		taskError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, task.Dependency{BlockerID: req.BlockerID, BlockedID: id})
+}
+
+func (h *TaskHandler) RemoveBlocker(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+	blockerID, err := strconv.Atoi(c.Param("blockerId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid blocker ID"})
+		return
+	}
+	if err := h.Service.RemoveBlocker(c.Request.Context(), id, blockerID); err != nil {
+		taskError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Blocker removed successfully"})
+}
+
+func (h *TaskHandler) GetExecutionOrder(c *gin.Context) {
+	tasks, err := h.Service.GetExecutionOrder(c.Request.Context())
+	if err != nil {
+		taskError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, tasks)
+}
+
+func (h *TaskHandler) AttachLabel(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+	var req labelRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	t, err := h.Service.AttachLabel(c.Request.Context(), id, req.LabelID)
+	if err != nil {
+		taskError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, t)
+}
+
+func (h *TaskHandler) DetachLabel(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+	labelID, err := strconv.Atoi(c.Param("labelId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid label ID"})
+		return
+	}
+	t, err := h.Service.DetachLabel(c.Request.Context(), id, labelID)
+	if err != nil {
+		taskError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, t)
+}
+
+func (h *TaskHandler) AssignTask(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+	var req assignRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	t, err := h.Service.AssignTask(c.Request.Context(), id, req.AssigneeID)
+	if err != nil {
+		taskError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, t)
+}
+
 func (h *TaskHandler) CreateTask(c *gin.Context) {
 	var t task.Info
 	if err := c.ShouldBindJSON(&t); err != nil {
@@ -56,9 +299,9 @@ func (h *TaskHandler) CreateTask(c *gin.Context) {
 		return
 	}
 
-	createdTask, err := h.Service.CreateTask(t)
+	createdTask, err := h.Service.CreateTask(c.Request.Context(), t)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		taskError(c, err)
 		return
 	}
 	c.JSON(http.StatusOK, createdTask)
@@ -78,9 +321,9 @@ func (h *TaskHandler) UpdateTask(c *gin.Context) {
 	}
 
 	t.ID = id
-	updatedTask, err := h.Service.UpdateTask(t)
+	updatedTask, err := h.Service.UpdateTask(c.Request.Context(), t)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		taskError(c, err)
 		return
 	}
 	c.JSON(http.StatusOK, updatedTask)
@@ -92,9 +335,37 @@ func (h *TaskHandler) DeleteTask(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
 		return
 	}
-	if err := h.Service.DeleteTask(id); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := h.Service.DeleteTask(c.Request.Context(), id); err != nil {
+		taskError(c, err)
 		return
 	}
 	c.JSON(http.StatusOK, gin.H{"message": "Task deleted successfully"})
 }
+
+func taskError(c *gin.Context, err error) {
+	switch {
+	case task.IsInvalid(err), errors.Is(err, task.ErrNotRecurring), errors.Is(err, label.ErrInvalidMatch):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, task.ErrNotFound), errors.Is(err, task.ErrDependencyNotFound),
+		errors.Is(err, label.ErrNotFound), errors.Is(err, label.ErrNotAttached), errors.Is(err, project.ErrNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, task.ErrBlocked), errors.Is(err, project.ErrArchived):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case errors.Is(err, role.ErrForbidden):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	case errors.Is(err, task.ErrEncrypted):
+		c.JSON(http.StatusNotImplemented, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
+
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
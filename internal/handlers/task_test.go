@@ -3,15 +3,23 @@ package handlers
 import (
 	"bytes"
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"strconv"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
 
+	"task-api/internal/auth"
+	"task-api/internal/config"
+	"task-api/internal/domain/apikey"
+	"task-api/internal/domain/label"
+	"task-api/internal/domain/role"
 	"task-api/internal/domain/task"
 	"task-api/internal/mocks"
 )
@@ -21,7 +29,7 @@ func TestTaskHandler_GetTasks(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockService := mocks.NewMockService(ctrl)
-	handler := NewTaskHandler(mockService)
+	handler := NewTaskHandler(mockService, noAuth)
 
 	gin.SetMode(gin.TestMode)
 	router := gin.Default()
@@ -40,7 +48,7 @@ func TestTaskHandler_GetTasks(t *testing.T) {
 				{ID: 2, Name: "Test Task 2", Status: 1},
 			},
 			Setup: func() {
-				mockService.EXPECT().GetAllTasks().Return([]task.Info{
+				mockService.EXPECT().GetAllTasks(gomock.Any()).Return([]task.Info{
 					{ID: 1, Name: "Test Task 1", Status: 0},
 					{ID: 2, Name: "Test Task 2", Status: 1},
 				}, nil)
@@ -65,12 +73,83 @@ func TestTaskHandler_GetTasks(t *testing.T) {
 	}
 }
 
+// noAuth lets every request through, for tests that are not about
+// authentication.
+var noAuth auth.Middleware = func(c *gin.Context) { c.Next() }
+
+func TestTaskHandler_RequiresAuth(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mocks.NewMockService(ctrl)
+	cfg := config.AuthConfig{StaticTokens: map[string]string{"secret": "board"}}
+	handler := NewTaskHandler(mockService, auth.NewMiddleware(auth.NewStaticTokenAuthenticator(cfg), cfg))
+
+	gin.SetMode(gin.TestMode)
+	router := gin.Default()
+	handler.RegisterRoutes(router)
+
+	for _, route := range router.Routes() {
+		req, _ := http.NewRequest(route.Method, strings.ReplaceAll(strings.ReplaceAll(route.Path, ":id", "1"), ":", ""), nil)
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, req)
+		assert.Equal(t, http.StatusUnauthorized, rr.Code, route.Method+" "+route.Path)
+	}
+
+	mockService.EXPECT().DeleteTask(gomock.Any(), 1).Return(nil)
+	req, _ := http.NewRequest(http.MethodDelete, "/tasks/1", nil)
+	req.Header.Set("Authorization", "Bearer secret")
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusOK, rr.Code)
+}
+
+func TestTaskHandler_APIKeyScopes(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mocks.NewMockService(ctrl)
+	mockKeys := mocks.NewMockAPIKeyService(ctrl)
+	cfg := config.AuthConfig{}
+	authenticator, err := auth.NewAuthenticator(cfg, mockKeys)
+	assert.NoError(t, err)
+	handler := NewTaskHandler(mockService, auth.NewMiddleware(authenticator, cfg))
+
+	gin.SetMode(gin.TestMode)
+	router := gin.Default()
+	handler.RegisterRoutes(router)
+
+	readOnly := apikey.Key{ID: 3, Scopes: []string{apikey.ScopeTasksRead}}
+	mockKeys.EXPECT().Verify("tk_read").Return(readOnly, nil).AnyTimes()
+
+	mockService.EXPECT().GetTaskByID(gomock.Any(), 1).Return(task.Info{ID: 1, Name: "Test Task"}, nil)
+	req, _ := http.NewRequest(http.MethodGet, "/tasks/1", nil)
+	req.Header.Set("Authorization", "Bearer tk_read")
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	req, _ = http.NewRequest(http.MethodDelete, "/tasks/1", nil)
+	req.Header.Set("Authorization", "Bearer tk_read")
+	rr = httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusForbidden, rr.Code)
+	assert.JSONEq(t, `{"error":"api key lacks scope tasks:write"}`, rr.Body.String())
+
+	mockKeys.EXPECT().Verify("tk_gone").Return(apikey.Key{}, apikey.ErrRevoked)
+	req, _ = http.NewRequest(http.MethodGet, "/tasks/1", nil)
+	req.Header.Set("Authorization", "Bearer tk_gone")
+	rr = httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusUnauthorized, rr.Code)
+}
+
 func TestTaskHandler_GetTask(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
 	mockService := mocks.NewMockService(ctrl)
-	handler := NewTaskHandler(mockService)
+	handler := NewTaskHandler(mockService, noAuth)
 
 	gin.SetMode(gin.TestMode)
 	router := gin.Default()
@@ -88,7 +167,7 @@ func TestTaskHandler_GetTask(t *testing.T) {
 			ID:       1,
 			Expected: task.Info{ID: 1, Name: "Test Task", Status: 0},
 			Setup: func() {
-				mockService.EXPECT().GetTaskByID(1).Return(task.Info{ID: 1, Name: "Test Task", Status: 0}, nil)
+				mockService.EXPECT().GetTaskByID(gomock.Any(), 1).Return(task.Info{ID: 1, Name: "Test Task", Status: 0}, nil)
 			},
 			Status: http.StatusOK,
 		},
@@ -110,12 +189,482 @@ func TestTaskHandler_GetTask(t *testing.T) {
 	}
 }
 
+func TestTaskHandler_GetChildren(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mocks.NewMockService(ctrl)
+	handler := NewTaskHandler(mockService, noAuth)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.Default()
+	router.GET("/tasks/:id/children", handler.GetChildren)
+
+	parentID := 1
+	tests := []struct {
+		TestCase string
+		URL      string
+		Expected []task.Info
+		Setup    func()
+		Status   int
+	}{
+		{
+			TestCase: "Get direct children",
+			URL:      "/tasks/1/children",
+			Expected: []task.Info{{ID: 2, Name: "Child", Status: 0, ParentID: &parentID}},
+			Setup: func() {
+				mockService.EXPECT().GetChildren(gomock.Any(), 1, false).Return([]task.Info{{ID: 2, Name: "Child", Status: 0, ParentID: &parentID}}, nil)
+			},
+			Status: http.StatusOK,
+		},
+		{
+			TestCase: "Get subtree",
+			URL:      "/tasks/1/children?recursive=true",
+			Expected: []task.Info{},
+			Setup: func() {
+				mockService.EXPECT().GetChildren(gomock.Any(), 1, true).Return(nil, nil)
+			},
+			Status: http.StatusOK,
+		},
+		{
+			TestCase: "Missing parent",
+			URL:      "/tasks/9/children",
+			Expected: nil,
+			Setup: func() {
+				mockService.EXPECT().GetChildren(gomock.Any(), 9, false).Return(nil, task.ErrNotFound)
+			},
+			Status: http.StatusNotFound,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.TestCase, func(t *testing.T) {
+			tc.Setup()
+			req, _ := http.NewRequest(http.MethodGet, tc.URL, nil)
+			rr := httptest.NewRecorder()
+			router.ServeHTTP(rr, req)
+
+			assert.Equal(t, tc.Status, rr.Code)
+			if tc.Status != http.StatusOK {
+				return
+			}
+			var response []task.Info
+			err := json.Unmarshal(rr.Body.Bytes(), &response)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.Expected, response)
+		})
+	}
+}
+
+func TestTaskHandler_GetOccurrences(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mocks.NewMockService(ctrl)
+	handler := NewTaskHandler(mockService, noAuth)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.Default()
+	router.GET("/tasks/:id/occurrences", handler.GetOccurrences)
+
+	berlin, _ := time.LoadLocation("Europe/Berlin")
+	from := time.Date(2024, 3, 25, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2024, 4, 8, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		TestCase string
+		URL      string
+		Setup    func()
+		Status   int
+		Expected string
+	}{
+		{
+			TestCase: "Occurrences in a range",
+			URL:      "/tasks/1/occurrences?from=2024-03-25T00:00:00Z&to=2024-04-08T00:00:00Z",
+			Setup: func() {
+				mockService.EXPECT().GetOccurrences(gomock.Any(), 1, from, to).Return([]time.Time{
+					time.Date(2024, 3, 25, 9, 0, 0, 0, berlin),
+					time.Date(2024, 4, 1, 9, 0, 0, 0, berlin),
+				}, nil)
+			},
+			Status:   http.StatusOK,
+			Expected: `["2024-03-25T09:00:00+01:00","2024-04-01T09:00:00+02:00"]`,
+		},
+		{
+			TestCase: "Invalid from",
+			URL:      "/tasks/1/occurrences?from=monday",
+			Setup:    func() {},
+			Status:   http.StatusBadRequest,
+			Expected: `{"error":"Invalid from"}`,
+		},
+		{
+			TestCase: "Task without recurrence",
+			URL:      "/tasks/2/occurrences?from=2024-03-25T00:00:00Z",
+			Setup: func() {
+				mockService.EXPECT().GetOccurrences(gomock.Any(), 2, from, time.Time{}).Return(nil, task.ErrNotRecurring)
+			},
+			Status:   http.StatusBadRequest,
+			Expected: `{"error":"task does not recur"}`,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.TestCase, func(t *testing.T) {
+			tc.Setup()
+			req, _ := http.NewRequest(http.MethodGet, tc.URL, nil)
+			rr := httptest.NewRecorder()
+			router.ServeHTTP(rr, req)
+
+			assert.Equal(t, tc.Status, rr.Code)
+			assert.JSONEq(t, tc.Expected, rr.Body.String())
+		})
+	}
+}
+
+func TestTaskHandler_SearchTasks(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mocks.NewMockService(ctrl)
+	handler := NewTaskHandler(mockService, noAuth)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.Default()
+	router.GET("/tasks/search", handler.SearchTasks)
+	router.GET("/tasks/:id", handler.GetTask)
+
+	tests := []struct {
+		TestCase string
+		URL      string
+		Setup    func()
+		Status   int
+		Expected string
+	}{
+		{
+			TestCase: "Matching tasks",
+			URL:      "/tasks/search?q=login&limit=5",
+			Setup: func() {
+				mockService.EXPECT().SearchTasks(gomock.Any(), "login", 5).Return([]task.SearchResult{
+					{Task: task.Info{ID: 1, Name: "Fix login"}, Score: 1.5, Snippet: "Fix <mark>login</mark>"},
+				}, nil)
+			},
+			Status:   http.StatusOK,
+			Expected: `[{"task":{"id":1,"name":"Fix login","status":0},"score":1.5,"snippet":"Fix \u003cmark\u003elogin\u003c/mark\u003e"}]`,
+		},
+		{
+			TestCase: "No matches",
+			URL:      "/tasks/search?q=nothing",
+			Setup: func() {
+				mockService.EXPECT().SearchTasks(gomock.Any(), "nothing", 0).Return(nil, nil)
+			},
+			Status:   http.StatusOK,
+			Expected: `[]`,
+		},
+		{
+			TestCase: "Empty query",
+			URL:      "/tasks/search",
+			Setup: func() {
+				mockService.EXPECT().SearchTasks(gomock.Any(), "", 0).Return(nil, fmt.Errorf("%w: search query has no words", task.ErrInvalidSearch))
+			},
+			Status:   http.StatusBadRequest,
+			Expected: `{"error":"invalid search: search query has no words"}`,
+		},
+		{
+			TestCase: "Encrypted names",
+			URL:      "/tasks/search?q=login",
+			Setup: func() {
+				mockService.EXPECT().SearchTasks(gomock.Any(), "login", 0).Return(nil, task.ErrEncrypted)
+			},
+			Status:   http.StatusNotImplemented,
+			Expected: `{"error":"task names are stored encrypted and cannot be searched"}`,
+		},
+		{
+			TestCase: "Invalid limit",
+			URL:      "/tasks/search?q=login&limit=all",
+			Setup:    func() {},
+			Status:   http.StatusBadRequest,
+			Expected: `{"error":"Invalid limit"}`,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.TestCase, func(t *testing.T) {
+			tc.Setup()
+			req, _ := http.NewRequest(http.MethodGet, tc.URL, nil)
+			rr := httptest.NewRecorder()
+			router.ServeHTTP(rr, req)
+
+			assert.Equal(t, tc.Status, rr.Code)
+			assert.JSONEq(t, tc.Expected, rr.Body.String())
+		})
+	}
+}
+
+func TestTaskHandler_Blockers(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mocks.NewMockService(ctrl)
+	handler := NewTaskHandler(mockService, noAuth)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.Default()
+	handler.RegisterRoutes(router)
+
+	tests := []struct {
+		TestCase string
+		Method   string
+		URL      string
+		Body     string
+		Setup    func()
+		Status   int
+		Expected string
+	}{
+		{
+			TestCase: "List blockers",
+			Method:   http.MethodGet,
+			URL:      "/tasks/2/blockers",
+			Setup: func() {
+				mockService.EXPECT().GetBlockers(gomock.Any(), 2).Return([]task.Info{{ID: 1, Name: "Design", Status: 0}}, nil)
+			},
+			Status:   http.StatusOK,
+			Expected: `[{"id":1,"name":"Design","status":0}]`,
+		},
+		{
+			TestCase: "Add blocker",
+			Method:   http.MethodPost,
+			URL:      "/tasks/2/blockers",
+			Body:     `{"blocker_id":1}`,
+			Setup: func() {
+				mockService.EXPECT().AddBlocker(gomock.Any(), 2, 1).Return(nil)
+			},
+			Status:   http.StatusCreated,
+			Expected: `{"blocker_id":1,"blocked_id":2}`,
+		},
+		{
+			TestCase: "Add blocker closing a cycle",
+			Method:   http.MethodPost,
+			URL:      "/tasks/1/blockers",
+			Body:     `{"blocker_id":2}`,
+			Setup: func() {
+				mockService.EXPECT().AddBlocker(gomock.Any(), 1, 2).Return(task.ErrDependencyCycle)
+			},
+			Status:   http.StatusBadRequest,
+			Expected: `{"error":"dependency would create a cycle"}`,
+		},
+		{
+			TestCase: "Remove missing blocker",
+			Method:   http.MethodDelete,
+			URL:      "/tasks/2/blockers/3",
+			Setup: func() {
+				mockService.EXPECT().RemoveBlocker(gomock.Any(), 2, 3).Return(task.ErrDependencyNotFound)
+			},
+			Status:   http.StatusNotFound,
+			Expected: `{"error":"dependency not found"}`,
+		},
+		{
+			TestCase: "Execution order",
+			Method:   http.MethodGet,
+			URL:      "/tasks/order",
+			Setup: func() {
+				mockService.EXPECT().GetExecutionOrder(gomock.Any()).Return([]task.Info{{ID: 1, Name: "Design"}, {ID: 2, Name: "Build"}}, nil)
+			},
+			Status:   http.StatusOK,
+			Expected: `[{"id":1,"name":"Design","status":0},{"id":2,"name":"Build","status":0}]`,
+		},
+		{
+			TestCase: "Complete a blocked task",
+			Method:   http.MethodPut,
+			URL:      "/tasks/2",
+			Body:     `{"name":"Build","status":1}`,
+			Setup: func() {
+				mockService.EXPECT().UpdateTask(gomock.Any(), task.Info{ID: 2, Name: "Build", Status: 1}).Return(task.Info{}, task.ErrBlocked)
+			},
+			Status:   http.StatusConflict,
+			Expected: `{"error":"task has open blockers"}`,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.TestCase, func(t *testing.T) {
+			tc.Setup()
+			req, _ := http.NewRequest(tc.Method, tc.URL, bytes.NewBufferString(tc.Body))
+			req.Header.Set("Content-Type", "application/json")
+			rr := httptest.NewRecorder()
+			router.ServeHTTP(rr, req)
+
+			assert.Equal(t, tc.Status, rr.Code)
+			assert.JSONEq(t, tc.Expected, rr.Body.String())
+		})
+	}
+}
+
+func TestTaskHandler_Labels(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mocks.NewMockService(ctrl)
+	handler := NewTaskHandler(mockService, noAuth)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.Default()
+	handler.RegisterRoutes(router)
+
+	tests := []struct {
+		TestCase string
+		Method   string
+		URL      string
+		Body     string
+		Setup    func()
+		Status   int
+		Expected string
+	}{
+		{
+			TestCase: "Filter by any label",
+			Method:   http.MethodGet,
+			URL:      "/tasks?labels=backend,%20urgent",
+			Setup: func() {
+				mockService.EXPECT().GetTasksByLabels(gomock.Any(), []string{"backend", "urgent"}, label.MatchAny).
+					Return([]task.Info{{ID: 1, Name: "API", Labels: []string{"backend"}}}, nil)
+			},
+			Status:   http.StatusOK,
+			Expected: `[{"id":1,"name":"API","status":0,"labels":["backend"]}]`,
+		},
+		{
+			TestCase: "Filter by all labels without matches",
+			Method:   http.MethodGet,
+			URL:      "/tasks?labels=backend,urgent&match=all",
+			Setup: func() {
+				mockService.EXPECT().GetTasksByLabels(gomock.Any(), []string{"backend", "urgent"}, label.MatchAll).Return(nil, nil)
+			},
+			Status:   http.StatusOK,
+			Expected: `[]`,
+		},
+		{
+			TestCase: "Invalid match",
+			Method:   http.MethodGet,
+			URL:      "/tasks?labels=backend&match=some",
+			Setup: func() {
+				mockService.EXPECT().GetTasksByLabels(gomock.Any(), []string{"backend"}, label.Match("some")).Return(nil, label.ErrInvalidMatch)
+			},
+			Status:   http.StatusBadRequest,
+			Expected: `{"error":"match must be any or all"}`,
+		},
+		{
+			TestCase: "Attach label",
+			Method:   http.MethodPost,
+			URL:      "/tasks/1/labels",
+			Body:     `{"label_id":2}`,
+			Setup: func() {
+				mockService.EXPECT().AttachLabel(gomock.Any(), 1, 2).Return(task.Info{ID: 1, Name: "API", Labels: []string{"urgent"}}, nil)
+			},
+			Status:   http.StatusOK,
+			Expected: `{"id":1,"name":"API","status":0,"labels":["urgent"]}`,
+		},
+		{
+			TestCase: "Attach unknown label",
+			Method:   http.MethodPost,
+			URL:      "/tasks/1/labels",
+			Body:     `{"label_id":9}`,
+			Setup: func() {
+				mockService.EXPECT().AttachLabel(gomock.Any(), 1, 9).Return(task.Info{}, label.ErrNotFound)
+			},
+			Status:   http.StatusNotFound,
+			Expected: `{"error":"label not found"}`,
+		},
+		{
+			TestCase: "Detach label",
+			Method:   http.MethodDelete,
+			URL:      "/tasks/1/labels/2",
+			Setup: func() {
+				mockService.EXPECT().DetachLabel(gomock.Any(), 1, 2).Return(task.Info{ID: 1, Name: "API"}, nil)
+			},
+			Status:   http.StatusOK,
+			Expected: `{"id":1,"name":"API","status":0}`,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.TestCase, func(t *testing.T) {
+			tc.Setup()
+			req, _ := http.NewRequest(tc.Method, tc.URL, bytes.NewBufferString(tc.Body))
+			req.Header.Set("Content-Type", "application/json")
+			rr := httptest.NewRecorder()
+			router.ServeHTTP(rr, req)
+
+			assert.Equal(t, tc.Status, rr.Code)
+			assert.JSONEq(t, tc.Expected, rr.Body.String())
+		})
+	}
+}
+
+func TestTaskHandler_AssignTask(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mocks.NewMockService(ctrl)
+	handler := NewTaskHandler(mockService, noAuth)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.Default()
+	handler.RegisterRoutes(router)
+
+	assigneeID := 7
+	tests := []struct {
+		TestCase string
+		Body     string
+		Setup    func()
+		Status   int
+		Expected string
+	}{
+		{
+			TestCase: "Assign",
+			Body:     `{"assignee_id":7}`,
+			Setup: func() {
+				mockService.EXPECT().AssignTask(gomock.Any(), 1, &assigneeID).Return(task.Info{ID: 1, Name: "API", AssigneeID: &assigneeID}, nil)
+			},
+			Status:   http.StatusOK,
+			Expected: `{"id":1,"name":"API","status":0,"assignee_id":7}`,
+		},
+		{
+			TestCase: "Unassign",
+			Body:     `{"assignee_id":null}`,
+			Setup: func() {
+				mockService.EXPECT().AssignTask(gomock.Any(), 1, nil).Return(task.Info{ID: 1, Name: "API"}, nil)
+			},
+			Status:   http.StatusOK,
+			Expected: `{"id":1,"name":"API","status":0}`,
+		},
+		{
+			TestCase: "Assign to an unknown user",
+			Body:     `{"assignee_id":7}`,
+			Setup: func() {
+				mockService.EXPECT().AssignTask(gomock.Any(), 1, &assigneeID).Return(task.Info{}, task.ErrAssigneeNotFound)
+			},
+			Status:   http.StatusBadRequest,
+			Expected: `{"error":"assignee not found"}`,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.TestCase, func(t *testing.T) {
+			tc.Setup()
+			req, _ := http.NewRequest(http.MethodPost, "/tasks/1/assign", bytes.NewBufferString(tc.Body))
+			req.Header.Set("Content-Type", "application/json")
+			rr := httptest.NewRecorder()
+			router.ServeHTTP(rr, req)
+
+			assert.Equal(t, tc.Status, rr.Code)
+			assert.JSONEq(t, tc.Expected, rr.Body.String())
+		})
+	}
+}
+
 func TestTaskHandler_CreateTask(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
 	mockService := mocks.NewMockService(ctrl)
-	handler := NewTaskHandler(mockService)
+	handler := NewTaskHandler(mockService, noAuth)
 
 	gin.SetMode(gin.TestMode)
 	router := gin.Default()
@@ -133,7 +682,7 @@ func TestTaskHandler_CreateTask(t *testing.T) {
 			Input:    task.Info{Name: "New Task", Status: 0},
 			Expected: task.Info{ID: 1, Name: "New Task", Status: 0},
 			Setup: func() {
-				mockService.EXPECT().CreateTask(task.Info{Name: "New Task", Status: 0}).Return(task.Info{ID: 1, Name: "New Task", Status: 0}, nil)
+				mockService.EXPECT().CreateTask(gomock.Any(), task.Info{Name: "New Task", Status: 0}).Return(task.Info{ID: 1, Name: "New Task", Status: 0}, nil)
 			},
 			Status: http.StatusOK,
 		},
@@ -162,7 +711,7 @@ func TestTaskHandler_UpdateTask(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockService := mocks.NewMockService(ctrl)
-	handler := NewTaskHandler(mockService)
+	handler := NewTaskHandler(mockService, noAuth)
 
 	gin.SetMode(gin.TestMode)
 	router := gin.Default()
@@ -182,7 +731,7 @@ func TestTaskHandler_UpdateTask(t *testing.T) {
 			Input:    task.Info{Name: "Updated Task", Status: 1},
 			Expected: task.Info{ID: 1, Name: "Updated Task", Status: 1},
 			Setup: func() {
-				mockService.EXPECT().UpdateTask(task.Info{ID: 1, Name: "Updated Task", Status: 1}).Return(task.Info{ID: 1, Name: "Updated Task", Status: 1}, nil)
+				mockService.EXPECT().UpdateTask(gomock.Any(), task.Info{ID: 1, Name: "Updated Task", Status: 1}).Return(task.Info{ID: 1, Name: "Updated Task", Status: 1}, nil)
 			},
 			Status: http.StatusOK,
 		},
@@ -211,7 +760,7 @@ func TestTaskHandler_DeleteTask(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockService := mocks.NewMockService(ctrl)
-	handler := NewTaskHandler(mockService)
+	handler := NewTaskHandler(mockService, noAuth)
 
 	gin.SetMode(gin.TestMode)
 	router := gin.Default()
@@ -222,14 +771,25 @@ func TestTaskHandler_DeleteTask(t *testing.T) {
 		ID       int
 		Setup    func()
 		Status   int
+		Expected string
 	}{
 		{
 			TestCase: "Delete task",
 			ID:       1,
 			Setup: func() {
-				mockService.EXPECT().DeleteTask(1).Return(nil)
+				mockService.EXPECT().DeleteTask(gomock.Any(), 1).Return(nil)
 			},
-			Status: http.StatusOK,
+			Status:   http.StatusOK,
+			Expected: `{"message":"Task deleted successfully"}`,
+		},
+		{
+			TestCase: "Role does not allow deleting",
+			ID:       2,
+			Setup: func() {
+				mockService.EXPECT().DeleteTask(gomock.Any(), 2).Return(fmt.Errorf("%w: editor may not delete tasks in project 1", role.ErrForbidden))
+			},
+			Status:   http.StatusForbidden,
+			Expected: `{"error":"forbidden: editor may not delete tasks in project 1"}`,
 		},
 	}
 
@@ -241,10 +801,7 @@ func TestTaskHandler_DeleteTask(t *testing.T) {
 			router.ServeHTTP(rr, req)
 
 			assert.Equal(t, tc.Status, rr.Code)
-			var response map[string]string
-			err := json.Unmarshal(rr.Body.Bytes(), &response)
-			assert.NoError(t, err)
-			assert.Equal(t, "Task deleted successfully", response["message"])
+			assert.JSONEq(t, tc.Expected, rr.Body.String())
 		})
 	}
 }
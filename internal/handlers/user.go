@@ -0,0 +1,134 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"task-api/internal/auth"
+	"task-api/internal/config"
+	"task-api/internal/domain/apikey"
+	"task-api/internal/domain/role"
+	"task-api/internal/domain/task"
+	"task-api/internal/domain/user"
+	taskService "task-api/internal/services/task"
+	userService "task-api/internal/services/user"
+)
+
+type UserHandler struct {
+	Service userService.Service
+	Tasks   taskService.Service
+	// Auth guards every route, and Admin the user directory, which only
+	// admin users read and change.
+	Auth  auth.Middleware
+	Admin gin.HandlerFunc
+}
+
+func NewUserHandler(service userService.Service, tasks taskService.Service, authMiddleware auth.Middleware, cfg config.AuthConfig) *UserHandler {
+	return &UserHandler{Service: service, Tasks: tasks, Auth: authMiddleware, Admin: auth.RequireAdmin(cfg)}
+}
+
+func (h *UserHandler) RegisterRoutes(router *gin.Engine) {
+	users := router.Group("/users", gin.HandlerFunc(h.Auth), h.Admin)
+	users.GET("", h.GetUsers)
+	users.GET("/:id", h.GetUser)
+	users.POST("", h.CreateUser)
+
+	me := router.Group("/me", gin.HandlerFunc(h.Auth))
+	me.GET("", h.GetMe)
+	me.GET("/tasks", auth.RequireScope(apikey.ScopeTasksRead), h.GetMyTasks)
+}
+
+func (h *UserHandler) GetUsers(c *gin.Context) {
+	users, err := h.Service.GetAllUsers()
+	if err != nil {
+		userError(c, err)
+		return
+	}
+	if users == nil {
+		users = []user.User{}
+	}
+	c.JSON(http.StatusOK, users)
+}
+
+func (h *UserHandler) GetUser(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+	u, err := h.Service.GetUser(id)
+	if err != nil {
+		userError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, u)
+}
+
+func (h *UserHandler) CreateUser(c *gin.Context) {
+	var u user.User
+	if err := c.ShouldBindJSON(&u); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	created, err := h.Service.CreateUser(u)
+	if err != nil {
+		userError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, created)
+}
+
+func (h *UserHandler) GetMe(c *gin.Context) {
+	u, ok := h.currentUser(c)
+	if !ok {
+		return
+	}
+	c.JSON(http.StatusOK, u)
+}
+
+// GetMyTasks lists the tasks assigned to the user behind the bearer token.
+func (h *UserHandler) GetMyTasks(c *gin.Context) {
+	u, ok := h.currentUser(c)
+	if !ok {
+		return
+	}
+	tasks, err := h.Tasks.GetTasksByAssignee(c.Request.Context(), u.ID)
+	if err != nil {
+		userError(c, err)
+		return
+	}
+	if tasks == nil {
+		tasks = []task.Info{}
+	}
+	c.JSON(http.StatusOK, tasks)
+}
+
+// currentUser looks up the user whose username is the subject of the
+// caller, writing a 404 without such a user.
+func (h *UserHandler) currentUser(c *gin.Context) (user.User, bool) {
+	identity, _ := auth.FromContext(c.Request.Context())
+	u, err := h.Service.GetUserByUsername(identity.Subject)
+	if err != nil {
+		userError(c, err)
+		return user.User{}, false
+	}
+	return u, true
+}
+
+func userError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, user.ErrInvalidUser):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, user.ErrNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, user.ErrDuplicateUsername):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case errors.Is(err, role.ErrForbidden):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
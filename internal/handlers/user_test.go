@@ -0,0 +1,149 @@
+package handlers
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"task-api/internal/auth"
+	"task-api/internal/config"
+	"task-api/internal/domain/task"
+	"task-api/internal/domain/user"
+	"task-api/internal/mocks"
+)
+
+func TestUserHandler(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mocks.NewMockUserService(ctrl)
+	mockTasks := mocks.NewMockService(ctrl)
+	cfg := config.AuthConfig{
+		StaticTokens:  map[string]string{"alice-token": "alice", "ghost-token": "ghost"},
+		AdminSubjects: []string{"alice"},
+	}
+	handler := NewUserHandler(mockService, mockTasks, auth.NewMiddleware(auth.NewStaticTokenAuthenticator(cfg), cfg), cfg)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.Default()
+	handler.RegisterRoutes(router)
+
+	alice := user.User{ID: 7, Username: "alice", Name: "Alice"}
+	tests := []struct {
+		TestCase string
+		Method   string
+		URL      string
+		Token    string
+		Body     string
+		Setup    func()
+		Status   int
+		Expected string
+	}{
+		{
+			TestCase: "Create user",
+			Method:   http.MethodPost,
+			URL:      "/users",
+			Token:    "alice-token",
+			Body:     `{"username":"alice","name":"Alice"}`,
+			Setup: func() {
+				mockService.EXPECT().CreateUser(user.User{Username: "alice", Name: "Alice"}).Return(alice, nil)
+			},
+			Status:   http.StatusCreated,
+			Expected: `{"id":7,"username":"alice","name":"Alice"}`,
+		},
+		{
+			TestCase: "Create duplicate user",
+			Method:   http.MethodPost,
+			URL:      "/users",
+			Token:    "alice-token",
+			Body:     `{"username":"alice"}`,
+			Setup: func() {
+				mockService.EXPECT().CreateUser(user.User{Username: "alice"}).Return(user.User{}, user.ErrDuplicateUsername)
+			},
+			Status:   http.StatusConflict,
+			Expected: `{"error":"username already exists"}`,
+		},
+		{
+			TestCase: "Users who are no admins may not create users",
+			Method:   http.MethodPost,
+			URL:      "/users",
+			Token:    "ghost-token",
+			Body:     `{"username":"mallory"}`,
+			Setup:    func() {},
+			Status:   http.StatusForbidden,
+			Expected: `{"error":"admin access required"}`,
+		},
+		{
+			TestCase: "Users who are no admins may not list users",
+			Method:   http.MethodGet,
+			URL:      "/users",
+			Token:    "ghost-token",
+			Setup:    func() {},
+			Status:   http.StatusForbidden,
+			Expected: `{"error":"admin access required"}`,
+		},
+		{
+			TestCase: "My tasks need a token",
+			Method:   http.MethodGet,
+			URL:      "/me/tasks",
+			Setup:    func() {},
+			Status:   http.StatusUnauthorized,
+			Expected: `{"error":"unauthorized"}`,
+		},
+		{
+			TestCase: "My tasks without a user for the token",
+			Method:   http.MethodGet,
+			URL:      "/me/tasks",
+			Token:    "ghost-token",
+			Setup: func() {
+				mockService.EXPECT().GetUserByUsername("ghost").Return(user.User{}, user.ErrNotFound)
+			},
+			Status:   http.StatusNotFound,
+			Expected: `{"error":"user not found"}`,
+		},
+		{
+			TestCase: "My tasks",
+			Method:   http.MethodGet,
+			URL:      "/me/tasks",
+			Token:    "alice-token",
+			Setup: func() {
+				mockService.EXPECT().GetUserByUsername("alice").Return(alice, nil)
+				mockTasks.EXPECT().GetTasksByAssignee(gomock.Any(), 7).Return([]task.Info{{ID: 1, Name: "API", AssigneeID: &alice.ID}}, nil)
+			},
+			Status:   http.StatusOK,
+			Expected: `[{"id":1,"name":"API","status":0,"assignee_id":7}]`,
+		},
+		{
+			TestCase: "Me",
+			Method:   http.MethodGet,
+			URL:      "/me",
+			Token:    "alice-token",
+			Setup: func() {
+				mockService.EXPECT().GetUserByUsername("alice").Return(alice, nil)
+			},
+			Status:   http.StatusOK,
+			Expected: `{"id":7,"username":"alice","name":"Alice"}`,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.TestCase, func(t *testing.T) {
+			tc.Setup()
+			req, _ := http.NewRequest(tc.Method, tc.URL, bytes.NewBufferString(tc.Body))
+			req.Header.Set("Content-Type", "application/json")
+			if tc.Token != "" {
+				req.Header.Set("Authorization", "Bearer "+tc.Token)
+			}
+			rr := httptest.NewRecorder()
+			router.ServeHTTP(rr, req)
+
+			assert.Equal(t, tc.Status, rr.Code)
+			assert.JSONEq(t, tc.Expected, rr.Body.String())
+		})
+	}
+}
@@ -0,0 +1,179 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"task-api/internal/auth"
+	"task-api/internal/config"
+	"task-api/internal/domain/webhook"
+	webhookService "task-api/internal/services/webhook"
+)
+
+type WebhookHandler struct {
+	Service webhookService.Service
+	// Auth and Admin guard every route: only admin users manage webhooks
+	// and see their deliveries.
+	Auth  auth.Middleware
+	Admin gin.HandlerFunc
+}
+
+type webhookRequest struct {
+	URL        string   `json:"url" binding:"required"`
+	EventTypes []string `json:"event_types"`
+	Secret     string   `json:"secret"`
+	Active     *bool    `json:"active"`
+}
+
+func (r webhookRequest) subscription() webhook.Subscription {
+	active := true
+	if r.Active != nil {
+		active = *r.Active
+	}
+	return webhook.Subscription{URL: r.URL, EventTypes: r.EventTypes, Secret: r.Secret, Active: active}
+}
+
+func NewWebhookHandler(service webhookService.Service, authMiddleware auth.Middleware, cfg config.AuthConfig) *WebhookHandler {
+	return &WebhookHandler{Service: service, Auth: authMiddleware, Admin: auth.RequireAdmin(cfg)}
+}
+
+func (h *WebhookHandler) RegisterRoutes(router *gin.Engine) {
+	webhooks := router.Group("/webhooks", gin.HandlerFunc(h.Auth), h.Admin)
+	webhooks.GET("", h.GetSubscriptions)
+	webhooks.GET("/:id", h.GetSubscription)
+	webhooks.POST("", h.CreateSubscription)
+	webhooks.PUT("/:id", h.UpdateSubscription)
+	webhooks.DELETE("/:id", h.DeleteSubscription)
+	webhooks.GET("/:id/deliveries", h.GetDeliveries)
+	webhooks.POST("/:id/deliveries/:deliveryId/replay", h.ReplayDelivery)
+}
+
+func (h *WebhookHandler) GetSubscriptions(c *gin.Context) {
+	subscriptions, err := h.Service.GetAllSubscriptions(c.Request.Context())
+	if err != nil {
+		webhookError(c, err)
+		return
+	}
+	for i := range subscriptions {
+		subscriptions[i].Secret = ""
+	}
+	c.JSON(http.StatusOK, subscriptions)
+}
+
+func (h *WebhookHandler) GetSubscription(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+	subscription, err := h.Service.GetSubscription(c.Request.Context(), id)
+	if err != nil {
+		webhookError(c, err)
+		return
+	}
+	subscription.Secret = ""
+	c.JSON(http.StatusOK, subscription)
+}
+
+// CreateSubscription is the only response that includes the signing secret.
+func (h *WebhookHandler) CreateSubscription(c *gin.Context) {
+	var req webhookRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	subscription, err := h.Service.CreateSubscription(c.Request.Context(), req.subscription())
+	if err != nil {
+		webhookError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, subscription)
+}
+
+func (h *WebhookHandler) UpdateSubscription(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+
+	var req webhookRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	s := req.subscription()
+	s.ID = id
+	subscription, err := h.Service.UpdateSubscription(c.Request.Context(), s)
+	if err != nil {
+		webhookError(c, err)
+		return
+	}
+	subscription.Secret = ""
+	c.JSON(http.StatusOK, subscription)
+}
+
+func (h *WebhookHandler) DeleteSubscription(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+	if err := h.Service.DeleteSubscription(c.Request.Context(), id); err != nil {
+		webhookError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Webhook deleted successfully"})
+}
+
+func (h *WebhookHandler) GetDeliveries(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+	deliveries, err := h.Service.ListDeliveries(c.Request.Context(), id, webhook.DeliveryStatus(c.Query("status")))
+	if err != nil {
+		webhookError(c, err)
+		return
+	}
+	if deliveries == nil {
+		deliveries = []webhook.Delivery{}
+	}
+	c.JSON(http.StatusOK, deliveries)
+}
+
+func (h *WebhookHandler) ReplayDelivery(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+	deliveryID, err := strconv.Atoi(c.Param("deliveryId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid delivery ID"})
+		return
+	}
+	delivery, err := h.Service.ReplayDelivery(c.Request.Context(), id, deliveryID)
+	if err != nil {
+		webhookError(c, err)
+		return
+	}
+	c.JSON(http.StatusAccepted, delivery)
+}
+
+func webhookError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, webhook.ErrInvalidSubscription):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, webhook.ErrNotFound), errors.Is(err, webhook.ErrDeliveryNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
@@ -0,0 +1,163 @@
+package handlers
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"task-api/internal/auth"
+	"task-api/internal/config"
+	"task-api/internal/domain/webhook"
+	"task-api/internal/mocks"
+)
+
+func TestWebhookHandler(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mocks.NewMockWebhookService(ctrl)
+	cfg := config.AuthConfig{
+		StaticTokens:  map[string]string{"root": "alice", "secret": "board"},
+		AdminSubjects: []string{"alice"},
+	}
+	handler := NewWebhookHandler(mockService, auth.NewMiddleware(auth.NewStaticTokenAuthenticator(cfg), cfg), cfg)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.Default()
+	handler.RegisterRoutes(router)
+
+	tests := []struct {
+		TestCase string
+		Method   string
+		URL      string
+		Token    string
+		Body     string
+		Expected string
+		Setup    func()
+		Status   int
+	}{
+		{
+			TestCase: "Create returns the secret once",
+			Method:   http.MethodPost,
+			URL:      "/webhooks",
+			Token:    "root",
+			Body:     `{"url":"https://example.com/hook","event_types":["task.created"]}`,
+			Expected: `{"id":1,"url":"https://example.com/hook","event_types":["task.created"],"secret":"s3cret","active":true,"created_at":"0001-01-01T00:00:00Z"}`,
+			Setup: func() {
+				mockService.EXPECT().CreateSubscription(gomock.Any(), webhook.Subscription{URL: "https://example.com/hook", EventTypes: []string{"task.created"}, Active: true}).
+					Return(webhook.Subscription{ID: 1, URL: "https://example.com/hook", EventTypes: []string{"task.created"}, Secret: "s3cret", Active: true}, nil)
+			},
+			Status: http.StatusCreated,
+		},
+		{
+			TestCase: "Create with an invalid url",
+			Method:   http.MethodPost,
+			URL:      "/webhooks",
+			Token:    "root",
+			Body:     `{"url":"/hook"}`,
+			Expected: `{"error":"invalid webhook subscription: url must be an absolute http or https url"}`,
+			Setup: func() {
+				mockService.EXPECT().CreateSubscription(gomock.Any(), gomock.Any()).
+					Return(webhook.Subscription{}, fmt.Errorf("%w: url must be an absolute http or https url", webhook.ErrInvalidSubscription))
+			},
+			Status: http.StatusBadRequest,
+		},
+		{
+			TestCase: "Get hides the secret",
+			Method:   http.MethodGet,
+			URL:      "/webhooks/1",
+			Token:    "root",
+			Expected: `{"id":1,"url":"https://example.com/hook","event_types":null,"active":true,"created_at":"0001-01-01T00:00:00Z"}`,
+			Setup: func() {
+				mockService.EXPECT().GetSubscription(gomock.Any(), 1).Return(webhook.Subscription{ID: 1, URL: "https://example.com/hook", Secret: "s3cret", Active: true}, nil)
+			},
+			Status: http.StatusOK,
+		},
+		{
+			TestCase: "Get missing subscription",
+			Method:   http.MethodGet,
+			URL:      "/webhooks/2",
+			Token:    "root",
+			Expected: `{"error":"webhook subscription not found"}`,
+			Setup: func() {
+				mockService.EXPECT().GetSubscription(gomock.Any(), 2).Return(webhook.Subscription{}, webhook.ErrNotFound)
+			},
+			Status: http.StatusNotFound,
+		},
+		{
+			TestCase: "List failed deliveries",
+			Method:   http.MethodGet,
+			URL:      "/webhooks/1/deliveries?status=failed",
+			Token:    "root",
+			Expected: `[]`,
+			Setup: func() {
+				mockService.EXPECT().ListDeliveries(gomock.Any(), 1, webhook.DeliveryFailed).Return(nil, nil)
+			},
+			Status: http.StatusOK,
+		},
+		{
+			TestCase: "Replay delivery",
+			Method:   http.MethodPost,
+			URL:      "/webhooks/1/deliveries/3/replay",
+			Token:    "root",
+			Expected: `{"id":4,"subscription_id":1,"event_id":9,"event_type":"task.updated","payload":{"id":9},"status":"pending","attempts":0,"next_attempt_at":"0001-01-01T00:00:00Z","created_at":"0001-01-01T00:00:00Z"}`,
+			Setup: func() {
+				mockService.EXPECT().ReplayDelivery(gomock.Any(), 1, 3).Return(webhook.Delivery{
+					ID: 4, SubscriptionID: 1, EventID: 9, EventType: "task.updated", Payload: []byte(`{"id":9}`), Status: webhook.DeliveryPending,
+				}, nil)
+			},
+			Status: http.StatusAccepted,
+		},
+		{
+			TestCase: "Delete subscription",
+			Method:   http.MethodDelete,
+			URL:      "/webhooks/1",
+			Token:    "root",
+			Expected: `{"message":"Webhook deleted successfully"}`,
+			Setup: func() {
+				mockService.EXPECT().DeleteSubscription(gomock.Any(), 1).Return(nil)
+			},
+			Status: http.StatusOK,
+		},
+		{
+			TestCase: "Webhooks need a token",
+			Method:   http.MethodGet,
+			URL:      "/webhooks",
+			Expected: `{"error":"unauthorized"}`,
+			Setup:    func() {},
+			Status:   http.StatusUnauthorized,
+		},
+		{
+			TestCase: "Users who are no admins are refused",
+			Method:   http.MethodPost,
+			URL:      "/webhooks",
+			Token:    "secret",
+			Body:     `{"url":"https://example.com/hook"}`,
+			Expected: `{"error":"admin access required"}`,
+			Setup:    func() {},
+			Status:   http.StatusForbidden,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.TestCase, func(t *testing.T) {
+			tc.Setup()
+			req, _ := http.NewRequest(tc.Method, tc.URL, bytes.NewBufferString(tc.Body))
+			req.Header.Set("Content-Type", "application/json")
+			if tc.Token != "" {
+				req.Header.Set("Authorization", "Bearer "+tc.Token)
+			}
+			rr := httptest.NewRecorder()
+			router.ServeHTTP(rr, req)
+
+			assert.Equal(t, tc.Status, rr.Code)
+			assert.JSONEq(t, tc.Expected, rr.Body.String())
+		})
+	}
+}
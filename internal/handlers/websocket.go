@@ -0,0 +1,263 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+
+	"task-api/internal/auth"
+	"task-api/internal/config"
+	"task-api/internal/domain/apikey"
+	"task-api/internal/events"
+	"task-api/internal/tenant"
+)
+
+const (
+	wsMaxMessageSize   = 4096
+	wsMaxSubscriptions = 32
+)
+
+type WebSocketHandler struct {
+	Broker *events.Broker
+	// Auth guards the upgrade like the event stream, as the socket carries
+	// the tasks of the caller's tenant.
+	Auth     auth.Middleware
+	Config   config.WebSocketConfig
+	upgrader websocket.Upgrader
+}
+
+// wsMessage is the envelope for every frame in both directions. Clients send
+// "subscribe", "unsubscribe" and "ping"; the server answers with
+// "subscribed", "unsubscribed", "pong", "event" and "error". Filters follow
+// events.ParseFilter: a status is pending or done, and a subscription to
+// any other, such as in_progress, gets an "error".
+type wsMessage struct {
+	Type   string        `json:"type"`
+	ID     string        `json:"id,omitempty"`
+	Filter string        `json:"filter,omitempty"`
+	Event  *events.Event `json:"event,omitempty"`
+	Error  string        `json:"error,omitempty"`
+}
+
+func NewWebSocketHandler(broker *events.Broker, authMiddleware auth.Middleware, cfg config.WebSocketConfig) *WebSocketHandler {
+	h := &WebSocketHandler{Broker: broker, Auth: authMiddleware, Config: cfg}
+	if len(cfg.AllowedOrigins) > 0 {
+		allowed := make(map[string]bool, len(cfg.AllowedOrigins))
+		for _, origin := range cfg.AllowedOrigins {
+			allowed[origin] = true
+		}
+		h.upgrader.CheckOrigin = func(r *http.Request) bool {
+			return allowed[r.Header.Get("Origin")]
+		}
+	}
+	return h
+}
+
+func (h *WebSocketHandler) RegisterRoutes(router *gin.Engine) {
+	router.GET("/ws", gin.HandlerFunc(h.Auth), auth.RequireScope(apikey.ScopeTasksRead), h.Connect)
+}
+
+func (h *WebSocketHandler) Connect(c *gin.Context) {
+	conn, err := h.upgrader.Upgrade(c.Writer, c.Request, nil)
+	if err != nil {
+		// The upgrader has already written an error response.
+		return
+	}
+
+	_, sub := h.Broker.Subscribe(0)
+	defer sub.Close()
+
+	client := newWSClient(conn, h.Config)
+	client.tenant = tenant.FromContext(c.Request.Context())
+	go client.writePump()
+	go client.fanOut(sub)
+	client.readPump()
+}
+
+type wsClient struct {
+	conn *websocket.Conn
+	cfg  config.WebSocketConfig
+	send chan wsMessage
+	done chan struct{}
+	once sync.Once
+	// tenant narrows every subscription to the events of its tasks.
+	tenant string
+
+	mu            sync.Mutex
+	subscriptions map[string]events.Filter
+}
+
+func newWSClient(conn *websocket.Conn, cfg config.WebSocketConfig) *wsClient {
+	if cfg.SendBuffer <= 0 {
+		cfg.SendBuffer = 1
+	}
+	if cfg.PingInterval <= 0 {
+		cfg.PingInterval = 30 * time.Second
+	}
+	if cfg.WriteTimeout <= 0 {
+		cfg.WriteTimeout = 10 * time.Second
+	}
+	return &wsClient{
+		conn:          conn,
+		cfg:           cfg,
+		send:          make(chan wsMessage, cfg.SendBuffer),
+		done:          make(chan struct{}),
+		subscriptions: make(map[string]events.Filter),
+	}
+}
+
+// enqueue hands a message to the write pump without blocking. A client whose
+// queue is full is too slow to keep up and gets disconnected.
+func (c *wsClient) enqueue(msg wsMessage) bool {
+	select {
+	case <-c.done:
+		return false
+	default:
+	}
+
+	select {
+	case c.send <- msg:
+		return true
+	default:
+		c.shutdown(websocket.ClosePolicyViolation, "slow consumer")
+		return false
+	}
+}
+
+func (c *wsClient) shutdown(code int, reason string) {
+	c.once.Do(func() {
+		close(c.done)
+		deadline := time.Now().Add(c.cfg.WriteTimeout)
+		_ = c.conn.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(code, reason), deadline)
+		_ = c.conn.Close()
+	})
+}
+
+func (c *wsClient) readPump() {
+	defer c.shutdown(websocket.CloseNormalClosure, "")
+
+	pongWait := 2 * c.cfg.PingInterval
+	c.conn.SetReadLimit(wsMaxMessageSize)
+	_ = c.conn.SetReadDeadline(time.Now().Add(pongWait))
+	c.conn.SetPongHandler(func(string) error {
+		return c.conn.SetReadDeadline(time.Now().Add(pongWait))
+	})
+
+	for {
+		var msg wsMessage
+		if err := c.conn.ReadJSON(&msg); err != nil {
+			var syntaxErr *json.SyntaxError
+			var typeErr *json.UnmarshalTypeError
+			if !errors.As(err, &syntaxErr) && !errors.As(err, &typeErr) {
+				return
+			}
+			if !c.enqueue(wsMessage{Type: "error", Error: "invalid message"}) {
+				return
+			}
+			continue
+		}
+		_ = c.conn.SetReadDeadline(time.Now().Add(pongWait))
+		if !c.handle(msg) {
+			return
+		}
+	}
+}
+
+func (c *wsClient) handle(msg wsMessage) bool {
+	switch msg.Type {
+	case "subscribe":
+		if msg.ID == "" {
+			return c.enqueue(wsMessage{Type: "error", Error: "subscription id is required"})
+		}
+		filter, err := events.ParseFilter(msg.Filter)
+		if err != nil {
+			return c.enqueue(wsMessage{Type: "error", ID: msg.ID, Error: err.Error()})
+		}
+		filter.Tenant = c.tenant
+		c.mu.Lock()
+		_, exists := c.subscriptions[msg.ID]
+		if !exists && len(c.subscriptions) >= wsMaxSubscriptions {
+			c.mu.Unlock()
+			return c.enqueue(wsMessage{Type: "error", ID: msg.ID, Error: "too many subscriptions"})
+		}
+		c.subscriptions[msg.ID] = filter
+		c.mu.Unlock()
+		return c.enqueue(wsMessage{Type: "subscribed", ID: msg.ID})
+	case "unsubscribe":
+		c.mu.Lock()
+		_, exists := c.subscriptions[msg.ID]
+		delete(c.subscriptions, msg.ID)
+		c.mu.Unlock()
+		if !exists {
+			return c.enqueue(wsMessage{Type: "error", ID: msg.ID, Error: "unknown subscription"})
+		}
+		return c.enqueue(wsMessage{Type: "unsubscribed", ID: msg.ID})
+	case "ping":
+		return c.enqueue(wsMessage{Type: "pong"})
+	default:
+		return c.enqueue(wsMessage{Type: "error", Error: "unknown message type"})
+	}
+}
+
+func (c *wsClient) writePump() {
+	ticker := time.NewTicker(c.cfg.PingInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-c.done:
+			return
+		case msg := <-c.send:
+			_ = c.conn.SetWriteDeadline(time.Now().Add(c.cfg.WriteTimeout))
+			if err := c.conn.WriteJSON(msg); err != nil {
+				c.shutdown(websocket.CloseGoingAway, "")
+				return
+			}
+		case <-ticker.C:
+			deadline := time.Now().Add(c.cfg.WriteTimeout)
+			if err := c.conn.WriteControl(websocket.PingMessage, nil, deadline); err != nil {
+				c.shutdown(websocket.CloseGoingAway, "")
+				return
+			}
+		}
+	}
+}
+
+func (c *wsClient) fanOut(sub *events.Subscription) {
+	for {
+		select {
+		case <-c.done:
+			return
+		case event, ok := <-sub.C:
+			if !ok {
+				if errors.Is(sub.Err(), events.ErrClosed) {
+					c.shutdown(websocket.CloseGoingAway, "server shutting down")
+				} else {
+					c.shutdown(websocket.ClosePolicyViolation, "slow consumer")
+				}
+				return
+			}
+			c.dispatch(event)
+		}
+	}
+}
+
+func (c *wsClient) dispatch(event events.Event) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for id, filter := range c.subscriptions {
+		if !filter.Matches(event) {
+			continue
+		}
+		e := event
+		if !c.enqueue(wsMessage{Type: "event", ID: id, Event: &e}) {
+			return
+		}
+	}
+}
@@ -0,0 +1,182 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+
+	"task-api/internal/auth"
+	"task-api/internal/config"
+	"task-api/internal/domain/apikey"
+	"task-api/internal/domain/task"
+	"task-api/internal/events"
+	"task-api/internal/mocks"
+	"task-api/internal/tenant"
+)
+
+func newWebSocketTestServer(t *testing.T, broker *events.Broker) *httptest.Server {
+	mockKeys := mocks.NewMockAPIKeyService(gomock.NewController(t))
+	mockKeys.EXPECT().Verify("tk_read").Return(apikey.Key{ID: 3, Scopes: []string{apikey.ScopeTasksRead}}, nil).AnyTimes()
+	mockKeys.EXPECT().Verify("tk_write").Return(apikey.Key{ID: 4, Scopes: []string{apikey.ScopeTasksWrite}}, nil).AnyTimes()
+	cfg := config.AuthConfig{StaticTokens: map[string]string{"secret": "board"}}
+	authenticator, err := auth.NewAuthenticator(cfg, mockKeys)
+	assert.NoError(t, err)
+	handler := NewWebSocketHandler(broker, auth.NewMiddleware(authenticator, cfg), config.WebSocketConfig{SendBuffer: 16})
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	handler.RegisterRoutes(router)
+	return httptest.NewServer(router)
+}
+
+func dialWebSocket(t *testing.T, server *httptest.Server, token string) (*websocket.Conn, *http.Response, error) {
+	url := "ws" + strings.TrimPrefix(server.URL, "http") + "/ws"
+	header := http.Header{}
+	if token != "" {
+		header.Set("Authorization", "Bearer "+token)
+	}
+	return websocket.DefaultDialer.Dial(url, header)
+}
+
+func readMessage(t *testing.T, conn *websocket.Conn) wsMessage {
+	var msg wsMessage
+	_ = conn.SetReadDeadline(time.Now().Add(time.Second))
+	assert.NoError(t, conn.ReadJSON(&msg))
+	return msg
+}
+
+func TestWebSocketHandler_Connect(t *testing.T) {
+	server := newWebSocketTestServer(t, events.NewBroker(16))
+	defer server.Close()
+
+	tests := []struct {
+		TestCase string
+		Token    string
+		Status   int
+	}{
+		{TestCase: "Valid token upgrades", Token: "secret", Status: http.StatusSwitchingProtocols},
+		{TestCase: "Key with tasks:read upgrades", Token: "tk_read", Status: http.StatusSwitchingProtocols},
+		{TestCase: "Key without tasks:read is forbidden", Token: "tk_write", Status: http.StatusForbidden},
+		{TestCase: "Invalid token is rejected", Token: "guess", Status: http.StatusUnauthorized},
+		{TestCase: "Missing token is rejected", Token: "", Status: http.StatusUnauthorized},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.TestCase, func(t *testing.T) {
+			conn, resp, _ := dialWebSocket(t, server, tc.Token)
+			if conn != nil {
+				conn.Close()
+			}
+			assert.Equal(t, tc.Status, resp.StatusCode)
+		})
+	}
+}
+
+func TestWebSocketHandler_Subscriptions(t *testing.T) {
+	broker := events.NewBroker(16)
+	server := newWebSocketTestServer(t, broker)
+	defer server.Close()
+
+	conn, _, err := dialWebSocket(t, server, "secret")
+	assert.NoError(t, err)
+	defer conn.Close()
+
+	tests := []struct {
+		TestCase string
+		Send     wsMessage
+		Publish  []task.Info
+		Expected []wsMessage
+	}{
+		{
+			TestCase: "Subscribe with an invalid filter",
+			Send:     wsMessage{Type: "subscribe", ID: "bad", Filter: "owner=me"},
+			Expected: []wsMessage{{Type: "error", ID: "bad", Error: `unknown filter field "owner"`}},
+		},
+		{
+			TestCase: "Subscribe to a status tasks do not have",
+			Send:     wsMessage{Type: "subscribe", ID: "wip", Filter: "status=in_progress"},
+			Expected: []wsMessage{{Type: "error", ID: "wip", Error: `invalid status "in_progress": must be pending or done`}},
+		},
+		{
+			TestCase: "Subscribe to done tasks",
+			Send:     wsMessage{Type: "subscribe", ID: "done", Filter: "status=done"},
+			Expected: []wsMessage{{Type: "subscribed", ID: "done"}},
+		},
+		{
+			TestCase: "Only matching events of the tenant are delivered",
+			Publish: []task.Info{
+				{ID: 1, Name: "Pending", Status: 0, TenantID: tenant.Default},
+				{ID: 4, Name: "Done elsewhere", Status: 1, TenantID: "acme"},
+				{ID: 2, Name: "Done", Status: 1, TenantID: tenant.Default},
+			},
+			Expected: []wsMessage{{Type: "event", ID: "done"}},
+		},
+		{
+			TestCase: "Unsubscribe",
+			Send:     wsMessage{Type: "unsubscribe", ID: "done"},
+			Expected: []wsMessage{{Type: "unsubscribed", ID: "done"}},
+		},
+		{
+			TestCase: "No events after unsubscribe",
+			Send:     wsMessage{Type: "ping"},
+			Publish:  []task.Info{{ID: 3, Name: "Done", Status: 1, TenantID: tenant.Default}},
+			Expected: []wsMessage{{Type: "pong"}},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.TestCase, func(t *testing.T) {
+			if tc.Send.Type != "" {
+				assert.NoError(t, conn.WriteJSON(tc.Send))
+			}
+			if len(tc.Publish) > 0 {
+				// Let the subscription settle before publishing.
+				time.Sleep(20 * time.Millisecond)
+			}
+			for _, info := range tc.Publish {
+				broker.Publish(events.TaskUpdated, info)
+			}
+			for _, expected := range tc.Expected {
+				msg := readMessage(t, conn)
+				assert.Equal(t, expected.Type, msg.Type)
+				assert.Equal(t, expected.ID, msg.ID)
+				assert.Equal(t, expected.Error, msg.Error)
+				if expected.Type == "event" {
+					assert.Equal(t, 2, msg.Event.Task.ID)
+				}
+			}
+		})
+	}
+}
+
+func TestWSClient_SlowConsumer(t *testing.T) {
+	accepted := make(chan *websocket.Conn, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := (&websocket.Upgrader{}).Upgrade(w, r, nil)
+		assert.NoError(t, err)
+		accepted <- conn
+	}))
+	defer server.Close()
+
+	conn, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(server.URL, "http"), nil)
+	assert.NoError(t, err)
+	defer conn.Close()
+
+	// No write pump runs, so the second message overflows the queue.
+	client := newWSClient(<-accepted, config.WebSocketConfig{SendBuffer: 1})
+	assert.True(t, client.enqueue(wsMessage{Type: "pong"}))
+	assert.False(t, client.enqueue(wsMessage{Type: "pong"}))
+
+	_, _, err = conn.ReadMessage()
+	closeErr, ok := err.(*websocket.CloseError)
+	assert.True(t, ok)
+	assert.Equal(t, websocket.ClosePolicyViolation, closeErr.Code)
+	assert.Equal(t, "slow consumer", closeErr.Text)
+}
@@ -0,0 +1,126 @@
+// Package health reports whether the API is up and whether the services it
+// depends on can be used, for orchestrators and load balancers to probe.
+package health
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"task-api/internal/config"
+)
+
+// Checker is implemented by the repositories and caches that depend on a
+// service outside the process.
+type Checker interface {
+	// Component names the service in readiness reports, such as "mysql".
+	Component() string
+	// CheckHealth returns an error when the service cannot be used.
+	CheckHealth(ctx context.Context) error
+}
+
+// Checkers returns those of deps that implement Checker, so that storage
+// kept in the process is left out.
+func Checkers(deps ...interface{}) []Checker {
+	var checkers []Checker
+	for _, dep := range deps {
+		if checker, ok := dep.(Checker); ok {
+			checkers = append(checkers, checker)
+		}
+	}
+	return checkers
+}
+
+type Status string
+
+const (
+	StatusOK      Status = "ok"
+	StatusFailing Status = "failing"
+)
+
+type ComponentReport struct {
+	Status    Status `json:"status"`
+	Error     string `json:"error,omitempty"`
+	LatencyMS int64  `json:"latency_ms"`
+}
+
+// Report is ok when every component is.
+type Report struct {
+	Status     Status                     `json:"status"`
+	Error      string                     `json:"error,omitempty"`
+	Components map[string]ComponentReport `json:"components"`
+}
+
+// Monitor checks the dependencies for readiness probes, and fails them
+// once the API is shutting down.
+type Monitor struct {
+	checkers []Checker
+	cfg      config.HealthConfig
+	draining atomic.Bool
+}
+
+func NewMonitor(checkers []Checker, cfg config.HealthConfig) *Monitor {
+	return &Monitor{checkers: checkers, cfg: cfg}
+}
+
+// Ready checks every dependency at once, each within the check timeout.
+// The dependencies are not checked while draining, as they are about to be
+// closed.
+func (m *Monitor) Ready(ctx context.Context) Report {
+	report := Report{Status: StatusOK, Components: make(map[string]ComponentReport, len(m.checkers))}
+	if m.draining.Load() {
+		report.Status = StatusFailing
+		report.Error = "shutting down"
+		return report
+	}
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for _, checker := range m.checkers {
+		wg.Add(1)
+		go func(checker Checker) {
+			defer wg.Done()
+			c := check(ctx, checker, m.cfg.CheckTimeout)
+			mu.Lock()
+			defer mu.Unlock()
+			report.Components[checker.Component()] = c
+			if c.Status != StatusOK {
+				report.Status = StatusFailing
+			}
+		}(checker)
+	}
+	wg.Wait()
+	return report
+}
+
+func check(ctx context.Context, checker Checker, timeout time.Duration) ComponentReport {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	start := time.Now()
+	err := checker.CheckHealth(ctx)
+	c := ComponentReport{Status: StatusOK, LatencyMS: time.Since(start).Milliseconds()}
+	if err != nil {
+		c.Status = StatusFailing
+		c.Error = err.Error()
+	}
+	return c
+}
+
+// Drain fails readiness from now on and waits for the shutdown delay, or
+// until ctx is done, so that load balancers notice before the server stops
+// accepting connections.
+func (m *Monitor) Drain(ctx context.Context) error {
+	m.draining.Store(true)
+	if m.cfg.ShutdownDelay <= 0 {
+		return nil
+	}
+	t := time.NewTimer(m.cfg.ShutdownDelay)
+	defer t.Stop()
+	select {
+	case <-t.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
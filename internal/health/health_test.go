@@ -0,0 +1,99 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"task-api/internal/config"
+)
+
+type stubChecker struct {
+	name  string
+	err   error
+	delay time.Duration
+}
+
+func (s stubChecker) Component() string { return s.name }
+
+func (s stubChecker) CheckHealth(ctx context.Context) error {
+	select {
+	case <-time.After(s.delay):
+		return s.err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func TestMonitorReady(t *testing.T) {
+	cfg := config.HealthConfig{CheckTimeout: 50 * time.Millisecond}
+
+	type testCase struct {
+		TestCase       string
+		Checkers       []Checker
+		ExpectedStatus Status
+		ExpectedErrors map[string]string
+	}
+
+	testCases := []testCase{
+		{
+			TestCase:       "no dependencies",
+			ExpectedStatus: StatusOK,
+			ExpectedErrors: map[string]string{},
+		},
+		{
+			TestCase:       "every dependency is up",
+			Checkers:       []Checker{stubChecker{name: "mysql"}, stubChecker{name: "redis"}},
+			ExpectedStatus: StatusOK,
+			ExpectedErrors: map[string]string{"mysql": "", "redis": ""},
+		},
+		{
+			TestCase:       "a dependency fails",
+			Checkers:       []Checker{stubChecker{name: "mysql", err: errors.New("connection refused")}, stubChecker{name: "redis"}},
+			ExpectedStatus: StatusFailing,
+			ExpectedErrors: map[string]string{"mysql": "connection refused", "redis": ""},
+		},
+		{
+			TestCase:       "a dependency times out",
+			Checkers:       []Checker{stubChecker{name: "mysql", delay: time.Second}},
+			ExpectedStatus: StatusFailing,
+			ExpectedErrors: map[string]string{"mysql": context.DeadlineExceeded.Error()},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.TestCase, func(t *testing.T) {
+			report := NewMonitor(tc.Checkers, cfg).Ready(context.Background())
+			assert.Equal(t, tc.ExpectedStatus, report.Status)
+			errs := make(map[string]string, len(report.Components))
+			for name, c := range report.Components {
+				errs[name] = c.Error
+				assert.Equal(t, c.Error == "", c.Status == StatusOK)
+			}
+			assert.Equal(t, tc.ExpectedErrors, errs)
+		})
+	}
+}
+
+func TestMonitorDrain(t *testing.T) {
+	m := NewMonitor([]Checker{stubChecker{name: "mysql"}}, config.HealthConfig{CheckTimeout: time.Second, ShutdownDelay: 50 * time.Millisecond})
+	assert.Equal(t, StatusOK, m.Ready(context.Background()).Status)
+
+	start := time.Now()
+	assert.NoError(t, m.Drain(context.Background()))
+	assert.GreaterOrEqual(t, time.Since(start), 50*time.Millisecond)
+	assert.Equal(t, Report{Status: StatusFailing, Error: "shutting down", Components: map[string]ComponentReport{}},
+		m.Ready(context.Background()))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	assert.ErrorIs(t, m.Drain(ctx), context.Canceled)
+}
+
+func TestCheckers(t *testing.T) {
+	mysql := stubChecker{name: "mysql"}
+	assert.Equal(t, []Checker{mysql}, Checkers(struct{}{}, mysql, nil))
+}
@@ -0,0 +1,37 @@
+// Package idempotency lets clients retry POST and PATCH requests safely:
+// a request carrying an Idempotency-Key header is handled once, and retries
+// with the same key get the stored response instead of repeating it.
+package idempotency
+
+import (
+	"context"
+	"time"
+)
+
+const (
+	Header = "Idempotency-Key"
+	// ReplayedHeader marks responses that were stored for an earlier request.
+	ReplayedHeader = "Idempotent-Replayed"
+)
+
+// Record is what a store keeps for a key: the fingerprint of the request
+// that claimed it and, once that request was handled, its response.
+type Record struct {
+	Fingerprint string `json:"fingerprint"`
+	// Done is false while the first request is still being handled.
+	Done        bool   `json:"done"`
+	Status      int    `json:"status,omitempty"`
+	ContentType string `json:"content_type,omitempty"`
+	Body        []byte `json:"body,omitempty"`
+}
+
+// Store keeps the records by key. Claim takes a key that is free for the
+// request with fingerprint, until the claim is completed or released, or
+// lockTimeout passes; when the key is taken, Claim returns the record
+// holding it and false. Complete replaces a claim with the response, kept
+// for ttl, and Release drops a claim so that a retry is handled again.
+type Store interface {
+	Claim(ctx context.Context, key, fingerprint string, lockTimeout time.Duration) (Record, bool, error)
+	Complete(ctx context.Context, key string, r Record, ttl time.Duration) error
+	Release(ctx context.Context, key string) error
+}
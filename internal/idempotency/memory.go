@@ -0,0 +1,64 @@
+package idempotency
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// sweepInterval is how often expired records are dropped.
+const sweepInterval = time.Minute
+
+type entry struct {
+	record  Record
+	expires time.Time
+}
+
+type memoryStore struct {
+	mu        sync.Mutex
+	entries   map[string]entry
+	lastSweep time.Time
+	now       func() time.Time
+}
+
+// NewMemoryStore keeps the records in the process, so retries are only
+// recognized by the API instance that handled the first request.
+func NewMemoryStore() Store {
+	return &memoryStore{entries: make(map[string]entry), now: time.Now}
+}
+
+func (s *memoryStore) Claim(_ context.Context, key, fingerprint string, lockTimeout time.Duration) (Record, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	if now.Sub(s.lastSweep) >= sweepInterval {
+		for k, e := range s.entries {
+			if !now.Before(e.expires) {
+				delete(s.entries, k)
+			}
+		}
+		s.lastSweep = now
+	}
+
+	if e, ok := s.entries[key]; ok && now.Before(e.expires) {
+		return e.record, false, nil
+	}
+	r := Record{Fingerprint: fingerprint}
+	s.entries[key] = entry{record: r, expires: now.Add(lockTimeout)}
+	return r, true, nil
+}
+
+func (s *memoryStore) Complete(_ context.Context, key string, r Record, ttl time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.entries[key] = entry{record: r, expires: s.now().Add(ttl)}
+	return nil
+}
+
+func (s *memoryStore) Release(_ context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.entries, key)
+	return nil
+}
@@ -0,0 +1,109 @@
+package idempotency
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemoryStore(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	store := &memoryStore{entries: make(map[string]entry), now: func() time.Time { return now }}
+	ctx := context.Background()
+	done := Record{Fingerprint: "fp", Done: true, Status: 201, ContentType: "application/json", Body: []byte(`{"id":1}`)}
+
+	type step struct {
+		TestCase        string
+		Advance         time.Duration
+		Run             func() error
+		Key             string
+		Expected        Record
+		ExpectedClaimed bool
+	}
+
+	steps := []step{
+		{
+			TestCase:        "claims a free key",
+			Key:             "a",
+			Expected:        Record{Fingerprint: "fp"},
+			ExpectedClaimed: true,
+		},
+		{
+			TestCase: "a claimed key is taken",
+			Key:      "a",
+			Expected: Record{Fingerprint: "fp"},
+		},
+		{
+			TestCase:        "other keys are free",
+			Key:             "b",
+			Expected:        Record{Fingerprint: "fp"},
+			ExpectedClaimed: true,
+		},
+		{
+			TestCase:        "a claim expires after the lock timeout",
+			Advance:         time.Minute,
+			Key:             "a",
+			Expected:        Record{Fingerprint: "fp"},
+			ExpectedClaimed: true,
+		},
+		{
+			TestCase: "a completed key holds the response",
+			Run:      func() error { return store.Complete(ctx, "a", done, time.Hour) },
+			Key:      "a",
+			Expected: done,
+		},
+		{
+			TestCase: "for the TTL rather than the lock timeout",
+			Advance:  59 * time.Minute,
+			Key:      "a",
+			Expected: done,
+		},
+		{
+			TestCase:        "the response expires after the TTL",
+			Advance:         time.Minute,
+			Key:             "a",
+			Expected:        Record{Fingerprint: "fp"},
+			ExpectedClaimed: true,
+		},
+		{
+			TestCase:        "a released key is free",
+			Run:             func() error { return store.Release(ctx, "a") },
+			Key:             "a",
+			Expected:        Record{Fingerprint: "fp"},
+			ExpectedClaimed: true,
+		},
+	}
+
+	for _, s := range steps {
+		t.Run(s.TestCase, func(t *testing.T) {
+			now = now.Add(s.Advance)
+			if s.Run != nil {
+				assert.NoError(t, s.Run())
+			}
+			r, claimed, err := store.Claim(ctx, s.Key, "fp", time.Minute)
+			assert.NoError(t, err)
+			assert.Equal(t, s.ExpectedClaimed, claimed)
+			assert.Equal(t, s.Expected, r)
+		})
+	}
+}
+
+func TestMemoryStoreSweepsExpiredRecords(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	store := &memoryStore{entries: make(map[string]entry), now: func() time.Time { return now }}
+	ctx := context.Background()
+
+	_, _, err := store.Claim(ctx, "short", "fp", time.Minute)
+	assert.NoError(t, err)
+	assert.NoError(t, store.Complete(ctx, "long", Record{Fingerprint: "fp", Done: true}, time.Hour))
+	assert.Len(t, store.entries, 2)
+
+	now = now.Add(2 * time.Minute)
+	_, _, err = store.Claim(ctx, "other", "fp", time.Minute)
+	assert.NoError(t, err)
+	assert.Contains(t, store.entries, "long")
+	assert.Contains(t, store.entries, "other")
+	assert.NotContains(t, store.entries, "short")
+}
@@ -0,0 +1,224 @@
+package idempotency
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"io"
+	"log"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"task-api/internal/auth"
+	"task-api/internal/config"
+	"task-api/internal/tenant"
+)
+
+const (
+	maxKeyLength = 255
+	// maxBodySize bounds the request bodies held in memory to fingerprint
+	// them and hand them on.
+	maxBodySize = 1 << 20
+	// uploadOverhead is allowed on top of the maximum attachment size for
+	// the rest of a multipart form, as the attachment handler allows it.
+	uploadOverhead = 1 << 20
+	// pollInterval is how often a duplicate checks whether the request it
+	// waits for has finished.
+	pollInterval = 50 * time.Millisecond
+)
+
+// Middleware handles POST and PATCH requests with an Idempotency-Key once.
+// Retries get the stored status and body with an Idempotent-Replayed
+// header, a key reused for a different request gets a 422, and a duplicate
+// arriving while the first request is still handled waits for its response,
+// or takes the key over once the lock timeout has passed.
+type Middleware gin.HandlerFunc
+
+// NewMiddleware keeps keys apart by tenant and by client, told apart like
+// the rate limiter does, so one client cannot replay another's responses.
+// Server errors and 401s are not stored, so retrying them handles the
+// request again. When the store fails, requests are handled without it.
+// Multipart forms, which carry attachments, may be as large as an upload.
+func NewMiddleware(store Store, cfg config.IdempotencyConfig, attachments config.AttachmentConfig) Middleware {
+	maxUploadSize := attachments.MaxSize + uploadOverhead
+	if maxUploadSize < maxBodySize {
+		maxUploadSize = maxBodySize
+	}
+	return func(c *gin.Context) {
+		key := c.GetHeader(Header)
+		method := c.Request.Method
+		if key == "" || cfg.TTL <= 0 || method != http.MethodPost && method != http.MethodPatch {
+			c.Next()
+			return
+		}
+		if !validKey(key) {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "invalid " + Header})
+			return
+		}
+		limit := int64(maxBodySize)
+		if isMultipart(c.Request) {
+			limit = maxUploadSize
+		}
+		body, err := io.ReadAll(io.LimitReader(c.Request.Body, limit+1))
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "could not read request body"})
+			return
+		}
+		if int64(len(body)) > limit {
+			c.AbortWithStatusJSON(http.StatusRequestEntityTooLarge, gin.H{"error": "request body is too large for an " + Header})
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		ctx := c.Request.Context()
+		key = "idempotency:" + tenant.FromContext(ctx) + ":" + clientKey(c) + ":" + key
+		fp := fingerprint(c.Request, body)
+		for {
+			r, claimed, err := store.Claim(ctx, key, fp, cfg.LockTimeout)
+			if err != nil {
+				log.Printf("idempotency: %v", err)
+				c.Next()
+				return
+			}
+			switch {
+			case claimed:
+				handle(c, store, key, fp, cfg.TTL)
+				return
+			case r.Fingerprint != fp:
+				c.AbortWithStatusJSON(http.StatusUnprocessableEntity, gin.H{"error": Header + " was used for a different request"})
+				return
+			case r.Done:
+				c.Header(ReplayedHeader, "true")
+				c.Data(r.Status, r.ContentType, r.Body)
+				c.Abort()
+				return
+			}
+
+			// The claim expires after the lock timeout at the latest, when
+			// the request holding it is taken to have failed.
+			select {
+			case <-ctx.Done():
+				c.Abort()
+				return
+			case <-time.After(pollInterval):
+			}
+		}
+	}
+}
+
+// handle runs the request holding key and stores its response.
+func handle(c *gin.Context, store Store, key, fp string, ttl time.Duration) {
+	w := &recorder{ResponseWriter: c.Writer}
+	c.Writer = w
+	c.Next()
+	c.Writer = w.ResponseWriter
+
+	// The response is stored even when the client has gone away, since its
+	// retry is what the key is for.
+	ctx := context.WithoutCancel(c.Request.Context())
+	status := w.Status()
+	if status >= http.StatusInternalServerError || status == http.StatusUnauthorized {
+		if err := store.Release(ctx, key); err != nil {
+			log.Printf("idempotency: %v", err)
+		}
+		return
+	}
+	r := Record{
+		Fingerprint: fp,
+		Done:        true,
+		Status:      status,
+		ContentType: w.Header().Get("Content-Type"),
+		Body:        w.body.Bytes(),
+	}
+	if err := store.Complete(ctx, key, r, ttl); err != nil {
+		log.Printf("idempotency: %v", err)
+	}
+}
+
+// recorder keeps a copy of the body written to the client.
+type recorder struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *recorder) Write(b []byte) (int, error) {
+	w.body.Write(b)
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *recorder) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
+
+// fingerprint identifies a request by its method, URI and body. Clients
+// pick a new boundary for every multipart form, so a form is identified by
+// its parts instead, and by its raw body only when it cannot be parsed.
+func fingerprint(r *http.Request, body []byte) string {
+	h := sha256.New()
+	io.WriteString(h, r.Method+" "+r.URL.RequestURI()+"\n")
+	if !isMultipart(r) || hashParts(h, r, body) != nil {
+		h.Reset()
+		io.WriteString(h, r.Method+" "+r.URL.RequestURI()+"\n")
+		h.Write(body)
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// hashParts writes the field name, file name, content type and content of
+// every part of a multipart form to h.
+func hashParts(h hash.Hash, r *http.Request, body []byte) error {
+	_, params, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if err != nil {
+		return err
+	}
+	parts := multipart.NewReader(bytes.NewReader(body), params["boundary"])
+	for {
+		part, err := parts.NextRawPart()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(h, "%q %q %q\n", part.FormName(), part.FileName(), part.Header.Get("Content-Type"))
+		n, err := io.Copy(h, part)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(h, "\n%d\n", n)
+	}
+}
+
+func isMultipart(r *http.Request) bool {
+	mediaType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	return err == nil && strings.HasPrefix(mediaType, "multipart/")
+}
+
+func clientKey(c *gin.Context) string {
+	if identity, ok := auth.FromContext(c.Request.Context()); ok {
+		return "sub:" + identity.Subject
+	}
+	return "ip:" + c.ClientIP()
+}
+
+// validKey accepts up to 255 printable ASCII characters.
+func validKey(key string) bool {
+	if len(key) > maxKeyLength {
+		return false
+	}
+	for i := 0; i < len(key); i++ {
+		if key[i] < 0x20 || key[i] > 0x7e {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,361 @@
+package idempotency
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+
+	"task-api/internal/auth"
+	"task-api/internal/config"
+)
+
+type failingStore struct{}
+
+func (failingStore) Claim(context.Context, string, string, time.Duration) (Record, bool, error) {
+	return Record{}, false, errors.New("connection refused")
+}
+
+func (failingStore) Complete(context.Context, string, Record, time.Duration) error {
+	return errors.New("connection refused")
+}
+
+func (failingStore) Release(context.Context, string) error {
+	return errors.New("connection refused")
+}
+
+// newTestRouter counts the requests reaching the handlers, which answer
+// with that count. POST /fail only fails the first time.
+func newTestRouter(store Store, cfg config.IdempotencyConfig, delay time.Duration) (*gin.Engine, *int32) {
+	var calls int32
+	router := gin.Default()
+	router.Use(func(c *gin.Context) {
+		if subject := c.GetHeader("X-Subject"); subject != "" {
+			c.Request = c.Request.WithContext(auth.NewContext(c.Request.Context(), auth.Identity{Subject: subject}))
+		}
+	}, gin.HandlerFunc(NewMiddleware(store, cfg, config.AttachmentConfig{MaxSize: 2 << 20})))
+	count := func() int {
+		n := atomic.AddInt32(&calls, 1)
+		time.Sleep(delay)
+		return int(n)
+	}
+	router.POST("/tasks", func(c *gin.Context) {
+		c.JSON(http.StatusCreated, gin.H{"id": count()})
+	})
+	router.PATCH("/tasks/1", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"id": 1, "call": count()})
+	})
+	router.GET("/tasks", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"call": count()})
+	})
+	var failed int32
+	router.POST("/fail", func(c *gin.Context) {
+		count()
+		if atomic.CompareAndSwapInt32(&failed, 0, 1) {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "storage unavailable"})
+			return
+		}
+		c.JSON(http.StatusCreated, gin.H{"id": 1})
+	})
+	return router, &calls
+}
+
+func send(router *gin.Engine, method, path, key, subject, body string) *httptest.ResponseRecorder {
+	req, _ := http.NewRequest(method, path, strings.NewReader(body))
+	req.RemoteAddr = "10.0.0.1:1234"
+	if key != "" {
+		req.Header.Set(Header, key)
+	}
+	if subject != "" {
+		req.Header.Set("X-Subject", subject)
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestMiddleware(t *testing.T) {
+	router, _ := newTestRouter(NewMemoryStore(), config.IdempotencyConfig{TTL: time.Hour, LockTimeout: time.Second}, 0)
+
+	type request struct {
+		TestCase         string
+		Method           string
+		Path             string
+		Key              string
+		Subject          string
+		Body             string
+		ExpectedCode     int
+		ExpectedBody     string
+		ExpectedReplayed bool
+	}
+
+	requests := []request{
+		{
+			TestCase:     "first request",
+			Method:       http.MethodPost,
+			Path:         "/tasks",
+			Key:          "create-1",
+			Body:         `{"name":"Report"}`,
+			ExpectedCode: http.StatusCreated,
+			ExpectedBody: `{"id":1}`,
+		},
+		{
+			TestCase:         "retry is replayed",
+			Method:           http.MethodPost,
+			Path:             "/tasks",
+			Key:              "create-1",
+			Body:             `{"name":"Report"}`,
+			ExpectedCode:     http.StatusCreated,
+			ExpectedBody:     `{"id":1}`,
+			ExpectedReplayed: true,
+		},
+		{
+			TestCase:     "key reused with another body",
+			Method:       http.MethodPost,
+			Path:         "/tasks",
+			Key:          "create-1",
+			Body:         `{"name":"Invoice"}`,
+			ExpectedCode: http.StatusUnprocessableEntity,
+			ExpectedBody: `{"error":"Idempotency-Key was used for a different request"}`,
+		},
+		{
+			TestCase:     "key reused on another path",
+			Method:       http.MethodPatch,
+			Path:         "/tasks/1",
+			Key:          "create-1",
+			Body:         `{"name":"Report"}`,
+			ExpectedCode: http.StatusUnprocessableEntity,
+			ExpectedBody: `{"error":"Idempotency-Key was used for a different request"}`,
+		},
+		{
+			TestCase:     "keys of other clients are apart",
+			Method:       http.MethodPost,
+			Path:         "/tasks",
+			Key:          "create-1",
+			Subject:      "alice",
+			Body:         `{"name":"Report"}`,
+			ExpectedCode: http.StatusCreated,
+			ExpectedBody: `{"id":2}`,
+		},
+		{
+			TestCase:     "requests without a key are handled each time",
+			Method:       http.MethodPost,
+			Path:         "/tasks",
+			Body:         `{"name":"Report"}`,
+			ExpectedCode: http.StatusCreated,
+			ExpectedBody: `{"id":3}`,
+		},
+		{
+			TestCase:     "PATCH is covered",
+			Method:       http.MethodPatch,
+			Path:         "/tasks/1",
+			Key:          "update-1",
+			Body:         `{"name":"Report","status":1}`,
+			ExpectedCode: http.StatusOK,
+			ExpectedBody: `{"id":1,"call":4}`,
+		},
+		{
+			TestCase:         "PATCH retry is replayed",
+			Method:           http.MethodPatch,
+			Path:             "/tasks/1",
+			Key:              "update-1",
+			Body:             `{"name":"Report","status":1}`,
+			ExpectedCode:     http.StatusOK,
+			ExpectedBody:     `{"id":1,"call":4}`,
+			ExpectedReplayed: true,
+		},
+		{
+			TestCase:     "GET ignores the key",
+			Method:       http.MethodGet,
+			Path:         "/tasks",
+			Key:          "read-1",
+			ExpectedCode: http.StatusOK,
+			ExpectedBody: `{"call":5}`,
+		},
+		{
+			TestCase:     "server errors are not stored",
+			Method:       http.MethodPost,
+			Path:         "/fail",
+			Key:          "fail-1",
+			ExpectedCode: http.StatusInternalServerError,
+			ExpectedBody: `{"error":"storage unavailable"}`,
+		},
+		{
+			TestCase:     "so the retry is handled again",
+			Method:       http.MethodPost,
+			Path:         "/fail",
+			Key:          "fail-1",
+			ExpectedCode: http.StatusCreated,
+			ExpectedBody: `{"id":1}`,
+		},
+		{
+			TestCase:         "and stored",
+			Method:           http.MethodPost,
+			Path:             "/fail",
+			Key:              "fail-1",
+			ExpectedCode:     http.StatusCreated,
+			ExpectedBody:     `{"id":1}`,
+			ExpectedReplayed: true,
+		},
+		{
+			TestCase:     "invalid key",
+			Method:       http.MethodPost,
+			Path:         "/tasks",
+			Key:          "bad\tkey",
+			ExpectedCode: http.StatusBadRequest,
+			ExpectedBody: `{"error":"invalid Idempotency-Key"}`,
+		},
+	}
+
+	for _, r := range requests {
+		t.Run(r.TestCase, func(t *testing.T) {
+			w := send(router, r.Method, r.Path, r.Key, r.Subject, r.Body)
+			assert.Equal(t, r.ExpectedCode, w.Code)
+			assert.JSONEq(t, r.ExpectedBody, w.Body.String())
+			replayed := ""
+			if r.ExpectedReplayed {
+				replayed = "true"
+			}
+			assert.Equal(t, replayed, w.Header().Get(ReplayedHeader))
+		})
+	}
+}
+
+func TestMiddlewareSerializesDuplicates(t *testing.T) {
+	router, calls := newTestRouter(NewMemoryStore(), config.IdempotencyConfig{TTL: time.Hour, LockTimeout: time.Second}, 100*time.Millisecond)
+
+	var wg sync.WaitGroup
+	responses := make([]*httptest.ResponseRecorder, 5)
+	for i := range responses {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			responses[i] = send(router, http.MethodPost, "/tasks", "create-1", "", `{"name":"Report"}`)
+		}(i)
+	}
+	wg.Wait()
+
+	assert.Equal(t, int32(1), atomic.LoadInt32(calls))
+	replayed := 0
+	for _, w := range responses {
+		assert.Equal(t, http.StatusCreated, w.Code)
+		assert.JSONEq(t, `{"id":1}`, w.Body.String())
+		if w.Header().Get(ReplayedHeader) == "true" {
+			replayed++
+		}
+	}
+	assert.Equal(t, len(responses)-1, replayed)
+}
+
+func TestMiddlewareTakesOverAbandonedKeys(t *testing.T) {
+	store := NewMemoryStore()
+	router, calls := newTestRouter(store, config.IdempotencyConfig{TTL: time.Hour, LockTimeout: 50 * time.Millisecond}, 0)
+
+	// An instance that claimed the key went away before it had a response.
+	req, _ := http.NewRequest(http.MethodPost, "/tasks", nil)
+	_, claimed, err := store.Claim(context.Background(), "idempotency:default:ip:10.0.0.1:create-1",
+		fingerprint(req, []byte(`{"name":"Report"}`)), 50*time.Millisecond)
+	assert.NoError(t, err)
+	assert.True(t, claimed)
+
+	w := send(router, http.MethodPost, "/tasks", "create-1", "", `{"name":"Report"}`)
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.Equal(t, int32(1), atomic.LoadInt32(calls))
+}
+
+func TestMiddlewareLetsThrough(t *testing.T) {
+	type testCase struct {
+		TestCase string
+		Store    Store
+		Config   config.IdempotencyConfig
+	}
+
+	testCases := []testCase{
+		{
+			TestCase: "when the store fails",
+			Store:    failingStore{},
+			Config:   config.IdempotencyConfig{TTL: time.Hour, LockTimeout: time.Second},
+		},
+		{
+			TestCase: "when idempotency keys are off",
+			Store:    NewMemoryStore(),
+			Config:   config.IdempotencyConfig{TTL: 0, LockTimeout: time.Second},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.TestCase, func(t *testing.T) {
+			router, _ := newTestRouter(tc.Store, tc.Config, 0)
+			for i := 1; i <= 3; i++ {
+				w := send(router, http.MethodPost, "/tasks", "create-1", "", `{"name":"Report"}`)
+				assert.Equal(t, http.StatusCreated, w.Code)
+				assert.JSONEq(t, `{"id":`+strconv.Itoa(i)+`}`, w.Body.String())
+				assert.Empty(t, w.Header().Get(ReplayedHeader))
+			}
+		})
+	}
+}
+
+func TestMiddlewareRefusesLargeBodies(t *testing.T) {
+	router, calls := newTestRouter(NewMemoryStore(), config.IdempotencyConfig{TTL: time.Hour, LockTimeout: time.Second}, 0)
+
+	w := send(router, http.MethodPost, "/tasks", "create-1", "", strings.Repeat("x", maxBodySize+1))
+	assert.Equal(t, http.StatusRequestEntityTooLarge, w.Code)
+	assert.Equal(t, int32(0), atomic.LoadInt32(calls))
+}
+
+func TestMiddlewareTakesUploads(t *testing.T) {
+	router, calls := newTestRouter(NewMemoryStore(), config.IdempotencyConfig{TTL: time.Hour, LockTimeout: time.Second}, 0)
+
+	// Every upload is a new form, with a boundary of its own.
+	upload := func(key, content string) *httptest.ResponseRecorder {
+		var body bytes.Buffer
+		form := multipart.NewWriter(&body)
+		file, _ := form.CreateFormFile("file", "report.txt")
+		io.WriteString(file, content)
+		form.Close()
+		req, _ := http.NewRequest(http.MethodPost, "/tasks", &body)
+		req.RemoteAddr = "10.0.0.1:1234"
+		req.Header.Set(Header, key)
+		req.Header.Set("Content-Type", form.FormDataContentType())
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+	large := strings.Repeat("x", maxBodySize+1)
+
+	tests := []struct {
+		TestCase string
+		Key      string
+		Content  string
+		Status   int
+		Replayed string
+		Calls    int32
+	}{
+		{TestCase: "Upload over the body limit", Key: "upload-1", Content: large, Status: http.StatusCreated, Calls: 1},
+		{TestCase: "Retry with another boundary is replayed", Key: "upload-1", Content: large, Status: http.StatusCreated, Replayed: "true", Calls: 1},
+		{TestCase: "Other content under the same key", Key: "upload-1", Content: large + "y", Status: http.StatusUnprocessableEntity, Calls: 1},
+		{TestCase: "Upload over the attachment limit", Key: "upload-2", Content: strings.Repeat("x", 2<<20+uploadOverhead), Status: http.StatusRequestEntityTooLarge, Calls: 1},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.TestCase, func(t *testing.T) {
+			w := upload(tc.Key, tc.Content)
+			assert.Equal(t, tc.Status, w.Code)
+			assert.Equal(t, tc.Replayed, w.Header().Get(ReplayedHeader))
+			assert.Equal(t, tc.Calls, atomic.LoadInt32(calls))
+		})
+	}
+}
@@ -0,0 +1,77 @@
+package blob
+
+import (
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"task-api/internal/config"
+	"task-api/internal/domain/attachment"
+)
+
+func TestFilesystemStore(t *testing.T) {
+	store, err := NewFilesystemStore(config.AttachmentConfig{Dir: t.TempDir()})
+	assert.NoError(t, err)
+
+	n, err := store.Put("tasks/1/abc", strings.NewReader("hello world"))
+	assert.NoError(t, err)
+	assert.Equal(t, int64(11), n)
+
+	tests := []struct {
+		TestCase string
+		Run      func() (interface{}, error)
+		Expected interface{}
+		Error    string
+	}{
+		{
+			TestCase: "Read back with seeking",
+			Run: func() (interface{}, error) {
+				f, err := store.Open("tasks/1/abc")
+				if err != nil {
+					return nil, err
+				}
+				defer f.Close()
+				if _, err := f.Seek(6, io.SeekStart); err != nil {
+					return nil, err
+				}
+				b, err := io.ReadAll(f)
+				return string(b), err
+			},
+			Expected: "world",
+		},
+		{
+			TestCase: "Open missing blob",
+			Run:      func() (interface{}, error) { return store.Open("tasks/1/missing") },
+			Error:    attachment.ErrBlobNotFound.Error(),
+		},
+		{
+			TestCase: "Reject keys escaping the root",
+			Run:      func() (interface{}, error) { return store.Put("../etc/passwd", strings.NewReader("x")) },
+			Error:    `invalid blob key "../etc/passwd"`,
+		},
+		{
+			TestCase: "Delete is idempotent",
+			Run: func() (interface{}, error) {
+				if err := store.Delete("tasks/1/abc"); err != nil {
+					return nil, err
+				}
+				return nil, store.Delete("tasks/1/abc")
+			},
+			Expected: nil,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.TestCase, func(t *testing.T) {
+			result, err := tc.Run()
+			if tc.Error != "" {
+				assert.EqualError(t, err, tc.Error)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.Expected, result)
+		})
+	}
+}
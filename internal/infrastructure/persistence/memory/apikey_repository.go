@@ -0,0 +1,108 @@
+package memory
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"task-api/internal/domain/apikey"
+)
+
+type APIKeyRepository struct {
+	mu     sync.Mutex
+	keys   map[int]apikey.Key
+	byHash map[string]int
+	nextID int
+}
+
+func NewInMemoryAPIKeyRepository() apikey.Repository {
+	return &APIKeyRepository{
+		keys:   make(map[int]apikey.Key),
+		byHash: make(map[string]int),
+		nextID: 1,
+	}
+}
+
+func (r *APIKeyRepository) GetAll() ([]apikey.Key, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var result []apikey.Key
+	for _, k := range r.keys {
+		result = append(result, cloneAPIKey(k))
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].ID < result[j].ID })
+	return result, nil
+}
+
+func (r *APIKeyRepository) GetByID(id int) (apikey.Key, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	k, exists := r.keys[id]
+	if !exists {
+		return apikey.Key{}, apikey.ErrNotFound
+	}
+	return cloneAPIKey(k), nil
+}
+
+func (r *APIKeyRepository) GetByHash(hash string) (apikey.Key, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	id, exists := r.byHash[hash]
+	if !exists {
+		return apikey.Key{}, apikey.ErrNotFound
+	}
+	return cloneAPIKey(r.keys[id]), nil
+}
+
+func (r *APIKeyRepository) Create(k apikey.Key) (apikey.Key, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	k.ID = r.nextID
+	r.nextID++
+	r.keys[k.ID] = cloneAPIKey(k)
+	r.byHash[k.Hash] = k.ID
+	return k, nil
+}
+
+func (r *APIKeyRepository) Touch(id int, at time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	k, exists := r.keys[id]
+	if !exists {
+		return apikey.ErrNotFound
+	}
+	k.LastUsedAt = &at
+	r.keys[id] = k
+	return nil
+}
+
+func (r *APIKeyRepository) Revoke(id int, at time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	k, exists := r.keys[id]
+	if !exists {
+		return apikey.ErrNotFound
+	}
+	k.RevokedAt = &at
+	r.keys[id] = k
+	return nil
+}
+
+func cloneAPIKey(k apikey.Key) apikey.Key {
+	k.Scopes = append([]string(nil), k.Scopes...)
+	if k.LastUsedAt != nil {
+		lastUsedAt := *k.LastUsedAt
+		k.LastUsedAt = &lastUsedAt
+	}
+	if k.RevokedAt != nil {
+		revokedAt := *k.RevokedAt
+		k.RevokedAt = &revokedAt
+	}
+	return k
+}
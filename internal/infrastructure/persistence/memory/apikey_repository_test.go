@@ -0,0 +1,80 @@
+package memory
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"task-api/internal/domain/apikey"
+)
+
+func TestAPIKeys(t *testing.T) {
+	repo := NewInMemoryAPIKeyRepository()
+	createdAt := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	usedAt := createdAt.Add(time.Hour)
+
+	ci, _ := repo.Create(apikey.Key{Name: "CI", Prefix: "tk_abcd", Hash: "hash-ci", Scopes: []string{apikey.ScopeTasksRead}, CreatedAt: createdAt})
+	cron, _ := repo.Create(apikey.Key{Name: "Cron", Prefix: "tk_efgh", Hash: "hash-cron", Scopes: []string{apikey.ScopeTasksWrite}, CreatedAt: createdAt})
+
+	tests := []struct {
+		TestCase string
+		Run      func() (interface{}, error)
+		Expected interface{}
+		Error    error
+	}{
+		{
+			TestCase: "By hash",
+			Run:      func() (interface{}, error) { return repo.GetByHash("hash-cron") },
+			Expected: cron,
+		},
+		{
+			TestCase: "Unknown hash",
+			Run:      func() (interface{}, error) { return repo.GetByHash("hash-none") },
+			Error:    apikey.ErrNotFound,
+		},
+		{
+			TestCase: "Touch and revoke",
+			Run: func() (interface{}, error) {
+				if err := repo.Touch(ci.ID, usedAt); err != nil {
+					return nil, err
+				}
+				if err := repo.Revoke(ci.ID, usedAt); err != nil {
+					return nil, err
+				}
+				return repo.GetByID(ci.ID)
+			},
+			Expected: apikey.Key{ID: ci.ID, Name: "CI", Prefix: "tk_abcd", Hash: "hash-ci", Scopes: []string{apikey.ScopeTasksRead},
+				CreatedAt: createdAt, LastUsedAt: &usedAt, RevokedAt: &usedAt},
+		},
+		{
+			TestCase: "Revoke missing key",
+			Run:      func() (interface{}, error) { return nil, repo.Revoke(99, usedAt) },
+			Error:    apikey.ErrNotFound,
+		},
+		{
+			TestCase: "All keys",
+			Run: func() (interface{}, error) {
+				keys, err := repo.GetAll()
+				var ids []int
+				for _, k := range keys {
+					ids = append(ids, k.ID)
+				}
+				return ids, err
+			},
+			Expected: []int{ci.ID, cron.ID},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.TestCase, func(t *testing.T) {
+			result, err := tc.Run()
+			if tc.Error != nil {
+				assert.Equal(t, tc.Error, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.Expected, result)
+		})
+	}
+}
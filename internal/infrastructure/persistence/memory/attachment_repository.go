@@ -0,0 +1,67 @@
+package memory
+
+import (
+	"sort"
+	"sync"
+
+	"task-api/internal/domain/attachment"
+)
+
+type AttachmentRepository struct {
+	mu          sync.Mutex
+	attachments map[int]attachment.Attachment
+	nextID      int
+}
+
+func NewInMemoryAttachmentRepository() attachment.Repository {
+	return &AttachmentRepository{
+		attachments: make(map[int]attachment.Attachment),
+		nextID:      1,
+	}
+}
+
+func (r *AttachmentRepository) ListByTask(taskID int) ([]attachment.Attachment, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var result []attachment.Attachment
+	for _, a := range r.attachments {
+		if a.TaskID == taskID {
+			result = append(result, a)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].ID < result[j].ID })
+	return result, nil
+}
+
+func (r *AttachmentRepository) GetByID(id int) (attachment.Attachment, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	a, exists := r.attachments[id]
+	if !exists {
+		return attachment.Attachment{}, attachment.ErrNotFound
+	}
+	return a, nil
+}
+
+func (r *AttachmentRepository) Create(a attachment.Attachment) (attachment.Attachment, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	a.ID = r.nextID
+	r.nextID++
+	r.attachments[a.ID] = a
+	return a, nil
+}
+
+func (r *AttachmentRepository) Delete(id int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.attachments[id]; !exists {
+		return attachment.ErrNotFound
+	}
+	delete(r.attachments, id)
+	return nil
+}
@@ -0,0 +1,57 @@
+package memory
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"task-api/internal/domain/attachment"
+)
+
+func TestAttachments(t *testing.T) {
+	repo := NewInMemoryAttachmentRepository()
+
+	log, _ := repo.Create(attachment.Attachment{TaskID: 1, Filename: "build.log", StorageKey: "tasks/1/a"})
+	shot, _ := repo.Create(attachment.Attachment{TaskID: 1, Filename: "screen.png", StorageKey: "tasks/1/b"})
+	_, _ = repo.Create(attachment.Attachment{TaskID: 2, Filename: "other.txt", StorageKey: "tasks/2/c"})
+
+	tests := []struct {
+		TestCase string
+		Run      func() (interface{}, error)
+		Expected interface{}
+		Error    error
+	}{
+		{
+			TestCase: "List by task",
+			Run:      func() (interface{}, error) { return repo.ListByTask(1) },
+			Expected: []attachment.Attachment{log, shot},
+		},
+		{
+			TestCase: "Get by ID",
+			Run:      func() (interface{}, error) { return repo.GetByID(shot.ID) },
+			Expected: shot,
+		},
+		{
+			TestCase: "Delete",
+			Run: func() (interface{}, error) {
+				if err := repo.Delete(log.ID); err != nil {
+					return nil, err
+				}
+				return repo.GetByID(log.ID)
+			},
+			Error: attachment.ErrNotFound,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.TestCase, func(t *testing.T) {
+			result, err := tc.Run()
+			if tc.Error != nil {
+				assert.Equal(t, tc.Error, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.Expected, result)
+		})
+	}
+}
@@ -0,0 +1,59 @@
+package memory
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+
+	"task-api/internal/domain/audit"
+)
+
+type AuditRepository struct {
+	mu      sync.Mutex
+	records []audit.Record
+}
+
+func NewInMemoryAuditRepository() audit.Repository {
+	return &AuditRepository{}
+}
+
+func (r *AuditRepository) Append(_ context.Context, rec audit.Record) (audit.Record, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	rec.ID = len(r.records) + 1
+	r.records = append(r.records, cloneRecord(rec))
+	return rec, nil
+}
+
+// Find returns records in the order they were appended, which is the order
+// of their times.
+func (r *AuditRepository) Find(f audit.Filter) ([]audit.Record, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var result []audit.Record
+	for _, rec := range r.records {
+		if rec.TenantID != f.TenantID ||
+			f.TaskID != 0 && rec.TaskID != f.TaskID ||
+			f.Actor != "" && rec.Actor != f.Actor ||
+			!f.From.IsZero() && rec.Time.Before(f.From) ||
+			!f.To.IsZero() && rec.Time.After(f.To) {
+			continue
+		}
+		result = append(result, cloneRecord(rec))
+	}
+	return result, nil
+}
+
+func cloneRecord(rec audit.Record) audit.Record {
+	changes := make(map[string]audit.Change, len(rec.Changes))
+	for field, c := range rec.Changes {
+		changes[field] = audit.Change{
+			Before: append(json.RawMessage(nil), c.Before...),
+			After:  append(json.RawMessage(nil), c.After...),
+		}
+	}
+	rec.Changes = changes
+	return rec
+}
@@ -0,0 +1,64 @@
+package memory
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"task-api/internal/domain/audit"
+)
+
+func TestAuditRecords(t *testing.T) {
+	ctx := context.Background()
+	repo := NewInMemoryAuditRepository()
+	start := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+
+	created, err := repo.Append(ctx, audit.Record{TaskID: 1, Actor: "alice", Time: start, Operation: audit.OperationCreate,
+		Changes: map[string]audit.Change{"name": {After: json.RawMessage(`"Draft"`)}}})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, created.ID)
+	updated, err := repo.Append(ctx, audit.Record{TaskID: 1, Actor: "bob", Time: start.Add(time.Hour), Operation: audit.OperationUpdate,
+		Changes: map[string]audit.Change{"name": {Before: json.RawMessage(`"Draft"`), After: json.RawMessage(`"Final"`)}}})
+	assert.NoError(t, err)
+	_, err = repo.Append(ctx, audit.Record{TaskID: 2, Actor: "alice", Time: start.Add(2 * time.Hour), Operation: audit.OperationCreate})
+	assert.NoError(t, err)
+	_, err = repo.Append(ctx, audit.Record{TenantID: "acme", TaskID: 1, Actor: "alice", Time: start, Operation: audit.OperationCreate})
+	assert.NoError(t, err)
+
+	// Changing what Append was given leaves the stored record alone.
+	created.Changes["name"] = audit.Change{}
+
+	tests := []struct {
+		TestCase string
+		Filter   audit.Filter
+		Expected []int
+	}{
+		{TestCase: "No filter", Expected: []int{1, 2, 3}},
+		{TestCase: "By task", Filter: audit.Filter{TaskID: 1}, Expected: []int{1, 2}},
+		{TestCase: "By actor", Filter: audit.Filter{Actor: "alice"}, Expected: []int{1, 3}},
+		{TestCase: "From is inclusive", Filter: audit.Filter{From: updated.Time}, Expected: []int{2, 3}},
+		{TestCase: "To is inclusive", Filter: audit.Filter{To: updated.Time}, Expected: []int{1, 2}},
+		{TestCase: "Combined", Filter: audit.Filter{Actor: "alice", From: start.Add(time.Minute)}, Expected: []int{3}},
+		{TestCase: "No match", Filter: audit.Filter{TaskID: 3}, Expected: nil},
+		{TestCase: "Other tenant", Filter: audit.Filter{TenantID: "acme", TaskID: 1}, Expected: []int{4}},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.TestCase, func(t *testing.T) {
+			records, err := repo.Find(tc.Filter)
+			assert.NoError(t, err)
+			var ids []int
+			for _, rec := range records {
+				ids = append(ids, rec.ID)
+			}
+			assert.Equal(t, tc.Expected, ids)
+		})
+	}
+
+	records, err := repo.Find(audit.Filter{TaskID: 1})
+	assert.NoError(t, err)
+	assert.JSONEq(t, `"Draft"`, string(records[0].Changes["name"].After))
+}
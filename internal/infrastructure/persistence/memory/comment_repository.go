@@ -0,0 +1,137 @@
+package memory
+
+import (
+	"sort"
+	"sync"
+
+	"task-api/internal/domain/comment"
+)
+
+type CommentRepository struct {
+	mu       sync.Mutex
+	comments map[int]comment.Comment
+	nextID   int
+}
+
+func NewInMemoryCommentRepository() comment.Repository {
+	return &CommentRepository{
+		comments: make(map[int]comment.Comment),
+		nextID:   1,
+	}
+}
+
+func (r *CommentRepository) List(taskID int, parentID *int, offset, limit int) ([]comment.Comment, int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var matching []comment.Comment
+	for _, c := range r.comments {
+		if c.TaskID == taskID && sameParent(c.ParentID, parentID) {
+			matching = append(matching, r.withReplyCount(c))
+		}
+	}
+	sort.Slice(matching, func(i, j int) bool { return matching[i].ID < matching[j].ID })
+
+	total := len(matching)
+	if offset >= total {
+		return nil, total, nil
+	}
+	end := offset + limit
+	if end > total {
+		end = total
+	}
+	return matching[offset:end], total, nil
+}
+
+func (r *CommentRepository) GetByID(id int) (comment.Comment, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	c, exists := r.comments[id]
+	if !exists {
+		return comment.Comment{}, comment.ErrNotFound
+	}
+	return r.withReplyCount(c), nil
+}
+
+func (r *CommentRepository) Create(c comment.Comment) (comment.Comment, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	c.ID = r.nextID
+	r.nextID++
+	c.ReplyCount = 0
+	r.comments[c.ID] = cloneComment(c)
+	return c, nil
+}
+
+func (r *CommentRepository) Update(c comment.Comment) (comment.Comment, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.comments[c.ID]; !exists {
+		return comment.Comment{}, comment.ErrNotFound
+	}
+	r.comments[c.ID] = cloneComment(c)
+	return r.withReplyCount(c), nil
+}
+
+func (r *CommentRepository) Delete(id int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.comments[id]; !exists {
+		return comment.ErrNotFound
+	}
+	queue := []int{id}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		delete(r.comments, current)
+		for _, c := range r.comments {
+			if c.ParentID != nil && *c.ParentID == current {
+				queue = append(queue, c.ID)
+			}
+		}
+	}
+	return nil
+}
+
+func (r *CommentRepository) DeleteByTask(taskID int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for id, c := range r.comments {
+		if c.TaskID == taskID {
+			delete(r.comments, id)
+		}
+	}
+	return nil
+}
+
+// withReplyCount must be called with the lock held.
+func (r *CommentRepository) withReplyCount(c comment.Comment) comment.Comment {
+	c = cloneComment(c)
+	c.ReplyCount = 0
+	for _, other := range r.comments {
+		if other.ParentID != nil && *other.ParentID == c.ID {
+			c.ReplyCount++
+		}
+	}
+	return c
+}
+
+func sameParent(a, b *int) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return *a == *b
+}
+
+func cloneComment(c comment.Comment) comment.Comment {
+	if c.ParentID != nil {
+		parentID := *c.ParentID
+		c.ParentID = &parentID
+	}
+	return c
+}
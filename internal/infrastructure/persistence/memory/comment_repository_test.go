@@ -0,0 +1,105 @@
+package memory
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"task-api/internal/domain/comment"
+)
+
+func TestComments(t *testing.T) {
+	repo := NewInMemoryCommentRepository()
+
+	first, _ := repo.Create(comment.Comment{TaskID: 1, Author: "alice", Body: "First"})
+	second, _ := repo.Create(comment.Comment{TaskID: 1, Author: "bob", Body: "Second"})
+	reply, _ := repo.Create(comment.Comment{TaskID: 1, ParentID: &first.ID, Author: "bob", Body: "Reply"})
+	nested, _ := repo.Create(comment.Comment{TaskID: 1, ParentID: &reply.ID, Author: "alice", Body: "Nested"})
+	_, _ = repo.Create(comment.Comment{TaskID: 2, Author: "carol", Body: "Elsewhere"})
+
+	type page struct {
+		IDs   []int
+		Total int
+	}
+	list := func(parentID *int, offset, limit int) (interface{}, error) {
+		comments, total, err := repo.List(1, parentID, offset, limit)
+		var ids []int
+		for _, c := range comments {
+			ids = append(ids, c.ID)
+		}
+		return page{IDs: ids, Total: total}, err
+	}
+
+	tests := []struct {
+		TestCase string
+		Run      func() (interface{}, error)
+		Expected interface{}
+		Error    error
+	}{
+		{
+			TestCase: "Top-level comments of a task",
+			Run:      func() (interface{}, error) { return list(nil, 0, 10) },
+			Expected: page{IDs: []int{first.ID, second.ID}, Total: 2},
+		},
+		{
+			TestCase: "Paginated top-level comments",
+			Run:      func() (interface{}, error) { return list(nil, 1, 1) },
+			Expected: page{IDs: []int{second.ID}, Total: 2},
+		},
+		{
+			TestCase: "Offset past the end",
+			Run:      func() (interface{}, error) { return list(nil, 5, 10) },
+			Expected: page{Total: 2},
+		},
+		{
+			TestCase: "Replies of a comment",
+			Run:      func() (interface{}, error) { return list(&first.ID, 0, 10) },
+			Expected: page{IDs: []int{reply.ID}, Total: 1},
+		},
+		{
+			TestCase: "Reply count",
+			Run: func() (interface{}, error) {
+				c, err := repo.GetByID(first.ID)
+				return c.ReplyCount, err
+			},
+			Expected: 1,
+		},
+		{
+			TestCase: "Deleting a comment deletes its replies",
+			Run: func() (interface{}, error) {
+				if err := repo.Delete(first.ID); err != nil {
+					return nil, err
+				}
+				return repo.GetByID(nested.ID)
+			},
+			Error: comment.ErrNotFound,
+		},
+		{
+			TestCase: "Delete comments of a task",
+			Run: func() (interface{}, error) {
+				if err := repo.DeleteByTask(1); err != nil {
+					return nil, err
+				}
+				return list(nil, 0, 10)
+			},
+			Expected: page{Total: 0},
+		},
+		{
+			TestCase: "Update missing comment",
+			Run:      func() (interface{}, error) { return repo.Update(comment.Comment{ID: 99, Body: "Nope"}) },
+			Error:    comment.ErrNotFound,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.TestCase, func(t *testing.T) {
+			result, err := tc.Run()
+			if tc.Error != nil {
+				assert.Equal(t, tc.Error, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.Expected, result)
+		})
+	}
+}
@@ -0,0 +1,209 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"task-api/internal/domain/label"
+	"task-api/internal/tenant"
+)
+
+// LabelRepository keeps the labels of every tenant apart, as the task
+// repository keeps their tasks.
+type LabelRepository struct {
+	mu     sync.Mutex
+	labels map[int]label.Label
+	nextID int
+	// tasks is the inverted index from a label to the tasks carrying it.
+	tasks map[int]map[int]bool
+}
+
+func NewInMemoryLabelRepository() label.Repository {
+	return &LabelRepository{
+		labels: make(map[int]label.Label),
+		nextID: 1,
+		tasks:  make(map[int]map[int]bool),
+	}
+}
+
+func (r *LabelRepository) GetAll(ctx context.Context) ([]label.Label, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	tenantID := tenant.FromContext(ctx)
+	var result []label.Label
+	for _, l := range r.labels {
+		if l.TenantID == tenantID {
+			result = append(result, l)
+		}
+	}
+	sortLabels(result)
+	return result, nil
+}
+
+func (r *LabelRepository) GetByID(ctx context.Context, id int) (label.Label, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	l, exists := r.get(ctx, id)
+	if !exists {
+		return label.Label{}, label.ErrNotFound
+	}
+	return l, nil
+}
+
+func (r *LabelRepository) GetByName(ctx context.Context, name string) (label.Label, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	tenantID := tenant.FromContext(ctx)
+	for _, l := range r.labels {
+		if l.TenantID == tenantID && l.Name == name {
+			return l, nil
+		}
+	}
+	return label.Label{}, label.ErrNotFound
+}
+
+func (r *LabelRepository) Create(ctx context.Context, l label.Label) (label.Label, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	l.TenantID = tenant.FromContext(ctx)
+	if r.nameTaken(l.TenantID, l.Name, 0) {
+		return label.Label{}, label.ErrDuplicateName
+	}
+	l.ID = r.nextID
+	r.nextID++
+	r.labels[l.ID] = l
+	return l, nil
+}
+
+func (r *LabelRepository) Update(ctx context.Context, l label.Label) (label.Label, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	l.TenantID = tenant.FromContext(ctx)
+	if _, exists := r.get(ctx, l.ID); !exists {
+		return label.Label{}, label.ErrNotFound
+	}
+	if r.nameTaken(l.TenantID, l.Name, l.ID) {
+		return label.Label{}, label.ErrDuplicateName
+	}
+	r.labels[l.ID] = l
+	return l, nil
+}
+
+func (r *LabelRepository) Delete(ctx context.Context, id int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.get(ctx, id); !exists {
+		return label.ErrNotFound
+	}
+	delete(r.labels, id)
+	delete(r.tasks, id)
+	return nil
+}
+
+func (r *LabelRepository) Attach(ctx context.Context, taskID, labelID int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.get(ctx, labelID); !exists {
+		return label.ErrNotFound
+	}
+	if r.tasks[labelID] == nil {
+		r.tasks[labelID] = make(map[int]bool)
+	}
+	r.tasks[labelID][taskID] = true
+	return nil
+}
+
+func (r *LabelRepository) Detach(ctx context.Context, taskID, labelID int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.get(ctx, labelID); !exists || !r.tasks[labelID][taskID] {
+		return label.ErrNotAttached
+	}
+	delete(r.tasks[labelID], taskID)
+	return nil
+}
+
+func (r *LabelRepository) DetachAll(ctx context.Context, taskID int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, tasks := range r.tasks {
+		delete(tasks, taskID)
+	}
+	return nil
+}
+
+func (r *LabelRepository) GetTaskLabels(ctx context.Context, taskIDs []int) (map[int][]label.Label, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	result := make(map[int][]label.Label)
+	for _, taskID := range taskIDs {
+		for labelID, tasks := range r.tasks {
+			if l, exists := r.get(ctx, labelID); exists && tasks[taskID] {
+				result[taskID] = append(result[taskID], l)
+			}
+		}
+	}
+	for _, labels := range result {
+		sortLabels(labels)
+	}
+	return result, nil
+}
+
+func (r *LabelRepository) FindTasks(ctx context.Context, labelIDs []int, match label.Match) ([]int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	counts := make(map[int]int)
+	for _, labelID := range labelIDs {
+		if _, exists := r.get(ctx, labelID); !exists {
+			continue
+		}
+		for taskID := range r.tasks[labelID] {
+			counts[taskID]++
+		}
+	}
+
+	var result []int
+	for taskID, count := range counts {
+		if match != label.MatchAll || count == len(labelIDs) {
+			result = append(result, taskID)
+		}
+	}
+	sort.Ints(result)
+	return result, nil
+}
+
+// get returns a label of the tenant of ctx. It must be called with the lock
+// held.
+func (r *LabelRepository) get(ctx context.Context, id int) (label.Label, bool) {
+	l, exists := r.labels[id]
+	if !exists || l.TenantID != tenant.FromContext(ctx) {
+		return label.Label{}, false
+	}
+	return l, true
+}
+
+// nameTaken must be called with the lock held.
+func (r *LabelRepository) nameTaken(tenantID, name string, exceptID int) bool {
+	for _, l := range r.labels {
+		if l.TenantID == tenantID && l.Name == name && l.ID != exceptID {
+			return true
+		}
+	}
+	return false
+}
+
+func sortLabels(labels []label.Label) {
+	sort.Slice(labels, func(i, j int) bool { return labels[i].Name < labels[j].Name })
+}
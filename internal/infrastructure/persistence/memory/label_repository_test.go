@@ -0,0 +1,208 @@
+package memory
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"task-api/internal/domain/label"
+	"task-api/internal/tenant"
+)
+
+func TestLabels(t *testing.T) {
+	ctx := context.Background()
+	repo := NewInMemoryLabelRepository()
+
+	urgent, err := repo.Create(ctx, label.Label{Name: "urgent", Color: "#ff0000"})
+	assert.NoError(t, err)
+	backend, err := repo.Create(ctx, label.Label{Name: "backend"})
+	assert.NoError(t, err)
+
+	tests := []struct {
+		TestCase string
+		Run      func() (interface{}, error)
+		Expected interface{}
+		Error    error
+	}{
+		{
+			TestCase: "Get all sorted by name",
+			Run:      func() (interface{}, error) { return repo.GetAll(ctx) },
+			Expected: []label.Label{backend, urgent},
+		},
+		{
+			TestCase: "Get by name",
+			Run:      func() (interface{}, error) { return repo.GetByName(ctx, "urgent") },
+			Expected: urgent,
+		},
+		{
+			TestCase: "Duplicate name",
+			Run:      func() (interface{}, error) { return repo.Create(ctx, label.Label{Name: "urgent"}) },
+			Error:    label.ErrDuplicateName,
+		},
+		{
+			TestCase: "Rename onto another label",
+			Run:      func() (interface{}, error) { return repo.Update(ctx, label.Label{ID: backend.ID, Name: "urgent"}) },
+			Error:    label.ErrDuplicateName,
+		},
+		{
+			TestCase: "Delete missing label",
+			Run:      func() (interface{}, error) { return nil, repo.Delete(ctx, 99) },
+			Error:    label.ErrNotFound,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.TestCase, func(t *testing.T) {
+			result, err := tc.Run()
+			if tc.Error != nil {
+				assert.Equal(t, tc.Error, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.Expected, result)
+		})
+	}
+}
+
+func TestLabelAttachments(t *testing.T) {
+	ctx := context.Background()
+	repo := NewInMemoryLabelRepository()
+
+	backend, _ := repo.Create(ctx, label.Label{Name: "backend"})
+	urgent, _ := repo.Create(ctx, label.Label{Name: "urgent"})
+	assert.NoError(t, repo.Attach(ctx, 1, backend.ID))
+	assert.NoError(t, repo.Attach(ctx, 1, urgent.ID))
+	assert.NoError(t, repo.Attach(ctx, 2, backend.ID))
+	assert.NoError(t, repo.Attach(ctx, 2, backend.ID))
+	assert.NoError(t, repo.Attach(ctx, 3, urgent.ID))
+
+	tests := []struct {
+		TestCase string
+		Run      func() (interface{}, error)
+		Expected interface{}
+		Error    error
+	}{
+		{
+			TestCase: "Any of the labels",
+			Run:      func() (interface{}, error) { return repo.FindTasks(ctx, []int{backend.ID, urgent.ID}, label.MatchAny) },
+			Expected: []int{1, 2, 3},
+		},
+		{
+			TestCase: "All of the labels",
+			Run:      func() (interface{}, error) { return repo.FindTasks(ctx, []int{backend.ID, urgent.ID}, label.MatchAll) },
+			Expected: []int{1},
+		},
+		{
+			TestCase: "Labels per task",
+			Run:      func() (interface{}, error) { return repo.GetTaskLabels(ctx, []int{1, 2, 4}) },
+			Expected: map[int][]label.Label{1: {backend, urgent}, 2: {backend}},
+		},
+		{
+			TestCase: "Attach unknown label",
+			Run:      func() (interface{}, error) { return nil, repo.Attach(ctx, 1, 99) },
+			Error:    label.ErrNotFound,
+		},
+		{
+			TestCase: "Detach label that is not attached",
+			Run:      func() (interface{}, error) { return nil, repo.Detach(ctx, 3, backend.ID) },
+			Error:    label.ErrNotAttached,
+		},
+		{
+			TestCase: "Deleting a label detaches it",
+			Run: func() (interface{}, error) {
+				if err := repo.Delete(ctx, urgent.ID); err != nil {
+					return nil, err
+				}
+				return repo.GetTaskLabels(ctx, []int{1, 3})
+			},
+			Expected: map[int][]label.Label{1: {backend}},
+		},
+		{
+			TestCase: "Detach all labels of a task",
+			Run: func() (interface{}, error) {
+				if err := repo.DetachAll(ctx, 1); err != nil {
+					return nil, err
+				}
+				return repo.FindTasks(ctx, []int{backend.ID}, label.MatchAny)
+			},
+			Expected: []int{2},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.TestCase, func(t *testing.T) {
+			result, err := tc.Run()
+			if tc.Error != nil {
+				assert.Equal(t, tc.Error, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.Expected, result)
+		})
+	}
+}
+
+func TestLabelTenantIsolation(t *testing.T) {
+	repo := NewInMemoryLabelRepository()
+	acme := tenant.NewContext(context.Background(), "acme")
+	globex := tenant.NewContext(context.Background(), "globex")
+	urgent, _ := repo.Create(acme, label.Label{Name: "urgent"})
+	assert.NoError(t, repo.Attach(acme, 1, urgent.ID))
+
+	tests := []struct {
+		TestCase string
+		Run      func() (interface{}, error)
+		Expected interface{}
+		Error    error
+	}{
+		{
+			TestCase: "The same name in another tenant",
+			Run: func() (interface{}, error) {
+				l, err := repo.Create(globex, label.Label{Name: "urgent"})
+				return l.TenantID, err
+			},
+			Expected: "globex",
+		},
+		{
+			TestCase: "Get a label of another tenant",
+			Run:      func() (interface{}, error) { return repo.GetByID(globex, urgent.ID) },
+			Error:    label.ErrNotFound,
+		},
+		{
+			TestCase: "Delete a label of another tenant",
+			Run:      func() (interface{}, error) { return nil, repo.Delete(globex, urgent.ID) },
+			Error:    label.ErrNotFound,
+		},
+		{
+			TestCase: "Attach a label of another tenant",
+			Run:      func() (interface{}, error) { return nil, repo.Attach(globex, 2, urgent.ID) },
+			Error:    label.ErrNotFound,
+		},
+		{
+			TestCase: "Find tasks by a label of another tenant",
+			Run:      func() (interface{}, error) { return repo.FindTasks(globex, []int{urgent.ID}, label.MatchAny) },
+			Expected: []int(nil),
+		},
+		{
+			TestCase: "List the labels of a tenant",
+			Run: func() (interface{}, error) {
+				labels, err := repo.GetAll(acme)
+				return len(labels), err
+			},
+			Expected: 1,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.TestCase, func(t *testing.T) {
+			result, err := tc.Run()
+			if tc.Error != nil {
+				assert.Equal(t, tc.Error, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.Expected, result)
+		})
+	}
+}
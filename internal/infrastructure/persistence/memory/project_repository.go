@@ -0,0 +1,80 @@
+package memory
+
+import (
+	"sort"
+	"sync"
+
+	"task-api/internal/domain/project"
+)
+
+type ProjectRepository struct {
+	mu       sync.Mutex
+	projects map[int]project.Project
+	nextID   int
+}
+
+func NewInMemoryProjectRepository() project.Repository {
+	return &ProjectRepository{
+		projects: map[int]project.Project{
+			project.DefaultProjectID: {ID: project.DefaultProjectID, Name: "Default"},
+		},
+		nextID: project.DefaultProjectID + 1,
+	}
+}
+
+func (r *ProjectRepository) GetAll(includeArchived bool) ([]project.Project, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var result []project.Project
+	for _, p := range r.projects {
+		if includeArchived || !p.Archived() {
+			result = append(result, cloneProject(p))
+		}
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].ID < result[j].ID })
+	return result, nil
+}
+
+func (r *ProjectRepository) GetByID(id int) (project.Project, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	p, exists := r.projects[id]
+	if !exists {
+		return project.Project{}, project.ErrNotFound
+	}
+	return cloneProject(p), nil
+}
+
+func (r *ProjectRepository) Create(p project.Project) (project.Project, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	p.ID = r.nextID
+	r.nextID++
+	r.projects[p.ID] = cloneProject(p)
+	return p, nil
+}
+
+func (r *ProjectRepository) Update(p project.Project) (project.Project, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.projects[p.ID]; !exists {
+		return project.Project{}, project.ErrNotFound
+	}
+	r.projects[p.ID] = cloneProject(p)
+	return p, nil
+}
+
+// cloneProject copies the archive time and drops the computed counts.
+func cloneProject(p project.Project) project.Project {
+	if p.ArchivedAt != nil {
+		archivedAt := *p.ArchivedAt
+		p.ArchivedAt = &archivedAt
+	}
+	p.TaskCount = 0
+	p.DoneCount = 0
+	return p
+}
@@ -0,0 +1,59 @@
+package memory
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"task-api/internal/domain/project"
+)
+
+func TestProjects(t *testing.T) {
+	repo := NewInMemoryProjectRepository()
+	archivedAt := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+
+	defaultProject := project.Project{ID: project.DefaultProjectID, Name: "Default"}
+	website, _ := repo.Create(project.Project{Name: "Website"})
+	legacy, _ := repo.Create(project.Project{Name: "Legacy", ArchivedAt: &archivedAt})
+
+	tests := []struct {
+		TestCase string
+		Run      func() (interface{}, error)
+		Expected interface{}
+		Error    error
+	}{
+		{
+			TestCase: "Default project exists",
+			Run:      func() (interface{}, error) { return repo.GetByID(project.DefaultProjectID) },
+			Expected: defaultProject,
+		},
+		{
+			TestCase: "Active projects",
+			Run:      func() (interface{}, error) { return repo.GetAll(false) },
+			Expected: []project.Project{defaultProject, website},
+		},
+		{
+			TestCase: "All projects",
+			Run:      func() (interface{}, error) { return repo.GetAll(true) },
+			Expected: []project.Project{defaultProject, website, legacy},
+		},
+		{
+			TestCase: "Update missing project",
+			Run:      func() (interface{}, error) { return repo.Update(project.Project{ID: 99, Name: "Nope"}) },
+			Error:    project.ErrNotFound,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.TestCase, func(t *testing.T) {
+			result, err := tc.Run()
+			if tc.Error != nil {
+				assert.Equal(t, tc.Error, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.Expected, result)
+		})
+	}
+}
@@ -0,0 +1,60 @@
+package memory
+
+import (
+	"sync"
+	"time"
+
+	"task-api/internal/domain/reminder"
+)
+
+type reminderKey struct {
+	taskID int
+	window time.Duration
+	dueAt  int64
+}
+
+type ReminderRepository struct {
+	mu        sync.Mutex
+	reminders map[reminderKey]reminder.Reminder
+}
+
+func NewInMemoryReminderRepository() reminder.Repository {
+	return &ReminderRepository{reminders: make(map[reminderKey]reminder.Reminder)}
+}
+
+func (r *ReminderRepository) Claim(rem reminder.Reminder) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	key := keyOf(rem)
+	if _, exists := r.reminders[key]; exists {
+		return reminder.ErrAlreadySent
+	}
+	r.reminders[key] = rem
+	return nil
+}
+
+func (r *ReminderRepository) Release(rem reminder.Reminder) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.reminders, keyOf(rem))
+	return nil
+}
+
+func (r *ReminderRepository) DeleteByTask(taskID int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for key := range r.reminders {
+		if key.taskID == taskID {
+			delete(r.reminders, key)
+		}
+	}
+	return nil
+}
+
+// keyOf identifies a reminder at the second precision MySQL stores.
+func keyOf(rem reminder.Reminder) reminderKey {
+	return reminderKey{taskID: rem.TaskID, window: rem.Window, dueAt: rem.DueAt.Unix()}
+}
@@ -0,0 +1,62 @@
+package memory
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"task-api/internal/domain/reminder"
+)
+
+func TestReminders(t *testing.T) {
+	repo := NewInMemoryReminderRepository()
+	dueAt := time.Date(2024, 3, 25, 9, 0, 0, 0, time.UTC)
+	dayBefore := reminder.Reminder{TaskID: 1, Window: 24 * time.Hour, DueAt: dueAt}
+
+	tests := []struct {
+		TestCase string
+		Run      func() error
+		Error    error
+	}{
+		{
+			TestCase: "Claim a reminder",
+			Run:      func() error { return repo.Claim(dayBefore) },
+		},
+		{
+			TestCase: "Claim it again",
+			Run:      func() error { return repo.Claim(dayBefore) },
+			Error:    reminder.ErrAlreadySent,
+		},
+		{
+			TestCase: "Another window of the same task",
+			Run:      func() error { return repo.Claim(reminder.Reminder{TaskID: 1, DueAt: dueAt}) },
+		},
+		{
+			TestCase: "The same window for a moved due date",
+			Run: func() error {
+				return repo.Claim(reminder.Reminder{TaskID: 1, Window: 24 * time.Hour, DueAt: dueAt.Add(time.Hour)})
+			},
+		},
+		{
+			TestCase: "Claim again after a release",
+			Run: func() error {
+				_ = repo.Release(dayBefore)
+				return repo.Claim(dayBefore)
+			},
+		},
+		{
+			TestCase: "Claim again after the task is deleted",
+			Run: func() error {
+				_ = repo.DeleteByTask(1)
+				return repo.Claim(dayBefore)
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.TestCase, func(t *testing.T) {
+			assert.Equal(t, tc.Error, tc.Run())
+		})
+	}
+}
@@ -0,0 +1,58 @@
+package memory
+
+import (
+	"sort"
+	"sync"
+
+	"task-api/internal/domain/role"
+)
+
+type roleKey struct {
+	userID    int
+	projectID int
+}
+
+type RoleRepository struct {
+	mu          sync.RWMutex
+	assignments map[roleKey]role.Role
+}
+
+func NewInMemoryRoleRepository() role.Repository {
+	return &RoleRepository{assignments: make(map[roleKey]role.Role)}
+}
+
+func (r *RoleRepository) GetByUser(userID int) ([]role.Assignment, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var assignments []role.Assignment
+	for key, rl := range r.assignments {
+		if key.userID == userID {
+			assignments = append(assignments, role.Assignment{UserID: userID, ProjectID: key.projectID, Role: rl})
+		}
+	}
+	sort.Slice(assignments, func(i, j int) bool {
+		return assignments[i].ProjectID < assignments[j].ProjectID
+	})
+	return assignments, nil
+}
+
+func (r *RoleRepository) Set(a role.Assignment) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.assignments[roleKey{userID: a.UserID, projectID: a.ProjectID}] = a.Role
+	return nil
+}
+
+func (r *RoleRepository) Delete(userID, projectID int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	key := roleKey{userID: userID, projectID: projectID}
+	if _, ok := r.assignments[key]; !ok {
+		return role.ErrNotFound
+	}
+	delete(r.assignments, key)
+	return nil
+}
@@ -0,0 +1,69 @@
+package memory
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"task-api/internal/domain/role"
+)
+
+func TestRoles(t *testing.T) {
+	repo := NewInMemoryRoleRepository()
+	assert.NoError(t, repo.Set(role.Assignment{UserID: 1, ProjectID: 3, Role: role.Admin}))
+	assert.NoError(t, repo.Set(role.Assignment{UserID: 1, Role: role.Viewer}))
+	assert.NoError(t, repo.Set(role.Assignment{UserID: 2, Role: role.Viewer}))
+
+	tests := []struct {
+		TestCase string
+		Run      func() (interface{}, error)
+		Expected interface{}
+		Error    error
+	}{
+		{
+			TestCase: "Global assignment comes first",
+			Run:      func() (interface{}, error) { return repo.GetByUser(1) },
+			Expected: []role.Assignment{{UserID: 1, Role: role.Viewer}, {UserID: 1, ProjectID: 3, Role: role.Admin}},
+		},
+		{
+			TestCase: "Set replaces the role",
+			Run: func() (interface{}, error) {
+				if err := repo.Set(role.Assignment{UserID: 2, Role: role.Editor}); err != nil {
+					return nil, err
+				}
+				return repo.GetByUser(2)
+			},
+			Expected: []role.Assignment{{UserID: 2, Role: role.Editor}},
+		},
+		{
+			TestCase: "Delete",
+			Run: func() (interface{}, error) {
+				if err := repo.Delete(1, 3); err != nil {
+					return nil, err
+				}
+				return repo.GetByUser(1)
+			},
+			Expected: []role.Assignment{{UserID: 1, Role: role.Viewer}},
+		},
+		{
+			TestCase: "Delete missing assignment",
+			Run:      func() (interface{}, error) { return nil, repo.Delete(1, 3) },
+			Error:    role.ErrNotFound,
+		},
+		{
+			TestCase: "User without roles",
+			Run:      func() (interface{}, error) { return repo.GetByUser(9) },
+			Expected: []role.Assignment(nil),
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.TestCase, func(t *testing.T) {
+			result, err := tc.Run()
+			assert.Equal(t, tc.Error, err)
+			if tc.Error == nil {
+				assert.Equal(t, tc.Expected, result)
+			}
+		})
+	}
+}
@@ -1,76 +1,383 @@
 package memory
 
 import (
-	"errors"
+	"context"
+	"sort"
 	"sync"
+	"time"
 
+	"task-api/internal/domain/project"
 	"task-api/internal/domain/task"
+	"task-api/internal/search"
+	"task-api/internal/tenant"
 )
 
 type TaskRepository struct {
 	mu     sync.Mutex
 	tasks  map[int]task.Info
 	nextID int
+	// blockers maps a blocked task to the set of tasks blocking it.
+	blockers map[int]map[int]bool
+	// assigned indexes the tasks of each assignee.
+	assigned map[int]map[int]bool
+	// names holds the full-text index of task names of each tenant.
+	names map[string]*search.Index
 }
 
 func NewInMemoryTaskRepository() task.Repository {
 	return &TaskRepository{
-		tasks:  make(map[int]task.Info),
-		nextID: 1,
+		tasks:    make(map[int]task.Info),
+		nextID:   1,
+		blockers: make(map[int]map[int]bool),
+		assigned: make(map[int]map[int]bool),
+		names:    make(map[string]*search.Index),
 	}
 }
 
-func (r *TaskRepository) GetAll() ([]task.Info, error) {
+func (r *TaskRepository) GetAll(ctx context.Context) ([]task.Info, error) {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
+	tenantID := tenant.FromContext(ctx)
 	var result []task.Info
 	for _, t := range r.tasks {
-		result = append(result, t)
+		if t.TenantID == tenantID {
+			result = append(result, clone(t))
+		}
 	}
 	return result, nil
 }
 
-func (r *TaskRepository) GetByID(id int) (task.Info, error) {
+func (r *TaskRepository) GetByID(ctx context.Context, id int) (task.Info, error) {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
-	t, exists := r.tasks[id]
+	t, exists := r.get(ctx, id)
 	if !exists {
-		return task.Info{}, errors.New("task not found")
+		return task.Info{}, task.ErrNotFound
 	}
-	return t, nil
+	return clone(t), nil
 }
 
-func (r *TaskRepository) Create(t task.Info) (task.Info, error) {
+func (r *TaskRepository) Create(ctx context.Context, t task.Info) (task.Info, error) {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
+	t.TenantID = tenant.FromContext(ctx)
+	t.ProjectID = projectOrDefault(t.ProjectID)
 	t.ID = r.nextID
 	r.nextID++
-	r.tasks[t.ID] = t
+	r.tasks[t.ID] = clone(t)
+	r.index(t.ID, nil, t.AssigneeID)
+	r.namesOf(t.TenantID).Add(t.ID, t.Name)
 	return t, nil
 }
 
-func (r *TaskRepository) Update(t task.Info) (task.Info, error) {
+func (r *TaskRepository) Update(ctx context.Context, t task.Info) (task.Info, error) {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
-	_, exists := r.tasks[t.ID]
+	existing, exists := r.get(ctx, t.ID)
 	if !exists {
-		return task.Info{}, errors.New("task not found")
+		return task.Info{}, task.ErrNotFound
 	}
-	r.tasks[t.ID] = t
+	t.TenantID = existing.TenantID
+	t.ProjectID = projectOrDefault(t.ProjectID)
+	r.tasks[t.ID] = clone(t)
+	r.index(t.ID, existing.AssigneeID, t.AssigneeID)
+	r.namesOf(t.TenantID).Add(t.ID, t.Name)
 	return t, nil
 }
 
-func (r *TaskRepository) Delete(id int) error {
+func (r *TaskRepository) Delete(ctx context.Context, id int) error {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
-	if _, exists := r.tasks[id]; !exists {
-		return errors.New("task not found")
+	existing, exists := r.get(ctx, id)
+	if !exists {
+		return task.ErrNotFound
 	}
+	r.index(id, existing.AssigneeID, nil)
+	r.namesOf(existing.TenantID).Remove(id)
 	delete(r.tasks, id)
+	delete(r.blockers, id)
+	for _, blockers := range r.blockers {
+		delete(blockers, id)
+	}
+	return nil
+}
+
+func (r *TaskRepository) GetChildren(ctx context.Context, parentID int) ([]task.Info, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.children(tenant.FromContext(ctx), parentID), nil
+}
+
+func (r *TaskRepository) GetDescendants(ctx context.Context, id int) ([]task.Info, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	tenantID := tenant.FromContext(ctx)
+	var result []task.Info
+	queue := []int{id}
+	visited := map[int]bool{id: true}
+	for len(queue) > 0 {
+		for _, child := range r.children(tenantID, queue[0]) {
+			if visited[child.ID] {
+				continue
+			}
+			visited[child.ID] = true
+			result = append(result, child)
+			queue = append(queue, child.ID)
+		}
+		queue = queue[1:]
+	}
+	return result, nil
+}
+
+func (r *TaskRepository) AddDependency(ctx context.Context, blockerID, blockedID int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.get(ctx, blockerID); !exists {
+		return task.ErrNotFound
+	}
+	if _, exists := r.get(ctx, blockedID); !exists {
+		return task.ErrNotFound
+	}
+	if r.blockers[blockedID] == nil {
+		r.blockers[blockedID] = make(map[int]bool)
+	}
+	r.blockers[blockedID][blockerID] = true
 	return nil
 }
+
+func (r *TaskRepository) RemoveDependency(ctx context.Context, blockerID, blockedID int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	// Dependencies never cross tenants, so checking one end suffices.
+	if _, exists := r.get(ctx, blockedID); !exists || !r.blockers[blockedID][blockerID] {
+		return task.ErrDependencyNotFound
+	}
+	delete(r.blockers[blockedID], blockerID)
+	return nil
+}
+
+func (r *TaskRepository) GetBlockers(ctx context.Context, id int) ([]task.Info, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.get(ctx, id); !exists {
+		return nil, nil
+	}
+	var result []task.Info
+	for blockerID := range r.blockers[id] {
+		result = append(result, clone(r.tasks[blockerID]))
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].ID < result[j].ID })
+	return result, nil
+}
+
+func (r *TaskRepository) GetDependencies(ctx context.Context) ([]task.Dependency, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var result []task.Dependency
+	for blockedID, blockers := range r.blockers {
+		if _, exists := r.get(ctx, blockedID); !exists {
+			continue
+		}
+		for blockerID := range blockers {
+			result = append(result, task.Dependency{BlockerID: blockerID, BlockedID: blockedID})
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].BlockerID != result[j].BlockerID {
+			return result[i].BlockerID < result[j].BlockerID
+		}
+		return result[i].BlockedID < result[j].BlockedID
+	})
+	return result, nil
+}
+
+func (r *TaskRepository) GetDueBefore(ctx context.Context, before time.Time) ([]task.Info, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	tenantID := tenant.FromContext(ctx)
+	var result []task.Info
+	for _, t := range r.tasks {
+		if t.TenantID == tenantID && t.Status == task.StatusPending && t.DueAt != nil && !t.DueAt.After(before) {
+			result = append(result, clone(t))
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if !result[i].DueAt.Equal(*result[j].DueAt) {
+			return result[i].DueAt.Before(*result[j].DueAt)
+		}
+		return result[i].ID < result[j].ID
+	})
+	return result, nil
+}
+
+func (r *TaskRepository) GetByProject(ctx context.Context, projectID int) ([]task.Info, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	tenantID := tenant.FromContext(ctx)
+	var result []task.Info
+	for _, t := range r.tasks {
+		if t.TenantID == tenantID && t.ProjectID == projectID {
+			result = append(result, clone(t))
+		}
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].ID < result[j].ID })
+	return result, nil
+}
+
+func (r *TaskRepository) CountByProject(ctx context.Context) (map[int]task.Progress, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	tenantID := tenant.FromContext(ctx)
+	counts := make(map[int]task.Progress)
+	for _, t := range r.tasks {
+		if t.TenantID != tenantID {
+			continue
+		}
+		c := counts[t.ProjectID]
+		c.Total++
+		if t.Status == task.StatusDone {
+			c.Done++
+		}
+		counts[t.ProjectID] = c
+	}
+	return counts, nil
+}
+
+func (r *TaskRepository) GetByAssignee(ctx context.Context, userID int) ([]task.Info, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var result []task.Info
+	for id := range r.assigned[userID] {
+		if t, exists := r.get(ctx, id); exists {
+			result = append(result, clone(t))
+		}
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].ID < result[j].ID })
+	return result, nil
+}
+
+func (r *TaskRepository) Search(ctx context.Context, query search.Query) ([]task.SearchResult, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	names, exists := r.names[tenant.FromContext(ctx)]
+	if !exists {
+		return nil, nil
+	}
+	var result []task.SearchResult
+	for _, hit := range names.Search(query) {
+		result = append(result, task.SearchResult{Task: clone(r.tasks[hit.ID]), Score: hit.Score})
+	}
+	return result, nil
+}
+
+func (r *TaskRepository) Tenants(context.Context) ([]string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	seen := make(map[string]bool)
+	var result []string
+	for _, t := range r.tasks {
+		if !seen[t.TenantID] {
+			seen[t.TenantID] = true
+			result = append(result, t.TenantID)
+		}
+	}
+	sort.Strings(result)
+	return result, nil
+}
+
+// get returns a task of the tenant in ctx. It must be called with the lock
+// held.
+func (r *TaskRepository) get(ctx context.Context, id int) (task.Info, bool) {
+	t, exists := r.tasks[id]
+	if !exists || t.TenantID != tenant.FromContext(ctx) {
+		return task.Info{}, false
+	}
+	return t, true
+}
+
+// namesOf returns the name index of a tenant, creating it on first use. It
+// must be called with the lock held.
+func (r *TaskRepository) namesOf(tenantID string) *search.Index {
+	names, exists := r.names[tenantID]
+	if !exists {
+		names = search.NewIndex()
+		r.names[tenantID] = names
+	}
+	return names
+}
+
+// index moves a task between assignees in the assignee index. It must be
+// called with the lock held.
+func (r *TaskRepository) index(id int, from, to *int) {
+	if from != nil {
+		delete(r.assigned[*from], id)
+		if len(r.assigned[*from]) == 0 {
+			delete(r.assigned, *from)
+		}
+	}
+	if to != nil {
+		if r.assigned[*to] == nil {
+			r.assigned[*to] = make(map[int]bool)
+		}
+		r.assigned[*to][id] = true
+	}
+}
+
+// children must be called with the lock held.
+func (r *TaskRepository) children(tenantID string, parentID int) []task.Info {
+	var result []task.Info
+	for _, t := range r.tasks {
+		if t.TenantID == tenantID && t.ParentID != nil && *t.ParentID == parentID {
+			result = append(result, clone(t))
+		}
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].ID < result[j].ID })
+	return result
+}
+
+func projectOrDefault(id int) int {
+	if id == 0 {
+		return project.DefaultProjectID
+	}
+	return id
+}
+
+// clone copies the pointer fields so callers cannot mutate stored tasks.
+func clone(t task.Info) task.Info {
+	if t.ParentID != nil {
+		parentID := *t.ParentID
+		t.ParentID = &parentID
+	}
+	if t.AssigneeID != nil {
+		assigneeID := *t.AssigneeID
+		t.AssigneeID = &assigneeID
+	}
+	if t.DueAt != nil {
+		dueAt := *t.DueAt
+		t.DueAt = &dueAt
+	}
+	if t.Recurrence != nil {
+		recurrence := *t.Recurrence
+		t.Recurrence = &recurrence
+	}
+	t.Progress = nil
+	t.Labels = nil
+	return t
+}
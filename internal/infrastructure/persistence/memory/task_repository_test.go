@@ -1,15 +1,21 @@
 package memory
 
 import (
+	"context"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 
+	"task-api/internal/domain/project"
 	"task-api/internal/domain/task"
+	"task-api/internal/search"
+	"task-api/internal/tenant"
 )
 
 func TestCreateTask(t *testing.T) {
 	repo := NewInMemoryTaskRepository()
+	ctx := context.Background()
 
 	tests := []struct {
 		TestCase string
@@ -25,7 +31,7 @@ func TestCreateTask(t *testing.T) {
 
 	for _, tc := range tests {
 		t.Run(tc.TestCase, func(t *testing.T) {
-			createdTask, err := repo.Create(tc.Input)
+			createdTask, err := repo.Create(ctx, tc.Input)
 			assert.NoError(t, err)
 			assert.Equal(t, tc.Expected.ID, createdTask.ID)
 			assert.Equal(t, tc.Expected.Name, createdTask.Name)
@@ -36,9 +42,10 @@ func TestCreateTask(t *testing.T) {
 
 func TestGetAllTasks(t *testing.T) {
 	repo := NewInMemoryTaskRepository()
+	ctx := context.Background()
 
 	newTask := task.Info{Name: "Test TaskInfo", Status: 0}
-	repo.Create(newTask)
+	repo.Create(ctx, newTask)
 
 	tests := []struct {
 		TestCase string
@@ -47,14 +54,14 @@ func TestGetAllTasks(t *testing.T) {
 		{
 			TestCase: "Get All Tasks",
 			Expected: []task.Info{
-				{ID: 1, Name: "Test TaskInfo", Status: 0},
+				{ID: 1, Name: "Test TaskInfo", Status: 0, ProjectID: project.DefaultProjectID, TenantID: tenant.Default},
 			},
 		},
 	}
 
 	for _, tc := range tests {
 		t.Run(tc.TestCase, func(t *testing.T) {
-			tasks, err := repo.GetAll()
+			tasks, err := repo.GetAll(ctx)
 			assert.NoError(t, err)
 			assert.Equal(t, tc.Expected, tasks)
 		})
@@ -63,9 +70,10 @@ func TestGetAllTasks(t *testing.T) {
 
 func TestGetTaskByID(t *testing.T) {
 	repo := NewInMemoryTaskRepository()
+	ctx := context.Background()
 
 	newTask := task.Info{Name: "Test TaskInfo", Status: 0}
-	createdTask, _ := repo.Create(newTask)
+	createdTask, _ := repo.Create(ctx, newTask)
 
 	tests := []struct {
 		TestCase string
@@ -81,7 +89,7 @@ func TestGetTaskByID(t *testing.T) {
 
 	for _, tc := range tests {
 		t.Run(tc.TestCase, func(t *testing.T) {
-			task, err := repo.GetByID(tc.ID)
+			task, err := repo.GetByID(ctx, tc.ID)
 			assert.NoError(t, err)
 			assert.Equal(t, tc.Expected, task)
 		})
@@ -90,9 +98,10 @@ func TestGetTaskByID(t *testing.T) {
 
 func TestUpdateTask(t *testing.T) {
 	repo := NewInMemoryTaskRepository()
+	ctx := context.Background()
 
 	newTask := task.Info{Name: "Test TaskInfo", Status: 0}
-	createdTask, _ := repo.Create(newTask)
+	createdTask, _ := repo.Create(ctx, newTask)
 
 	tests := []struct {
 		TestCase string
@@ -108,7 +117,7 @@ func TestUpdateTask(t *testing.T) {
 
 	for _, tc := range tests {
 		t.Run(tc.TestCase, func(t *testing.T) {
-			updatedTask, err := repo.Update(tc.Input)
+			updatedTask, err := repo.Update(ctx, tc.Input)
 			assert.NoError(t, err)
 			assert.Equal(t, tc.Expected.ID, updatedTask.ID)
 			assert.Equal(t, tc.Expected.Name, updatedTask.Name)
@@ -119,9 +128,10 @@ func TestUpdateTask(t *testing.T) {
 
 func TestDeleteTask(t *testing.T) {
 	repo := NewInMemoryTaskRepository()
+	ctx := context.Background()
 
 	newTask := task.Info{Name: "Test TaskInfo", Status: 0}
-	createdTask, _ := repo.Create(newTask)
+	createdTask, _ := repo.Create(ctx, newTask)
 
 	tests := []struct {
 		TestCase string
@@ -135,12 +145,344 @@ func TestDeleteTask(t *testing.T) {
 
 	for _, tc := range tests {
 		t.Run(tc.TestCase, func(t *testing.T) {
-			err := repo.Delete(tc.ID)
+			err := repo.Delete(ctx, tc.ID)
 			assert.NoError(t, err)
 
-			tasks, err := repo.GetAll()
+			tasks, err := repo.GetAll(ctx)
 			assert.NoError(t, err)
 			assert.Empty(t, tasks)
 		})
 	}
 }
+
+func TestGetChildrenAndDescendants(t *testing.T) {
+	repo := NewInMemoryTaskRepository()
+	ctx := context.Background()
+
+	root, _ := repo.Create(ctx, task.Info{Name: "Root"})
+	child, _ := repo.Create(ctx, task.Info{Name: "Child", ParentID: &root.ID})
+	sibling, _ := repo.Create(ctx, task.Info{Name: "Sibling", ParentID: &root.ID})
+	grandchild, _ := repo.Create(ctx, task.Info{Name: "Grandchild", ParentID: &child.ID})
+
+	tests := []struct {
+		TestCase string
+		Fetch    func() ([]task.Info, error)
+		Expected []int
+	}{
+		{
+			TestCase: "Direct children",
+			Fetch:    func() ([]task.Info, error) { return repo.GetChildren(ctx, root.ID) },
+			Expected: []int{child.ID, sibling.ID},
+		},
+		{
+			TestCase: "Descendants breadth-first",
+			Fetch:    func() ([]task.Info, error) { return repo.GetDescendants(ctx, root.ID) },
+			Expected: []int{child.ID, sibling.ID, grandchild.ID},
+		},
+		{
+			TestCase: "Leaf has no children",
+			Fetch:    func() ([]task.Info, error) { return repo.GetChildren(ctx, grandchild.ID) },
+			Expected: nil,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.TestCase, func(t *testing.T) {
+			tasks, err := tc.Fetch()
+			assert.NoError(t, err)
+			var ids []int
+			for _, found := range tasks {
+				ids = append(ids, found.ID)
+			}
+			assert.Equal(t, tc.Expected, ids)
+		})
+	}
+}
+
+func TestDependencies(t *testing.T) {
+	repo := NewInMemoryTaskRepository()
+	ctx := context.Background()
+
+	design, _ := repo.Create(ctx, task.Info{Name: "Design"})
+	build, _ := repo.Create(ctx, task.Info{Name: "Build"})
+	ship, _ := repo.Create(ctx, task.Info{Name: "Ship"})
+	assert.NoError(t, repo.AddDependency(ctx, design.ID, build.ID))
+	assert.NoError(t, repo.AddDependency(ctx, build.ID, ship.ID))
+	assert.NoError(t, repo.AddDependency(ctx, design.ID, ship.ID))
+	assert.NoError(t, repo.AddDependency(ctx, design.ID, ship.ID))
+
+	tests := []struct {
+		TestCase string
+		Run      func() (interface{}, error)
+		Expected interface{}
+		Error    error
+	}{
+		{
+			TestCase: "Blockers sorted by ID",
+			Run:      func() (interface{}, error) { return repo.GetBlockers(ctx, ship.ID) },
+			Expected: []task.Info{design, build},
+		},
+		{
+			TestCase: "Unknown blocker",
+			Run:      func() (interface{}, error) { return nil, repo.AddDependency(ctx, 99, ship.ID) },
+			Error:    task.ErrNotFound,
+		},
+		{
+			TestCase: "Remove missing dependency",
+			Run:      func() (interface{}, error) { return nil, repo.RemoveDependency(ctx, ship.ID, design.ID) },
+			Error:    task.ErrDependencyNotFound,
+		},
+		{
+			TestCase: "Deleting a task drops its dependencies",
+			Run: func() (interface{}, error) {
+				if err := repo.Delete(ctx, build.ID); err != nil {
+					return nil, err
+				}
+				return repo.GetDependencies(ctx)
+			},
+			Expected: []task.Dependency{{BlockerID: design.ID, BlockedID: ship.ID}},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.TestCase, func(t *testing.T) {
+			result, err := tc.Run()
+			if tc.Error != nil {
+				assert.Equal(t, tc.Error, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.Expected, result)
+		})
+	}
+}
+
+func TestGetByAssignee(t *testing.T) {
+	repo := NewInMemoryTaskRepository()
+	ctx := context.Background()
+	alice, bob := 1, 2
+
+	first, _ := repo.Create(ctx, task.Info{Name: "First", AssigneeID: &alice})
+	second, _ := repo.Create(ctx, task.Info{Name: "Second", AssigneeID: &bob})
+	third, _ := repo.Create(ctx, task.Info{Name: "Third", AssigneeID: &alice})
+
+	tests := []struct {
+		TestCase string
+		Setup    func()
+		UserID   int
+		Expected []int
+	}{
+		{
+			TestCase: "Tasks of an assignee",
+			Setup:    func() {},
+			UserID:   alice,
+			Expected: []int{first.ID, third.ID},
+		},
+		{
+			TestCase: "Reassigned task moves to the new assignee",
+			Setup: func() {
+				second.AssigneeID = &alice
+				_, _ = repo.Update(ctx, second)
+			},
+			UserID:   alice,
+			Expected: []int{first.ID, second.ID, third.ID},
+		},
+		{
+			TestCase: "Unassigned and deleted tasks are dropped",
+			Setup: func() {
+				first.AssigneeID = nil
+				_, _ = repo.Update(ctx, first)
+				_ = repo.Delete(ctx, third.ID)
+			},
+			UserID:   alice,
+			Expected: []int{second.ID},
+		},
+		{
+			TestCase: "Assignee without tasks",
+			Setup:    func() {},
+			UserID:   bob,
+			Expected: nil,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.TestCase, func(t *testing.T) {
+			tc.Setup()
+			tasks, err := repo.GetByAssignee(ctx, tc.UserID)
+			assert.NoError(t, err)
+			var ids []int
+			for _, found := range tasks {
+				ids = append(ids, found.ID)
+			}
+			assert.Equal(t, tc.Expected, ids)
+		})
+	}
+}
+
+func TestRecurrenceIsCopied(t *testing.T) {
+	repo := NewInMemoryTaskRepository()
+	ctx := context.Background()
+	dueAt := time.Date(2024, 3, 4, 8, 0, 0, 0, time.UTC)
+	recurrence := &task.Recurrence{Rule: "FREQ=WEEKLY;BYDAY=MO", Start: dueAt}
+
+	created, err := repo.Create(ctx, task.Info{Name: "Weekly report", DueAt: &dueAt, Recurrence: recurrence})
+	assert.NoError(t, err)
+
+	recurrence.Rule = "FREQ=DAILY"
+	dueAt = dueAt.Add(time.Hour)
+
+	found, err := repo.GetByID(ctx, created.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, "FREQ=WEEKLY;BYDAY=MO", found.Recurrence.Rule)
+	assert.Equal(t, time.Date(2024, 3, 4, 8, 0, 0, 0, time.UTC), *found.DueAt)
+}
+
+func TestGetDueBefore(t *testing.T) {
+	repo := NewInMemoryTaskRepository()
+	ctx := context.Background()
+	later := time.Date(2024, 3, 5, 8, 0, 0, 0, time.UTC)
+	sooner := time.Date(2024, 3, 4, 8, 0, 0, 0, time.UTC)
+	outside := time.Date(2024, 3, 9, 8, 0, 0, 0, time.UTC)
+
+	second, _ := repo.Create(ctx, task.Info{Name: "Later", DueAt: &later})
+	first, _ := repo.Create(ctx, task.Info{Name: "Sooner", DueAt: &sooner})
+	_, _ = repo.Create(ctx, task.Info{Name: "Done", Status: task.StatusDone, DueAt: &sooner})
+	_, _ = repo.Create(ctx, task.Info{Name: "Outside", DueAt: &outside})
+	_, _ = repo.Create(ctx, task.Info{Name: "Undated"})
+
+	tasks, err := repo.GetDueBefore(ctx, time.Date(2024, 3, 6, 0, 0, 0, 0, time.UTC))
+	assert.NoError(t, err)
+	var ids []int
+	for _, found := range tasks {
+		ids = append(ids, found.ID)
+	}
+	assert.Equal(t, []int{first.ID, second.ID}, ids)
+}
+
+func TestSearch(t *testing.T) {
+	repo := NewInMemoryTaskRepository()
+	ctx := context.Background()
+	login, _ := repo.Create(ctx, task.Info{Name: "Fix login page"})
+	notes, _ := repo.Create(ctx, task.Info{Name: "Write release notes for the login redesign"})
+
+	find := func(q string) []int {
+		query, err := search.Parse(q)
+		assert.NoError(t, err)
+		results, err := repo.Search(ctx, query)
+		assert.NoError(t, err)
+		var ids []int
+		for _, result := range results {
+			ids = append(ids, result.Task.ID)
+		}
+		return ids
+	}
+
+	assert.Equal(t, []int{login.ID, notes.ID}, find("login"))
+
+	login.Name = "Fix sign-in page"
+	_, _ = repo.Update(ctx, login)
+	assert.Equal(t, []int{notes.ID}, find("login"))
+	assert.Equal(t, []int{login.ID}, find(`"sign in"`))
+
+	_ = repo.Delete(ctx, notes.ID)
+	assert.Nil(t, find("login"))
+}
+
+func TestTenantIsolation(t *testing.T) {
+	repo := NewInMemoryTaskRepository()
+	acme := tenant.NewContext(context.Background(), "acme")
+	globex := tenant.NewContext(context.Background(), "globex")
+	dueAt := time.Date(2024, 3, 4, 8, 0, 0, 0, time.UTC)
+	report, _ := repo.Create(acme, task.Info{Name: "Quarterly report", DueAt: &dueAt})
+	child, _ := repo.Create(acme, task.Info{Name: "Collect numbers", ParentID: &report.ID})
+	other, _ := repo.Create(globex, task.Info{Name: "Quarterly report"})
+
+	tests := []struct {
+		TestCase string
+		Run      func() (interface{}, error)
+		Expected interface{}
+		Error    error
+	}{
+		{
+			TestCase: "Get a task of another tenant",
+			Run:      func() (interface{}, error) { return repo.GetByID(globex, report.ID) },
+			Expected: task.Info{},
+			Error:    task.ErrNotFound,
+		},
+		{
+			TestCase: "Update a task of another tenant",
+			Run: func() (interface{}, error) {
+				return repo.Update(globex, task.Info{ID: report.ID, Name: "Taken over"})
+			},
+			Expected: task.Info{},
+			Error:    task.ErrNotFound,
+		},
+		{
+			TestCase: "Delete a task of another tenant",
+			Run:      func() (interface{}, error) { return nil, repo.Delete(globex, report.ID) },
+			Error:    task.ErrNotFound,
+		},
+		{
+			TestCase: "Depend on a task of another tenant",
+			Run:      func() (interface{}, error) { return nil, repo.AddDependency(globex, report.ID, other.ID) },
+			Error:    task.ErrNotFound,
+		},
+		{
+			TestCase: "List only the tasks of the tenant",
+			Run: func() (interface{}, error) {
+				tasks, err := repo.GetAll(globex)
+				return len(tasks), err
+			},
+			Expected: 1,
+		},
+		{
+			TestCase: "Children of a task of another tenant",
+			Run: func() (interface{}, error) {
+				children, err := repo.GetChildren(globex, report.ID)
+				return len(children), err
+			},
+			Expected: 0,
+		},
+		{
+			TestCase: "Search only the tasks of the tenant",
+			Run: func() (interface{}, error) {
+				query, _ := search.Parse("quarterly")
+				results, err := repo.Search(globex, query)
+				if len(results) != 1 {
+					return len(results), err
+				}
+				return results[0].Task.ID, err
+			},
+			Expected: other.ID,
+		},
+		{
+			TestCase: "Due tasks of the tenant",
+			Run: func() (interface{}, error) {
+				tasks, err := repo.GetDueBefore(globex, dueAt.Add(time.Hour))
+				return len(tasks), err
+			},
+			Expected: 0,
+		},
+		{
+			TestCase: "The task stays with its tenant",
+			Run: func() (interface{}, error) {
+				found, err := repo.GetByID(acme, child.ID)
+				return found.TenantID, err
+			},
+			Expected: "acme",
+		},
+		{
+			TestCase: "Tenants with tasks",
+			Run:      func() (interface{}, error) { return repo.Tenants(context.Background()) },
+			Expected: []string{"acme", "globex"},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.TestCase, func(t *testing.T) {
+			result, err := tc.Run()
+			assert.Equal(t, tc.Error, err)
+			assert.Equal(t, tc.Expected, result)
+		})
+	}
+}
@@ -0,0 +1,71 @@
+package memory
+
+import (
+	"sort"
+	"sync"
+
+	"task-api/internal/domain/user"
+)
+
+type UserRepository struct {
+	mu     sync.Mutex
+	users  map[int]user.User
+	nextID int
+}
+
+func NewInMemoryUserRepository() user.Repository {
+	return &UserRepository{
+		users:  make(map[int]user.User),
+		nextID: 1,
+	}
+}
+
+func (r *UserRepository) GetAll() ([]user.User, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var result []user.User
+	for _, u := range r.users {
+		result = append(result, u)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Username < result[j].Username })
+	return result, nil
+}
+
+func (r *UserRepository) GetByID(id int) (user.User, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	u, exists := r.users[id]
+	if !exists {
+		return user.User{}, user.ErrNotFound
+	}
+	return u, nil
+}
+
+func (r *UserRepository) GetByUsername(username string) (user.User, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, u := range r.users {
+		if u.Username == username {
+			return u, nil
+		}
+	}
+	return user.User{}, user.ErrNotFound
+}
+
+func (r *UserRepository) Create(u user.User) (user.User, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, existing := range r.users {
+		if existing.Username == u.Username {
+			return user.User{}, user.ErrDuplicateUsername
+		}
+	}
+	u.ID = r.nextID
+	r.nextID++
+	r.users[u.ID] = u
+	return u, nil
+}
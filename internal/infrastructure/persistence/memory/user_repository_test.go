@@ -0,0 +1,56 @@
+package memory
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"task-api/internal/domain/user"
+)
+
+func TestUsers(t *testing.T) {
+	repo := NewInMemoryUserRepository()
+
+	bob, _ := repo.Create(user.User{Username: "bob", Name: "Bob"})
+	alice, _ := repo.Create(user.User{Username: "alice", Email: "alice@example.com"})
+
+	tests := []struct {
+		TestCase string
+		Run      func() (interface{}, error)
+		Expected interface{}
+		Error    error
+	}{
+		{
+			TestCase: "All users by username",
+			Run:      func() (interface{}, error) { return repo.GetAll() },
+			Expected: []user.User{alice, bob},
+		},
+		{
+			TestCase: "Get by username",
+			Run:      func() (interface{}, error) { return repo.GetByUsername("bob") },
+			Expected: bob,
+		},
+		{
+			TestCase: "Unknown username",
+			Run:      func() (interface{}, error) { return repo.GetByUsername("carol") },
+			Error:    user.ErrNotFound,
+		},
+		{
+			TestCase: "Duplicate username",
+			Run:      func() (interface{}, error) { return repo.Create(user.User{Username: "alice"}) },
+			Error:    user.ErrDuplicateUsername,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.TestCase, func(t *testing.T) {
+			result, err := tc.Run()
+			if tc.Error != nil {
+				assert.Equal(t, tc.Error, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.Expected, result)
+		})
+	}
+}
@@ -0,0 +1,147 @@
+package memory
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"task-api/internal/domain/webhook"
+)
+
+type WebhookRepository struct {
+	mu             sync.Mutex
+	subscriptions  map[int]webhook.Subscription
+	deliveries     map[int]webhook.Delivery
+	nextID         int
+	nextDeliveryID int
+}
+
+func NewInMemoryWebhookRepository() webhook.Repository {
+	return &WebhookRepository{
+		subscriptions:  make(map[int]webhook.Subscription),
+		deliveries:     make(map[int]webhook.Delivery),
+		nextID:         1,
+		nextDeliveryID: 1,
+	}
+}
+
+func (r *WebhookRepository) GetAll() ([]webhook.Subscription, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var result []webhook.Subscription
+	for _, s := range r.subscriptions {
+		result = append(result, s)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].ID < result[j].ID })
+	return result, nil
+}
+
+func (r *WebhookRepository) GetByID(id int) (webhook.Subscription, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	s, exists := r.subscriptions[id]
+	if !exists {
+		return webhook.Subscription{}, webhook.ErrNotFound
+	}
+	return s, nil
+}
+
+func (r *WebhookRepository) Create(s webhook.Subscription) (webhook.Subscription, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	s.ID = r.nextID
+	r.nextID++
+	r.subscriptions[s.ID] = s
+	return s, nil
+}
+
+func (r *WebhookRepository) Update(s webhook.Subscription) (webhook.Subscription, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.subscriptions[s.ID]; !exists {
+		return webhook.Subscription{}, webhook.ErrNotFound
+	}
+	r.subscriptions[s.ID] = s
+	return s, nil
+}
+
+func (r *WebhookRepository) Delete(id int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.subscriptions[id]; !exists {
+		return webhook.ErrNotFound
+	}
+	delete(r.subscriptions, id)
+	for deliveryID, d := range r.deliveries {
+		if d.SubscriptionID == id {
+			delete(r.deliveries, deliveryID)
+		}
+	}
+	return nil
+}
+
+func (r *WebhookRepository) CreateDelivery(d webhook.Delivery) (webhook.Delivery, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.subscriptions[d.SubscriptionID]; !exists {
+		return webhook.Delivery{}, webhook.ErrNotFound
+	}
+	d.ID = r.nextDeliveryID
+	r.nextDeliveryID++
+	r.deliveries[d.ID] = d
+	return d, nil
+}
+
+func (r *WebhookRepository) UpdateDelivery(d webhook.Delivery) (webhook.Delivery, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.deliveries[d.ID]; !exists {
+		return webhook.Delivery{}, webhook.ErrDeliveryNotFound
+	}
+	r.deliveries[d.ID] = d
+	return d, nil
+}
+
+func (r *WebhookRepository) GetDelivery(id int) (webhook.Delivery, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	d, exists := r.deliveries[id]
+	if !exists {
+		return webhook.Delivery{}, webhook.ErrDeliveryNotFound
+	}
+	return d, nil
+}
+
+func (r *WebhookRepository) ListDeliveries(subscriptionID int) ([]webhook.Delivery, error) {
+	return r.listDeliveries(func(d webhook.Delivery) bool {
+		return d.SubscriptionID == subscriptionID
+	}), nil
+}
+
+func (r *WebhookRepository) ListDueDeliveries(before time.Time) ([]webhook.Delivery, error) {
+	return r.listDeliveries(func(d webhook.Delivery) bool {
+		return d.Status == webhook.DeliveryPending && !d.NextAttemptAt.After(before)
+	}), nil
+}
+
+func (r *WebhookRepository) listDeliveries(match func(webhook.Delivery) bool) []webhook.Delivery {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var result []webhook.Delivery
+	for _, d := range r.deliveries {
+		if match(d) {
+			result = append(result, d)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].ID < result[j].ID })
+	return result
+}
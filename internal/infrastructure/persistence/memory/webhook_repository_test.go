@@ -0,0 +1,111 @@
+package memory
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"task-api/internal/domain/webhook"
+)
+
+func TestWebhookSubscriptions(t *testing.T) {
+	repo := NewInMemoryWebhookRepository()
+
+	created, err := repo.Create(webhook.Subscription{URL: "https://example.com/hook", Secret: "secret", Active: true})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, created.ID)
+
+	tests := []struct {
+		TestCase string
+		Run      func() (interface{}, error)
+		Expected interface{}
+		Error    error
+	}{
+		{
+			TestCase: "Get subscription by ID",
+			Run:      func() (interface{}, error) { return repo.GetByID(created.ID) },
+			Expected: created,
+		},
+		{
+			TestCase: "Update subscription",
+			Run: func() (interface{}, error) {
+				created.Active = false
+				return repo.Update(created)
+			},
+			Expected: webhook.Subscription{ID: 1, URL: "https://example.com/hook", Secret: "secret", Active: false},
+		},
+		{
+			TestCase: "Get all subscriptions",
+			Run:      func() (interface{}, error) { return repo.GetAll() },
+			Expected: []webhook.Subscription{{ID: 1, URL: "https://example.com/hook", Secret: "secret", Active: false}},
+		},
+		{
+			TestCase: "Update missing subscription",
+			Run:      func() (interface{}, error) { return repo.Update(webhook.Subscription{ID: 9}) },
+			Expected: webhook.Subscription{},
+			Error:    webhook.ErrNotFound,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.TestCase, func(t *testing.T) {
+			result, err := tc.Run()
+			assert.Equal(t, tc.Error, err)
+			assert.Equal(t, tc.Expected, result)
+		})
+	}
+}
+
+func TestWebhookDeliveries(t *testing.T) {
+	repo := NewInMemoryWebhookRepository()
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	sub, _ := repo.Create(webhook.Subscription{URL: "https://example.com/hook", Active: true})
+	due, err := repo.CreateDelivery(webhook.Delivery{SubscriptionID: sub.ID, Status: webhook.DeliveryPending, NextAttemptAt: now})
+	assert.NoError(t, err)
+	later, _ := repo.CreateDelivery(webhook.Delivery{SubscriptionID: sub.ID, Status: webhook.DeliveryPending, NextAttemptAt: now.Add(time.Minute)})
+	done, _ := repo.CreateDelivery(webhook.Delivery{SubscriptionID: sub.ID, Status: webhook.DeliverySucceeded, NextAttemptAt: now})
+
+	tests := []struct {
+		TestCase string
+		Run      func() ([]webhook.Delivery, error)
+		Expected []webhook.Delivery
+		Error    error
+	}{
+		{
+			TestCase: "List deliveries of a subscription",
+			Run:      func() ([]webhook.Delivery, error) { return repo.ListDeliveries(sub.ID) },
+			Expected: []webhook.Delivery{due, later, done},
+		},
+		{
+			TestCase: "List due deliveries",
+			Run:      func() ([]webhook.Delivery, error) { return repo.ListDueDeliveries(now) },
+			Expected: []webhook.Delivery{due},
+		},
+		{
+			TestCase: "Deleting the subscription removes its deliveries",
+			Run: func() ([]webhook.Delivery, error) {
+				assert.NoError(t, repo.Delete(sub.ID))
+				return repo.ListDeliveries(sub.ID)
+			},
+			Expected: nil,
+		},
+		{
+			TestCase: "Delivery for a missing subscription",
+			Run: func() ([]webhook.Delivery, error) {
+				_, err := repo.CreateDelivery(webhook.Delivery{SubscriptionID: sub.ID})
+				return nil, err
+			},
+			Error: webhook.ErrNotFound,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.TestCase, func(t *testing.T) {
+			result, err := tc.Run()
+			assert.Equal(t, tc.Error, err)
+			assert.Equal(t, tc.Expected, result)
+		})
+	}
+}
@@ -0,0 +1,105 @@
+package mysql
+
+import (
+	"database/sql"
+	"strings"
+	"time"
+
+	"task-api/internal/domain/apikey"
+)
+
+const apiKeyColumns = "id, tenant_id, name, prefix, key_hash, scopes, created_at, last_used_at, revoked_at"
+
+type APIKeyRepository struct {
+	DB *sql.DB
+}
+
+func NewMySQLAPIKeyRepository(db *sql.DB) apikey.Repository {
+	return &APIKeyRepository{DB: db}
+}
+
+func (r *APIKeyRepository) GetAll() ([]apikey.Key, error) {
+	rows, err := r.DB.Query("SELECT " + apiKeyColumns + " FROM api_keys ORDER BY id")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var keys []apikey.Key
+	for rows.Next() {
+		k, err := scanAPIKey(rows)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, k)
+	}
+	return keys, rows.Err()
+}
+
+func (r *APIKeyRepository) GetByID(id int) (apikey.Key, error) {
+	return r.get("SELECT "+apiKeyColumns+" FROM api_keys WHERE id = ?", id)
+}
+
+// GetByHash is served by the unique index on key_hash.
+func (r *APIKeyRepository) GetByHash(hash string) (apikey.Key, error) {
+	return r.get("SELECT "+apiKeyColumns+" FROM api_keys WHERE key_hash = ?", hash)
+}
+
+func (r *APIKeyRepository) Create(k apikey.Key) (apikey.Key, error) {
+	result, err := r.DB.Exec(`INSERT INTO api_keys (tenant_id, name, prefix, key_hash, scopes, created_at, last_used_at, revoked_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
+		k.TenantID, k.Name, k.Prefix, k.Hash, strings.Join(k.Scopes, ","), k.CreatedAt, k.LastUsedAt, k.RevokedAt)
+	if err != nil {
+		return apikey.Key{}, err
+	}
+	id, err := result.LastInsertId()
+	if err != nil {
+		return apikey.Key{}, err
+	}
+	k.ID = int(id)
+	return k, nil
+}
+
+func (r *APIKeyRepository) Touch(id int, at time.Time) error {
+	result, err := r.DB.Exec("UPDATE api_keys SET last_used_at = ? WHERE id = ?", at, id)
+	if err != nil {
+		return err
+	}
+	return requireRow(result, apikey.ErrNotFound)
+}
+
+func (r *APIKeyRepository) Revoke(id int, at time.Time) error {
+	result, err := r.DB.Exec("UPDATE api_keys SET revoked_at = ? WHERE id = ?", at, id)
+	if err != nil {
+		return err
+	}
+	return requireRow(result, apikey.ErrNotFound)
+}
+
+func (r *APIKeyRepository) get(query string, args ...interface{}) (apikey.Key, error) {
+	k, err := scanAPIKey(r.DB.QueryRow(query, args...))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return apikey.Key{}, apikey.ErrNotFound
+		}
+		return apikey.Key{}, err
+	}
+	return k, nil
+}
+
+func scanAPIKey(row scanner) (apikey.Key, error) {
+	var k apikey.Key
+	var scopes string
+	var lastUsedAt, revokedAt sql.NullTime
+	if err := row.Scan(&k.ID, &k.TenantID, &k.Name, &k.Prefix, &k.Hash, &scopes, &k.CreatedAt, &lastUsedAt, &revokedAt); err != nil {
+		return apikey.Key{}, err
+	}
+	k.Scopes = strings.Split(scopes, ",")
+	if lastUsedAt.Valid {
+		k.LastUsedAt = &lastUsedAt.Time
+	}
+	if revokedAt.Valid {
+		k.RevokedAt = &revokedAt.Time
+	}
+	return k, nil
+}
@@ -0,0 +1,42 @@
+package mysql
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"task-api/internal/domain/apikey"
+)
+
+func TestAPIKeys(t *testing.T) {
+	repo := &APIKeyRepository{DB: db}
+	createdAt := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	usedAt := createdAt.Add(time.Hour)
+
+	err := truncateTables(db, "api_keys")
+	assert.NoError(t, err)
+
+	ci, err := repo.Create(apikey.Key{Name: "CI", Prefix: "tk_abcd", Hash: "hash-ci",
+		Scopes: []string{apikey.ScopeTasksRead, apikey.ScopeTasksWrite}, CreatedAt: createdAt})
+	assert.NoError(t, err)
+	cron, err := repo.Create(apikey.Key{Name: "Cron", Prefix: "tk_efgh", Hash: "hash-cron",
+		Scopes: []string{apikey.ScopeTasksRead}, CreatedAt: createdAt})
+	assert.NoError(t, err)
+
+	found, err := repo.GetByHash("hash-ci")
+	assert.NoError(t, err)
+	assert.Equal(t, ci, found)
+	_, err = repo.GetByHash("hash-none")
+	assert.Equal(t, apikey.ErrNotFound, err)
+
+	assert.NoError(t, repo.Touch(cron.ID, usedAt))
+	assert.NoError(t, repo.Revoke(cron.ID, usedAt))
+	assert.Equal(t, apikey.ErrNotFound, repo.Revoke(99, usedAt))
+
+	keys, err := repo.GetAll()
+	assert.NoError(t, err)
+	cron.LastUsedAt = &usedAt
+	cron.RevokedAt = &usedAt
+	assert.Equal(t, []apikey.Key{ci, cron}, keys)
+}
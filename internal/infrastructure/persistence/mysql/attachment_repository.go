@@ -0,0 +1,76 @@
+package mysql
+
+import (
+	"database/sql"
+
+	"task-api/internal/domain/attachment"
+)
+
+const attachmentColumns = "id, task_id, filename, content_type, size, checksum, uploader, created_at, storage_key"
+
+type AttachmentRepository struct {
+	DB *sql.DB
+}
+
+func NewMySQLAttachmentRepository(db *sql.DB) attachment.Repository {
+	return &AttachmentRepository{DB: db}
+}
+
+func (r *AttachmentRepository) ListByTask(taskID int) ([]attachment.Attachment, error) {
+	rows, err := r.DB.Query("SELECT "+attachmentColumns+" FROM attachments WHERE task_id = ? ORDER BY id", taskID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var attachments []attachment.Attachment
+	for rows.Next() {
+		a, err := scanAttachment(rows)
+		if err != nil {
+			return nil, err
+		}
+		attachments = append(attachments, a)
+	}
+	return attachments, rows.Err()
+}
+
+func (r *AttachmentRepository) GetByID(id int) (attachment.Attachment, error) {
+	a, err := scanAttachment(r.DB.QueryRow("SELECT "+attachmentColumns+" FROM attachments WHERE id = ?", id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return attachment.Attachment{}, attachment.ErrNotFound
+		}
+		return attachment.Attachment{}, err
+	}
+	return a, nil
+}
+
+func (r *AttachmentRepository) Create(a attachment.Attachment) (attachment.Attachment, error) {
+	result, err := r.DB.Exec(`INSERT INTO attachments
+		(task_id, filename, content_type, size, checksum, uploader, created_at, storage_key)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
+		a.TaskID, a.Filename, a.ContentType, a.Size, a.Checksum, a.Uploader, a.CreatedAt, a.StorageKey)
+	if err != nil {
+		return attachment.Attachment{}, err
+	}
+	id, err := result.LastInsertId()
+	if err != nil {
+		return attachment.Attachment{}, err
+	}
+	a.ID = int(id)
+	return a, nil
+}
+
+func (r *AttachmentRepository) Delete(id int) error {
+	result, err := r.DB.Exec("DELETE FROM attachments WHERE id = ?", id)
+	if err != nil {
+		return err
+	}
+	return requireRow(result, attachment.ErrNotFound)
+}
+
+func scanAttachment(row scanner) (attachment.Attachment, error) {
+	var a attachment.Attachment
+	err := row.Scan(&a.ID, &a.TaskID, &a.Filename, &a.ContentType, &a.Size, &a.Checksum, &a.Uploader, &a.CreatedAt, &a.StorageKey)
+	return a, err
+}
@@ -0,0 +1,33 @@
+package mysql
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"task-api/internal/domain/attachment"
+)
+
+func TestAttachments(t *testing.T) {
+	repo := &AttachmentRepository{DB: db}
+	createdAt := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	err := clearTestDB(db)
+	assert.NoError(t, err)
+
+	log, err := repo.Create(attachment.Attachment{TaskID: 1, Filename: "build.log", ContentType: "text/plain; charset=utf-8", Size: 5,
+		Checksum: "2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824", Uploader: "alice", CreatedAt: createdAt, StorageKey: "tasks/1/a"})
+	assert.NoError(t, err)
+	_, err = repo.Create(attachment.Attachment{TaskID: 2, Filename: "other.txt", CreatedAt: createdAt, StorageKey: "tasks/2/b"})
+	assert.NoError(t, err)
+
+	attachments, err := repo.ListByTask(1)
+	assert.NoError(t, err)
+	assert.Equal(t, []attachment.Attachment{log}, attachments)
+
+	assert.NoError(t, repo.Delete(log.ID))
+	_, err = repo.GetByID(log.ID)
+	assert.Equal(t, attachment.ErrNotFound, err)
+	assert.Equal(t, attachment.ErrNotFound, repo.Delete(log.ID))
+}
@@ -0,0 +1,196 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"task-api/internal/domain/audit"
+	"task-api/internal/encryption"
+)
+
+const auditColumns = "id, tenant_id, task_id, actor, created_at, request_id, operation, changes"
+
+// sealedAuditFields are the task fields whose values are sealed in the
+// changes of a record, as the TaskRepository seals them in tasks.
+var sealedAuditFields = []string{"name"}
+
+// AuditRepository writes to the audit_log table, whose triggers refuse
+// updates and deletes.
+type AuditRepository struct {
+	DB *sql.DB
+	// Keyring, when set, seals the values of sealedAuditFields, in base64,
+	// with the ID of the key next to them. As records cannot be changed,
+	// they are never sealed again under a newer key.
+	Keyring *encryption.Keyring
+}
+
+// NewMySQLAuditRepository stores every value in plain text when keyring is
+// nil.
+func NewMySQLAuditRepository(db *sql.DB, keyring *encryption.Keyring) audit.Repository {
+	return &AuditRepository{DB: db, Keyring: keyring}
+}
+
+// storedChange is a change as kept in the changes column. KeyID is set when
+// the values are sealed.
+type storedChange struct {
+	Before json.RawMessage `json:"before"`
+	After  json.RawMessage `json:"after"`
+	KeyID  string          `json:"key_id,omitempty"`
+}
+
+// Append writes the record in the transaction of ctx when there is one, so
+// that it is kept with the change it records.
+func (r *AuditRepository) Append(ctx context.Context, rec audit.Record) (audit.Record, error) {
+	stored, err := r.sealChanges(rec)
+	if err != nil {
+		return audit.Record{}, err
+	}
+	changes, err := json.Marshal(stored)
+	if err != nil {
+		return audit.Record{}, err
+	}
+	result, err := conn(ctx, r.DB).ExecContext(ctx, `INSERT INTO audit_log (tenant_id, task_id, actor, created_at, request_id, operation, changes)
+		VALUES (?, ?, ?, ?, ?, ?, ?)`,
+		rec.TenantID, rec.TaskID, rec.Actor, rec.Time, rec.RequestID, rec.Operation, changes)
+	if err != nil {
+		return audit.Record{}, err
+	}
+	id, err := result.LastInsertId()
+	if err != nil {
+		return audit.Record{}, err
+	}
+	rec.ID = int(id)
+	return rec, nil
+}
+
+func (r *AuditRepository) Find(f audit.Filter) ([]audit.Record, error) {
+	conditions := []string{"tenant_id = ?"}
+	args := []interface{}{f.TenantID}
+	if f.TaskID != 0 {
+		conditions = append(conditions, "task_id = ?")
+		args = append(args, f.TaskID)
+	}
+	if f.Actor != "" {
+		conditions = append(conditions, "actor = ?")
+		args = append(args, f.Actor)
+	}
+	if !f.From.IsZero() {
+		conditions = append(conditions, "created_at >= ?")
+		args = append(args, f.From)
+	}
+	if !f.To.IsZero() {
+		conditions = append(conditions, "created_at <= ?")
+		args = append(args, f.To)
+	}
+	query := "SELECT " + auditColumns + " FROM audit_log WHERE " + strings.Join(conditions, " AND ")
+
+	rows, err := r.DB.Query(query+" ORDER BY created_at, id", args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var records []audit.Record
+	for rows.Next() {
+		var rec audit.Record
+		var changes []byte
+		if err := rows.Scan(&rec.ID, &rec.TenantID, &rec.TaskID, &rec.Actor, &rec.Time, &rec.RequestID, &rec.Operation, &changes); err != nil {
+			return nil, err
+		}
+		var stored map[string]storedChange
+		if err := json.Unmarshal(changes, &stored); err != nil {
+			return nil, err
+		}
+		if rec.Changes, err = r.openChanges(rec, stored); err != nil {
+			return nil, fmt.Errorf("audit record %d: %w", rec.ID, err)
+		}
+		records = append(records, rec)
+	}
+	return records, rows.Err()
+}
+
+// sealChanges seals the values of sealedAuditFields. Record IDs are only
+// known once written, so the values are bound to the tenant, task and time
+// of their record instead, and to whether they come before or after the
+// change.
+func (r *AuditRepository) sealChanges(rec audit.Record) (map[string]storedChange, error) {
+	stored := make(map[string]storedChange, len(rec.Changes))
+	for field, c := range rec.Changes {
+		stored[field] = storedChange{Before: c.Before, After: c.After}
+	}
+	if r.Keyring == nil {
+		return stored, nil
+	}
+	for _, field := range sealedAuditFields {
+		c, ok := stored[field]
+		if !ok {
+			continue
+		}
+		var err error
+		if c.Before, err = r.sealValue(rec, field, "before", c.Before); err != nil {
+			return nil, err
+		}
+		if c.After, err = r.sealValue(rec, field, "after", c.After); err != nil {
+			return nil, err
+		}
+		c.KeyID = r.Keyring.CurrentKeyID()
+		stored[field] = c
+	}
+	return stored, nil
+}
+
+// sealValue leaves a missing value missing, so creations and deletions
+// still read as such.
+func (r *AuditRepository) sealValue(rec audit.Record, field, side string, value json.RawMessage) (json.RawMessage, error) {
+	if len(value) == 0 || string(value) == "null" {
+		return value, nil
+	}
+	_, sealed, err := r.Keyring.Encrypt(value, auditAdditionalData(rec, field, side))
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(base64.StdEncoding.EncodeToString(sealed))
+}
+
+func (r *AuditRepository) openChanges(rec audit.Record, stored map[string]storedChange) (map[string]audit.Change, error) {
+	changes := make(map[string]audit.Change, len(stored))
+	for field, c := range stored {
+		if c.KeyID != "" {
+			if r.Keyring == nil {
+				return nil, fmt.Errorf("%s is sealed under key %q, but no keyring is configured", field, c.KeyID)
+			}
+			var err error
+			if c.Before, err = r.openValue(rec, field, "before", c.KeyID, c.Before); err != nil {
+				return nil, err
+			}
+			if c.After, err = r.openValue(rec, field, "after", c.KeyID, c.After); err != nil {
+				return nil, err
+			}
+		}
+		changes[field] = audit.Change{Before: c.Before, After: c.After}
+	}
+	return changes, nil
+}
+
+func (r *AuditRepository) openValue(rec audit.Record, field, side, keyID string, value json.RawMessage) (json.RawMessage, error) {
+	if len(value) == 0 || string(value) == "null" {
+		return value, nil
+	}
+	var encoded string
+	if err := json.Unmarshal(value, &encoded); err != nil {
+		return nil, fmt.Errorf("%w: %v", encryption.ErrDecrypt, err)
+	}
+	sealed, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", encryption.ErrDecrypt, err)
+	}
+	return r.Keyring.Decrypt(keyID, sealed, auditAdditionalData(rec, field, side))
+}
+
+func auditAdditionalData(rec audit.Record, field, side string) []byte {
+	return []byte(fmt.Sprintf("audit_log.changes.%s.%s:%s:%d:%d", field, side, rec.TenantID, rec.TaskID, rec.Time.UnixMicro()))
+}
@@ -0,0 +1,131 @@
+package mysql
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"task-api/internal/domain/audit"
+	"task-api/internal/encryption"
+)
+
+func TestAuditLog(t *testing.T) {
+	ctx := context.Background()
+	repo := &AuditRepository{DB: db}
+	start := time.Date(2026, 3, 1, 9, 0, 0, 123456000, time.UTC)
+
+	err := truncateTables(db, "audit_log")
+	assert.NoError(t, err)
+
+	created, err := repo.Append(ctx, audit.Record{TaskID: 1, Actor: "alice", Time: start, RequestID: "req-1",
+		Operation: audit.OperationCreate, Changes: map[string]audit.Change{"name": {After: json.RawMessage(`"Draft"`)}}})
+	assert.NoError(t, err)
+	_, err = repo.Append(ctx, audit.Record{TaskID: 1, Actor: "bob", Time: start.Add(time.Hour),
+		Operation: audit.OperationUpdate, Changes: map[string]audit.Change{"status": {Before: json.RawMessage(`0`), After: json.RawMessage(`1`)}}})
+	assert.NoError(t, err)
+	_, err = repo.Append(ctx, audit.Record{TaskID: 2, Actor: "alice", Time: start.Add(2 * time.Hour),
+		Operation: audit.OperationDelete, Changes: map[string]audit.Change{}})
+	assert.NoError(t, err)
+	_, err = repo.Append(ctx, audit.Record{TenantID: "acme", TaskID: 1, Actor: "alice", Time: start,
+		Operation: audit.OperationCreate, Changes: map[string]audit.Change{}})
+	assert.NoError(t, err)
+
+	records, err := repo.Find(audit.Filter{TaskID: 1, Actor: "alice"})
+	assert.NoError(t, err)
+	if assert.Len(t, records, 1) {
+		rec := records[0]
+		assert.Equal(t, created.ID, rec.ID)
+		assert.Equal(t, start, rec.Time)
+		assert.Equal(t, "req-1", rec.RequestID)
+		assert.Equal(t, audit.OperationCreate, rec.Operation)
+		assert.JSONEq(t, `{"name":{"before":null,"after":"Draft"}}`, mustJSON(t, rec.Changes))
+	}
+
+	tests := []struct {
+		TestCase string
+		Filter   audit.Filter
+		Expected []int
+	}{
+		{TestCase: "No filter", Expected: []int{1, 2, 3}},
+		{TestCase: "By actor", Filter: audit.Filter{Actor: "alice"}, Expected: []int{1, 3}},
+		{TestCase: "Time range is inclusive", Filter: audit.Filter{From: start.Add(time.Hour), To: start.Add(2 * time.Hour)}, Expected: []int{2, 3}},
+		{TestCase: "No match", Filter: audit.Filter{TaskID: 3}, Expected: nil},
+		{TestCase: "Other tenant", Filter: audit.Filter{TenantID: "acme", TaskID: 1}, Expected: []int{4}},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.TestCase, func(t *testing.T) {
+			records, err := repo.Find(tc.Filter)
+			assert.NoError(t, err)
+			var ids []int
+			for _, rec := range records {
+				ids = append(ids, rec.ID)
+			}
+			assert.Equal(t, tc.Expected, ids)
+		})
+	}
+
+	// The log is append-only.
+	_, err = db.Exec("UPDATE audit_log SET actor = 'mallory' WHERE id = ?", created.ID)
+	assert.Error(t, err)
+	_, err = db.Exec("DELETE FROM audit_log WHERE id = ?", created.ID)
+	assert.Error(t, err)
+}
+
+func TestAuditLogSealsNames(t *testing.T) {
+	ctx := context.Background()
+	repo := &AuditRepository{DB: db, Keyring: testKeyring(t, "k1")}
+	at := time.Date(2026, 3, 1, 9, 0, 0, 123456000, time.UTC)
+
+	err := truncateTables(db, "audit_log")
+	assert.NoError(t, err)
+
+	changes := map[string]audit.Change{
+		"name":   {Before: json.RawMessage(`"Call ACME"`), After: json.RawMessage(`"Call Globex"`)},
+		"status": {Before: json.RawMessage(`0`), After: json.RawMessage(`1`)},
+	}
+	_, err = repo.Append(ctx, audit.Record{TenantID: "acme", TaskID: 1, Actor: "alice", Time: at,
+		Operation: audit.OperationUpdate, Changes: changes})
+	assert.NoError(t, err)
+	_, err = repo.Append(ctx, audit.Record{TenantID: "acme", TaskID: 2, Actor: "alice", Time: at,
+		Operation: audit.OperationCreate, Changes: map[string]audit.Change{"name": {After: json.RawMessage(`"Lunch"`)}}})
+	assert.NoError(t, err)
+
+	var stored string
+	assert.NoError(t, db.QueryRow("SELECT changes FROM audit_log WHERE task_id = 1").Scan(&stored))
+	assert.NotContains(t, stored, "ACME")
+	assert.NotContains(t, stored, "Globex")
+	assert.Contains(t, stored, `"key_id": "k1"`)
+
+	records, err := repo.Find(audit.Filter{TenantID: "acme"})
+	assert.NoError(t, err)
+	if assert.Len(t, records, 2) {
+		assert.JSONEq(t, mustJSON(t, changes), mustJSON(t, records[0].Changes))
+		assert.JSONEq(t, `{"name":{"before":null,"after":"Lunch"}}`, mustJSON(t, records[1].Changes))
+	}
+
+	// Values are bound to their record: the log of task 1 cannot be read
+	// once another copies it, which takes lifting the trigger.
+	_, err = db.Exec("DROP TRIGGER audit_log_no_update")
+	assert.NoError(t, err)
+	_, err = db.Exec("UPDATE audit_log SET task_id = 2 WHERE task_id = 1")
+	assert.NoError(t, err)
+	_, err = db.Exec(`CREATE TRIGGER audit_log_no_update BEFORE UPDATE ON audit_log
+		FOR EACH ROW SIGNAL SQLSTATE '45000' SET MESSAGE_TEXT = 'audit_log is append-only'`)
+	assert.NoError(t, err)
+	_, err = repo.Find(audit.Filter{TenantID: "acme"})
+	assert.ErrorIs(t, err, encryption.ErrDecrypt)
+
+	// Sealed values cannot be read without the keyring.
+	_, err = (&AuditRepository{DB: db}).Find(audit.Filter{TenantID: "acme", TaskID: 2})
+	assert.Error(t, err)
+}
+
+func mustJSON(t *testing.T, v interface{}) string {
+	data, err := json.Marshal(v)
+	assert.NoError(t, err)
+	return string(data)
+}
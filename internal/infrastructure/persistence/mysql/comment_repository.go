@@ -0,0 +1,109 @@
+package mysql
+
+import (
+	"database/sql"
+
+	"task-api/internal/domain/comment"
+)
+
+const commentColumns = `c.id, c.task_id, c.parent_id, c.author, c.body,
+	(SELECT COUNT(*) FROM comments r WHERE r.parent_id = c.id), c.created_at, c.updated_at`
+
+type CommentRepository struct {
+	DB *sql.DB
+}
+
+func NewMySQLCommentRepository(db *sql.DB) comment.Repository {
+	return &CommentRepository{DB: db}
+}
+
+func (r *CommentRepository) List(taskID int, parentID *int, offset, limit int) ([]comment.Comment, int, error) {
+	// The null-safe <=> matches top-level comments when parentID is nil.
+	var total int
+	if err := r.DB.QueryRow("SELECT COUNT(*) FROM comments WHERE task_id = ? AND parent_id <=> ?", taskID, parentID).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	rows, err := r.DB.Query("SELECT "+commentColumns+" FROM comments c WHERE c.task_id = ? AND c.parent_id <=> ? ORDER BY c.id LIMIT ? OFFSET ?",
+		taskID, parentID, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	var comments []comment.Comment
+	for rows.Next() {
+		c, err := scanComment(rows)
+		if err != nil {
+			return nil, 0, err
+		}
+		comments = append(comments, c)
+	}
+	return comments, total, rows.Err()
+}
+
+func (r *CommentRepository) GetByID(id int) (comment.Comment, error) {
+	c, err := scanComment(r.DB.QueryRow("SELECT "+commentColumns+" FROM comments c WHERE c.id = ?", id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return comment.Comment{}, comment.ErrNotFound
+		}
+		return comment.Comment{}, err
+	}
+	return c, nil
+}
+
+func (r *CommentRepository) Create(c comment.Comment) (comment.Comment, error) {
+	result, err := r.DB.Exec("INSERT INTO comments (task_id, parent_id, author, body, created_at, updated_at) VALUES (?, ?, ?, ?, ?, ?)",
+		c.TaskID, c.ParentID, c.Author, c.Body, c.CreatedAt, c.UpdatedAt)
+	if err != nil {
+		return comment.Comment{}, err
+	}
+	id, err := result.LastInsertId()
+	if err != nil {
+		return comment.Comment{}, err
+	}
+	c.ID = int(id)
+	c.ReplyCount = 0
+	return c, nil
+}
+
+func (r *CommentRepository) Update(c comment.Comment) (comment.Comment, error) {
+	result, err := r.DB.Exec("UPDATE comments SET body = ?, updated_at = ? WHERE id = ?", c.Body, c.UpdatedAt, c.ID)
+	if err != nil {
+		return comment.Comment{}, err
+	}
+	if err := requireRow(result, comment.ErrNotFound); err != nil {
+		return comment.Comment{}, err
+	}
+	return r.GetByID(c.ID)
+}
+
+// Delete relies on the parent_id foreign key to cascade to replies.
+func (r *CommentRepository) Delete(id int) error {
+	result, err := r.DB.Exec("DELETE FROM comments WHERE id = ?", id)
+	if err != nil {
+		return err
+	}
+	return requireRow(result, comment.ErrNotFound)
+}
+
+// DeleteByTask is usually a no-op, as the task_id foreign key already
+// removed the comments together with their task.
+func (r *CommentRepository) DeleteByTask(taskID int) error {
+	_, err := r.DB.Exec("DELETE FROM comments WHERE task_id = ?", taskID)
+	return err
+}
+
+func scanComment(row scanner) (comment.Comment, error) {
+	var c comment.Comment
+	var parentID sql.NullInt64
+	if err := row.Scan(&c.ID, &c.TaskID, &parentID, &c.Author, &c.Body, &c.ReplyCount, &c.CreatedAt, &c.UpdatedAt); err != nil {
+		return comment.Comment{}, err
+	}
+	if parentID.Valid {
+		id := int(parentID.Int64)
+		c.ParentID = &id
+	}
+	return c, nil
+}
@@ -0,0 +1,85 @@
+package mysql
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"task-api/internal/domain/comment"
+	"task-api/internal/domain/task"
+)
+
+func TestComments(t *testing.T) {
+	repo := &CommentRepository{DB: db}
+	tasks := &TaskRepository{DB: db}
+	createdAt := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	err := clearTestDB(db)
+	assert.NoError(t, err)
+
+	owner, _ := tasks.Create(context.Background(), task.Info{Name: "Task"})
+	first, err := repo.Create(comment.Comment{TaskID: owner.ID, Author: "alice", Body: "First", CreatedAt: createdAt, UpdatedAt: createdAt})
+	assert.NoError(t, err)
+	second, _ := repo.Create(comment.Comment{TaskID: owner.ID, Author: "bob", Body: "Second", CreatedAt: createdAt, UpdatedAt: createdAt})
+	reply, _ := repo.Create(comment.Comment{TaskID: owner.ID, ParentID: &first.ID, Author: "bob", Body: "Reply", CreatedAt: createdAt, UpdatedAt: createdAt})
+
+	tests := []struct {
+		TestCase string
+		Run      func() (interface{}, error)
+		Expected interface{}
+		Error    error
+	}{
+		{
+			TestCase: "Top-level comments with reply counts",
+			Run: func() (interface{}, error) {
+				comments, total, err := repo.List(owner.ID, nil, 0, 10)
+				assert.Equal(t, 2, total)
+				return comments, err
+			},
+			Expected: []comment.Comment{
+				{ID: first.ID, TaskID: owner.ID, Author: "alice", Body: "First", ReplyCount: 1, CreatedAt: createdAt, UpdatedAt: createdAt},
+				{ID: second.ID, TaskID: owner.ID, Author: "bob", Body: "Second", CreatedAt: createdAt, UpdatedAt: createdAt},
+			},
+		},
+		{
+			TestCase: "Replies",
+			Run: func() (interface{}, error) {
+				comments, _, err := repo.List(owner.ID, &first.ID, 0, 10)
+				return comments, err
+			},
+			Expected: []comment.Comment{reply},
+		},
+		{
+			TestCase: "Update body",
+			Run: func() (interface{}, error) {
+				c, err := repo.Update(comment.Comment{ID: second.ID, Body: "Edited", UpdatedAt: createdAt.Add(time.Hour)})
+				return c.Body, err
+			},
+			Expected: "Edited",
+		},
+		{
+			TestCase: "Deleting a comment deletes its replies",
+			Run: func() (interface{}, error) {
+				if err := repo.Delete(first.ID); err != nil {
+					return nil, err
+				}
+				return repo.GetByID(reply.ID)
+			},
+			Error: comment.ErrNotFound,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.TestCase, func(t *testing.T) {
+			result, err := tc.Run()
+			if tc.Error != nil {
+				assert.Equal(t, tc.Error, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.Expected, result)
+		})
+	}
+}
@@ -0,0 +1,38 @@
+package mysql
+
+import (
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+
+	"task-api/internal/config"
+)
+
+// NewDB opens the connection pool shared by every MySQL repository. Times are
+// always parsed into time.Time and stored in UTC, and updates report matched
+// rather than changed rows so "not found" checks work, whatever the DSN says.
+func NewDB(cfg config.MySQLConfig) (*sql.DB, error) {
+	dsn, err := mysql.ParseDSN(cfg.DSN)
+	if err != nil {
+		return nil, err
+	}
+	dsn.ParseTime = true
+	dsn.Loc = time.UTC
+	dsn.ClientFoundRows = true
+	return sql.Open("mysql", dsn.FormatDSN())
+}
+
+// isForeignKeyError reports whether err is a failed foreign key check on
+// insert, i.e. the referenced row does not exist.
+func isForeignKeyError(err error) bool {
+	var mysqlErr *mysql.MySQLError
+	return errors.As(err, &mysqlErr) && mysqlErr.Number == 1452
+}
+
+// isDuplicateError reports whether err is a unique key violation.
+func isDuplicateError(err error) bool {
+	var mysqlErr *mysql.MySQLError
+	return errors.As(err, &mysqlErr) && mysqlErr.Number == 1062
+}
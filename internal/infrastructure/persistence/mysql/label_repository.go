@@ -0,0 +1,194 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+
+	"task-api/internal/domain/label"
+	"task-api/internal/tenant"
+)
+
+// LabelRepository has every query reach only the labels whose tenant_id is
+// the tenant of the context, like the TaskRepository.
+type LabelRepository struct {
+	DB *sql.DB
+}
+
+func NewMySQLLabelRepository(db *sql.DB) label.Repository {
+	return &LabelRepository{DB: db}
+}
+
+func (r *LabelRepository) GetAll(ctx context.Context) ([]label.Label, error) {
+	rows, err := r.DB.QueryContext(ctx, "SELECT id, tenant_id, name, color FROM labels WHERE tenant_id = ? ORDER BY name", tenant.FromContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var labels []label.Label
+	for rows.Next() {
+		var l label.Label
+		if err := rows.Scan(&l.ID, &l.TenantID, &l.Name, &l.Color); err != nil {
+			return nil, err
+		}
+		labels = append(labels, l)
+	}
+	return labels, rows.Err()
+}
+
+func (r *LabelRepository) GetByID(ctx context.Context, id int) (label.Label, error) {
+	return r.getOne(ctx, "SELECT id, tenant_id, name, color FROM labels WHERE tenant_id = ? AND id = ?", tenant.FromContext(ctx), id)
+}
+
+func (r *LabelRepository) GetByName(ctx context.Context, name string) (label.Label, error) {
+	return r.getOne(ctx, "SELECT id, tenant_id, name, color FROM labels WHERE tenant_id = ? AND name = ?", tenant.FromContext(ctx), name)
+}
+
+func (r *LabelRepository) Create(ctx context.Context, l label.Label) (label.Label, error) {
+	l.TenantID = tenant.FromContext(ctx)
+	result, err := r.DB.ExecContext(ctx, "INSERT INTO labels (tenant_id, name, color) VALUES (?, ?, ?)", l.TenantID, l.Name, l.Color)
+	if err != nil {
+		if isDuplicateError(err) {
+			return label.Label{}, label.ErrDuplicateName
+		}
+		return label.Label{}, err
+	}
+	id, err := result.LastInsertId()
+	if err != nil {
+		return label.Label{}, err
+	}
+	l.ID = int(id)
+	return l, nil
+}
+
+func (r *LabelRepository) Update(ctx context.Context, l label.Label) (label.Label, error) {
+	l.TenantID = tenant.FromContext(ctx)
+	result, err := r.DB.ExecContext(ctx, "UPDATE labels SET name = ?, color = ? WHERE tenant_id = ? AND id = ?", l.Name, l.Color, l.TenantID, l.ID)
+	if err != nil {
+		if isDuplicateError(err) {
+			return label.Label{}, label.ErrDuplicateName
+		}
+		return label.Label{}, err
+	}
+	if err := requireRow(result, label.ErrNotFound); err != nil {
+		return label.Label{}, err
+	}
+	return l, nil
+}
+
+func (r *LabelRepository) Delete(ctx context.Context, id int) error {
+	result, err := r.DB.ExecContext(ctx, "DELETE FROM labels WHERE tenant_id = ? AND id = ?", tenant.FromContext(ctx), id)
+	if err != nil {
+		return err
+	}
+	return requireRow(result, label.ErrNotFound)
+}
+
+func (r *LabelRepository) Attach(ctx context.Context, taskID, labelID int) error {
+	// Only a label of the tenant is inserted; attaching it again counts as
+	// a found row.
+	result, err := r.DB.ExecContext(ctx, `INSERT INTO task_labels (task_id, label_id)
+		SELECT ?, id FROM labels WHERE tenant_id = ? AND id = ?
+		ON DUPLICATE KEY UPDATE label_id = task_labels.label_id`, taskID, tenant.FromContext(ctx), labelID)
+	if isForeignKeyError(err) {
+		return label.ErrNotFound
+	}
+	if err != nil {
+		return err
+	}
+	return requireRow(result, label.ErrNotFound)
+}
+
+func (r *LabelRepository) Detach(ctx context.Context, taskID, labelID int) error {
+	result, err := r.DB.ExecContext(ctx, `DELETE tl FROM task_labels tl JOIN labels l ON l.id = tl.label_id
+		WHERE l.tenant_id = ? AND tl.task_id = ? AND tl.label_id = ?`, tenant.FromContext(ctx), taskID, labelID)
+	if err != nil {
+		return err
+	}
+	return requireRow(result, label.ErrNotAttached)
+}
+
+func (r *LabelRepository) DetachAll(ctx context.Context, taskID int) error {
+	_, err := r.DB.ExecContext(ctx, "DELETE FROM task_labels WHERE task_id = ?", taskID)
+	return err
+}
+
+func (r *LabelRepository) GetTaskLabels(ctx context.Context, taskIDs []int) (map[int][]label.Label, error) {
+	result := make(map[int][]label.Label)
+	if len(taskIDs) == 0 {
+		return result, nil
+	}
+
+	rows, err := r.DB.QueryContext(ctx, `
+		SELECT tl.task_id, l.id, l.tenant_id, l.name, l.color
+		FROM task_labels tl JOIN labels l ON l.id = tl.label_id
+		WHERE l.tenant_id = ? AND tl.task_id IN (`+placeholders(len(taskIDs))+`)
+		ORDER BY tl.task_id, l.name`, append([]interface{}{tenant.FromContext(ctx)}, intArgs(taskIDs)...)...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var taskID int
+		var l label.Label
+		if err := rows.Scan(&taskID, &l.ID, &l.TenantID, &l.Name, &l.Color); err != nil {
+			return nil, err
+		}
+		result[taskID] = append(result[taskID], l)
+	}
+	return result, rows.Err()
+}
+
+func (r *LabelRepository) FindTasks(ctx context.Context, labelIDs []int, match label.Match) ([]int, error) {
+	if len(labelIDs) == 0 {
+		return nil, nil
+	}
+
+	query := `SELECT tl.task_id FROM task_labels tl JOIN labels l ON l.id = tl.label_id
+		WHERE l.tenant_id = ? AND tl.label_id IN (` + placeholders(len(labelIDs)) + ") GROUP BY tl.task_id"
+	args := append([]interface{}{tenant.FromContext(ctx)}, intArgs(labelIDs)...)
+	if match == label.MatchAll {
+		query += " HAVING COUNT(*) = ?"
+		args = append(args, len(labelIDs))
+	}
+	rows, err := r.DB.QueryContext(ctx, query+" ORDER BY tl.task_id", args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var taskIDs []int
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		taskIDs = append(taskIDs, id)
+	}
+	return taskIDs, rows.Err()
+}
+
+func (r *LabelRepository) getOne(ctx context.Context, query string, args ...interface{}) (label.Label, error) {
+	var l label.Label
+	if err := r.DB.QueryRowContext(ctx, query, args...).Scan(&l.ID, &l.TenantID, &l.Name, &l.Color); err != nil {
+		if err == sql.ErrNoRows {
+			return label.Label{}, label.ErrNotFound
+		}
+		return label.Label{}, err
+	}
+	return l, nil
+}
+
+func placeholders(n int) string {
+	return strings.TrimSuffix(strings.Repeat("?, ", n), ", ")
+}
+
+func intArgs(values []int) []interface{} {
+	args := make([]interface{}, len(values))
+	for i, v := range values {
+		args[i] = v
+	}
+	return args
+}
@@ -0,0 +1,161 @@
+package mysql
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"task-api/internal/domain/label"
+	"task-api/internal/domain/task"
+	"task-api/internal/tenant"
+)
+
+func TestLabels(t *testing.T) {
+	ctx := context.Background()
+	repo := &LabelRepository{DB: db}
+	tasks := &TaskRepository{DB: db}
+
+	err := clearTestDB(db)
+	assert.NoError(t, err)
+
+	first, _ := tasks.Create(ctx, task.Info{Name: "First"})
+	second, _ := tasks.Create(ctx, task.Info{Name: "Second"})
+	backend, err := repo.Create(ctx, label.Label{Name: "backend"})
+	assert.NoError(t, err)
+	urgent, err := repo.Create(ctx, label.Label{Name: "urgent", Color: "#ff0000"})
+	assert.NoError(t, err)
+	assert.NoError(t, repo.Attach(ctx, first.ID, backend.ID))
+	assert.NoError(t, repo.Attach(ctx, first.ID, urgent.ID))
+	assert.NoError(t, repo.Attach(ctx, second.ID, backend.ID))
+	assert.NoError(t, repo.Attach(ctx, second.ID, backend.ID))
+
+	tests := []struct {
+		TestCase string
+		Run      func() (interface{}, error)
+		Expected interface{}
+		Error    error
+	}{
+		{
+			TestCase: "Get by name",
+			Run:      func() (interface{}, error) { return repo.GetByName(ctx, "urgent") },
+			Expected: urgent,
+		},
+		{
+			TestCase: "Duplicate name",
+			Run:      func() (interface{}, error) { return repo.Create(ctx, label.Label{Name: "urgent"}) },
+			Error:    label.ErrDuplicateName,
+		},
+		{
+			TestCase: "Any of the labels",
+			Run:      func() (interface{}, error) { return repo.FindTasks(ctx, []int{backend.ID, urgent.ID}, label.MatchAny) },
+			Expected: []int{first.ID, second.ID},
+		},
+		{
+			TestCase: "All of the labels",
+			Run:      func() (interface{}, error) { return repo.FindTasks(ctx, []int{backend.ID, urgent.ID}, label.MatchAll) },
+			Expected: []int{first.ID},
+		},
+		{
+			TestCase: "Labels per task",
+			Run:      func() (interface{}, error) { return repo.GetTaskLabels(ctx, []int{first.ID, second.ID}) },
+			Expected: map[int][]label.Label{first.ID: {backend, urgent}, second.ID: {backend}},
+		},
+		{
+			TestCase: "Attach unknown label",
+			Run:      func() (interface{}, error) { return nil, repo.Attach(ctx, first.ID, 99) },
+			Error:    label.ErrNotFound,
+		},
+		{
+			TestCase: "Deleting a task detaches its labels",
+			Run: func() (interface{}, error) {
+				if err := tasks.Delete(context.Background(), first.ID); err != nil {
+					return nil, err
+				}
+				return repo.FindTasks(ctx, []int{urgent.ID}, label.MatchAny)
+			},
+			Expected: []int(nil),
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.TestCase, func(t *testing.T) {
+			result, err := tc.Run()
+			if tc.Error != nil {
+				assert.Equal(t, tc.Error, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.Expected, result)
+		})
+	}
+}
+
+func TestLabelTenantIsolation(t *testing.T) {
+	repo := &LabelRepository{DB: db}
+	tasks := &TaskRepository{DB: db}
+	acme := tenant.NewContext(context.Background(), "acme")
+	globex := tenant.NewContext(context.Background(), "globex")
+
+	err := clearTestDB(db)
+	assert.NoError(t, err)
+
+	report, _ := tasks.Create(acme, task.Info{Name: "Report"})
+	other, _ := tasks.Create(globex, task.Info{Name: "Report"})
+	urgent, err := repo.Create(acme, label.Label{Name: "urgent"})
+	assert.NoError(t, err)
+	assert.NoError(t, repo.Attach(acme, report.ID, urgent.ID))
+
+	tests := []struct {
+		TestCase string
+		Run      func() (interface{}, error)
+		Expected interface{}
+		Error    error
+	}{
+		{
+			TestCase: "The same name in another tenant",
+			Run: func() (interface{}, error) {
+				l, err := repo.Create(globex, label.Label{Name: "urgent"})
+				return l.TenantID, err
+			},
+			Expected: "globex",
+		},
+		{
+			TestCase: "Get a label of another tenant",
+			Run:      func() (interface{}, error) { return repo.GetByID(globex, urgent.ID) },
+			Error:    label.ErrNotFound,
+		},
+		{
+			TestCase: "Rename a label of another tenant",
+			Run:      func() (interface{}, error) { return repo.Update(globex, label.Label{ID: urgent.ID, Name: "taken"}) },
+			Error:    label.ErrNotFound,
+		},
+		{
+			TestCase: "Attach a label of another tenant",
+			Run:      func() (interface{}, error) { return nil, repo.Attach(globex, other.ID, urgent.ID) },
+			Error:    label.ErrNotFound,
+		},
+		{
+			TestCase: "Find tasks by a label of another tenant",
+			Run:      func() (interface{}, error) { return repo.FindTasks(globex, []int{urgent.ID}, label.MatchAny) },
+			Expected: []int(nil),
+		},
+		{
+			TestCase: "Detach a label of another tenant",
+			Run:      func() (interface{}, error) { return nil, repo.Detach(globex, report.ID, urgent.ID) },
+			Error:    label.ErrNotAttached,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.TestCase, func(t *testing.T) {
+			result, err := tc.Run()
+			if tc.Error != nil {
+				assert.Equal(t, tc.Error, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.Expected, result)
+		})
+	}
+}
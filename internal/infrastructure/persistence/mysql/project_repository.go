@@ -0,0 +1,87 @@
+package mysql
+
+import (
+	"database/sql"
+
+	"task-api/internal/domain/project"
+)
+
+const projectColumns = "id, name, description, archived_at"
+
+type ProjectRepository struct {
+	DB *sql.DB
+}
+
+func NewMySQLProjectRepository(db *sql.DB) project.Repository {
+	return &ProjectRepository{DB: db}
+}
+
+func (r *ProjectRepository) GetAll(includeArchived bool) ([]project.Project, error) {
+	query := "SELECT " + projectColumns + " FROM projects"
+	if !includeArchived {
+		query += " WHERE archived_at IS NULL"
+	}
+	rows, err := r.DB.Query(query + " ORDER BY id")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var projects []project.Project
+	for rows.Next() {
+		p, err := scanProject(rows)
+		if err != nil {
+			return nil, err
+		}
+		projects = append(projects, p)
+	}
+	return projects, rows.Err()
+}
+
+func (r *ProjectRepository) GetByID(id int) (project.Project, error) {
+	p, err := scanProject(r.DB.QueryRow("SELECT "+projectColumns+" FROM projects WHERE id = ?", id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return project.Project{}, project.ErrNotFound
+		}
+		return project.Project{}, err
+	}
+	return p, nil
+}
+
+func (r *ProjectRepository) Create(p project.Project) (project.Project, error) {
+	result, err := r.DB.Exec("INSERT INTO projects (name, description, archived_at) VALUES (?, ?, ?)", p.Name, p.Description, p.ArchivedAt)
+	if err != nil {
+		return project.Project{}, err
+	}
+	id, err := result.LastInsertId()
+	if err != nil {
+		return project.Project{}, err
+	}
+	p.ID = int(id)
+	return p, nil
+}
+
+func (r *ProjectRepository) Update(p project.Project) (project.Project, error) {
+	if _, err := r.GetByID(p.ID); err != nil {
+		return project.Project{}, err
+	}
+	_, err := r.DB.Exec("UPDATE projects SET name = ?, description = ?, archived_at = ? WHERE id = ?", p.Name, p.Description, p.ArchivedAt, p.ID)
+	if err != nil {
+		return project.Project{}, err
+	}
+	return p, nil
+}
+
+func scanProject(row scanner) (project.Project, error) {
+	var p project.Project
+	var archivedAt sql.NullTime
+	if err := row.Scan(&p.ID, &p.Name, &p.Description, &archivedAt); err != nil {
+		return project.Project{}, err
+	}
+	if archivedAt.Valid {
+		t := archivedAt.Time
+		p.ArchivedAt = &t
+	}
+	return p, nil
+}
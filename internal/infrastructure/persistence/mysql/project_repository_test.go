@@ -0,0 +1,58 @@
+package mysql
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"task-api/internal/domain/project"
+	"task-api/internal/domain/task"
+)
+
+func TestProjects(t *testing.T) {
+	repo := &ProjectRepository{DB: db}
+	tasks := &TaskRepository{DB: db}
+	archivedAt := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+
+	err := clearTestDB(db)
+	assert.NoError(t, err)
+
+	defaultProject, err := repo.GetByID(project.DefaultProjectID)
+	assert.NoError(t, err)
+	website, err := repo.Create(project.Project{Name: "Website"})
+	assert.NoError(t, err)
+
+	website.ArchivedAt = &archivedAt
+	_, err = repo.Update(website)
+	assert.NoError(t, err)
+
+	active, err := repo.GetAll(false)
+	assert.NoError(t, err)
+	assert.Equal(t, []project.Project{defaultProject}, active)
+	all, err := repo.GetAll(true)
+	assert.NoError(t, err)
+	assert.Equal(t, []project.Project{defaultProject, website}, all)
+
+	_, err = tasks.Create(context.Background(), task.Info{Name: "Landing page", ProjectID: website.ID, Status: task.StatusDone})
+	assert.NoError(t, err)
+	_, err = tasks.Create(context.Background(), task.Info{Name: "Copy", ProjectID: website.ID})
+	assert.NoError(t, err)
+	_, err = tasks.Create(context.Background(), task.Info{Name: "Elsewhere"})
+	assert.NoError(t, err)
+
+	counts, err := tasks.CountByProject(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, map[int]task.Progress{
+		project.DefaultProjectID: {Total: 1},
+		website.ID:               {Total: 2, Done: 1},
+	}, counts)
+
+	websiteTasks, err := tasks.GetByProject(context.Background(), website.ID)
+	assert.NoError(t, err)
+	assert.Len(t, websiteTasks, 2)
+
+	_, err = repo.Update(project.Project{ID: 99, Name: "Nope"})
+	assert.Equal(t, project.ErrNotFound, err)
+}
@@ -0,0 +1,224 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"log"
+	"time"
+
+	"task-api/internal/config"
+	"task-api/internal/encryption"
+)
+
+// Reencryptor seals the task names and webhook delivery payloads stored in
+// plain text or under a key other than the current one of the keyring
+// again, so that retired keys can be dropped from the keyring once it is
+// through. It goes through every tenant. The audit log cannot be changed,
+// so its values stay under the key they were written with.
+type Reencryptor struct {
+	tasks    *TaskRepository
+	webhooks *WebhookRepository
+	interval time.Duration
+	batch    int
+}
+
+func NewReencryptor(db *sql.DB, keyring *encryption.Keyring, cfg config.EncryptionConfig) *Reencryptor {
+	return &Reencryptor{
+		tasks:    &TaskRepository{DB: db, Keyring: keyring},
+		webhooks: &WebhookRepository{DB: db, Keyring: keyring},
+		interval: cfg.ReencryptInterval,
+		batch:    cfg.ReencryptBatchSize,
+	}
+}
+
+// Run seals names and payloads again every interval until ctx is done. Without a
+// keyring, it returns at once.
+func (r *Reencryptor) Run(ctx context.Context) {
+	if r.tasks.Keyring == nil {
+		return
+	}
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+
+	for {
+		if n, err := r.RunOnce(ctx); err != nil {
+			log.Printf("reencrypt: %v", err)
+		} else if n > 0 {
+			log.Printf("reencrypt: sealed %d task names and webhook payloads under key %q", n, r.tasks.Keyring.CurrentKeyID())
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// staleName is a task name that is not sealed under the current key.
+type staleName struct {
+	id       int
+	tenantID string
+	stored   string
+	keyID    sql.NullString
+}
+
+// RunOnce goes through the tasks and deliveries once, a batch at a time,
+// and returns how many names and payloads it sealed again. Values that
+// cannot be opened, for instance because their key was dropped from the
+// keyring, are logged and left as they are. A name changed in the meantime
+// is left to its update, which seals it under the current key already.
+func (r *Reencryptor) RunOnce(ctx context.Context) (int, error) {
+	if r.tasks.Keyring == nil {
+		return 0, nil
+	}
+	names, err := r.sealNames(ctx)
+	if err != nil {
+		return names, err
+	}
+	payloads, err := r.sealPayloads(ctx)
+	return names + payloads, err
+}
+
+func (r *Reencryptor) sealNames(ctx context.Context) (int, error) {
+	sealed := 0
+	lastID := 0
+	for {
+		names, err := r.staleNames(ctx, lastID)
+		if err != nil {
+			return sealed, err
+		}
+		if len(names) == 0 {
+			return sealed, nil
+		}
+		for _, n := range names {
+			lastID = n.id
+			ok, err := r.seal(ctx, n)
+			if err != nil {
+				return sealed, err
+			}
+			if ok {
+				sealed++
+			}
+		}
+	}
+}
+
+func (r *Reencryptor) staleNames(ctx context.Context, afterID int) ([]staleName, error) {
+	rows, err := r.tasks.DB.QueryContext(ctx, `SELECT id, tenant_id, name, name_key_id FROM tasks
+		WHERE id > ? AND (name_key_id IS NULL OR name_key_id <> ?) ORDER BY id LIMIT ?`,
+		afterID, r.tasks.Keyring.CurrentKeyID(), r.batch)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var names []staleName
+	for rows.Next() {
+		var n staleName
+		if err := rows.Scan(&n.id, &n.tenantID, &n.stored, &n.keyID); err != nil {
+			return nil, err
+		}
+		names = append(names, n)
+	}
+	return names, rows.Err()
+}
+
+func (r *Reencryptor) seal(ctx context.Context, n staleName) (bool, error) {
+	name := n.stored
+	if n.keyID.Valid {
+		var err error
+		if name, err = r.tasks.openName(n.tenantID, n.id, n.stored, n.keyID.String); err != nil {
+			log.Printf("reencrypt: task %d: %v", n.id, err)
+			return false, nil
+		}
+	}
+	stored, keyID, err := r.tasks.sealName(n.tenantID, n.id, name)
+	if err != nil {
+		return false, err
+	}
+	result, err := r.tasks.DB.ExecContext(ctx, `UPDATE tasks SET name = ?, name_key_id = ?
+		WHERE id = ? AND BINARY name = ? AND name_key_id <=> ?`, stored, keyID, n.id, n.stored, n.keyID)
+	if err != nil {
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	return affected > 0, err
+}
+
+// stalePayload is a delivery payload that is not sealed under the current
+// key.
+type stalePayload struct {
+	id       int
+	tenantID string
+	stored   []byte
+	keyID    sql.NullString
+}
+
+func (r *Reencryptor) sealPayloads(ctx context.Context) (int, error) {
+	sealed := 0
+	lastID := 0
+	for {
+		payloads, err := r.stalePayloads(ctx, lastID)
+		if err != nil {
+			return sealed, err
+		}
+		if len(payloads) == 0 {
+			return sealed, nil
+		}
+		for _, p := range payloads {
+			lastID = p.id
+			ok, err := r.sealPayload(ctx, p)
+			if err != nil {
+				return sealed, err
+			}
+			if ok {
+				sealed++
+			}
+		}
+	}
+}
+
+func (r *Reencryptor) stalePayloads(ctx context.Context, afterID int) ([]stalePayload, error) {
+	rows, err := r.webhooks.DB.QueryContext(ctx, `SELECT d.id, s.tenant_id, d.payload, d.payload_key_id
+		FROM webhook_deliveries d JOIN webhook_subscriptions s ON s.id = d.subscription_id
+		WHERE d.id > ? AND (d.payload_key_id IS NULL OR d.payload_key_id <> ?) ORDER BY d.id LIMIT ?`,
+		afterID, r.webhooks.Keyring.CurrentKeyID(), r.batch)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var payloads []stalePayload
+	for rows.Next() {
+		var p stalePayload
+		if err := rows.Scan(&p.id, &p.tenantID, &p.stored, &p.keyID); err != nil {
+			return nil, err
+		}
+		payloads = append(payloads, p)
+	}
+	return payloads, rows.Err()
+}
+
+// sealPayload only changes payloads still sealed as they were read, as the
+// payload of a delivery is never updated otherwise.
+func (r *Reencryptor) sealPayload(ctx context.Context, p stalePayload) (bool, error) {
+	payload := p.stored
+	if p.keyID.Valid {
+		var err error
+		if payload, err = r.webhooks.openPayload(p.tenantID, p.id, p.stored, p.keyID.String); err != nil {
+			log.Printf("reencrypt: delivery %d: %v", p.id, err)
+			return false, nil
+		}
+	}
+	stored, keyID, err := r.webhooks.sealPayload(p.tenantID, p.id, payload)
+	if err != nil {
+		return false, err
+	}
+	result, err := r.webhooks.DB.ExecContext(ctx, `UPDATE webhook_deliveries SET payload = ?, payload_key_id = ?
+		WHERE id = ? AND payload_key_id <=> ?`, stored, keyID, p.id, p.keyID)
+	if err != nil {
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	return affected > 0, err
+}
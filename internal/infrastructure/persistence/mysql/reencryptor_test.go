@@ -0,0 +1,99 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"task-api/internal/config"
+	"task-api/internal/domain/task"
+	"task-api/internal/domain/webhook"
+	"task-api/internal/tenant"
+)
+
+func TestReencryptor(t *testing.T) {
+	err := clearTestDB(db)
+	assert.NoError(t, err)
+	assert.NoError(t, clearWebhookTables())
+
+	acme := tenant.NewContext(context.Background(), "acme")
+	globex := tenant.NewContext(context.Background(), "globex")
+	old := &TaskRepository{DB: db, Keyring: testKeyring(t, "k1")}
+	first, err := old.Create(acme, task.Info{Name: "Sealed under k1"})
+	assert.NoError(t, err)
+	second, err := old.Create(globex, task.Info{Name: "Sealed under k1 too"})
+	assert.NoError(t, err)
+	plain, err := (&TaskRepository{DB: db}).Create(acme, task.Info{Name: "Plain"})
+	assert.NoError(t, err)
+	_, err = db.Exec("INSERT INTO tasks (tenant_id, name, name_key_id, status) VALUES ('acme', 'garbage', 'k0', 0)")
+	assert.NoError(t, err)
+
+	cfg := config.EncryptionConfig{ReencryptInterval: time.Hour, ReencryptBatchSize: 2}
+	keyring := testKeyring(t, "k2")
+	reencryptor := NewReencryptor(db, keyring, cfg)
+	n, err := reencryptor.RunOnce(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, 3, n)
+
+	keyIDs := make(map[int]sql.NullString)
+	rows, err := db.Query("SELECT id, name_key_id FROM tasks")
+	assert.NoError(t, err)
+	for rows.Next() {
+		var id int
+		var keyID sql.NullString
+		assert.NoError(t, rows.Scan(&id, &keyID))
+		keyIDs[id] = keyID
+	}
+	assert.NoError(t, rows.Close())
+	for _, id := range []int{first.ID, second.ID, plain.ID} {
+		assert.Equal(t, sql.NullString{String: "k2", Valid: true}, keyIDs[id])
+	}
+
+	repo := &TaskRepository{DB: db, Keyring: keyring}
+	found, err := repo.GetByID(globex, second.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, "Sealed under k1 too", found.Name)
+	found, err = repo.GetByID(acme, plain.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, "Plain", found.Name)
+
+	// Names that cannot be opened are left for someone to look into.
+	n, err = reencryptor.RunOnce(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, 0, n)
+}
+
+func TestReencryptorPayloads(t *testing.T) {
+	assert.NoError(t, clearTestDB(db))
+	assert.NoError(t, clearWebhookTables())
+
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	plainRepo := &WebhookRepository{DB: db}
+	sub, err := plainRepo.Create(webhook.Subscription{TenantID: "acme", URL: "https://example.com/hook", Active: true, CreatedAt: now})
+	assert.NoError(t, err)
+	delivery := webhook.Delivery{SubscriptionID: sub.ID, EventID: 1, EventType: "task.created",
+		Payload: json.RawMessage(`{"id":1}`), Status: webhook.DeliveryPending, NextAttemptAt: now, CreatedAt: now}
+	plain, err := plainRepo.CreateDelivery(delivery)
+	assert.NoError(t, err)
+	old, err := (&WebhookRepository{DB: db, Keyring: testKeyring(t, "k1")}).CreateDelivery(delivery)
+	assert.NoError(t, err)
+
+	keyring := testKeyring(t, "k2")
+	n, err := NewReencryptor(db, keyring, config.EncryptionConfig{ReencryptBatchSize: 1}).RunOnce(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, 2, n)
+
+	repo := &WebhookRepository{DB: db, Keyring: keyring}
+	for _, id := range []int{plain.ID, old.ID} {
+		var keyID sql.NullString
+		assert.NoError(t, db.QueryRow("SELECT payload_key_id FROM webhook_deliveries WHERE id = ?", id).Scan(&keyID))
+		assert.Equal(t, sql.NullString{String: "k2", Valid: true}, keyID)
+		found, err := repo.GetDelivery(id)
+		assert.NoError(t, err)
+		assert.JSONEq(t, `{"id":1}`, string(found.Payload))
+	}
+}
@@ -0,0 +1,37 @@
+package mysql
+
+import (
+	"database/sql"
+	"time"
+
+	"task-api/internal/domain/reminder"
+)
+
+type ReminderRepository struct {
+	DB *sql.DB
+}
+
+func NewMySQLReminderRepository(db *sql.DB) reminder.Repository {
+	return &ReminderRepository{DB: db}
+}
+
+// Claim relies on the primary key of reminders to reject a second claim.
+func (r *ReminderRepository) Claim(rem reminder.Reminder) error {
+	_, err := r.DB.Exec("INSERT INTO reminders (task_id, window_seconds, due_at, sent_at) VALUES (?, ?, ?, ?)",
+		rem.TaskID, int64(rem.Window/time.Second), rem.DueAt, rem.SentAt)
+	if isDuplicateError(err) {
+		return reminder.ErrAlreadySent
+	}
+	return err
+}
+
+func (r *ReminderRepository) Release(rem reminder.Reminder) error {
+	_, err := r.DB.Exec("DELETE FROM reminders WHERE task_id = ? AND window_seconds = ? AND due_at = ?",
+		rem.TaskID, int64(rem.Window/time.Second), rem.DueAt)
+	return err
+}
+
+func (r *ReminderRepository) DeleteByTask(taskID int) error {
+	_, err := r.DB.Exec("DELETE FROM reminders WHERE task_id = ?", taskID)
+	return err
+}
@@ -0,0 +1,31 @@
+package mysql
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"task-api/internal/domain/reminder"
+)
+
+func TestReminders(t *testing.T) {
+	repo := &ReminderRepository{DB: db}
+	dueAt := time.Date(2024, 3, 25, 9, 0, 0, 0, time.UTC)
+	sentAt := time.Date(2024, 3, 24, 9, 0, 0, 0, time.UTC)
+	dayBefore := reminder.Reminder{TaskID: 1, Window: 24 * time.Hour, DueAt: dueAt, SentAt: sentAt}
+
+	err := clearTestDB(db)
+	assert.NoError(t, err)
+
+	assert.NoError(t, repo.Claim(dayBefore))
+	assert.Equal(t, reminder.ErrAlreadySent, repo.Claim(dayBefore))
+	assert.NoError(t, repo.Claim(reminder.Reminder{TaskID: 1, Window: -24 * time.Hour, DueAt: dueAt, SentAt: sentAt}))
+	assert.NoError(t, repo.Claim(reminder.Reminder{TaskID: 1, Window: 24 * time.Hour, DueAt: dueAt.Add(time.Hour), SentAt: sentAt}))
+
+	assert.NoError(t, repo.Release(dayBefore))
+	assert.NoError(t, repo.Claim(dayBefore))
+
+	assert.NoError(t, repo.DeleteByTask(1))
+	assert.NoError(t, repo.Claim(dayBefore))
+}
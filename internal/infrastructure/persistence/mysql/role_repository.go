@@ -0,0 +1,47 @@
+package mysql
+
+import (
+	"database/sql"
+
+	"task-api/internal/domain/role"
+)
+
+type RoleRepository struct {
+	DB *sql.DB
+}
+
+func NewMySQLRoleRepository(db *sql.DB) role.Repository {
+	return &RoleRepository{DB: db}
+}
+
+func (r *RoleRepository) GetByUser(userID int) ([]role.Assignment, error) {
+	rows, err := r.DB.Query("SELECT user_id, project_id, role FROM role_assignments WHERE user_id = ? ORDER BY project_id", userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var assignments []role.Assignment
+	for rows.Next() {
+		var a role.Assignment
+		if err := rows.Scan(&a.UserID, &a.ProjectID, &a.Role); err != nil {
+			return nil, err
+		}
+		assignments = append(assignments, a)
+	}
+	return assignments, rows.Err()
+}
+
+func (r *RoleRepository) Set(a role.Assignment) error {
+	_, err := r.DB.Exec(`INSERT INTO role_assignments (user_id, project_id, role) VALUES (?, ?, ?)
+		ON DUPLICATE KEY UPDATE role = VALUES(role)`, a.UserID, a.ProjectID, a.Role)
+	return err
+}
+
+func (r *RoleRepository) Delete(userID, projectID int) error {
+	result, err := r.DB.Exec("DELETE FROM role_assignments WHERE user_id = ? AND project_id = ?", userID, projectID)
+	if err != nil {
+		return err
+	}
+	return requireRow(result, role.ErrNotFound)
+}
@@ -0,0 +1,39 @@
+package mysql
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"task-api/internal/domain/role"
+	"task-api/internal/domain/user"
+)
+
+func TestRoles(t *testing.T) {
+	repo := &RoleRepository{DB: db}
+	users := &UserRepository{DB: db}
+
+	err := clearTestDB(db)
+	assert.NoError(t, err)
+
+	bob, err := users.Create(user.User{Username: "bob"})
+	assert.NoError(t, err)
+
+	assert.NoError(t, repo.Set(role.Assignment{UserID: bob.ID, ProjectID: 3, Role: role.Admin}))
+	assert.NoError(t, repo.Set(role.Assignment{UserID: bob.ID, Role: role.Viewer}))
+	assert.NoError(t, repo.Set(role.Assignment{UserID: bob.ID, Role: role.Editor}))
+
+	assignments, err := repo.GetByUser(bob.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, []role.Assignment{
+		{UserID: bob.ID, Role: role.Editor},
+		{UserID: bob.ID, ProjectID: 3, Role: role.Admin},
+	}, assignments)
+
+	assert.NoError(t, repo.Delete(bob.ID, 3))
+	assert.Equal(t, role.ErrNotFound, repo.Delete(bob.ID, 3))
+
+	assignments, err = repo.GetByUser(bob.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, []role.Assignment{{UserID: bob.ID, Role: role.Editor}}, assignments)
+}
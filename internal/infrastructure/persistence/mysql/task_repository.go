@@ -1,78 +1,408 @@
 package mysql
 
 import (
+	"context"
 	"database/sql"
-	"errors"
-
-	_ "github.com/go-sql-driver/mysql"
+	"encoding/base64"
+	"fmt"
+	"strings"
+	"time"
 
+	"task-api/internal/domain/project"
 	"task-api/internal/domain/task"
+	"task-api/internal/encryption"
+	"task-api/internal/search"
+	"task-api/internal/tenant"
 )
 
+const taskColumns = "id, tenant_id, name, name_key_id, status, project_id, parent_id, assignee_id, due_at, recurrence_rule, recurrence_timezone, recurrence_start"
+
+// TaskRepository has every query select, change or count only rows whose
+// tenant_id is the tenant of the context, and run in the transaction of the
+// context when there is one.
 type TaskRepository struct {
 	DB *sql.DB
+	// Keyring, when set, seals the names written, in base64, with the ID
+	// of the key in name_key_id. Names are read back whether they are
+	// sealed or not, as the Reencryptor seals the older ones.
+	Keyring *encryption.Keyring
+}
+
+// NewMySQLTaskRepository stores names in plain text when keyring is nil.
+func NewMySQLTaskRepository(db *sql.DB, keyring *encryption.Keyring) task.Repository {
+	return &TaskRepository{DB: db, Keyring: keyring}
+}
+
+func (r *TaskRepository) Component() string {
+	return "mysql"
+}
+
+// CheckHealth pings the pool shared by every MySQL repository.
+func (r *TaskRepository) CheckHealth(ctx context.Context) error {
+	return r.DB.PingContext(ctx)
+}
+
+func (r *TaskRepository) GetAll(ctx context.Context) ([]task.Info, error) {
+	return r.queryTasks(ctx, "SELECT "+taskColumns+" FROM tasks WHERE tenant_id = ?", tenant.FromContext(ctx))
 }
 
-func NewMySQLTaskRepository(dsn string) (task.Repository, error) {
-	db, err := sql.Open("mysql", dsn)
+func (r *TaskRepository) GetByID(ctx context.Context, id int) (task.Info, error) {
+	t, err := r.scanTask(conn(ctx, r.DB).QueryRowContext(ctx, "SELECT "+taskColumns+" FROM tasks WHERE tenant_id = ? AND id = ?",
+		tenant.FromContext(ctx), id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return task.Info{}, task.ErrNotFound
+		}
+		return task.Info{}, err
+	}
+	return t, nil
+}
+
+// Create writes a sealed name once the insert has given the task its ID,
+// which the name is bound to, in the same transaction.
+func (r *TaskRepository) Create(ctx context.Context, t task.Info) (task.Info, error) {
+	t.TenantID = tenant.FromContext(ctx)
+	t.ProjectID = projectOrDefault(t.ProjectID)
+	err := (&Transactor{DB: r.DB}).WithinTx(ctx, func(ctx context.Context) error {
+		name := t.Name
+		if r.Keyring != nil {
+			name = ""
+		}
+		rule, timezone, start := recurrenceColumns(t.Recurrence)
+		result, err := conn(ctx, r.DB).ExecContext(ctx, `INSERT INTO tasks (tenant_id, name, status, project_id, parent_id, assignee_id, due_at, recurrence_rule, recurrence_timezone, recurrence_start)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+			t.TenantID, name, t.Status, t.ProjectID, t.ParentID, t.AssigneeID, t.DueAt, rule, timezone, start)
+		if err != nil {
+			return err
+		}
+		id, err := result.LastInsertId()
+		if err != nil {
+			return err
+		}
+		t.ID = int(id)
+		if r.Keyring == nil {
+			return nil
+		}
+		name, keyID, err := r.sealName(t.TenantID, t.ID, t.Name)
+		if err != nil {
+			return err
+		}
+		_, err = conn(ctx, r.DB).ExecContext(ctx, "UPDATE tasks SET name = ?, name_key_id = ? WHERE id = ?", name, keyID, t.ID)
+		return err
+	})
+	if err != nil {
+		return task.Info{}, err
+	}
+	return t, nil
+}
+
+// Update cannot move a task to another tenant: the tenant of ctx both finds
+// the task and stays its tenant.
+func (r *TaskRepository) Update(ctx context.Context, t task.Info) (task.Info, error) {
+	t.TenantID = tenant.FromContext(ctx)
+	t.ProjectID = projectOrDefault(t.ProjectID)
+	name, keyID, err := r.sealName(t.TenantID, t.ID, t.Name)
+	if err != nil {
+		return task.Info{}, err
+	}
+	rule, timezone, start := recurrenceColumns(t.Recurrence)
+	result, err := conn(ctx, r.DB).ExecContext(ctx, `UPDATE tasks SET name = ?, name_key_id = ?, status = ?, project_id = ?, parent_id = ?, assignee_id = ?,
+		due_at = ?, recurrence_rule = ?, recurrence_timezone = ?, recurrence_start = ? WHERE tenant_id = ? AND id = ?`,
+		name, keyID, t.Status, t.ProjectID, t.ParentID, t.AssigneeID, t.DueAt, rule, timezone, start, t.TenantID, t.ID)
+	if err != nil {
+		return task.Info{}, err
+	}
+	if err := requireRow(result, task.ErrNotFound); err != nil {
+		return task.Info{}, err
+	}
+	return t, nil
+}
+
+func (r *TaskRepository) Delete(ctx context.Context, id int) error {
+	result, err := conn(ctx, r.DB).ExecContext(ctx, "DELETE FROM tasks WHERE tenant_id = ? AND id = ?", tenant.FromContext(ctx), id)
+	if err != nil {
+		return err
+	}
+	return requireRow(result, task.ErrNotFound)
+}
+
+func (r *TaskRepository) GetChildren(ctx context.Context, parentID int) ([]task.Info, error) {
+	return r.queryTasks(ctx, "SELECT "+taskColumns+" FROM tasks WHERE tenant_id = ? AND parent_id = ? ORDER BY id",
+		tenant.FromContext(ctx), parentID)
+}
+
+func (r *TaskRepository) GetDescendants(ctx context.Context, id int) ([]task.Info, error) {
+	return r.queryTasks(ctx, `
+		WITH RECURSIVE descendants AS (
+			SELECT `+taskColumns+`, 1 AS depth FROM tasks WHERE tenant_id = ? AND parent_id = ?
+			UNION ALL
+			SELECT t.id, t.tenant_id, t.name, t.name_key_id, t.status, t.project_id, t.parent_id, t.assignee_id,
+				t.due_at, t.recurrence_rule, t.recurrence_timezone, t.recurrence_start, d.depth + 1
+			FROM tasks t JOIN descendants d ON t.parent_id = d.id AND t.tenant_id = d.tenant_id
+		)
+		SELECT `+taskColumns+` FROM descendants ORDER BY depth, id`, tenant.FromContext(ctx), id)
+}
+
+// AddDependency only inserts a row when both tasks belong to the tenant.
+func (r *TaskRepository) AddDependency(ctx context.Context, blockerID, blockedID int) error {
+	// INSERT IGNORE would also swallow other errors, so duplicates are
+	// absorbed by the no-op update instead; they count as a found row.
+	tenantID := tenant.FromContext(ctx)
+	result, err := conn(ctx, r.DB).ExecContext(ctx, `INSERT INTO task_dependencies (blocker_id, blocked_id)
+		SELECT blocker.id, blocked.id FROM tasks blocker JOIN tasks blocked
+		WHERE blocker.tenant_id = ? AND blocker.id = ? AND blocked.tenant_id = ? AND blocked.id = ?
+		ON DUPLICATE KEY UPDATE blocker_id = task_dependencies.blocker_id`, tenantID, blockerID, tenantID, blockedID)
+	if err != nil {
+		return err
+	}
+	return requireRow(result, task.ErrNotFound)
+}
+
+func (r *TaskRepository) RemoveDependency(ctx context.Context, blockerID, blockedID int) error {
+	result, err := conn(ctx, r.DB).ExecContext(ctx, `DELETE d FROM task_dependencies d JOIN tasks t ON t.id = d.blocked_id
+		WHERE t.tenant_id = ? AND d.blocker_id = ? AND d.blocked_id = ?`, tenant.FromContext(ctx), blockerID, blockedID)
+	if err != nil {
+		return err
+	}
+	return requireRow(result, task.ErrDependencyNotFound)
+}
+
+func (r *TaskRepository) GetBlockers(ctx context.Context, id int) ([]task.Info, error) {
+	return r.queryTasks(ctx, `
+		SELECT t.id, t.tenant_id, t.name, t.name_key_id, t.status, t.project_id, t.parent_id, t.assignee_id,
+			t.due_at, t.recurrence_rule, t.recurrence_timezone, t.recurrence_start
+		FROM tasks t JOIN task_dependencies d ON d.blocker_id = t.id
+		WHERE t.tenant_id = ? AND d.blocked_id = ? ORDER BY t.id`, tenant.FromContext(ctx), id)
+}
+
+func (r *TaskRepository) GetDependencies(ctx context.Context) ([]task.Dependency, error) {
+	rows, err := conn(ctx, r.DB).QueryContext(ctx, `SELECT d.blocker_id, d.blocked_id FROM task_dependencies d JOIN tasks t ON t.id = d.blocked_id
+		WHERE t.tenant_id = ? ORDER BY d.blocker_id, d.blocked_id`, tenant.FromContext(ctx))
 	if err != nil {
 		return nil, err
 	}
-	return &TaskRepository{DB: db}, nil
+	defer rows.Close()
+
+	var deps []task.Dependency
+	for rows.Next() {
+		var d task.Dependency
+		if err := rows.Scan(&d.BlockerID, &d.BlockedID); err != nil {
+			return nil, err
+		}
+		deps = append(deps, d)
+	}
+	return deps, rows.Err()
+}
+
+func (r *TaskRepository) GetByProject(ctx context.Context, projectID int) ([]task.Info, error) {
+	return r.queryTasks(ctx, "SELECT "+taskColumns+" FROM tasks WHERE tenant_id = ? AND project_id = ? ORDER BY id",
+		tenant.FromContext(ctx), projectID)
 }
 
-func (r *TaskRepository) GetAll() ([]task.Info, error) {
-	rows, err := r.DB.Query("SELECT id, name, status FROM tasks")
+func (r *TaskRepository) CountByProject(ctx context.Context) (map[int]task.Progress, error) {
+	rows, err := conn(ctx, r.DB).QueryContext(ctx, "SELECT project_id, COUNT(*), SUM(status = ?) FROM tasks WHERE tenant_id = ? GROUP BY project_id",
+		task.StatusDone, tenant.FromContext(ctx))
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 
-	var tasks []task.Info
+	counts := make(map[int]task.Progress)
 	for rows.Next() {
-		var t task.Info
-		if err := rows.Scan(&t.ID, &t.Name, &t.Status); err != nil {
+		var projectID int
+		var c task.Progress
+		if err := rows.Scan(&projectID, &c.Total, &c.Done); err != nil {
 			return nil, err
 		}
-		tasks = append(tasks, t)
+		counts[projectID] = c
 	}
-	return tasks, nil
+	return counts, rows.Err()
 }
 
-func (r *TaskRepository) GetByID(id int) (task.Info, error) {
-	var t task.Info
-	err := r.DB.QueryRow("SELECT id, name, status FROM tasks WHERE id = ?", id).Scan(&t.ID, &t.Name, &t.Status)
+// GetDueBefore is served by the idx_tasks_due index.
+func (r *TaskRepository) GetDueBefore(ctx context.Context, before time.Time) ([]task.Info, error) {
+	return r.queryTasks(ctx, "SELECT "+taskColumns+" FROM tasks WHERE tenant_id = ? AND status = ? AND due_at <= ? ORDER BY due_at, id",
+		tenant.FromContext(ctx), task.StatusPending, before)
+}
+
+// GetByAssignee is served by the idx_tasks_assignee index.
+func (r *TaskRepository) GetByAssignee(ctx context.Context, userID int) ([]task.Info, error) {
+	return r.queryTasks(ctx, "SELECT "+taskColumns+" FROM tasks WHERE tenant_id = ? AND assignee_id = ? ORDER BY id",
+		tenant.FromContext(ctx), userID)
+}
+
+// Search is served by the idx_tasks_name FULLTEXT index. MySQL's
+// innodb_ft_min_token_size and stopword list apply: a query word the index
+// leaves out matches nothing. Sealed names cannot be matched, so with a
+// keyring every search fails with task.ErrEncrypted.
+func (r *TaskRepository) Search(ctx context.Context, query search.Query) ([]task.SearchResult, error) {
+	if r.Keyring != nil {
+		return nil, task.ErrEncrypted
+	}
+	against := booleanQuery(query)
+	rows, err := conn(ctx, r.DB).QueryContext(ctx, `SELECT `+taskColumns+`, MATCH (name) AGAINST (? IN BOOLEAN MODE) AS score
+		FROM tasks WHERE tenant_id = ? AND MATCH (name) AGAINST (? IN BOOLEAN MODE) ORDER BY score DESC, id`,
+		against, tenant.FromContext(ctx), against)
 	if err != nil {
-		if err == sql.ErrNoRows {
-			return task.Info{}, errors.New("task not found")
+		return nil, err
+	}
+	defer rows.Close()
+
+	var results []task.SearchResult
+	for rows.Next() {
+		var result task.SearchResult
+		if result.Task, err = r.scanTask(scoredRow{row: rows, score: &result.Score}); err != nil {
+			return nil, err
 		}
-		return task.Info{}, err
+		results = append(results, result)
 	}
-	return t, nil
+	return results, rows.Err()
 }
 
-func (r *TaskRepository) Create(t task.Info) (task.Info, error) {
-	result, err := r.DB.Exec("INSERT INTO tasks (name, status) VALUES (?, ?)", t.Name, t.Status)
+func (r *TaskRepository) Tenants(ctx context.Context) ([]string, error) {
+	rows, err := conn(ctx, r.DB).QueryContext(ctx, "SELECT DISTINCT tenant_id FROM tasks ORDER BY tenant_id")
 	if err != nil {
-		return task.Info{}, err
+		return nil, err
+	}
+	defer rows.Close()
+
+	var tenants []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		tenants = append(tenants, id)
+	}
+	return tenants, rows.Err()
+}
+
+// booleanQuery requires every term of a query in MySQL's boolean mode
+// syntax. Query words hold only letters, digits and underscores, so they
+// cannot smuggle in operators.
+func booleanQuery(query search.Query) string {
+	terms := make([]string, len(query.Terms))
+	for i, term := range query.Terms {
+		switch {
+		case term.Prefix:
+			terms[i] = "+" + term.Words[0] + "*"
+		case len(term.Words) == 1:
+			terms[i] = "+" + term.Words[0]
+		default:
+			terms[i] = `+"` + strings.Join(term.Words, " ") + `"`
+		}
 	}
-	id, err := result.LastInsertId()
+	return strings.Join(terms, " ")
+}
+
+// scoredRow reads the relevance score that follows the task columns.
+type scoredRow struct {
+	row   scanner
+	score *float64
+}
+
+func (s scoredRow) Scan(dest ...interface{}) error {
+	return s.row.Scan(append(dest, s.score)...)
+}
+
+func (r *TaskRepository) queryTasks(ctx context.Context, query string, args ...interface{}) ([]task.Info, error) {
+	rows, err := conn(ctx, r.DB).QueryContext(ctx, query, args...)
 	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var tasks []task.Info
+	for rows.Next() {
+		t, err := r.scanTask(rows)
+		if err != nil {
+			return nil, err
+		}
+		tasks = append(tasks, t)
+	}
+	return tasks, rows.Err()
+}
+
+func (r *TaskRepository) scanTask(row scanner) (task.Info, error) {
+	var t task.Info
+	var parentID, assigneeID sql.NullInt64
+	var dueAt, start sql.NullTime
+	var keyID, rule sql.NullString
+	var timezone string
+	if err := row.Scan(&t.ID, &t.TenantID, &t.Name, &keyID, &t.Status, &t.ProjectID, &parentID, &assigneeID, &dueAt, &rule, &timezone, &start); err != nil {
 		return task.Info{}, err
 	}
-	t.ID = int(id)
+	if keyID.Valid {
+		name, err := r.openName(t.TenantID, t.ID, t.Name, keyID.String)
+		if err != nil {
+			return task.Info{}, fmt.Errorf("task %d: %w", t.ID, err)
+		}
+		t.Name = name
+	}
+	t.ParentID = nullableInt(parentID)
+	t.AssigneeID = nullableInt(assigneeID)
+	if dueAt.Valid {
+		t.DueAt = &dueAt.Time
+	}
+	if rule.Valid {
+		t.Recurrence = &task.Recurrence{Rule: rule.String, Timezone: timezone, Start: start.Time}
+	}
 	return t, nil
 }
 
-func (r *TaskRepository) Update(t task.Info) (task.Info, error) {
-	_, err := r.DB.Exec("UPDATE tasks SET name = ?, status = ? WHERE id = ?", t.Name, t.Status, t.ID)
+// sealName returns the name to store and the ID of the key it is sealed
+// under, which is nil for names stored in plain text. Sealed names are bound
+// to their tenant and task, so that they cannot be moved to another one.
+func (r *TaskRepository) sealName(tenantID string, id int, name string) (string, *string, error) {
+	if r.Keyring == nil {
+		return name, nil, nil
+	}
+	keyID, sealed, err := r.Keyring.Encrypt([]byte(name), nameAdditionalData(tenantID, id))
 	if err != nil {
-		return task.Info{}, err
+		return "", nil, err
 	}
-	return t, nil
+	return base64.StdEncoding.EncodeToString(sealed), &keyID, nil
 }
 
-func (r *TaskRepository) Delete(id int) error {
-	_, err := r.DB.Exec("DELETE FROM tasks WHERE id = ?", id)
-	return err
+func (r *TaskRepository) openName(tenantID string, id int, stored, keyID string) (string, error) {
+	if r.Keyring == nil {
+		return "", fmt.Errorf("name is sealed under key %q, but no keyring is configured", keyID)
+	}
+	sealed, err := base64.StdEncoding.DecodeString(stored)
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", encryption.ErrDecrypt, err)
+	}
+	name, err := r.Keyring.Decrypt(keyID, sealed, nameAdditionalData(tenantID, id))
+	if err != nil {
+		return "", err
+	}
+	return string(name), nil
+}
+
+func nameAdditionalData(tenantID string, id int) []byte {
+	return []byte(fmt.Sprintf("tasks.name:%s:%d", tenantID, id))
+}
+
+// recurrenceColumns flattens a recurrence into its columns; a task without
+// one has a NULL rule.
+func recurrenceColumns(r *task.Recurrence) (rule *string, timezone string, start *time.Time) {
+	if r == nil {
+		return nil, "", nil
+	}
+	return &r.Rule, r.Timezone, &r.Start
+}
+
+func projectOrDefault(id int) int {
+	if id == 0 {
+		return project.DefaultProjectID
+	}
+	return id
+}
+
+func nullableInt(v sql.NullInt64) *int {
+	if !v.Valid {
+		return nil
+	}
+	id := int(v.Int64)
+	return &id
 }
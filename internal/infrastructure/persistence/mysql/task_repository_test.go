@@ -1,6 +1,7 @@
 package mysql
 
 import (
+	"bytes"
 	"context"
 	"database/sql"
 	"fmt"
@@ -13,7 +14,13 @@ import (
 	"github.com/testcontainers/testcontainers-go"
 	"github.com/testcontainers/testcontainers-go/wait"
 
+	"task-api/internal/config"
+	"task-api/internal/domain/project"
 	"task-api/internal/domain/task"
+	"task-api/internal/domain/user"
+	"task-api/internal/encryption"
+	"task-api/internal/search"
+	"task-api/internal/tenant"
 )
 
 const (
@@ -32,6 +39,8 @@ func TestMain(m *testing.M) {
 	req := testcontainers.ContainerRequest{
 		Image:        "mysql:8.0",
 		ExposedPorts: []string{"3306/tcp"},
+		// Index every word, so that search behaves like the in-memory index.
+		Cmd: []string{"--innodb-ft-min-token-size=1", "--innodb-ft-enable-stopword=OFF"},
 		Env: map[string]string{
 			"MYSQL_ROOT_PASSWORD": mysqlPassword,
 			"MYSQL_DATABASE":      mysqlDB,
@@ -79,28 +88,214 @@ func TestMain(m *testing.M) {
 }
 
 func setupTestDB() (*sql.DB, error) {
-	db, err := sql.Open("mysql", dsn)
+	db, err := NewDB(config.MySQLConfig{DSN: dsn})
 	if err != nil {
 		return nil, err
 	}
 
-	// Create tasks table for testing
-	_, err = db.Exec(`
+	// Create tables for testing
+	for _, stmt := range schema {
+		if _, err := db.Exec(stmt); err != nil {
+			return nil, err
+		}
+	}
+
+	return db, nil
+}
+
+var schema = []string{`
+        CREATE TABLE IF NOT EXISTS users (
+            id INT AUTO_INCREMENT PRIMARY KEY,
+            username VARCHAR(64) NOT NULL UNIQUE,
+            name VARCHAR(255) NOT NULL DEFAULT '',
+            email VARCHAR(255) NOT NULL DEFAULT ''
+        )
+    `, `
+        CREATE TABLE IF NOT EXISTS projects (
+            id INT AUTO_INCREMENT PRIMARY KEY,
+            name VARCHAR(255) NOT NULL,
+            description TEXT NOT NULL,
+            archived_at DATETIME NULL
+        )
+    `, `
         CREATE TABLE IF NOT EXISTS tasks (
             id INT AUTO_INCREMENT PRIMARY KEY,
+            tenant_id VARCHAR(64) NOT NULL DEFAULT 'default',
+            name VARCHAR(2048) NOT NULL,
+            name_key_id VARCHAR(64) NULL,
+            status INT NOT NULL,
+            project_id INT NOT NULL DEFAULT 1,
+            parent_id INT NULL,
+            assignee_id INT NULL,
+            due_at DATETIME NULL,
+            recurrence_rule VARCHAR(255) NULL,
+            recurrence_timezone VARCHAR(64) NOT NULL DEFAULT '',
+            recurrence_start DATETIME NULL,
+            INDEX idx_tasks_tenant (tenant_id),
+            INDEX idx_tasks_project (project_id),
+            INDEX idx_tasks_assignee (assignee_id),
+            INDEX idx_tasks_due (due_at),
+            INDEX idx_tasks_name_key (name_key_id),
+            FULLTEXT INDEX idx_tasks_name (name),
+            FOREIGN KEY (project_id) REFERENCES projects (id),
+            FOREIGN KEY (parent_id) REFERENCES tasks (id) ON DELETE SET NULL,
+            FOREIGN KEY (assignee_id) REFERENCES users (id) ON DELETE SET NULL
+        )
+    `, `
+        CREATE TABLE IF NOT EXISTS task_dependencies (
+            blocker_id INT NOT NULL,
+            blocked_id INT NOT NULL,
+            PRIMARY KEY (blocker_id, blocked_id),
+            FOREIGN KEY (blocker_id) REFERENCES tasks (id) ON DELETE CASCADE,
+            FOREIGN KEY (blocked_id) REFERENCES tasks (id) ON DELETE CASCADE
+        )
+    `, `
+        CREATE TABLE IF NOT EXISTS labels (
+            id INT AUTO_INCREMENT PRIMARY KEY,
+            tenant_id VARCHAR(64) NOT NULL DEFAULT 'default',
+            name VARCHAR(64) NOT NULL,
+            color VARCHAR(16) NOT NULL DEFAULT '',
+            UNIQUE KEY uq_labels_tenant_name (tenant_id, name)
+        )
+    `, `
+        CREATE TABLE IF NOT EXISTS task_labels (
+            task_id INT NOT NULL,
+            label_id INT NOT NULL,
+            PRIMARY KEY (task_id, label_id),
+            FOREIGN KEY (task_id) REFERENCES tasks (id) ON DELETE CASCADE,
+            FOREIGN KEY (label_id) REFERENCES labels (id) ON DELETE CASCADE
+        )
+    `, `
+        CREATE TABLE IF NOT EXISTS comments (
+            id INT AUTO_INCREMENT PRIMARY KEY,
+            task_id INT NOT NULL,
+            parent_id INT NULL,
+            author VARCHAR(255) NOT NULL,
+            body TEXT NOT NULL,
+            created_at DATETIME NOT NULL,
+            updated_at DATETIME NOT NULL,
+            FOREIGN KEY (task_id) REFERENCES tasks (id) ON DELETE CASCADE,
+            FOREIGN KEY (parent_id) REFERENCES comments (id) ON DELETE CASCADE
+        )
+    `, `
+        CREATE TABLE IF NOT EXISTS attachments (
+            id INT AUTO_INCREMENT PRIMARY KEY,
+            task_id INT NOT NULL,
+            filename VARCHAR(255) NOT NULL,
+            content_type VARCHAR(255) NOT NULL,
+            size BIGINT NOT NULL,
+            checksum CHAR(64) NOT NULL,
+            uploader VARCHAR(255) NOT NULL,
+            created_at DATETIME NOT NULL,
+            storage_key VARCHAR(255) NOT NULL
+        )
+    `, `
+        CREATE TABLE IF NOT EXISTS webhook_subscriptions (
+            id INT AUTO_INCREMENT PRIMARY KEY,
+            tenant_id VARCHAR(64) NOT NULL DEFAULT 'default',
+            url VARCHAR(2048) NOT NULL,
+            event_types VARCHAR(255) NOT NULL DEFAULT '',
+            secret VARCHAR(255) NOT NULL,
+            active BOOLEAN NOT NULL DEFAULT TRUE,
+            created_at DATETIME NOT NULL
+        )
+    `, `
+        CREATE TABLE IF NOT EXISTS webhook_deliveries (
+            id INT AUTO_INCREMENT PRIMARY KEY,
+            subscription_id INT NOT NULL,
+            event_id BIGINT UNSIGNED NOT NULL,
+            event_type VARCHAR(64) NOT NULL,
+            payload JSON NOT NULL,
+            payload_key_id VARCHAR(64) NULL,
+            status VARCHAR(16) NOT NULL,
+            attempts INT NOT NULL DEFAULT 0,
+            response_code INT NOT NULL DEFAULT 0,
+            last_error TEXT NOT NULL,
+            next_attempt_at DATETIME NOT NULL,
+            created_at DATETIME NOT NULL,
+            FOREIGN KEY (subscription_id) REFERENCES webhook_subscriptions (id) ON DELETE CASCADE
+        )
+    `, `
+        CREATE TABLE IF NOT EXISTS reminders (
+            task_id INT NOT NULL,
+            window_seconds INT NOT NULL,
+            due_at DATETIME NOT NULL,
+            sent_at DATETIME NOT NULL,
+            PRIMARY KEY (task_id, window_seconds, due_at)
+        )
+    `, `
+        CREATE TABLE IF NOT EXISTS api_keys (
+            id INT AUTO_INCREMENT PRIMARY KEY,
+            tenant_id VARCHAR(64) NOT NULL DEFAULT 'default',
             name VARCHAR(255) NOT NULL,
-            status INT NOT NULL
+            prefix VARCHAR(16) NOT NULL,
+            key_hash CHAR(64) NOT NULL UNIQUE,
+            scopes VARCHAR(255) NOT NULL,
+            created_at DATETIME NOT NULL,
+            last_used_at DATETIME NULL,
+            revoked_at DATETIME NULL
+        )
+    `, `
+        CREATE TABLE IF NOT EXISTS role_assignments (
+            user_id INT NOT NULL,
+            project_id INT NOT NULL DEFAULT 0,
+            role VARCHAR(16) NOT NULL,
+            PRIMARY KEY (user_id, project_id),
+            FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE
+        )
+    `, `
+        CREATE TABLE IF NOT EXISTS audit_log (
+            id BIGINT AUTO_INCREMENT PRIMARY KEY,
+            tenant_id VARCHAR(64) NOT NULL DEFAULT 'default',
+            task_id INT NOT NULL,
+            actor VARCHAR(255) NOT NULL,
+            created_at DATETIME(6) NOT NULL,
+            request_id VARCHAR(128) NOT NULL DEFAULT '',
+            operation VARCHAR(16) NOT NULL,
+            changes JSON NOT NULL,
+            INDEX idx_audit_log_tenant (tenant_id, created_at),
+            INDEX idx_audit_log_task (task_id, created_at),
+            INDEX idx_audit_log_actor (actor, created_at),
+            INDEX idx_audit_log_time (created_at)
         )
-    `)
+    `, `
+        CREATE TRIGGER audit_log_no_update BEFORE UPDATE ON audit_log
+        FOR EACH ROW SIGNAL SQLSTATE '45000' SET MESSAGE_TEXT = 'audit_log is append-only'
+    `, `
+        CREATE TRIGGER audit_log_no_delete BEFORE DELETE ON audit_log
+        FOR EACH ROW SIGNAL SQLSTATE '45000' SET MESSAGE_TEXT = 'audit_log is append-only'
+    `,
+}
+
+// truncateTables empties the given tables and resets their ids, ignoring
+// foreign keys between them.
+func truncateTables(db *sql.DB, tables ...string) error {
+	conn, err := db.Conn(context.Background())
 	if err != nil {
-		return nil, err
+		return err
 	}
+	defer conn.Close()
 
-	return db, nil
+	ctx := context.Background()
+	if _, err := conn.ExecContext(ctx, "SET FOREIGN_KEY_CHECKS = 0"); err != nil {
+		return err
+	}
+	defer conn.ExecContext(ctx, "SET FOREIGN_KEY_CHECKS = 1")
+	for _, table := range tables {
+		if _, err := conn.ExecContext(ctx, "TRUNCATE TABLE "+table); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
+// clearTestDB empties every table and recreates the default project.
 func clearTestDB(db *sql.DB) error {
-	_, err := db.Exec(`TRUNCATE TABLE tasks`)
+	err := truncateTables(db, "reminders", "attachments", "comments", "task_labels", "labels", "task_dependencies", "tasks", "role_assignments", "users", "projects")
+	if err != nil {
+		return err
+	}
+	_, err = db.Exec("INSERT INTO projects (id, name, description) VALUES (?, 'Default', '')", project.DefaultProjectID)
 	return err
 }
 
@@ -124,7 +319,7 @@ func TestCreateTask(t *testing.T) {
 			err := clearTestDB(db)
 			assert.NoError(t, err)
 
-			createdTask, err := repo.Create(tc.Input)
+			createdTask, err := repo.Create(context.Background(), tc.Input)
 			assert.NoError(t, err)
 			assert.Equal(t, tc.Expected.ID, createdTask.ID)
 			assert.Equal(t, tc.Expected.Name, createdTask.Name)
@@ -143,7 +338,7 @@ func TestGetAllTasks(t *testing.T) {
 		{
 			TestCase: "Get All Tasks",
 			Expected: []task.Info{
-				{ID: 1, Name: "Test TaskInfo", Status: 0},
+				{ID: 1, Name: "Test TaskInfo", Status: 0, ProjectID: project.DefaultProjectID, TenantID: tenant.Default},
 			},
 		},
 	}
@@ -153,11 +348,11 @@ func TestGetAllTasks(t *testing.T) {
 
 	// First, create a task
 	newTask := task.Info{Name: "Test TaskInfo", Status: 0}
-	_, _ = repo.Create(newTask)
+	_, _ = repo.Create(context.Background(), newTask)
 
 	for _, tc := range tests {
 		t.Run(tc.TestCase, func(t *testing.T) {
-			tasks, err := repo.GetAll()
+			tasks, err := repo.GetAll(context.Background())
 			assert.NoError(t, err)
 			assert.Equal(t, tc.Expected, tasks)
 		})
@@ -175,7 +370,7 @@ func TestGetTaskByID(t *testing.T) {
 		{
 			TestCase: "Get TaskInfo By ID",
 			ID:       1,
-			Expected: task.Info{ID: 1, Name: "Test TaskInfo", Status: 0},
+			Expected: task.Info{ID: 1, Name: "Test TaskInfo", Status: 0, ProjectID: project.DefaultProjectID, TenantID: tenant.Default},
 		},
 	}
 
@@ -184,12 +379,12 @@ func TestGetTaskByID(t *testing.T) {
 
 	// First, create a task
 	newTask := task.Info{Name: "Test TaskInfo", Status: 0}
-	_, err = repo.Create(newTask)
+	_, err = repo.Create(context.Background(), newTask)
 	assert.NoError(t, err)
 
 	for _, tc := range tests {
 		t.Run(tc.TestCase, func(t *testing.T) {
-			testTask, err := repo.GetByID(tc.ID)
+			testTask, err := repo.GetByID(context.Background(), tc.ID)
 			assert.NoError(t, err)
 			assert.Equal(t, tc.Expected, testTask)
 		})
@@ -216,12 +411,12 @@ func TestUpdateTask(t *testing.T) {
 
 	// First, create a task
 	newTask := task.Info{Name: "Test TaskInfo", Status: 0}
-	_, err = repo.Create(newTask)
+	_, err = repo.Create(context.Background(), newTask)
 	assert.NoError(t, err)
 
 	for _, tc := range tests {
 		t.Run(tc.TestCase, func(t *testing.T) {
-			updatedTask, err := repo.Update(tc.Input)
+			updatedTask, err := repo.Update(context.Background(), tc.Input)
 			assert.NoError(t, err)
 			assert.Equal(t, tc.Expected.ID, updatedTask.ID)
 			assert.Equal(t, tc.Expected.Name, updatedTask.Name)
@@ -248,17 +443,404 @@ func TestDeleteTask(t *testing.T) {
 
 	// First, create a task
 	newTask := task.Info{Name: "Test TaskInfo", Status: 0}
-	_, err = repo.Create(newTask)
+	_, err = repo.Create(context.Background(), newTask)
 	assert.NoError(t, err)
 
 	for _, tc := range tests {
 		t.Run(tc.TestCase, func(t *testing.T) {
-			err := repo.Delete(tc.ID)
+			err := repo.Delete(context.Background(), tc.ID)
 			assert.NoError(t, err)
 
-			tasks, err := repo.GetAll()
+			tasks, err := repo.GetAll(context.Background())
 			assert.NoError(t, err)
 			assert.Empty(t, tasks)
 		})
 	}
 }
+
+func TestGetChildrenAndDescendants(t *testing.T) {
+	repo := &TaskRepository{DB: db}
+
+	err := clearTestDB(db)
+	assert.NoError(t, err)
+
+	root, err := repo.Create(context.Background(), task.Info{Name: "Root"})
+	assert.NoError(t, err)
+	child, err := repo.Create(context.Background(), task.Info{Name: "Child", ParentID: &root.ID})
+	assert.NoError(t, err)
+	sibling, err := repo.Create(context.Background(), task.Info{Name: "Sibling", ParentID: &root.ID})
+	assert.NoError(t, err)
+	grandchild, err := repo.Create(context.Background(), task.Info{Name: "Grandchild", ParentID: &child.ID})
+	assert.NoError(t, err)
+
+	tests := []struct {
+		TestCase string
+		Fetch    func() ([]task.Info, error)
+		Expected []int
+	}{
+		{
+			TestCase: "Direct children",
+			Fetch:    func() ([]task.Info, error) { return repo.GetChildren(context.Background(), root.ID) },
+			Expected: []int{child.ID, sibling.ID},
+		},
+		{
+			TestCase: "Descendants breadth-first",
+			Fetch:    func() ([]task.Info, error) { return repo.GetDescendants(context.Background(), root.ID) },
+			Expected: []int{child.ID, sibling.ID, grandchild.ID},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.TestCase, func(t *testing.T) {
+			tasks, err := tc.Fetch()
+			assert.NoError(t, err)
+			var ids []int
+			for _, found := range tasks {
+				ids = append(ids, found.ID)
+			}
+			assert.Equal(t, tc.Expected, ids)
+		})
+	}
+}
+
+func TestDependencies(t *testing.T) {
+	repo := &TaskRepository{DB: db}
+
+	err := clearTestDB(db)
+	assert.NoError(t, err)
+
+	design, _ := repo.Create(context.Background(), task.Info{Name: "Design"})
+	build, _ := repo.Create(context.Background(), task.Info{Name: "Build"})
+	ship, _ := repo.Create(context.Background(), task.Info{Name: "Ship"})
+	assert.NoError(t, repo.AddDependency(context.Background(), design.ID, build.ID))
+	assert.NoError(t, repo.AddDependency(context.Background(), build.ID, ship.ID))
+	assert.NoError(t, repo.AddDependency(context.Background(), design.ID, ship.ID))
+	assert.NoError(t, repo.AddDependency(context.Background(), design.ID, ship.ID))
+
+	blockers, err := repo.GetBlockers(context.Background(), ship.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, []task.Info{design, build}, blockers)
+
+	assert.Equal(t, task.ErrNotFound, repo.AddDependency(context.Background(), 99, ship.ID))
+	assert.NoError(t, repo.RemoveDependency(context.Background(), design.ID, ship.ID))
+	assert.Equal(t, task.ErrDependencyNotFound, repo.RemoveDependency(context.Background(), design.ID, ship.ID))
+
+	assert.NoError(t, repo.Delete(context.Background(), build.ID))
+	deps, err := repo.GetDependencies(context.Background())
+	assert.NoError(t, err)
+	assert.Empty(t, deps)
+}
+
+func TestGetByAssignee(t *testing.T) {
+	repo := &TaskRepository{DB: db}
+	users := &UserRepository{DB: db}
+
+	err := clearTestDB(db)
+	assert.NoError(t, err)
+
+	alice, err := users.Create(user.User{Username: "alice"})
+	assert.NoError(t, err)
+	first, err := repo.Create(context.Background(), task.Info{Name: "First", AssigneeID: &alice.ID})
+	assert.NoError(t, err)
+	_, err = repo.Create(context.Background(), task.Info{Name: "Unassigned"})
+	assert.NoError(t, err)
+
+	tasks, err := repo.GetByAssignee(context.Background(), alice.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, []task.Info{first}, tasks)
+
+	unknown := 99
+	_, err = repo.Create(context.Background(), task.Info{Name: "Unknown user", AssigneeID: &unknown})
+	assert.Error(t, err)
+}
+
+func TestRecurrence(t *testing.T) {
+	repo := &TaskRepository{DB: db}
+
+	err := clearTestDB(db)
+	assert.NoError(t, err)
+
+	dueAt := time.Date(2024, 3, 4, 8, 0, 0, 0, time.UTC)
+	created, err := repo.Create(context.Background(), task.Info{
+		Name:       "Weekly report",
+		DueAt:      &dueAt,
+		Recurrence: &task.Recurrence{Rule: "FREQ=WEEKLY;BYDAY=MO", Timezone: "Europe/Berlin", Start: dueAt},
+	})
+	assert.NoError(t, err)
+
+	found, err := repo.GetByID(context.Background(), created.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, created, found)
+
+	found.Recurrence = nil
+	_, err = repo.Update(context.Background(), found)
+	assert.NoError(t, err)
+
+	found, err = repo.GetByID(context.Background(), created.ID)
+	assert.NoError(t, err)
+	assert.Nil(t, found.Recurrence)
+	assert.Equal(t, &dueAt, found.DueAt)
+}
+
+func TestSearch(t *testing.T) {
+	repo := &TaskRepository{DB: db}
+
+	err := clearTestDB(db)
+	assert.NoError(t, err)
+
+	login, err := repo.Create(context.Background(), task.Info{Name: "Fix login page"})
+	assert.NoError(t, err)
+	notes, err := repo.Create(context.Background(), task.Info{Name: "Write release notes for the login redesign"})
+	assert.NoError(t, err)
+	errorPage, err := repo.Create(context.Background(), task.Info{Name: "Page through the error page"})
+	assert.NoError(t, err)
+	deploy, err := repo.Create(context.Background(), task.Info{Name: "Deploy the deployment scripts"})
+	assert.NoError(t, err)
+
+	tests := []struct {
+		TestCase string
+		Query    string
+		Expected []int
+	}{
+		{TestCase: "Word", Query: "login", Expected: []int{login.ID, notes.ID}},
+		{TestCase: "All words must match", Query: "login page", Expected: []int{login.ID}},
+		{TestCase: "Prefix", Query: "deploy*", Expected: []int{deploy.ID}},
+		{TestCase: "Phrase", Query: `"error page"`, Expected: []int{errorPage.ID}},
+		{TestCase: "Phrase words out of order", Query: `"page error"`, Expected: nil},
+		{TestCase: "Operators are ignored", Query: "-login +(page", Expected: []int{login.ID}},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.TestCase, func(t *testing.T) {
+			query, err := search.Parse(tc.Query)
+			assert.NoError(t, err)
+			results, err := repo.Search(context.Background(), query)
+			assert.NoError(t, err)
+			var ids []int
+			for _, result := range results {
+				ids = append(ids, result.Task.ID)
+				assert.Greater(t, result.Score, 0.0)
+			}
+			assert.Equal(t, tc.Expected, ids)
+		})
+	}
+
+	login.Name = "Fix sign-in page"
+	_, err = repo.Update(context.Background(), login)
+	assert.NoError(t, err)
+	assert.NoError(t, repo.Delete(context.Background(), notes.ID))
+
+	query, _ := search.Parse("login")
+	results, err := repo.Search(context.Background(), query)
+	assert.NoError(t, err)
+	assert.Empty(t, results)
+}
+
+func TestTenantIsolation(t *testing.T) {
+	repo := &TaskRepository{DB: db}
+
+	err := clearTestDB(db)
+	assert.NoError(t, err)
+
+	acme := tenant.NewContext(context.Background(), "acme")
+	globex := tenant.NewContext(context.Background(), "globex")
+	dueAt := time.Date(2024, 3, 4, 8, 0, 0, 0, time.UTC)
+	report, err := repo.Create(acme, task.Info{Name: "Quarterly report", DueAt: &dueAt})
+	assert.NoError(t, err)
+	child, err := repo.Create(acme, task.Info{Name: "Collect numbers", ParentID: &report.ID})
+	assert.NoError(t, err)
+	other, err := repo.Create(globex, task.Info{Name: "Quarterly report"})
+	assert.NoError(t, err)
+	assert.NoError(t, repo.AddDependency(acme, child.ID, report.ID))
+
+	tests := []struct {
+		TestCase string
+		Run      func() (interface{}, error)
+		Expected interface{}
+		Error    error
+	}{
+		{
+			TestCase: "Get a task of another tenant",
+			Run:      func() (interface{}, error) { return repo.GetByID(globex, report.ID) },
+			Expected: task.Info{},
+			Error:    task.ErrNotFound,
+		},
+		{
+			TestCase: "Update a task of another tenant",
+			Run: func() (interface{}, error) {
+				return repo.Update(globex, task.Info{ID: report.ID, Name: "Taken over"})
+			},
+			Expected: task.Info{},
+			Error:    task.ErrNotFound,
+		},
+		{
+			TestCase: "Delete a task of another tenant",
+			Run:      func() (interface{}, error) { return nil, repo.Delete(globex, report.ID) },
+			Error:    task.ErrNotFound,
+		},
+		{
+			TestCase: "Depend on a task of another tenant",
+			Run:      func() (interface{}, error) { return nil, repo.AddDependency(globex, report.ID, other.ID) },
+			Error:    task.ErrNotFound,
+		},
+		{
+			TestCase: "Remove a dependency of another tenant",
+			Run:      func() (interface{}, error) { return nil, repo.RemoveDependency(globex, child.ID, report.ID) },
+			Error:    task.ErrDependencyNotFound,
+		},
+		{
+			TestCase: "List only the tasks of the tenant",
+			Run: func() (interface{}, error) {
+				tasks, err := repo.GetAll(globex)
+				return len(tasks), err
+			},
+			Expected: 1,
+		},
+		{
+			TestCase: "Descendants of a task of another tenant",
+			Run: func() (interface{}, error) {
+				descendants, err := repo.GetDescendants(globex, report.ID)
+				return len(descendants), err
+			},
+			Expected: 0,
+		},
+		{
+			TestCase: "Dependencies of the tenant",
+			Run: func() (interface{}, error) {
+				deps, err := repo.GetDependencies(globex)
+				return len(deps), err
+			},
+			Expected: 0,
+		},
+		{
+			TestCase: "Search only the tasks of the tenant",
+			Run: func() (interface{}, error) {
+				query, _ := search.Parse("quarterly")
+				results, err := repo.Search(globex, query)
+				if len(results) != 1 {
+					return len(results), err
+				}
+				return results[0].Task.ID, err
+			},
+			Expected: other.ID,
+		},
+		{
+			TestCase: "Count only the tasks of the tenant",
+			Run: func() (interface{}, error) {
+				counts, err := repo.CountByProject(globex)
+				return counts[project.DefaultProjectID].Total, err
+			},
+			Expected: 1,
+		},
+		{
+			TestCase: "Due tasks of the tenant",
+			Run: func() (interface{}, error) {
+				tasks, err := repo.GetDueBefore(globex, dueAt.Add(time.Hour))
+				return len(tasks), err
+			},
+			Expected: 0,
+		},
+		{
+			TestCase: "The task stays with its tenant",
+			Run: func() (interface{}, error) {
+				found, err := repo.GetByID(acme, report.ID)
+				return found.TenantID, err
+			},
+			Expected: "acme",
+		},
+		{
+			TestCase: "Tenants with tasks",
+			Run:      func() (interface{}, error) { return repo.Tenants(context.Background()) },
+			Expected: []string{"acme", "globex"},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.TestCase, func(t *testing.T) {
+			result, err := tc.Run()
+			assert.Equal(t, tc.Error, err)
+			assert.Equal(t, tc.Expected, result)
+		})
+	}
+}
+
+func testKeyring(t *testing.T, current string) *encryption.Keyring {
+	keyring, err := encryption.NewKeyring(current, map[string][]byte{
+		"k1": bytes.Repeat([]byte{1}, 32),
+		"k2": bytes.Repeat([]byte{2}, 32),
+	})
+	assert.NoError(t, err)
+	return keyring
+}
+
+func TestEncryptedNames(t *testing.T) {
+	repo := &TaskRepository{DB: db, Keyring: testKeyring(t, "k1")}
+
+	err := clearTestDB(db)
+	assert.NoError(t, err)
+
+	acme := tenant.NewContext(context.Background(), "acme")
+	globex := tenant.NewContext(context.Background(), "globex")
+	report, err := repo.Create(acme, task.Info{Name: "Call ACME about invoice 42"})
+	assert.NoError(t, err)
+	assert.Equal(t, "Call ACME about invoice 42", report.Name)
+
+	var name string
+	var keyID sql.NullString
+	assert.NoError(t, db.QueryRow("SELECT name, name_key_id FROM tasks WHERE id = ?", report.ID).Scan(&name, &keyID))
+	assert.NotContains(t, name, "ACME")
+	assert.Equal(t, sql.NullString{String: "k1", Valid: true}, keyID)
+
+	found, err := repo.GetByID(acme, report.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, "Call ACME about invoice 42", found.Name)
+
+	report.Name = "Call ACME about invoice 43"
+	_, err = repo.Update(acme, report)
+	assert.NoError(t, err)
+	tasks, err := repo.GetAll(acme)
+	assert.NoError(t, err)
+	assert.Len(t, tasks, 1)
+	assert.Equal(t, "Call ACME about invoice 43", tasks[0].Name)
+
+	query, _ := search.Parse("ACME")
+	_, err = repo.Search(acme, query)
+	assert.ErrorIs(t, err, task.ErrEncrypted)
+
+	// Names stored before encryption was turned on are read as they are.
+	_, err = db.Exec("INSERT INTO tasks (tenant_id, name, status) VALUES ('acme', 'Plain name', 0)")
+	assert.NoError(t, err)
+	tasks, err = repo.GetAll(acme)
+	assert.NoError(t, err)
+	assert.Len(t, tasks, 2)
+	assert.Equal(t, "Plain name", tasks[1].Name)
+
+	// Sealed names cannot be read without the keyring.
+	_, err = (&TaskRepository{DB: db}).GetByID(acme, report.ID)
+	assert.Error(t, err)
+
+	// Nor once copied to another task of the tenant.
+	other, err := repo.Create(acme, task.Info{Name: "Lunch"})
+	assert.NoError(t, err)
+	_, err = db.Exec("UPDATE tasks o JOIN tasks r ON r.id = ? SET o.name = r.name, o.name_key_id = r.name_key_id WHERE o.id = ?",
+		report.ID, other.ID)
+	assert.NoError(t, err)
+	_, err = repo.GetByID(acme, other.ID)
+	assert.ErrorIs(t, err, encryption.ErrDecrypt)
+
+	// Nor once moved to another tenant.
+	_, err = db.Exec("UPDATE tasks SET tenant_id = 'globex' WHERE id = ?", report.ID)
+	assert.NoError(t, err)
+	_, err = repo.GetByID(globex, report.ID)
+	assert.ErrorIs(t, err, encryption.ErrDecrypt)
+}
+
+func TestCheckHealth(t *testing.T) {
+	ctx := context.Background()
+	assert.NoError(t, (&TaskRepository{DB: db}).CheckHealth(ctx))
+
+	down, err := NewDB(config.MySQLConfig{DSN: "root:root@tcp(127.0.0.1:1)/testDB"})
+	assert.NoError(t, err)
+	defer down.Close()
+	assert.Error(t, (&TaskRepository{DB: down}).CheckHealth(ctx))
+}
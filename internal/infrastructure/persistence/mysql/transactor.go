@@ -0,0 +1,53 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+
+	"task-api/internal/domain/task"
+)
+
+// querier is what *sql.DB and *sql.Tx have in common.
+type querier interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+type txKey struct{}
+
+// Transactor carries its transaction in the context, where the task and
+// audit repositories find it through conn.
+type Transactor struct {
+	DB *sql.DB
+}
+
+func NewMySQLTransactor(db *sql.DB) task.Transactor {
+	return &Transactor{DB: db}
+}
+
+// WithinTx commits once fn returns without an error and rolls back
+// otherwise. Within a transaction, fn runs in it and the outermost call
+// commits.
+func (t *Transactor) WithinTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	if _, ok := ctx.Value(txKey{}).(*sql.Tx); ok {
+		return fn(ctx)
+	}
+	tx, err := t.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	if err := fn(context.WithValue(ctx, txKey{}, tx)); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// conn returns the transaction carried by ctx, or db outside of one.
+func conn(ctx context.Context, db *sql.DB) querier {
+	if tx, ok := ctx.Value(txKey{}).(*sql.Tx); ok {
+		return tx
+	}
+	return db
+}
@@ -0,0 +1,62 @@
+package mysql
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"task-api/internal/domain/audit"
+	"task-api/internal/domain/task"
+)
+
+func TestTransactor(t *testing.T) {
+	tasks := &TaskRepository{DB: db}
+	records := &AuditRepository{DB: db}
+	tx := &Transactor{DB: db}
+	ctx := context.Background()
+
+	err := clearTestDB(db)
+	assert.NoError(t, err)
+	err = truncateTables(db, "audit_log")
+	assert.NoError(t, err)
+
+	write := func(ctx context.Context, name string) error {
+		created, err := tasks.Create(ctx, task.Info{Name: name})
+		if err != nil {
+			return err
+		}
+		_, err = records.Append(ctx, audit.Record{TaskID: created.ID, Actor: "alice", Time: time.Now().UTC(), Operation: audit.OperationCreate})
+		return err
+	}
+
+	failed := errors.New("failed")
+	err = tx.WithinTx(ctx, func(ctx context.Context) error {
+		if err := write(ctx, "Rolled back"); err != nil {
+			return err
+		}
+		return failed
+	})
+	assert.Equal(t, failed, err)
+
+	err = tx.WithinTx(ctx, func(ctx context.Context) error {
+		// A nested call joins the transaction rather than committing it.
+		return tx.WithinTx(ctx, func(ctx context.Context) error {
+			return write(ctx, "Committed")
+		})
+	})
+	assert.NoError(t, err)
+
+	all, err := tasks.GetAll(ctx)
+	assert.NoError(t, err)
+	if assert.Len(t, all, 1) {
+		assert.Equal(t, "Committed", all[0].Name)
+	}
+	found, err := records.Find(audit.Filter{TenantID: "default"})
+	assert.NoError(t, err)
+	if assert.Len(t, found, 1) {
+		assert.Equal(t, all[0].ID, found[0].TaskID)
+	}
+}
@@ -0,0 +1,76 @@
+package mysql
+
+import (
+	"database/sql"
+
+	"task-api/internal/domain/user"
+)
+
+const userColumns = "id, username, name, email"
+
+type UserRepository struct {
+	DB *sql.DB
+}
+
+func NewMySQLUserRepository(db *sql.DB) user.Repository {
+	return &UserRepository{DB: db}
+}
+
+func (r *UserRepository) GetAll() ([]user.User, error) {
+	rows, err := r.DB.Query("SELECT " + userColumns + " FROM users ORDER BY username")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var users []user.User
+	for rows.Next() {
+		u, err := scanUser(rows)
+		if err != nil {
+			return nil, err
+		}
+		users = append(users, u)
+	}
+	return users, rows.Err()
+}
+
+func (r *UserRepository) GetByID(id int) (user.User, error) {
+	return r.getOne("SELECT "+userColumns+" FROM users WHERE id = ?", id)
+}
+
+func (r *UserRepository) GetByUsername(username string) (user.User, error) {
+	return r.getOne("SELECT "+userColumns+" FROM users WHERE username = ?", username)
+}
+
+func (r *UserRepository) Create(u user.User) (user.User, error) {
+	result, err := r.DB.Exec("INSERT INTO users (username, name, email) VALUES (?, ?, ?)", u.Username, u.Name, u.Email)
+	if err != nil {
+		if isDuplicateError(err) {
+			return user.User{}, user.ErrDuplicateUsername
+		}
+		return user.User{}, err
+	}
+	id, err := result.LastInsertId()
+	if err != nil {
+		return user.User{}, err
+	}
+	u.ID = int(id)
+	return u, nil
+}
+
+func (r *UserRepository) getOne(query string, args ...interface{}) (user.User, error) {
+	u, err := scanUser(r.DB.QueryRow(query, args...))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return user.User{}, user.ErrNotFound
+		}
+		return user.User{}, err
+	}
+	return u, nil
+}
+
+func scanUser(row scanner) (user.User, error) {
+	var u user.User
+	err := row.Scan(&u.ID, &u.Username, &u.Name, &u.Email)
+	return u, err
+}
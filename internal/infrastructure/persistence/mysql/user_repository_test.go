@@ -0,0 +1,34 @@
+package mysql
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"task-api/internal/domain/user"
+)
+
+func TestUsers(t *testing.T) {
+	repo := &UserRepository{DB: db}
+
+	err := clearTestDB(db)
+	assert.NoError(t, err)
+
+	bob, err := repo.Create(user.User{Username: "bob", Name: "Bob"})
+	assert.NoError(t, err)
+	alice, err := repo.Create(user.User{Username: "alice", Email: "alice@example.com"})
+	assert.NoError(t, err)
+
+	users, err := repo.GetAll()
+	assert.NoError(t, err)
+	assert.Equal(t, []user.User{alice, bob}, users)
+
+	found, err := repo.GetByUsername("bob")
+	assert.NoError(t, err)
+	assert.Equal(t, bob, found)
+
+	_, err = repo.GetByID(99)
+	assert.Equal(t, user.ErrNotFound, err)
+	_, err = repo.Create(user.User{Username: "alice"})
+	assert.Equal(t, user.ErrDuplicateUsername, err)
+}
@@ -0,0 +1,273 @@
+package mysql
+
+import (
+	"database/sql"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"task-api/internal/domain/webhook"
+	"task-api/internal/encryption"
+)
+
+const subscriptionColumns = "id, tenant_id, url, event_types, secret, active, created_at"
+
+// selectDeliveries reads deliveries with the tenant of their subscription,
+// which their sealed payloads are bound to.
+const selectDeliveries = `SELECT d.id, d.subscription_id, d.event_id, d.event_type, d.payload, d.payload_key_id, d.status, d.attempts,
+	d.response_code, d.last_error, d.next_attempt_at, d.created_at, s.tenant_id
+	FROM webhook_deliveries d JOIN webhook_subscriptions s ON s.id = d.subscription_id`
+
+type WebhookRepository struct {
+	DB *sql.DB
+	// Keyring, when set, seals the payloads of deliveries, which carry the
+	// task, as a base64 JSON string with the ID of the key in
+	// payload_key_id. Payloads are read back whether they are sealed or
+	// not, as the Reencryptor seals the older ones.
+	Keyring *encryption.Keyring
+}
+
+// NewMySQLWebhookRepository stores payloads in plain text when keyring is
+// nil.
+func NewMySQLWebhookRepository(db *sql.DB, keyring *encryption.Keyring) webhook.Repository {
+	return &WebhookRepository{DB: db, Keyring: keyring}
+}
+
+func (r *WebhookRepository) GetAll() ([]webhook.Subscription, error) {
+	rows, err := r.DB.Query("SELECT " + subscriptionColumns + " FROM webhook_subscriptions ORDER BY id")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var subscriptions []webhook.Subscription
+	for rows.Next() {
+		s, err := scanSubscription(rows)
+		if err != nil {
+			return nil, err
+		}
+		subscriptions = append(subscriptions, s)
+	}
+	return subscriptions, rows.Err()
+}
+
+func (r *WebhookRepository) GetByID(id int) (webhook.Subscription, error) {
+	row := r.DB.QueryRow("SELECT "+subscriptionColumns+" FROM webhook_subscriptions WHERE id = ?", id)
+	s, err := scanSubscription(row)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return webhook.Subscription{}, webhook.ErrNotFound
+		}
+		return webhook.Subscription{}, err
+	}
+	return s, nil
+}
+
+func (r *WebhookRepository) Create(s webhook.Subscription) (webhook.Subscription, error) {
+	result, err := r.DB.Exec("INSERT INTO webhook_subscriptions (tenant_id, url, event_types, secret, active, created_at) VALUES (?, ?, ?, ?, ?, ?)",
+		s.TenantID, s.URL, strings.Join(s.EventTypes, ","), s.Secret, s.Active, s.CreatedAt)
+	if err != nil {
+		return webhook.Subscription{}, err
+	}
+	id, err := result.LastInsertId()
+	if err != nil {
+		return webhook.Subscription{}, err
+	}
+	s.ID = int(id)
+	return s, nil
+}
+
+func (r *WebhookRepository) Update(s webhook.Subscription) (webhook.Subscription, error) {
+	result, err := r.DB.Exec("UPDATE webhook_subscriptions SET url = ?, event_types = ?, secret = ?, active = ? WHERE id = ?",
+		s.URL, strings.Join(s.EventTypes, ","), s.Secret, s.Active, s.ID)
+	if err != nil {
+		return webhook.Subscription{}, err
+	}
+	if err := requireRow(result, webhook.ErrNotFound); err != nil {
+		return webhook.Subscription{}, err
+	}
+	return s, nil
+}
+
+func (r *WebhookRepository) Delete(id int) error {
+	result, err := r.DB.Exec("DELETE FROM webhook_subscriptions WHERE id = ?", id)
+	if err != nil {
+		return err
+	}
+	return requireRow(result, webhook.ErrNotFound)
+}
+
+// CreateDelivery writes a sealed payload once the insert has given the
+// delivery its ID, which the payload is bound to, in the same transaction.
+func (r *WebhookRepository) CreateDelivery(d webhook.Delivery) (webhook.Delivery, error) {
+	tx, err := r.DB.Begin()
+	if err != nil {
+		return webhook.Delivery{}, err
+	}
+	defer tx.Rollback()
+
+	payload := string(d.Payload)
+	if r.Keyring != nil {
+		payload = "null"
+	}
+	result, err := tx.Exec(`INSERT INTO webhook_deliveries
+		(subscription_id, event_id, event_type, payload, status, attempts, response_code, last_error, next_attempt_at, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		d.SubscriptionID, d.EventID, d.EventType, payload, d.Status, d.Attempts, d.ResponseCode, d.LastError, d.NextAttemptAt, d.CreatedAt)
+	if err != nil {
+		return webhook.Delivery{}, err
+	}
+	id, err := result.LastInsertId()
+	if err != nil {
+		return webhook.Delivery{}, err
+	}
+	d.ID = int(id)
+	if r.Keyring != nil {
+		var tenantID string
+		if err := tx.QueryRow("SELECT tenant_id FROM webhook_subscriptions WHERE id = ?", d.SubscriptionID).Scan(&tenantID); err != nil {
+			return webhook.Delivery{}, err
+		}
+		payload, keyID, err := r.sealPayload(tenantID, d.ID, d.Payload)
+		if err != nil {
+			return webhook.Delivery{}, err
+		}
+		if _, err := tx.Exec("UPDATE webhook_deliveries SET payload = ?, payload_key_id = ? WHERE id = ?", payload, keyID, d.ID); err != nil {
+			return webhook.Delivery{}, err
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return webhook.Delivery{}, err
+	}
+	return d, nil
+}
+
+func (r *WebhookRepository) UpdateDelivery(d webhook.Delivery) (webhook.Delivery, error) {
+	_, err := r.DB.Exec("UPDATE webhook_deliveries SET status = ?, attempts = ?, response_code = ?, last_error = ?, next_attempt_at = ? WHERE id = ?",
+		d.Status, d.Attempts, d.ResponseCode, d.LastError, d.NextAttemptAt, d.ID)
+	if err != nil {
+		return webhook.Delivery{}, err
+	}
+	return d, nil
+}
+
+func (r *WebhookRepository) GetDelivery(id int) (webhook.Delivery, error) {
+	row := r.DB.QueryRow(selectDeliveries+" WHERE d.id = ?", id)
+	d, err := r.scanDelivery(row)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return webhook.Delivery{}, webhook.ErrDeliveryNotFound
+		}
+		return webhook.Delivery{}, err
+	}
+	return d, nil
+}
+
+func (r *WebhookRepository) ListDeliveries(subscriptionID int) ([]webhook.Delivery, error) {
+	return r.queryDeliveries(selectDeliveries+" WHERE d.subscription_id = ? ORDER BY d.id", subscriptionID)
+}
+
+func (r *WebhookRepository) ListDueDeliveries(before time.Time) ([]webhook.Delivery, error) {
+	return r.queryDeliveries(selectDeliveries+" WHERE d.status = ? AND d.next_attempt_at <= ? ORDER BY d.id",
+		webhook.DeliveryPending, before)
+}
+
+func (r *WebhookRepository) queryDeliveries(query string, args ...interface{}) ([]webhook.Delivery, error) {
+	rows, err := r.DB.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var deliveries []webhook.Delivery
+	for rows.Next() {
+		d, err := r.scanDelivery(rows)
+		if err != nil {
+			return nil, err
+		}
+		deliveries = append(deliveries, d)
+	}
+	return deliveries, rows.Err()
+}
+
+type scanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanSubscription(row scanner) (webhook.Subscription, error) {
+	var s webhook.Subscription
+	var eventTypes string
+	if err := row.Scan(&s.ID, &s.TenantID, &s.URL, &eventTypes, &s.Secret, &s.Active, &s.CreatedAt); err != nil {
+		return webhook.Subscription{}, err
+	}
+	if eventTypes != "" {
+		s.EventTypes = strings.Split(eventTypes, ",")
+	}
+	return s, nil
+}
+
+func (r *WebhookRepository) scanDelivery(row scanner) (webhook.Delivery, error) {
+	var d webhook.Delivery
+	var payload []byte
+	var keyID sql.NullString
+	var tenantID string
+	if err := row.Scan(&d.ID, &d.SubscriptionID, &d.EventID, &d.EventType, &payload, &keyID, &d.Status, &d.Attempts,
+		&d.ResponseCode, &d.LastError, &d.NextAttemptAt, &d.CreatedAt, &tenantID); err != nil {
+		return webhook.Delivery{}, err
+	}
+	d.Payload = payload
+	if keyID.Valid {
+		opened, err := r.openPayload(tenantID, d.ID, payload, keyID.String)
+		if err != nil {
+			return webhook.Delivery{}, fmt.Errorf("delivery %d: %w", d.ID, err)
+		}
+		d.Payload = opened
+	}
+	return d, nil
+}
+
+// sealPayload returns the payload to store, a JSON string, and the ID of
+// the key it is sealed under. Sealed payloads are bound to their tenant and
+// delivery, so that they cannot be moved to another one.
+func (r *WebhookRepository) sealPayload(tenantID string, id int, payload []byte) (string, string, error) {
+	keyID, sealed, err := r.Keyring.Encrypt(payload, payloadAdditionalData(tenantID, id))
+	if err != nil {
+		return "", "", err
+	}
+	stored, err := json.Marshal(base64.StdEncoding.EncodeToString(sealed))
+	if err != nil {
+		return "", "", err
+	}
+	return string(stored), keyID, nil
+}
+
+func (r *WebhookRepository) openPayload(tenantID string, id int, stored []byte, keyID string) ([]byte, error) {
+	if r.Keyring == nil {
+		return nil, fmt.Errorf("payload is sealed under key %q, but no keyring is configured", keyID)
+	}
+	var encoded string
+	if err := json.Unmarshal(stored, &encoded); err != nil {
+		return nil, fmt.Errorf("%w: %v", encryption.ErrDecrypt, err)
+	}
+	sealed, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", encryption.ErrDecrypt, err)
+	}
+	return r.Keyring.Decrypt(keyID, sealed, payloadAdditionalData(tenantID, id))
+}
+
+func payloadAdditionalData(tenantID string, id int) []byte {
+	return []byte(fmt.Sprintf("webhook_deliveries.payload:%s:%d", tenantID, id))
+}
+
+func requireRow(result sql.Result, notFound error) error {
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return notFound
+	}
+	return nil
+}
@@ -0,0 +1,166 @@
+package mysql
+
+import (
+	"database/sql"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"task-api/internal/domain/webhook"
+	"task-api/internal/encryption"
+)
+
+func clearWebhookTables() error {
+	return truncateTables(db, "webhook_deliveries", "webhook_subscriptions")
+}
+
+func TestWebhookSubscriptions(t *testing.T) {
+	repo := &WebhookRepository{DB: db}
+	createdAt := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	err := clearWebhookTables()
+	assert.NoError(t, err)
+
+	created, err := repo.Create(webhook.Subscription{
+		URL:        "https://example.com/hook",
+		EventTypes: []string{"task.created", "task.deleted"},
+		Secret:     "secret",
+		Active:     true,
+		CreatedAt:  createdAt,
+		TenantID:   "acme",
+	})
+	assert.NoError(t, err)
+
+	tests := []struct {
+		TestCase string
+		Run      func() (interface{}, error)
+		Expected interface{}
+		Error    error
+	}{
+		{
+			TestCase: "Get subscription by ID",
+			Run:      func() (interface{}, error) { return repo.GetByID(created.ID) },
+			Expected: created,
+		},
+		{
+			TestCase: "Update subscription",
+			Run: func() (interface{}, error) {
+				created.EventTypes = nil
+				created.Active = false
+				return repo.Update(created)
+			},
+			Expected: webhook.Subscription{ID: created.ID, URL: "https://example.com/hook", Secret: "secret", CreatedAt: createdAt, TenantID: "acme"},
+		},
+		{
+			TestCase: "Get all subscriptions",
+			Run:      func() (interface{}, error) { return repo.GetAll() },
+			Expected: []webhook.Subscription{{ID: created.ID, URL: "https://example.com/hook", Secret: "secret", CreatedAt: createdAt, TenantID: "acme"}},
+		},
+		{
+			TestCase: "Delete missing subscription",
+			Run:      func() (interface{}, error) { return nil, repo.Delete(created.ID + 1) },
+			Error:    webhook.ErrNotFound,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.TestCase, func(t *testing.T) {
+			result, err := tc.Run()
+			assert.Equal(t, tc.Error, err)
+			assert.Equal(t, tc.Expected, result)
+		})
+	}
+}
+
+func TestWebhookDeliveries(t *testing.T) {
+	repo := &WebhookRepository{DB: db}
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	err := clearWebhookTables()
+	assert.NoError(t, err)
+
+	sub, err := repo.Create(webhook.Subscription{URL: "https://example.com/hook", Secret: "secret", Active: true, CreatedAt: now})
+	assert.NoError(t, err)
+
+	delivery, err := repo.CreateDelivery(webhook.Delivery{
+		SubscriptionID: sub.ID,
+		EventID:        7,
+		EventType:      "task.created",
+		Payload:        json.RawMessage(`{"id": 7}`),
+		Status:         webhook.DeliveryPending,
+		NextAttemptAt:  now,
+		CreatedAt:      now,
+	})
+	assert.NoError(t, err)
+
+	due, err := repo.ListDueDeliveries(now)
+	assert.NoError(t, err)
+	assert.Len(t, due, 1)
+	assert.Equal(t, delivery.ID, due[0].ID)
+	assert.JSONEq(t, `{"id": 7}`, string(due[0].Payload))
+
+	delivery.Status = webhook.DeliveryFailed
+	delivery.Attempts = 3
+	delivery.ResponseCode = 500
+	delivery.LastError = "unexpected status 500"
+	_, err = repo.UpdateDelivery(delivery)
+	assert.NoError(t, err)
+
+	stored, err := repo.GetDelivery(delivery.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, webhook.DeliveryFailed, stored.Status)
+	assert.Equal(t, 3, stored.Attempts)
+	assert.Equal(t, "unexpected status 500", stored.LastError)
+
+	due, err = repo.ListDueDeliveries(now)
+	assert.NoError(t, err)
+	assert.Empty(t, due)
+
+	assert.NoError(t, repo.Delete(sub.ID))
+	deliveries, err := repo.ListDeliveries(sub.ID)
+	assert.NoError(t, err)
+	assert.Empty(t, deliveries)
+}
+
+func TestWebhookDeliveryPayloadsAreSealed(t *testing.T) {
+	repo := &WebhookRepository{DB: db, Keyring: testKeyring(t, "k1")}
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	err := clearWebhookTables()
+	assert.NoError(t, err)
+
+	sub, err := repo.Create(webhook.Subscription{TenantID: "acme", URL: "https://example.com/hook", Secret: "secret", Active: true, CreatedAt: now})
+	assert.NoError(t, err)
+	payload := json.RawMessage(`{"type":"task.created","task":{"id":7,"name":"Call ACME"}}`)
+	first, err := repo.CreateDelivery(webhook.Delivery{SubscriptionID: sub.ID, EventID: 7, EventType: "task.created",
+		Payload: payload, Status: webhook.DeliveryPending, NextAttemptAt: now, CreatedAt: now})
+	assert.NoError(t, err)
+	second, err := repo.CreateDelivery(webhook.Delivery{SubscriptionID: sub.ID, EventID: 8, EventType: "task.created",
+		Payload: json.RawMessage(`{"id":8}`), Status: webhook.DeliveryPending, NextAttemptAt: now, CreatedAt: now})
+	assert.NoError(t, err)
+
+	var stored string
+	var keyID sql.NullString
+	assert.NoError(t, db.QueryRow("SELECT payload, payload_key_id FROM webhook_deliveries WHERE id = ?", first.ID).Scan(&stored, &keyID))
+	assert.NotContains(t, stored, "ACME")
+	assert.Equal(t, sql.NullString{String: "k1", Valid: true}, keyID)
+
+	// Sealed payloads read back as they were, byte for byte, as they are
+	// signed when sent.
+	found, err := repo.GetDelivery(first.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, string(payload), string(found.Payload))
+
+	// They cannot be read once copied to another delivery.
+	_, err = db.Exec(`UPDATE webhook_deliveries o JOIN webhook_deliveries f ON f.id = ?
+		SET o.payload = f.payload, o.payload_key_id = f.payload_key_id WHERE o.id = ?`, first.ID, second.ID)
+	assert.NoError(t, err)
+	_, err = repo.GetDelivery(second.ID)
+	assert.ErrorIs(t, err, encryption.ErrDecrypt)
+
+	// Sealed payloads cannot be read without the keyring.
+	_, err = (&WebhookRepository{DB: db}).GetDelivery(first.ID)
+	assert.Error(t, err)
+}
@@ -0,0 +1,17 @@
+// Package redis keeps state shared by every API instance in Redis.
+package redis
+
+import (
+	goredis "github.com/redis/go-redis/v9"
+
+	"task-api/internal/config"
+)
+
+// NewClient connects lazily, on the first command.
+func NewClient(cfg config.RedisConfig) *goredis.Client {
+	return goredis.NewClient(&goredis.Options{
+		Addr:     cfg.Addr,
+		Password: cfg.Password,
+		DB:       cfg.DB,
+	})
+}
@@ -0,0 +1,67 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	goredis "github.com/redis/go-redis/v9"
+
+	"task-api/internal/idempotency"
+)
+
+// claimScript returns the record at KEYS[1], or stores the pending record
+// in ARGV[1] there for ARGV[2] milliseconds when there is none, in one step
+// so that only one of the instances racing for a key gets it.
+var claimScript = goredis.NewScript(`
+local record = redis.call("GET", KEYS[1])
+if record then
+	return record
+end
+redis.call("SET", KEYS[1], ARGV[1], "PX", ARGV[2])
+return false
+`)
+
+type idempotencyStore struct {
+	client goredis.Cmdable
+}
+
+// NewIdempotencyStore keeps the records in Redis, so that a retry is
+// recognized whichever instance it reaches.
+func NewIdempotencyStore(client goredis.Cmdable) idempotency.Store {
+	return &idempotencyStore{client: client}
+}
+
+func (s *idempotencyStore) Claim(ctx context.Context, key, fingerprint string, lockTimeout time.Duration) (idempotency.Record, bool, error) {
+	pending := idempotency.Record{Fingerprint: fingerprint}
+	data, err := json.Marshal(pending)
+	if err != nil {
+		return idempotency.Record{}, false, err
+	}
+	text, err := claimScript.Run(ctx, s.client, []string{key}, data, max(lockTimeout.Milliseconds(), 1)).Text()
+	if errors.Is(err, goredis.Nil) {
+		return pending, true, nil
+	}
+	if err != nil {
+		return idempotency.Record{}, false, err
+	}
+	var r idempotency.Record
+	if err := json.Unmarshal([]byte(text), &r); err != nil {
+		return idempotency.Record{}, false, fmt.Errorf("unexpected idempotency record at %s: %w", key, err)
+	}
+	return r, false, nil
+}
+
+func (s *idempotencyStore) Complete(ctx context.Context, key string, r idempotency.Record, ttl time.Duration) error {
+	data, err := json.Marshal(r)
+	if err != nil {
+		return err
+	}
+	return s.client.Set(ctx, key, data, ttl).Err()
+}
+
+func (s *idempotencyStore) Release(ctx context.Context, key string) error {
+	return s.client.Del(ctx, key).Err()
+}
@@ -0,0 +1,70 @@
+package redis
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"task-api/internal/idempotency"
+)
+
+func TestIdempotencyStore(t *testing.T) {
+	ctx := context.Background()
+	store := NewIdempotencyStore(client)
+	key := "idempotency:default:sub:alice:create-1"
+	done := idempotency.Record{Fingerprint: "fp", Done: true, Status: 201, ContentType: "application/json", Body: []byte(`{"id":1}`)}
+
+	assert.NoError(t, client.FlushDB(ctx).Err())
+
+	r, claimed, err := store.Claim(ctx, key, "fp", time.Minute)
+	assert.NoError(t, err)
+	assert.True(t, claimed)
+	assert.Equal(t, idempotency.Record{Fingerprint: "fp"}, r)
+
+	// A claimed key is taken, by requests with any fingerprint.
+	r, claimed, err = store.Claim(ctx, key, "other", time.Minute)
+	assert.NoError(t, err)
+	assert.False(t, claimed)
+	assert.Equal(t, idempotency.Record{Fingerprint: "fp"}, r)
+
+	ttl, err := client.PTTL(ctx, key).Result()
+	assert.NoError(t, err)
+	assert.InDelta(t, time.Minute, ttl, float64(time.Second))
+
+	// A completed key holds the response for the TTL.
+	assert.NoError(t, store.Complete(ctx, key, done, time.Hour))
+	r, claimed, err = store.Claim(ctx, key, "fp", time.Minute)
+	assert.NoError(t, err)
+	assert.False(t, claimed)
+	assert.Equal(t, done, r)
+
+	ttl, err = client.PTTL(ctx, key).Result()
+	assert.NoError(t, err)
+	assert.InDelta(t, time.Hour, ttl, float64(time.Second))
+
+	// A released key is free.
+	assert.NoError(t, store.Release(ctx, key))
+	_, claimed, err = store.Claim(ctx, key, "fp", time.Minute)
+	assert.NoError(t, err)
+	assert.True(t, claimed)
+}
+
+func TestIdempotencyStoreClaimsExpire(t *testing.T) {
+	ctx := context.Background()
+	store := NewIdempotencyStore(client)
+	key := "idempotency:default:ip:10.0.0.1:create-1"
+
+	assert.NoError(t, client.FlushDB(ctx).Err())
+
+	_, claimed, err := store.Claim(ctx, key, "fp", 100*time.Millisecond)
+	assert.NoError(t, err)
+	assert.True(t, claimed)
+
+	time.Sleep(150 * time.Millisecond)
+
+	_, claimed, err = store.Claim(ctx, key, "fp", 100*time.Millisecond)
+	assert.NoError(t, err)
+	assert.True(t, claimed)
+}
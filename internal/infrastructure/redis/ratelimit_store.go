@@ -0,0 +1,78 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	goredis "github.com/redis/go-redis/v9"
+
+	"task-api/internal/ratelimit"
+)
+
+// takeScript refills and takes from the bucket at KEYS[1] in one step, so
+// that instances racing for the last token cannot both get it. Times come
+// from the Redis server, as the clocks of the instances may disagree. The
+// bucket expires once it would be full again.
+var takeScript = goredis.NewScript(`
+local burst = tonumber(ARGV[1])
+local rate = tonumber(ARGV[2])
+local time = redis.call("TIME")
+local now = tonumber(time[1]) * 1000 + math.floor(tonumber(time[2]) / 1000)
+
+local bucket = redis.call("HMGET", KEYS[1], "tokens", "ts")
+local tokens = tonumber(bucket[1])
+local ts = tonumber(bucket[2])
+if tokens == nil or ts == nil then
+	tokens = burst
+elseif now > ts then
+	tokens = math.min(burst, tokens + (now - ts) / 1000 * rate)
+end
+
+local allowed = 0
+if tokens >= 1 then
+	tokens = tokens - 1
+	allowed = 1
+end
+
+redis.call("HSET", KEYS[1], "tokens", tostring(tokens), "ts", now)
+redis.call("PEXPIRE", KEYS[1], math.max(1, math.ceil((burst - tokens) / rate * 1000)))
+return {allowed, tostring(tokens)}
+`)
+
+type rateLimitStore struct {
+	client goredis.Cmdable
+}
+
+// NewRateLimitStore keeps the buckets in Redis, so that a client has the
+// same limit whichever instance it calls.
+func NewRateLimitStore(client goredis.Cmdable) ratelimit.Store {
+	return &rateLimitStore{client: client}
+}
+
+func (s *rateLimitStore) Take(ctx context.Context, key string, limit ratelimit.Limit) (ratelimit.Decision, error) {
+	result, err := takeScript.Run(ctx, s.client, []string{key}, limit.Burst, limit.Rate()).Slice()
+	if err != nil {
+		return ratelimit.Decision{}, err
+	}
+	if len(result) != 2 {
+		return ratelimit.Decision{}, fmt.Errorf("unexpected rate limit script result %v", result)
+	}
+	allowed, _ := result[0].(int64)
+	text, _ := result[1].(string)
+	tokens, err := strconv.ParseFloat(text, 64)
+	if err != nil {
+		return ratelimit.Decision{}, fmt.Errorf("unexpected token count %q", text)
+	}
+	return limit.Decide(tokens, allowed == 1), nil
+}
+
+func (s *rateLimitStore) Component() string {
+	return "redis"
+}
+
+// CheckHealth pings the Redis server, which idempotency keys are kept on
+// too.
+func (s *rateLimitStore) CheckHealth(ctx context.Context) error {
+	return s.client.Ping(ctx).Err()
+}
@@ -0,0 +1,129 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	goredis "github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/testcontainers/testcontainers-go"
+	"github.com/testcontainers/testcontainers-go/wait"
+
+	"task-api/internal/config"
+	"task-api/internal/ratelimit"
+)
+
+var client *goredis.Client
+
+func TestMain(m *testing.M) {
+	ctx := context.Background()
+
+	req := testcontainers.ContainerRequest{
+		Image:        "redis:6.0",
+		ExposedPorts: []string{"6379/tcp"},
+		WaitingFor:   wait.ForLog("Ready to accept connections").WithStartupTimeout(60 * time.Second),
+	}
+
+	redisC, err := testcontainers.GenericContainer(ctx, testcontainers.GenericContainerRequest{
+		ContainerRequest: req,
+		Started:          true,
+	})
+
+	if err != nil {
+		fmt.Printf("Failed to start redis container: %v", err)
+		os.Exit(1)
+	}
+
+	host, err := redisC.Host(ctx)
+	if err != nil {
+		fmt.Printf("Failed to get redis container host: %v", err)
+		os.Exit(1)
+	}
+
+	port, err := redisC.MappedPort(ctx, "6379/tcp")
+	if err != nil {
+		fmt.Printf("Failed to get redis container port: %v", err)
+		os.Exit(1)
+	}
+
+	client = NewClient(config.RedisConfig{Addr: fmt.Sprintf("%s:%s", host, port.Port())})
+
+	code := m.Run()
+
+	client.Close()
+	err = redisC.Terminate(ctx)
+	if err != nil {
+		fmt.Printf("Failed to terminate redis: %v", err)
+	}
+
+	os.Exit(code)
+}
+
+func TestRateLimitStore(t *testing.T) {
+	ctx := context.Background()
+	store := NewRateLimitStore(client)
+	limit := ratelimit.Limit{Burst: 2, Period: time.Hour}
+
+	assert.NoError(t, client.FlushDB(ctx).Err())
+
+	d, err := store.Take(ctx, "ratelimit:read:ip:10.0.0.1", limit)
+	assert.NoError(t, err)
+	assert.True(t, d.Allowed)
+	assert.Equal(t, 1, d.Remaining)
+	assert.InDelta(t, 30*time.Minute, d.Reset, float64(time.Second))
+
+	d, err = store.Take(ctx, "ratelimit:read:ip:10.0.0.1", limit)
+	assert.NoError(t, err)
+	assert.True(t, d.Allowed)
+	assert.Equal(t, 0, d.Remaining)
+
+	d, err = store.Take(ctx, "ratelimit:read:ip:10.0.0.1", limit)
+	assert.NoError(t, err)
+	assert.False(t, d.Allowed)
+	assert.Equal(t, 0, d.Remaining)
+	assert.InDelta(t, 30*time.Minute, d.RetryAfter, float64(time.Second))
+
+	// Other keys have buckets of their own.
+	d, err = store.Take(ctx, "ratelimit:read:ip:10.0.0.2", limit)
+	assert.NoError(t, err)
+	assert.True(t, d.Allowed)
+
+	// Buckets expire once they would be full again.
+	ttl, err := client.PTTL(ctx, "ratelimit:read:ip:10.0.0.1").Result()
+	assert.NoError(t, err)
+	assert.InDelta(t, time.Hour, ttl, float64(time.Second))
+}
+
+func TestRateLimitStoreRefills(t *testing.T) {
+	ctx := context.Background()
+	store := NewRateLimitStore(client)
+	limit := ratelimit.Limit{Burst: 1, Period: 200 * time.Millisecond}
+
+	assert.NoError(t, client.FlushDB(ctx).Err())
+
+	d, err := store.Take(ctx, "ratelimit:write:sub:alice", limit)
+	assert.NoError(t, err)
+	assert.True(t, d.Allowed)
+
+	d, err = store.Take(ctx, "ratelimit:write:sub:alice", limit)
+	assert.NoError(t, err)
+	assert.False(t, d.Allowed)
+
+	time.Sleep(250 * time.Millisecond)
+
+	d, err = store.Take(ctx, "ratelimit:write:sub:alice", limit)
+	assert.NoError(t, err)
+	assert.True(t, d.Allowed)
+}
+
+func TestRateLimitStoreCheckHealth(t *testing.T) {
+	ctx := context.Background()
+	assert.NoError(t, NewRateLimitStore(client).(*rateLimitStore).CheckHealth(ctx))
+
+	down := NewClient(config.RedisConfig{Addr: "127.0.0.1:1"})
+	defer down.Close()
+	assert.Error(t, NewRateLimitStore(down).(*rateLimitStore).CheckHealth(ctx))
+}
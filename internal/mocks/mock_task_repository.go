@@ -5,8 +5,11 @@
 package mocks
 
 import (
+	context "context"
 	reflect "reflect"
 	task "task-api/internal/domain/task"
+	search "task-api/internal/search"
+	time "time"
 
 	gomock "github.com/golang/mock/gomock"
 )
@@ -34,76 +37,254 @@ func (m *MockRepository) EXPECT() *MockRepositoryMockRecorder {
 	return m.recorder
 }
 
+// AddDependency mocks base method.
+func (m *MockRepository) AddDependency(ctx context.Context, blockerID, blockedID int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddDependency", ctx, blockerID, blockedID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddDependency indicates an expected call of AddDependency.
+func (mr *MockRepositoryMockRecorder) AddDependency(ctx, blockerID, blockedID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddDependency", reflect.TypeOf((*MockRepository)(nil).AddDependency), ctx, blockerID, blockedID)
+}
+
+// CountByProject mocks base method.
+func (m *MockRepository) CountByProject(ctx context.Context) (map[int]task.Progress, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountByProject", ctx)
+	ret0, _ := ret[0].(map[int]task.Progress)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountByProject indicates an expected call of CountByProject.
+func (mr *MockRepositoryMockRecorder) CountByProject(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountByProject", reflect.TypeOf((*MockRepository)(nil).CountByProject), ctx)
+}
+
 // Create mocks base method.
-func (m *MockRepository) Create(taskInfo task.Info) (task.Info, error) {
+func (m *MockRepository) Create(ctx context.Context, taskInfo task.Info) (task.Info, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Create", taskInfo)
+	ret := m.ctrl.Call(m, "Create", ctx, taskInfo)
 	ret0, _ := ret[0].(task.Info)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // Create indicates an expected call of Create.
-func (mr *MockRepositoryMockRecorder) Create(taskInfo interface{}) *gomock.Call {
+func (mr *MockRepositoryMockRecorder) Create(ctx, taskInfo interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockRepository)(nil).Create), taskInfo)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockRepository)(nil).Create), ctx, taskInfo)
 }
 
 // Delete mocks base method.
-func (m *MockRepository) Delete(id int) error {
+func (m *MockRepository) Delete(ctx context.Context, id int) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Delete", id)
+	ret := m.ctrl.Call(m, "Delete", ctx, id)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // Delete indicates an expected call of Delete.
-func (mr *MockRepositoryMockRecorder) Delete(id interface{}) *gomock.Call {
+func (mr *MockRepositoryMockRecorder) Delete(ctx, id interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockRepository)(nil).Delete), id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockRepository)(nil).Delete), ctx, id)
 }
 
 // GetAll mocks base method.
-func (m *MockRepository) GetAll() ([]task.Info, error) {
+func (m *MockRepository) GetAll(ctx context.Context) ([]task.Info, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetAll")
+	ret := m.ctrl.Call(m, "GetAll", ctx)
 	ret0, _ := ret[0].([]task.Info)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetAll indicates an expected call of GetAll.
-func (mr *MockRepositoryMockRecorder) GetAll() *gomock.Call {
+func (mr *MockRepositoryMockRecorder) GetAll(ctx interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAll", reflect.TypeOf((*MockRepository)(nil).GetAll))
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAll", reflect.TypeOf((*MockRepository)(nil).GetAll), ctx)
+}
+
+// GetBlockers mocks base method.
+func (m *MockRepository) GetBlockers(ctx context.Context, id int) ([]task.Info, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBlockers", ctx, id)
+	ret0, _ := ret[0].([]task.Info)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBlockers indicates an expected call of GetBlockers.
+func (mr *MockRepositoryMockRecorder) GetBlockers(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBlockers", reflect.TypeOf((*MockRepository)(nil).GetBlockers), ctx, id)
+}
+
+// GetByAssignee mocks base method.
+func (m *MockRepository) GetByAssignee(ctx context.Context, userID int) ([]task.Info, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByAssignee", ctx, userID)
+	ret0, _ := ret[0].([]task.Info)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByAssignee indicates an expected call of GetByAssignee.
+func (mr *MockRepositoryMockRecorder) GetByAssignee(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByAssignee", reflect.TypeOf((*MockRepository)(nil).GetByAssignee), ctx, userID)
 }
 
 // GetByID mocks base method.
-func (m *MockRepository) GetByID(id int) (task.Info, error) {
+func (m *MockRepository) GetByID(ctx context.Context, id int) (task.Info, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetByID", id)
+	ret := m.ctrl.Call(m, "GetByID", ctx, id)
 	ret0, _ := ret[0].(task.Info)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetByID indicates an expected call of GetByID.
-func (mr *MockRepositoryMockRecorder) GetByID(id interface{}) *gomock.Call {
+func (mr *MockRepositoryMockRecorder) GetByID(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockRepository)(nil).GetByID), ctx, id)
+}
+
+// GetByProject mocks base method.
+func (m *MockRepository) GetByProject(ctx context.Context, projectID int) ([]task.Info, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByProject", ctx, projectID)
+	ret0, _ := ret[0].([]task.Info)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByProject indicates an expected call of GetByProject.
+func (mr *MockRepositoryMockRecorder) GetByProject(ctx, projectID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByProject", reflect.TypeOf((*MockRepository)(nil).GetByProject), ctx, projectID)
+}
+
+// GetChildren mocks base method.
+func (m *MockRepository) GetChildren(ctx context.Context, parentID int) ([]task.Info, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetChildren", ctx, parentID)
+	ret0, _ := ret[0].([]task.Info)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetChildren indicates an expected call of GetChildren.
+func (mr *MockRepositoryMockRecorder) GetChildren(ctx, parentID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetChildren", reflect.TypeOf((*MockRepository)(nil).GetChildren), ctx, parentID)
+}
+
+// GetDependencies mocks base method.
+func (m *MockRepository) GetDependencies(ctx context.Context) ([]task.Dependency, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDependencies", ctx)
+	ret0, _ := ret[0].([]task.Dependency)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDependencies indicates an expected call of GetDependencies.
+func (mr *MockRepositoryMockRecorder) GetDependencies(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDependencies", reflect.TypeOf((*MockRepository)(nil).GetDependencies), ctx)
+}
+
+// GetDescendants mocks base method.
+func (m *MockRepository) GetDescendants(ctx context.Context, id int) ([]task.Info, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDescendants", ctx, id)
+	ret0, _ := ret[0].([]task.Info)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDescendants indicates an expected call of GetDescendants.
+func (mr *MockRepositoryMockRecorder) GetDescendants(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDescendants", reflect.TypeOf((*MockRepository)(nil).GetDescendants), ctx, id)
+}
+
+// GetDueBefore mocks base method.
+func (m *MockRepository) GetDueBefore(ctx context.Context, before time.Time) ([]task.Info, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDueBefore", ctx, before)
+	ret0, _ := ret[0].([]task.Info)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDueBefore indicates an expected call of GetDueBefore.
+func (mr *MockRepositoryMockRecorder) GetDueBefore(ctx, before interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDueBefore", reflect.TypeOf((*MockRepository)(nil).GetDueBefore), ctx, before)
+}
+
+// RemoveDependency mocks base method.
+func (m *MockRepository) RemoveDependency(ctx context.Context, blockerID, blockedID int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveDependency", ctx, blockerID, blockedID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RemoveDependency indicates an expected call of RemoveDependency.
+func (mr *MockRepositoryMockRecorder) RemoveDependency(ctx, blockerID, blockedID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveDependency", reflect.TypeOf((*MockRepository)(nil).RemoveDependency), ctx, blockerID, blockedID)
+}
+
+// Search mocks base method.
+func (m *MockRepository) Search(ctx context.Context, query search.Query) ([]task.SearchResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Search", ctx, query)
+	ret0, _ := ret[0].([]task.SearchResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Search indicates an expected call of Search.
+func (mr *MockRepositoryMockRecorder) Search(ctx, query interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Search", reflect.TypeOf((*MockRepository)(nil).Search), ctx, query)
+}
+
+// Tenants mocks base method.
+func (m *MockRepository) Tenants(ctx context.Context) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Tenants", ctx)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Tenants indicates an expected call of Tenants.
+func (mr *MockRepositoryMockRecorder) Tenants(ctx interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockRepository)(nil).GetByID), id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Tenants", reflect.TypeOf((*MockRepository)(nil).Tenants), ctx)
 }
 
 // Update mocks base method.
-func (m *MockRepository) Update(taskInfo task.Info) (task.Info, error) {
+func (m *MockRepository) Update(ctx context.Context, taskInfo task.Info) (task.Info, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Update", taskInfo)
+	ret := m.ctrl.Call(m, "Update", ctx, taskInfo)
 	ret0, _ := ret[0].(task.Info)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // Update indicates an expected call of Update.
-func (mr *MockRepositoryMockRecorder) Update(taskInfo interface{}) *gomock.Call {
+func (mr *MockRepositoryMockRecorder) Update(ctx, taskInfo interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockRepository)(nil).Update), taskInfo)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockRepository)(nil).Update), ctx, taskInfo)
 }
@@ -5,8 +5,11 @@
 package mocks
 
 import (
+	context "context"
 	reflect "reflect"
+	label "task-api/internal/domain/label"
 	task "task-api/internal/domain/task"
+	time "time"
 
 	gomock "github.com/golang/mock/gomock"
 )
@@ -34,76 +37,269 @@ func (m *MockService) EXPECT() *MockServiceMockRecorder {
 	return m.recorder
 }
 
+// AddBlocker mocks base method.
+func (m *MockService) AddBlocker(ctx context.Context, id, blockerID int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddBlocker", ctx, id, blockerID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddBlocker indicates an expected call of AddBlocker.
+func (mr *MockServiceMockRecorder) AddBlocker(ctx, id, blockerID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddBlocker", reflect.TypeOf((*MockService)(nil).AddBlocker), ctx, id, blockerID)
+}
+
+// AssignTask mocks base method.
+func (m *MockService) AssignTask(ctx context.Context, id int, assigneeID *int) (task.Info, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AssignTask", ctx, id, assigneeID)
+	ret0, _ := ret[0].(task.Info)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AssignTask indicates an expected call of AssignTask.
+func (mr *MockServiceMockRecorder) AssignTask(ctx, id, assigneeID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AssignTask", reflect.TypeOf((*MockService)(nil).AssignTask), ctx, id, assigneeID)
+}
+
+// AttachLabel mocks base method.
+func (m *MockService) AttachLabel(ctx context.Context, id, labelID int) (task.Info, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AttachLabel", ctx, id, labelID)
+	ret0, _ := ret[0].(task.Info)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AttachLabel indicates an expected call of AttachLabel.
+func (mr *MockServiceMockRecorder) AttachLabel(ctx, id, labelID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AttachLabel", reflect.TypeOf((*MockService)(nil).AttachLabel), ctx, id, labelID)
+}
+
 // CreateTask mocks base method.
-func (m *MockService) CreateTask(t task.Info) (task.Info, error) {
+func (m *MockService) CreateTask(ctx context.Context, t task.Info) (task.Info, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreateTask", t)
+	ret := m.ctrl.Call(m, "CreateTask", ctx, t)
 	ret0, _ := ret[0].(task.Info)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // CreateTask indicates an expected call of CreateTask.
-func (mr *MockServiceMockRecorder) CreateTask(t interface{}) *gomock.Call {
+func (mr *MockServiceMockRecorder) CreateTask(ctx, t interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateTask", reflect.TypeOf((*MockService)(nil).CreateTask), t)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateTask", reflect.TypeOf((*MockService)(nil).CreateTask), ctx, t)
 }
 
 // DeleteTask mocks base method.
-func (m *MockService) DeleteTask(id int) error {
+func (m *MockService) DeleteTask(ctx context.Context, id int) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DeleteTask", id)
+	ret := m.ctrl.Call(m, "DeleteTask", ctx, id)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // DeleteTask indicates an expected call of DeleteTask.
-func (mr *MockServiceMockRecorder) DeleteTask(id interface{}) *gomock.Call {
+func (mr *MockServiceMockRecorder) DeleteTask(ctx, id interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteTask", reflect.TypeOf((*MockService)(nil).DeleteTask), id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteTask", reflect.TypeOf((*MockService)(nil).DeleteTask), ctx, id)
+}
+
+// DetachLabel mocks base method.
+func (m *MockService) DetachLabel(ctx context.Context, id, labelID int) (task.Info, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DetachLabel", ctx, id, labelID)
+	ret0, _ := ret[0].(task.Info)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DetachLabel indicates an expected call of DetachLabel.
+func (mr *MockServiceMockRecorder) DetachLabel(ctx, id, labelID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DetachLabel", reflect.TypeOf((*MockService)(nil).DetachLabel), ctx, id, labelID)
 }
 
 // GetAllTasks mocks base method.
-func (m *MockService) GetAllTasks() ([]task.Info, error) {
+func (m *MockService) GetAllTasks(ctx context.Context) ([]task.Info, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetAllTasks")
+	ret := m.ctrl.Call(m, "GetAllTasks", ctx)
 	ret0, _ := ret[0].([]task.Info)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetAllTasks indicates an expected call of GetAllTasks.
-func (mr *MockServiceMockRecorder) GetAllTasks() *gomock.Call {
+func (mr *MockServiceMockRecorder) GetAllTasks(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllTasks", reflect.TypeOf((*MockService)(nil).GetAllTasks), ctx)
+}
+
+// GetBlockers mocks base method.
+func (m *MockService) GetBlockers(ctx context.Context, id int) ([]task.Info, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBlockers", ctx, id)
+	ret0, _ := ret[0].([]task.Info)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBlockers indicates an expected call of GetBlockers.
+func (mr *MockServiceMockRecorder) GetBlockers(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBlockers", reflect.TypeOf((*MockService)(nil).GetBlockers), ctx, id)
+}
+
+// GetChildren mocks base method.
+func (m *MockService) GetChildren(ctx context.Context, id int, recursive bool) ([]task.Info, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetChildren", ctx, id, recursive)
+	ret0, _ := ret[0].([]task.Info)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetChildren indicates an expected call of GetChildren.
+func (mr *MockServiceMockRecorder) GetChildren(ctx, id, recursive interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllTasks", reflect.TypeOf((*MockService)(nil).GetAllTasks))
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetChildren", reflect.TypeOf((*MockService)(nil).GetChildren), ctx, id, recursive)
+}
+
+// GetExecutionOrder mocks base method.
+func (m *MockService) GetExecutionOrder(ctx context.Context) ([]task.Info, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetExecutionOrder", ctx)
+	ret0, _ := ret[0].([]task.Info)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetExecutionOrder indicates an expected call of GetExecutionOrder.
+func (mr *MockServiceMockRecorder) GetExecutionOrder(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetExecutionOrder", reflect.TypeOf((*MockService)(nil).GetExecutionOrder), ctx)
+}
+
+// GetOccurrences mocks base method.
+func (m *MockService) GetOccurrences(ctx context.Context, id int, from, to time.Time) ([]time.Time, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOccurrences", ctx, id, from, to)
+	ret0, _ := ret[0].([]time.Time)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetOccurrences indicates an expected call of GetOccurrences.
+func (mr *MockServiceMockRecorder) GetOccurrences(ctx, id, from, to interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOccurrences", reflect.TypeOf((*MockService)(nil).GetOccurrences), ctx, id, from, to)
 }
 
 // GetTaskByID mocks base method.
-func (m *MockService) GetTaskByID(id int) (task.Info, error) {
+func (m *MockService) GetTaskByID(ctx context.Context, id int) (task.Info, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetTaskByID", id)
+	ret := m.ctrl.Call(m, "GetTaskByID", ctx, id)
 	ret0, _ := ret[0].(task.Info)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetTaskByID indicates an expected call of GetTaskByID.
-func (mr *MockServiceMockRecorder) GetTaskByID(id interface{}) *gomock.Call {
+func (mr *MockServiceMockRecorder) GetTaskByID(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTaskByID", reflect.TypeOf((*MockService)(nil).GetTaskByID), ctx, id)
+}
+
+// GetTasksByAssignee mocks base method.
+func (m *MockService) GetTasksByAssignee(ctx context.Context, userID int) ([]task.Info, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTasksByAssignee", ctx, userID)
+	ret0, _ := ret[0].([]task.Info)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTasksByAssignee indicates an expected call of GetTasksByAssignee.
+func (mr *MockServiceMockRecorder) GetTasksByAssignee(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTasksByAssignee", reflect.TypeOf((*MockService)(nil).GetTasksByAssignee), ctx, userID)
+}
+
+// GetTasksByLabels mocks base method.
+func (m *MockService) GetTasksByLabels(ctx context.Context, names []string, match label.Match) ([]task.Info, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTasksByLabels", ctx, names, match)
+	ret0, _ := ret[0].([]task.Info)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTasksByLabels indicates an expected call of GetTasksByLabels.
+func (mr *MockServiceMockRecorder) GetTasksByLabels(ctx, names, match interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTasksByLabels", reflect.TypeOf((*MockService)(nil).GetTasksByLabels), ctx, names, match)
+}
+
+// GetTasksByProject mocks base method.
+func (m *MockService) GetTasksByProject(ctx context.Context, projectID int) ([]task.Info, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTasksByProject", ctx, projectID)
+	ret0, _ := ret[0].([]task.Info)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTasksByProject indicates an expected call of GetTasksByProject.
+func (mr *MockServiceMockRecorder) GetTasksByProject(ctx, projectID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTasksByProject", reflect.TypeOf((*MockService)(nil).GetTasksByProject), ctx, projectID)
+}
+
+// RemoveBlocker mocks base method.
+func (m *MockService) RemoveBlocker(ctx context.Context, id, blockerID int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveBlocker", ctx, id, blockerID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RemoveBlocker indicates an expected call of RemoveBlocker.
+func (mr *MockServiceMockRecorder) RemoveBlocker(ctx, id, blockerID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveBlocker", reflect.TypeOf((*MockService)(nil).RemoveBlocker), ctx, id, blockerID)
+}
+
+// SearchTasks mocks base method.
+func (m *MockService) SearchTasks(ctx context.Context, query string, limit int) ([]task.SearchResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SearchTasks", ctx, query, limit)
+	ret0, _ := ret[0].([]task.SearchResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SearchTasks indicates an expected call of SearchTasks.
+func (mr *MockServiceMockRecorder) SearchTasks(ctx, query, limit interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTaskByID", reflect.TypeOf((*MockService)(nil).GetTaskByID), id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SearchTasks", reflect.TypeOf((*MockService)(nil).SearchTasks), ctx, query, limit)
 }
 
 // UpdateTask mocks base method.
-func (m *MockService) UpdateTask(t task.Info) (task.Info, error) {
+func (m *MockService) UpdateTask(ctx context.Context, t task.Info) (task.Info, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "UpdateTask", t)
+	ret := m.ctrl.Call(m, "UpdateTask", ctx, t)
 	ret0, _ := ret[0].(task.Info)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // UpdateTask indicates an expected call of UpdateTask.
-func (mr *MockServiceMockRecorder) UpdateTask(t interface{}) *gomock.Call {
+func (mr *MockServiceMockRecorder) UpdateTask(ctx, t interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateTask", reflect.TypeOf((*MockService)(nil).UpdateTask), t)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateTask", reflect.TypeOf((*MockService)(nil).UpdateTask), ctx, t)
 }
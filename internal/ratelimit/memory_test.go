@@ -0,0 +1,97 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemoryStore(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	store := &memoryStore{buckets: make(map[string]*bucket), now: func() time.Time { return now }}
+	limit := Limit{Burst: 3, Period: time.Minute}
+	ctx := context.Background()
+
+	type step struct {
+		TestCase string
+		Advance  time.Duration
+		Key      string
+		Expected Decision
+	}
+
+	steps := []step{
+		{
+			TestCase: "a new key has a full bucket",
+			Key:      "a",
+			Expected: Decision{Allowed: true, Remaining: 2, Reset: 20 * time.Second},
+		},
+		{
+			TestCase: "takes a token",
+			Key:      "a",
+			Expected: Decision{Allowed: true, Remaining: 1, Reset: 40 * time.Second},
+		},
+		{
+			TestCase: "takes the last token",
+			Key:      "a",
+			Expected: Decision{Allowed: true, Remaining: 0, Reset: time.Minute},
+		},
+		{
+			TestCase: "denies an empty bucket",
+			Key:      "a",
+			Expected: Decision{Allowed: false, Remaining: 0, Reset: time.Minute, RetryAfter: 20 * time.Second},
+		},
+		{
+			TestCase: "other keys have buckets of their own",
+			Key:      "b",
+			Expected: Decision{Allowed: true, Remaining: 2, Reset: 20 * time.Second},
+		},
+		{
+			TestCase: "tells how long until the next token",
+			Advance:  5 * time.Second,
+			Key:      "a",
+			Expected: Decision{Allowed: false, Remaining: 0, Reset: 55 * time.Second, RetryAfter: 15 * time.Second},
+		},
+		{
+			TestCase: "refills over time",
+			Advance:  25 * time.Second,
+			Key:      "a",
+			Expected: Decision{Allowed: true, Remaining: 0, Reset: 50 * time.Second},
+		},
+		{
+			TestCase: "never holds more than the burst",
+			Advance:  time.Hour,
+			Key:      "a",
+			Expected: Decision{Allowed: true, Remaining: 2, Reset: 20 * time.Second},
+		},
+	}
+
+	for _, s := range steps {
+		t.Run(s.TestCase, func(t *testing.T) {
+			now = now.Add(s.Advance)
+			d, err := store.Take(ctx, s.Key, limit)
+			assert.NoError(t, err)
+			assert.Equal(t, s.Expected, d)
+		})
+	}
+}
+
+func TestMemoryStoreSweepsFullBuckets(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	store := &memoryStore{buckets: make(map[string]*bucket), now: func() time.Time { return now }}
+	ctx := context.Background()
+
+	_, err := store.Take(ctx, "short", Limit{Burst: 10, Period: time.Minute})
+	assert.NoError(t, err)
+	_, err = store.Take(ctx, "long", Limit{Burst: 10, Period: time.Hour})
+	assert.NoError(t, err)
+	assert.Len(t, store.buckets, 2)
+
+	now = now.Add(2 * time.Minute)
+	_, err = store.Take(ctx, "other", Limit{Burst: 10, Period: time.Minute})
+	assert.NoError(t, err)
+	assert.Contains(t, store.buckets, "long")
+	assert.Contains(t, store.buckets, "other")
+	assert.NotContains(t, store.buckets, "short")
+}
@@ -0,0 +1,67 @@
+package ratelimit
+
+import (
+	"log"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"task-api/internal/auth"
+	"task-api/internal/config"
+)
+
+// Middleware answers requests over a client's limit with a 429. Every
+// limited response carries RateLimit-Limit, RateLimit-Remaining and
+// RateLimit-Reset headers, and 429s a Retry-After, all in seconds.
+type Middleware gin.HandlerFunc
+
+// NewMiddleware limits reads and writes separately. Clients are told apart
+// by the identity in the request context, so an API key or user has the same
+// limit from any address, and by IP address otherwise. When the store fails,
+// requests are let through.
+func NewMiddleware(store Store, cfg config.RateLimitConfig) Middleware {
+	reads := Limit{Burst: cfg.Reads, Period: cfg.Period}
+	writes := Limit{Burst: cfg.Writes, Period: cfg.Period}
+	return func(c *gin.Context) {
+		kind, limit := "write", writes
+		switch c.Request.Method {
+		case http.MethodGet, http.MethodHead, http.MethodOptions:
+			kind, limit = "read", reads
+		}
+		if limit.Burst <= 0 {
+			c.Next()
+			return
+		}
+
+		d, err := store.Take(c.Request.Context(), "ratelimit:"+kind+":"+clientKey(c), limit)
+		if err != nil {
+			log.Printf("ratelimit: %v", err)
+			c.Next()
+			return
+		}
+		c.Header("RateLimit-Limit", strconv.Itoa(limit.Burst))
+		c.Header("RateLimit-Remaining", strconv.Itoa(d.Remaining))
+		c.Header("RateLimit-Reset", seconds(d.Reset))
+		if !d.Allowed {
+			c.Header("Retry-After", seconds(d.RetryAfter))
+			c.AbortWithStatusJSON(http.StatusTooManyRequests, gin.H{"error": "rate limit exceeded"})
+			return
+		}
+		c.Next()
+	}
+}
+
+func clientKey(c *gin.Context) string {
+	if identity, ok := auth.FromContext(c.Request.Context()); ok {
+		return "sub:" + identity.Subject
+	}
+	return "ip:" + c.ClientIP()
+}
+
+// seconds rounds up, so that clients waiting as told find a token.
+func seconds(d time.Duration) string {
+	return strconv.Itoa(int(math.Ceil(d.Seconds())))
+}
@@ -0,0 +1,218 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+
+	"task-api/internal/auth"
+	"task-api/internal/config"
+)
+
+type failingStore struct{}
+
+func (failingStore) Take(context.Context, string, Limit) (Decision, error) {
+	return Decision{}, errors.New("connection refused")
+}
+
+func newTestRouter(store Store, cfg config.RateLimitConfig) *gin.Engine {
+	router := gin.Default()
+	router.Use(func(c *gin.Context) {
+		if subject := c.GetHeader("X-Subject"); subject != "" {
+			c.Request = c.Request.WithContext(auth.NewContext(c.Request.Context(), auth.Identity{Subject: subject}))
+		}
+	}, gin.HandlerFunc(NewMiddleware(store, cfg)))
+	router.GET("/tasks", func(c *gin.Context) { c.Status(http.StatusOK) })
+	router.POST("/tasks", func(c *gin.Context) { c.Status(http.StatusCreated) })
+	return router
+}
+
+func TestMiddleware(t *testing.T) {
+	router := newTestRouter(NewMemoryStore(), config.RateLimitConfig{Reads: 2, Writes: 1, Period: time.Minute})
+
+	type request struct {
+		TestCase          string
+		Method            string
+		IP                string
+		Subject           string
+		ExpectedCode      int
+		ExpectedRemaining string
+		ExpectedReset     string
+		ExpectedRetry     string
+	}
+
+	requests := []request{
+		{
+			TestCase:          "first read",
+			Method:            http.MethodGet,
+			IP:                "10.0.0.1",
+			ExpectedCode:      http.StatusOK,
+			ExpectedRemaining: "1",
+			ExpectedReset:     "30",
+		},
+		{
+			TestCase:          "last read",
+			Method:            http.MethodGet,
+			IP:                "10.0.0.1",
+			ExpectedCode:      http.StatusOK,
+			ExpectedRemaining: "0",
+			ExpectedReset:     "60",
+		},
+		{
+			TestCase:          "read over the limit",
+			Method:            http.MethodGet,
+			IP:                "10.0.0.1",
+			ExpectedCode:      http.StatusTooManyRequests,
+			ExpectedRemaining: "0",
+			ExpectedReset:     "60",
+			ExpectedRetry:     "30",
+		},
+		{
+			TestCase:          "writes are limited apart from reads",
+			Method:            http.MethodPost,
+			IP:                "10.0.0.1",
+			ExpectedCode:      http.StatusCreated,
+			ExpectedRemaining: "0",
+			ExpectedReset:     "60",
+		},
+		{
+			TestCase:          "write over the limit",
+			Method:            http.MethodPost,
+			IP:                "10.0.0.1",
+			ExpectedCode:      http.StatusTooManyRequests,
+			ExpectedRemaining: "0",
+			ExpectedReset:     "60",
+			ExpectedRetry:     "60",
+		},
+		{
+			TestCase:          "other addresses are limited apart",
+			Method:            http.MethodGet,
+			IP:                "10.0.0.2",
+			ExpectedCode:      http.StatusOK,
+			ExpectedRemaining: "1",
+			ExpectedReset:     "30",
+		},
+		{
+			TestCase:          "identified callers are limited by subject",
+			Method:            http.MethodGet,
+			IP:                "10.0.0.1",
+			Subject:           "alice",
+			ExpectedCode:      http.StatusOK,
+			ExpectedRemaining: "1",
+			ExpectedReset:     "30",
+		},
+		{
+			TestCase:          "whatever their address",
+			Method:            http.MethodGet,
+			IP:                "10.0.0.3",
+			Subject:           "alice",
+			ExpectedCode:      http.StatusOK,
+			ExpectedRemaining: "0",
+			ExpectedReset:     "60",
+		},
+	}
+
+	for _, r := range requests {
+		t.Run(r.TestCase, func(t *testing.T) {
+			req, _ := http.NewRequest(r.Method, "/tasks", nil)
+			req.RemoteAddr = r.IP + ":1234"
+			if r.Subject != "" {
+				req.Header.Set("X-Subject", r.Subject)
+			}
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, r.ExpectedCode, w.Code)
+			limit := "1"
+			if r.Method == http.MethodGet {
+				limit = "2"
+			}
+			assert.Equal(t, limit, w.Header().Get("RateLimit-Limit"))
+			assert.Equal(t, r.ExpectedRemaining, w.Header().Get("RateLimit-Remaining"))
+			assert.Equal(t, r.ExpectedReset, w.Header().Get("RateLimit-Reset"))
+			assert.Equal(t, r.ExpectedRetry, w.Header().Get("Retry-After"))
+			if r.ExpectedCode == http.StatusTooManyRequests {
+				assert.JSONEq(t, `{"error":"rate limit exceeded"}`, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestMiddlewareLetsThrough(t *testing.T) {
+	type testCase struct {
+		TestCase string
+		Store    Store
+		Config   config.RateLimitConfig
+		Method   string
+	}
+
+	testCases := []testCase{
+		{
+			TestCase: "when the store fails",
+			Store:    failingStore{},
+			Config:   config.RateLimitConfig{Reads: 1, Writes: 1, Period: time.Minute},
+			Method:   http.MethodGet,
+		},
+		{
+			TestCase: "when the limit is off",
+			Store:    NewMemoryStore(),
+			Config:   config.RateLimitConfig{Reads: 1, Writes: 0, Period: time.Minute},
+			Method:   http.MethodPost,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.TestCase, func(t *testing.T) {
+			router := newTestRouter(tc.Store, tc.Config)
+			for i := 0; i < 3; i++ {
+				req, _ := http.NewRequest(tc.Method, "/tasks", nil)
+				w := httptest.NewRecorder()
+				router.ServeHTTP(w, req)
+
+				assert.Less(t, w.Code, 300)
+				assert.Empty(t, w.Header().Get("RateLimit-Limit"))
+			}
+		})
+	}
+}
+
+func TestMiddlewareTrustsOnlyConfiguredProxies(t *testing.T) {
+	tests := []struct {
+		TestCase       string
+		TrustedProxies []string
+		ExpectedCode   int
+	}{
+		{
+			TestCase:     "forwarded addresses from untrusted peers are ignored",
+			ExpectedCode: http.StatusTooManyRequests,
+		},
+		{
+			TestCase:       "trusted proxies forward the client address",
+			TrustedProxies: []string{"10.0.0.0/8"},
+			ExpectedCode:   http.StatusCreated,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.TestCase, func(t *testing.T) {
+			router := newTestRouter(NewMemoryStore(), config.RateLimitConfig{Reads: 1, Writes: 1, Period: time.Minute})
+			assert.NoError(t, router.SetTrustedProxies(tc.TrustedProxies))
+
+			var w *httptest.ResponseRecorder
+			for _, forwardedFor := range []string{"192.0.2.1", "192.0.2.2"} {
+				req, _ := http.NewRequest(http.MethodPost, "/tasks", nil)
+				req.RemoteAddr = "10.0.0.1:1234"
+				req.Header.Set("X-Forwarded-For", forwardedFor)
+				w = httptest.NewRecorder()
+				router.ServeHTTP(w, req)
+			}
+			assert.Equal(t, tc.ExpectedCode, w.Code)
+		})
+	}
+}
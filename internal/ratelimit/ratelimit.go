@@ -0,0 +1,63 @@
+// Package ratelimit limits how often clients call the API with token
+// buckets: every client has a bucket per kind of request that holds up to a
+// burst of tokens, each request takes one and tokens flow back at a steady
+// rate.
+package ratelimit
+
+import (
+	"context"
+	"math"
+	"time"
+)
+
+// Limit lets a client make Burst requests at once and Burst requests per
+// Period in the long run.
+type Limit struct {
+	Burst  int
+	Period time.Duration
+}
+
+// Rate is the number of tokens returned to a bucket per second.
+func (l Limit) Rate() float64 {
+	return float64(l.Burst) / l.Period.Seconds()
+}
+
+// Decision is the outcome of taking a token.
+type Decision struct {
+	Allowed bool
+	// Remaining is the number of whole tokens left in the bucket.
+	Remaining int
+	// Reset is the time until the bucket is full again.
+	Reset time.Duration
+	// RetryAfter is the time until the next token, when none was left.
+	RetryAfter time.Duration
+}
+
+// Decide describes the bucket holding tokens after a request took one, or
+// was denied because there was none.
+func (l Limit) Decide(tokens float64, allowed bool) Decision {
+	d := Decision{
+		Allowed:   allowed,
+		Remaining: int(math.Floor(tokens)),
+		Reset:     l.timeFor(float64(l.Burst) - tokens),
+	}
+	if !allowed {
+		d.RetryAfter = l.timeFor(1 - tokens)
+	}
+	return d
+}
+
+// timeFor returns the time it takes to refill n tokens.
+func (l Limit) timeFor(n float64) time.Duration {
+	if n <= 0 {
+		return 0
+	}
+	return time.Duration(n / l.Rate() * float64(time.Second))
+}
+
+// Store keeps the buckets. Take refills the bucket of key for the time that
+// passed since it was last used and takes a token from it if there is one.
+// A key that was never used has a full bucket.
+type Store interface {
+	Take(ctx context.Context, key string, limit Limit) (Decision, error)
+}
@@ -0,0 +1,211 @@
+// Package recurrence parses iCalendar recurrence rules (RFC 5545 RRULE) and
+// expands them into occurrences in a time zone.
+package recurrence
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	// Time zones have to resolve even on hosts without a zoneinfo database.
+	_ "time/tzdata"
+)
+
+type Frequency int
+
+const (
+	Daily Frequency = iota
+	Weekly
+	Monthly
+	Yearly
+)
+
+var frequencies = map[string]Frequency{
+	"DAILY":   Daily,
+	"WEEKLY":  Weekly,
+	"MONTHLY": Monthly,
+	"YEARLY":  Yearly,
+}
+
+var weekdays = map[string]time.Weekday{
+	"SU": time.Sunday,
+	"MO": time.Monday,
+	"TU": time.Tuesday,
+	"WE": time.Wednesday,
+	"TH": time.Thursday,
+	"FR": time.Friday,
+	"SA": time.Saturday,
+}
+
+// WeekdayNum is a BYDAY entry such as MO, 2TU or -1FR. N is zero for every
+// such weekday of the period.
+type WeekdayNum struct {
+	N       int
+	Weekday time.Weekday
+}
+
+// Rule is a parsed RRULE. Sub-daily frequencies and the BYHOUR, BYMINUTE,
+// BYSECOND, BYWEEKNO and BYYEARDAY parts are not supported, since every
+// occurrence of a task keeps the time of day of its first one.
+type Rule struct {
+	Freq     Frequency
+	Interval int
+	Count    int
+	// Until is the last possible occurrence, inclusive.
+	Until time.Time
+	// floatingUntil marks an UNTIL without a UTC designator, which is a
+	// wall-clock time in the zone of the series.
+	floatingUntil bool
+	ByDay         []WeekdayNum
+	ByMonthDay    []int
+	ByMonth       []time.Month
+	BySetPos      []int
+	WeekStart     time.Weekday
+}
+
+// Parse parses a rule such as "FREQ=MONTHLY;BYDAY=-1FR;COUNT=6". A leading
+// "RRULE:" is accepted, and names and values are case-insensitive.
+func Parse(s string) (Rule, error) {
+	s = strings.ToUpper(strings.TrimSpace(s))
+	s = strings.TrimPrefix(s, "RRULE:")
+	if s == "" {
+		return Rule{}, fmt.Errorf("empty rule")
+	}
+
+	r := Rule{Interval: 1, WeekStart: time.Monday}
+	seen := make(map[string]bool)
+	for _, part := range strings.Split(s, ";") {
+		name, value, ok := strings.Cut(part, "=")
+		if !ok || value == "" {
+			return Rule{}, fmt.Errorf("invalid rule part %q", part)
+		}
+		if seen[name] {
+			return Rule{}, fmt.Errorf("%s is given more than once", name)
+		}
+		seen[name] = true
+
+		var err error
+		switch name {
+		case "FREQ":
+			freq, ok := frequencies[value]
+			if !ok {
+				return Rule{}, fmt.Errorf("FREQ=%s is not supported", value)
+			}
+			r.Freq = freq
+		case "INTERVAL":
+			r.Interval, err = parseInt(name, value, 1, 1<<16)
+		case "COUNT":
+			r.Count, err = parseInt(name, value, 1, 1<<16)
+		case "UNTIL":
+			r.Until, r.floatingUntil, err = parseUntil(value)
+		case "BYDAY":
+			r.ByDay, err = parseByDay(value)
+		case "BYMONTHDAY":
+			r.ByMonthDay, err = parseIntList(name, value, 31)
+		case "BYMONTH":
+			var months []int
+			months, err = parseIntList(name, value, 12)
+			for _, m := range months {
+				if m < 0 {
+					return Rule{}, fmt.Errorf("invalid BYMONTH value %d", m)
+				}
+				r.ByMonth = append(r.ByMonth, time.Month(m))
+			}
+		case "BYSETPOS":
+			r.BySetPos, err = parseIntList(name, value, 366)
+		case "WKST":
+			day, ok := weekdays[value]
+			if !ok {
+				return Rule{}, fmt.Errorf("invalid WKST value %q", value)
+			}
+			r.WeekStart = day
+		default:
+			return Rule{}, fmt.Errorf("%s is not supported", name)
+		}
+		if err != nil {
+			return Rule{}, err
+		}
+	}
+
+	if !seen["FREQ"] {
+		return Rule{}, fmt.Errorf("FREQ is required")
+	}
+	if seen["COUNT"] && seen["UNTIL"] {
+		return Rule{}, fmt.Errorf("COUNT and UNTIL cannot be combined")
+	}
+	if r.Freq == Weekly && len(r.ByMonthDay) > 0 {
+		return Rule{}, fmt.Errorf("BYMONTHDAY cannot be used with FREQ=WEEKLY")
+	}
+	if r.Freq == Daily || r.Freq == Weekly {
+		for _, d := range r.ByDay {
+			if d.N != 0 {
+				return Rule{}, fmt.Errorf("numbered BYDAY values need FREQ=MONTHLY or FREQ=YEARLY")
+			}
+		}
+	}
+	if len(r.BySetPos) > 0 && len(r.ByDay) == 0 && len(r.ByMonthDay) == 0 && len(r.ByMonth) == 0 {
+		return Rule{}, fmt.Errorf("BYSETPOS needs another BYxxx part")
+	}
+	return r, nil
+}
+
+func parseInt(name, value string, min, max int) (int, error) {
+	n, err := strconv.Atoi(value)
+	if err != nil || n < min || n > max {
+		return 0, fmt.Errorf("invalid %s value %q", name, value)
+	}
+	return n, nil
+}
+
+// parseIntList parses a comma-separated list of non-zero values between
+// -max and max.
+func parseIntList(name, value string, max int) ([]int, error) {
+	var values []int
+	for _, item := range strings.Split(value, ",") {
+		n, err := strconv.Atoi(item)
+		if err != nil || n == 0 || n < -max || n > max {
+			return nil, fmt.Errorf("invalid %s value %q", name, item)
+		}
+		values = append(values, n)
+	}
+	return values, nil
+}
+
+func parseByDay(value string) ([]WeekdayNum, error) {
+	var days []WeekdayNum
+	for _, item := range strings.Split(value, ",") {
+		if len(item) < 2 {
+			return nil, fmt.Errorf("invalid BYDAY value %q", item)
+		}
+		day, ok := weekdays[item[len(item)-2:]]
+		if !ok {
+			return nil, fmt.Errorf("invalid BYDAY value %q", item)
+		}
+		var n int
+		if prefix := item[:len(item)-2]; prefix != "" {
+			var err error
+			n, err = strconv.Atoi(prefix)
+			if err != nil || n == 0 || n < -53 || n > 53 {
+				return nil, fmt.Errorf("invalid BYDAY value %q", item)
+			}
+		}
+		days = append(days, WeekdayNum{N: n, Weekday: day})
+	}
+	return days, nil
+}
+
+// parseUntil accepts a UTC date-time (19971224T000000Z), a floating
+// date-time (19971224T000000) or a date, which includes that whole day.
+func parseUntil(value string) (time.Time, bool, error) {
+	if t, err := time.Parse("20060102T150405Z", value); err == nil {
+		return t, false, nil
+	}
+	if t, err := time.Parse("20060102T150405", value); err == nil {
+		return t, true, nil
+	}
+	if t, err := time.Parse("20060102", value); err == nil {
+		return t.Add(24*time.Hour - time.Second), true, nil
+	}
+	return time.Time{}, false, fmt.Errorf("invalid UNTIL value %q", value)
+}
@@ -0,0 +1,73 @@
+package recurrence
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		TestCase string
+		Input    string
+		Expected Rule
+		Error    bool
+	}{
+		{
+			TestCase: "Weekly on weekdays",
+			Input:    "FREQ=WEEKLY;BYDAY=MO,TU,WE,TH,FR",
+			Expected: Rule{Freq: Weekly, Interval: 1, WeekStart: time.Monday, ByDay: []WeekdayNum{
+				{Weekday: time.Monday}, {Weekday: time.Tuesday}, {Weekday: time.Wednesday}, {Weekday: time.Thursday}, {Weekday: time.Friday},
+			}},
+		},
+		{
+			TestCase: "Prefix and lower case",
+			Input:    "RRULE:freq=monthly;byday=-1fr;count=6;wkst=su",
+			Expected: Rule{Freq: Monthly, Interval: 1, Count: 6, WeekStart: time.Sunday, ByDay: []WeekdayNum{{N: -1, Weekday: time.Friday}}},
+		},
+		{
+			TestCase: "Yearly with months and set position",
+			Input:    "FREQ=YEARLY;INTERVAL=2;BYMONTH=1,7;BYMONTHDAY=-1;BYSETPOS=1",
+			Expected: Rule{Freq: Yearly, Interval: 2, WeekStart: time.Monday, ByMonth: []time.Month{time.January, time.July}, ByMonthDay: []int{-1}, BySetPos: []int{1}},
+		},
+		{
+			TestCase: "UTC until",
+			Input:    "FREQ=DAILY;UNTIL=20240301T090000Z",
+			Expected: Rule{Freq: Daily, Interval: 1, WeekStart: time.Monday, Until: time.Date(2024, 3, 1, 9, 0, 0, 0, time.UTC)},
+		},
+		{
+			TestCase: "Floating until date",
+			Input:    "FREQ=DAILY;UNTIL=20240301",
+			Expected: Rule{Freq: Daily, Interval: 1, WeekStart: time.Monday, Until: time.Date(2024, 3, 1, 23, 59, 59, 0, time.UTC), floatingUntil: true},
+		},
+		{TestCase: "Empty rule", Input: "", Error: true},
+		{TestCase: "Missing FREQ", Input: "COUNT=3", Error: true},
+		{TestCase: "Sub-daily frequency", Input: "FREQ=HOURLY", Error: true},
+		{TestCase: "Unsupported part", Input: "FREQ=DAILY;BYHOUR=9", Error: true},
+		{TestCase: "Repeated part", Input: "FREQ=DAILY;FREQ=WEEKLY", Error: true},
+		{TestCase: "Malformed part", Input: "FREQ=DAILY;COUNT", Error: true},
+		{TestCase: "COUNT with UNTIL", Input: "FREQ=DAILY;COUNT=2;UNTIL=20240301", Error: true},
+		{TestCase: "Zero interval", Input: "FREQ=DAILY;INTERVAL=0", Error: true},
+		{TestCase: "Month out of range", Input: "FREQ=YEARLY;BYMONTH=13", Error: true},
+		{TestCase: "Negative month", Input: "FREQ=YEARLY;BYMONTH=-1", Error: true},
+		{TestCase: "Zero month day", Input: "FREQ=MONTHLY;BYMONTHDAY=0", Error: true},
+		{TestCase: "Unknown weekday", Input: "FREQ=WEEKLY;BYDAY=XX", Error: true},
+		{TestCase: "Numbered weekday in a weekly rule", Input: "FREQ=WEEKLY;BYDAY=1MO", Error: true},
+		{TestCase: "Month day in a weekly rule", Input: "FREQ=WEEKLY;BYMONTHDAY=1", Error: true},
+		{TestCase: "Set position alone", Input: "FREQ=MONTHLY;BYSETPOS=1", Error: true},
+		{TestCase: "Invalid until", Input: "FREQ=DAILY;UNTIL=tomorrow", Error: true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.TestCase, func(t *testing.T) {
+			rule, err := Parse(tc.Input)
+			if tc.Error {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.Expected, rule)
+		})
+	}
+}
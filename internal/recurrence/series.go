@@ -0,0 +1,347 @@
+package recurrence
+
+import (
+	"sort"
+	"time"
+)
+
+// maxEmptyPeriods stops the expansion of rules that can never match again,
+// such as the 30th of February.
+const maxEmptyPeriods = 100000
+
+// Series is a rule anchored at a start time. Occurrences fall on the dates
+// the rule selects from the start date on, at the wall-clock time of the
+// start in its location, so a 09:00 meeting stays at 09:00 across DST
+// changes. The start itself is only an occurrence if the rule selects it.
+type Series struct {
+	rule  Rule
+	start time.Time
+	until time.Time
+}
+
+func NewSeries(rule Rule, start time.Time) Series {
+	s := Series{rule: rule, start: start, until: rule.Until}
+	if rule.floatingUntil {
+		hour, min, sec := rule.Until.Clock()
+		s.until = resolve(rule.Until, hour, min, sec, start.Location())
+	}
+	return s
+}
+
+// Between returns up to limit occurrences from from on, stopping before to
+// unless to is zero.
+func (s Series) Between(from, to time.Time, limit int) []time.Time {
+	var occurrences []time.Time
+	s.each(func(t time.Time) bool {
+		if !to.IsZero() && !t.Before(to) {
+			return false
+		}
+		if !t.Before(from) {
+			occurrences = append(occurrences, t)
+		}
+		return len(occurrences) < limit
+	})
+	return occurrences
+}
+
+// After returns the first occurrence strictly after t, and false when the
+// series ends before that.
+func (s Series) After(t time.Time) (time.Time, bool) {
+	var next time.Time
+	s.each(func(occurrence time.Time) bool {
+		if occurrence.After(t) {
+			next = occurrence
+			return false
+		}
+		return true
+	})
+	return next, !next.IsZero()
+}
+
+// each calls fn with the occurrences in order until fn returns false or the
+// series ends.
+func (s Series) each(fn func(time.Time) bool) {
+	loc := s.start.Location()
+	hour, min, sec := s.start.Clock()
+	startDate := date(s.start.Date())
+
+	emitted, empty := 0, 0
+	for period := s.periodStart(startDate); period.Year() <= 9999 && empty < maxEmptyPeriods; period = s.nextPeriod(period) {
+		days := s.rule.setPos(s.expand(period))
+		if len(days) == 0 {
+			empty++
+			continue
+		}
+		empty = 0
+		for _, day := range days {
+			if day.Before(startDate) {
+				continue
+			}
+			t := resolve(day, hour, min, sec, loc)
+			if !s.until.IsZero() && t.After(s.until) {
+				return
+			}
+			if !fn(t) {
+				return
+			}
+			emitted++
+			if s.rule.Count > 0 && emitted == s.rule.Count {
+				return
+			}
+		}
+	}
+}
+
+// Periods and days are dates at midnight UTC, which keeps the calendar
+// arithmetic clear of DST.
+func date(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+func (s Series) periodStart(day time.Time) time.Time {
+	switch s.rule.Freq {
+	case Weekly:
+		offset := (int(day.Weekday()) - int(s.rule.WeekStart) + 7) % 7
+		return day.AddDate(0, 0, -offset)
+	case Monthly:
+		return date(day.Year(), day.Month(), 1)
+	case Yearly:
+		return date(day.Year(), time.January, 1)
+	default:
+		return day
+	}
+}
+
+func (s Series) nextPeriod(period time.Time) time.Time {
+	switch s.rule.Freq {
+	case Weekly:
+		return period.AddDate(0, 0, 7*s.rule.Interval)
+	case Monthly:
+		return period.AddDate(0, s.rule.Interval, 0)
+	case Yearly:
+		return period.AddDate(s.rule.Interval, 0, 0)
+	default:
+		return period.AddDate(0, 0, s.rule.Interval)
+	}
+}
+
+// expand returns the sorted days of a period the rule selects, before
+// BYSETPOS is applied.
+func (s Series) expand(period time.Time) []time.Time {
+	r := s.rule
+	switch r.Freq {
+	case Daily:
+		if !r.inMonths(period.Month()) || !r.onMonthDays(period) || !r.onWeekdays(period) {
+			return nil
+		}
+		return []time.Time{period}
+	case Weekly:
+		var days []time.Time
+		for i := 0; i < 7; i++ {
+			day := period.AddDate(0, 0, i)
+			if !r.inMonths(day.Month()) {
+				continue
+			}
+			if len(r.ByDay) > 0 && r.onWeekdays(day) || len(r.ByDay) == 0 && day.Weekday() == s.start.Weekday() {
+				days = append(days, day)
+			}
+		}
+		return days
+	case Monthly:
+		if !r.inMonths(period.Month()) {
+			return nil
+		}
+		return s.monthDays(period.Year(), period.Month())
+	default:
+		if len(r.ByDay) > 0 && len(r.ByMonth) == 0 && len(r.ByMonthDay) == 0 {
+			// Numbered weekdays count through the whole year here.
+			return sortDays(byDay(span(period, period.AddDate(1, 0, 0)), r.ByDay))
+		}
+		months := r.ByMonth
+		if len(months) == 0 {
+			if len(r.ByMonthDay) > 0 {
+				for m := time.January; m <= time.December; m++ {
+					months = append(months, m)
+				}
+			} else {
+				months = []time.Month{s.start.Month()}
+			}
+		}
+		var days []time.Time
+		for _, m := range months {
+			days = append(days, s.monthDays(period.Year(), m)...)
+		}
+		return sortDays(days)
+	}
+}
+
+// monthDays selects days within one month by BYMONTHDAY and BYDAY, or
+// falls back to the day of the month of the start. Months too short for
+// that day are skipped rather than clamped.
+func (s Series) monthDays(year int, month time.Month) []time.Time {
+	r := s.rule
+	first := date(year, month, 1)
+	days := span(first, first.AddDate(0, 1, 0))
+
+	if len(r.ByMonthDay) == 0 && len(r.ByDay) == 0 {
+		if day := s.start.Day(); day <= len(days) {
+			return []time.Time{days[day-1]}
+		}
+		return nil
+	}
+	if len(r.ByMonthDay) == 0 {
+		return sortDays(byDay(days, r.ByDay))
+	}
+
+	var selected []time.Time
+	for _, n := range r.ByMonthDay {
+		if n < 0 {
+			n += len(days) + 1
+		}
+		if n >= 1 && n <= len(days) {
+			selected = append(selected, days[n-1])
+		}
+	}
+	if len(r.ByDay) > 0 {
+		// BYDAY only limits the days BYMONTHDAY picked.
+		allowed := make(map[time.Time]bool)
+		for _, day := range byDay(days, r.ByDay) {
+			allowed[day] = true
+		}
+		var limited []time.Time
+		for _, day := range selected {
+			if allowed[day] {
+				limited = append(limited, day)
+			}
+		}
+		selected = limited
+	}
+	return sortDays(selected)
+}
+
+// span lists the days from first up to but excluding end.
+func span(first, end time.Time) []time.Time {
+	var days []time.Time
+	for day := first; day.Before(end); day = day.AddDate(0, 0, 1) {
+		days = append(days, day)
+	}
+	return days
+}
+
+// byDay picks the days matching BYDAY entries, where 2TU is the second and
+// -1TU the last Tuesday among days.
+func byDay(days []time.Time, entries []WeekdayNum) []time.Time {
+	var selected []time.Time
+	for _, entry := range entries {
+		var matching []time.Time
+		for _, day := range days {
+			if day.Weekday() == entry.Weekday {
+				matching = append(matching, day)
+			}
+		}
+		switch {
+		case entry.N == 0:
+			selected = append(selected, matching...)
+		case entry.N > 0 && entry.N <= len(matching):
+			selected = append(selected, matching[entry.N-1])
+		case entry.N < 0 && -entry.N <= len(matching):
+			selected = append(selected, matching[len(matching)+entry.N])
+		}
+	}
+	return selected
+}
+
+// setPos keeps the BYSETPOS positions of the days of one period.
+func (r Rule) setPos(days []time.Time) []time.Time {
+	if len(r.BySetPos) == 0 {
+		return days
+	}
+	var selected []time.Time
+	for _, pos := range r.BySetPos {
+		if pos < 0 {
+			pos += len(days) + 1
+		}
+		if pos >= 1 && pos <= len(days) {
+			selected = append(selected, days[pos-1])
+		}
+	}
+	return sortDays(selected)
+}
+
+func (r Rule) inMonths(month time.Month) bool {
+	if len(r.ByMonth) == 0 {
+		return true
+	}
+	for _, m := range r.ByMonth {
+		if m == month {
+			return true
+		}
+	}
+	return false
+}
+
+func (r Rule) onMonthDays(day time.Time) bool {
+	if len(r.ByMonthDay) == 0 {
+		return true
+	}
+	length := date(day.Year(), day.Month()+1, 0).Day()
+	for _, n := range r.ByMonthDay {
+		if n == day.Day() || n < 0 && n+length+1 == day.Day() {
+			return true
+		}
+	}
+	return false
+}
+
+func (r Rule) onWeekdays(day time.Time) bool {
+	if len(r.ByDay) == 0 {
+		return true
+	}
+	for _, entry := range r.ByDay {
+		if entry.Weekday == day.Weekday() {
+			return true
+		}
+	}
+	return false
+}
+
+// sortDays sorts days and drops duplicates.
+func sortDays(days []time.Time) []time.Time {
+	sort.Slice(days, func(i, j int) bool { return days[i].Before(days[j]) })
+	unique := days[:0]
+	for i, day := range days {
+		if i == 0 || !day.Equal(days[i-1]) {
+			unique = append(unique, day)
+		}
+	}
+	return unique
+}
+
+// resolve places a wall-clock time on a day in loc. As RFC 5545 prescribes,
+// a time skipped when clocks spring forward is moved on by the length of the
+// gap, and a time repeated when they fall back means its first instance.
+func resolve(day time.Time, hour, min, sec int, loc *time.Location) time.Time {
+	wall := time.Date(day.Year(), day.Month(), day.Day(), hour, min, sec, 0, time.UTC)
+	_, before := wall.Add(-24 * time.Hour).In(loc).Zone()
+	_, after := wall.Add(24 * time.Hour).In(loc).Zone()
+
+	var match time.Time
+	for _, offset := range []int{before, after} {
+		t := wall.Add(-time.Duration(offset) * time.Second).In(loc)
+		if sameWall(t, wall) && (match.IsZero() || t.Before(match)) {
+			match = t
+		}
+	}
+	if match.IsZero() {
+		// The time falls into a gap; read it with the offset before the gap.
+		return wall.Add(-time.Duration(before) * time.Second).In(loc)
+	}
+	return match
+}
+
+func sameWall(t, wall time.Time) bool {
+	y, m, d := t.Date()
+	hour, min, sec := t.Clock()
+	return y == wall.Year() && m == wall.Month() && d == wall.Day() &&
+		hour == wall.Hour() && min == wall.Minute() && sec == wall.Second()
+}
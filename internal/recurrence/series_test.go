@@ -0,0 +1,227 @@
+package recurrence
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSeriesBetween(t *testing.T) {
+	tests := []struct {
+		TestCase string
+		Rule     string
+		Zone     string
+		Start    string
+		Limit    int
+		Expected []string
+	}{
+		{
+			TestCase: "Daily for ten occurrences",
+			Rule:     "FREQ=DAILY;COUNT=10",
+			Zone:     "America/New_York",
+			Start:    "1997-09-02T09:00:00",
+			Limit:    20,
+			Expected: []string{
+				"1997-09-02T09:00:00-04:00", "1997-09-03T09:00:00-04:00", "1997-09-04T09:00:00-04:00", "1997-09-05T09:00:00-04:00",
+				"1997-09-06T09:00:00-04:00", "1997-09-07T09:00:00-04:00", "1997-09-08T09:00:00-04:00", "1997-09-09T09:00:00-04:00",
+				"1997-09-10T09:00:00-04:00", "1997-09-11T09:00:00-04:00",
+			},
+		},
+		{
+			TestCase: "Every other week across the end of DST",
+			Rule:     "FREQ=WEEKLY;INTERVAL=2;UNTIL=19971224T000000Z;WKST=SU;BYDAY=MO,WE,FR",
+			Zone:     "America/New_York",
+			Start:    "1997-09-01T09:00:00",
+			Limit:    50,
+			Expected: []string{
+				"1997-09-01T09:00:00-04:00", "1997-09-03T09:00:00-04:00", "1997-09-05T09:00:00-04:00",
+				"1997-09-15T09:00:00-04:00", "1997-09-17T09:00:00-04:00", "1997-09-19T09:00:00-04:00",
+				"1997-09-29T09:00:00-04:00", "1997-10-01T09:00:00-04:00", "1997-10-03T09:00:00-04:00",
+				"1997-10-13T09:00:00-04:00", "1997-10-15T09:00:00-04:00", "1997-10-17T09:00:00-04:00",
+				"1997-10-27T09:00:00-05:00", "1997-10-29T09:00:00-05:00", "1997-10-31T09:00:00-05:00",
+				"1997-11-10T09:00:00-05:00", "1997-11-12T09:00:00-05:00", "1997-11-14T09:00:00-05:00",
+				"1997-11-24T09:00:00-05:00", "1997-11-26T09:00:00-05:00", "1997-11-28T09:00:00-05:00",
+				"1997-12-08T09:00:00-05:00", "1997-12-10T09:00:00-05:00", "1997-12-12T09:00:00-05:00",
+				"1997-12-22T09:00:00-05:00",
+			},
+		},
+		{
+			TestCase: "First Friday of the month",
+			Rule:     "FREQ=MONTHLY;COUNT=6;BYDAY=1FR",
+			Zone:     "America/New_York",
+			Start:    "1997-09-05T09:00:00",
+			Limit:    20,
+			Expected: []string{
+				"1997-09-05T09:00:00-04:00", "1997-10-03T09:00:00-04:00", "1997-11-07T09:00:00-05:00",
+				"1997-12-05T09:00:00-05:00", "1998-01-02T09:00:00-05:00", "1998-02-06T09:00:00-05:00",
+			},
+		},
+		{
+			TestCase: "Last work day of the month",
+			Rule:     "FREQ=MONTHLY;BYDAY=MO,TU,WE,TH,FR;BYSETPOS=-1",
+			Zone:     "UTC",
+			Start:    "1997-09-30T17:00:00",
+			Limit:    6,
+			Expected: []string{
+				"1997-09-30T17:00:00Z", "1997-10-31T17:00:00Z", "1997-11-28T17:00:00Z",
+				"1997-12-31T17:00:00Z", "1998-01-30T17:00:00Z", "1998-02-27T17:00:00Z",
+			},
+		},
+		{
+			TestCase: "Months without the day are skipped",
+			Rule:     "FREQ=MONTHLY;COUNT=4",
+			Zone:     "UTC",
+			Start:    "2024-01-31T10:00:00",
+			Limit:    10,
+			Expected: []string{"2024-01-31T10:00:00Z", "2024-03-31T10:00:00Z", "2024-05-31T10:00:00Z", "2024-07-31T10:00:00Z"},
+		},
+		{
+			TestCase: "Leap day",
+			Rule:     "FREQ=YEARLY;COUNT=3",
+			Zone:     "UTC",
+			Start:    "2024-02-29T08:00:00",
+			Limit:    10,
+			Expected: []string{"2024-02-29T08:00:00Z", "2028-02-29T08:00:00Z", "2032-02-29T08:00:00Z"},
+		},
+		{
+			TestCase: "Numbered weekday of the year",
+			Rule:     "FREQ=YEARLY;BYDAY=20MO",
+			Zone:     "America/New_York",
+			Start:    "1997-05-19T09:00:00",
+			Limit:    3,
+			Expected: []string{"1997-05-19T09:00:00-04:00", "1998-05-18T09:00:00-04:00", "1999-05-17T09:00:00-04:00"},
+		},
+		{
+			TestCase: "Every Thursday in March",
+			Rule:     "FREQ=YEARLY;BYMONTH=3;BYDAY=TH",
+			Zone:     "America/New_York",
+			Start:    "1997-03-13T09:00:00",
+			Limit:    5,
+			Expected: []string{
+				"1997-03-13T09:00:00-05:00", "1997-03-20T09:00:00-05:00", "1997-03-27T09:00:00-05:00",
+				"1998-03-05T09:00:00-05:00", "1998-03-12T09:00:00-05:00",
+			},
+		},
+		{
+			TestCase: "Friday the 13th leaves out a start that does not match",
+			Rule:     "FREQ=MONTHLY;BYDAY=FR;BYMONTHDAY=13",
+			Zone:     "America/New_York",
+			Start:    "1997-09-02T09:00:00",
+			Limit:    5,
+			Expected: []string{
+				"1998-02-13T09:00:00-05:00", "1998-03-13T09:00:00-05:00", "1998-11-13T09:00:00-05:00",
+				"1999-08-13T09:00:00-04:00", "2000-10-13T09:00:00-04:00",
+			},
+		},
+		{
+			TestCase: "Daily keeps the wall time when DST starts",
+			Rule:     "FREQ=DAILY;COUNT=3",
+			Zone:     "Europe/Berlin",
+			Start:    "2024-03-30T09:00:00",
+			Limit:    10,
+			Expected: []string{"2024-03-30T09:00:00+01:00", "2024-03-31T09:00:00+02:00", "2024-04-01T09:00:00+02:00"},
+		},
+		{
+			TestCase: "A time skipped by DST moves past the gap",
+			Rule:     "FREQ=DAILY;COUNT=3",
+			Zone:     "Europe/Berlin",
+			Start:    "2024-03-30T02:30:00",
+			Limit:    10,
+			Expected: []string{"2024-03-30T02:30:00+01:00", "2024-03-31T03:30:00+02:00", "2024-04-01T02:30:00+02:00"},
+		},
+		{
+			TestCase: "A time repeated when DST ends resolves to the first one",
+			Rule:     "FREQ=DAILY;COUNT=3",
+			Zone:     "Europe/Berlin",
+			Start:    "2024-10-26T02:30:00",
+			Limit:    10,
+			Expected: []string{"2024-10-26T02:30:00+02:00", "2024-10-27T02:30:00+02:00", "2024-10-28T02:30:00+01:00"},
+		},
+		{
+			TestCase: "Weekly across the American DST start",
+			Rule:     "FREQ=WEEKLY;BYDAY=SU;COUNT=2",
+			Zone:     "America/New_York",
+			Start:    "2024-03-03T02:30:00",
+			Limit:    10,
+			Expected: []string{"2024-03-03T02:30:00-05:00", "2024-03-10T03:30:00-04:00"},
+		},
+		{
+			TestCase: "Floating until includes its whole day",
+			Rule:     "FREQ=DAILY;UNTIL=20240302",
+			Zone:     "Asia/Tokyo",
+			Start:    "2024-02-28T09:00:00",
+			Limit:    10,
+			Expected: []string{"2024-02-28T09:00:00+09:00", "2024-02-29T09:00:00+09:00", "2024-03-01T09:00:00+09:00", "2024-03-02T09:00:00+09:00"},
+		},
+		{
+			TestCase: "UTC until is an instant",
+			Rule:     "FREQ=DAILY;UNTIL=20240301T000000Z",
+			Zone:     "Asia/Tokyo",
+			Start:    "2024-02-28T09:00:00",
+			Limit:    10,
+			Expected: []string{"2024-02-28T09:00:00+09:00", "2024-02-29T09:00:00+09:00", "2024-03-01T09:00:00+09:00"},
+		},
+		{
+			TestCase: "A rule that never matches ends",
+			Rule:     "FREQ=DAILY;BYMONTH=2;BYMONTHDAY=30",
+			Zone:     "UTC",
+			Start:    "2024-01-01T09:00:00",
+			Limit:    10,
+			Expected: nil,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.TestCase, func(t *testing.T) {
+			series := newSeries(t, tc.Rule, tc.Zone, tc.Start)
+			assert.Equal(t, tc.Expected, format(series.Between(time.Time{}, time.Time{}, tc.Limit)))
+		})
+	}
+}
+
+func TestSeriesRange(t *testing.T) {
+	series := newSeries(t, "FREQ=WEEKLY;BYDAY=MO", "Europe/Berlin", "2024-01-01T09:00:00")
+	berlin, _ := time.LoadLocation("Europe/Berlin")
+
+	from := time.Date(2024, 3, 18, 9, 0, 0, 0, berlin)
+	to := time.Date(2024, 4, 8, 9, 0, 0, 0, berlin)
+	assert.Equal(t, []string{"2024-03-18T09:00:00+01:00", "2024-03-25T09:00:00+01:00", "2024-04-01T09:00:00+02:00"},
+		format(series.Between(from, to, 10)))
+	assert.Equal(t, []string{"2024-03-18T09:00:00+01:00"}, format(series.Between(from, to, 1)))
+}
+
+func TestSeriesAfter(t *testing.T) {
+	series := newSeries(t, "FREQ=MONTHLY;BYMONTHDAY=-1;COUNT=3", "UTC", "2024-01-31T12:00:00")
+
+	next, ok := series.After(time.Date(2024, 1, 31, 12, 0, 0, 0, time.UTC))
+	assert.True(t, ok)
+	assert.Equal(t, "2024-02-29T12:00:00Z", next.Format(time.RFC3339))
+
+	next, ok = series.After(time.Date(2024, 2, 29, 12, 0, 0, 0, time.UTC))
+	assert.True(t, ok)
+	assert.Equal(t, "2024-03-31T12:00:00Z", next.Format(time.RFC3339))
+
+	_, ok = series.After(time.Date(2024, 3, 31, 12, 0, 0, 0, time.UTC))
+	assert.False(t, ok)
+}
+
+func newSeries(t *testing.T, rule, zone, start string) Series {
+	t.Helper()
+	r, err := Parse(rule)
+	require.NoError(t, err)
+	loc, err := time.LoadLocation(zone)
+	require.NoError(t, err)
+	s, err := time.ParseInLocation("2006-01-02T15:04:05", start, loc)
+	require.NoError(t, err)
+	return NewSeries(r, s)
+}
+
+func format(times []time.Time) []string {
+	var formatted []string
+	for _, t := range times {
+		formatted = append(formatted, t.Format(time.RFC3339))
+	}
+	return formatted
+}
@@ -0,0 +1,66 @@
+// Package requestid gives every request an ID, taken from the X-Request-ID
+// header when the client or a proxy sent one, so that what happened during
+// a request can be traced back to it.
+package requestid
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	Header = "X-Request-ID"
+	// maxLength caps the IDs accepted from clients.
+	maxLength = 128
+)
+
+type contextKey struct{}
+
+// NewContext returns a copy of ctx carrying the request ID.
+func NewContext(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, contextKey{}, id)
+}
+
+// FromContext returns the request ID in ctx, or "".
+func FromContext(ctx context.Context) string {
+	id, _ := ctx.Value(contextKey{}).(string)
+	return id
+}
+
+// Middleware puts the request ID into the request context and echoes it in
+// the X-Request-ID response header. IDs sent by clients are kept unless they
+// are too long or hold anything but printable ASCII, in which case a new one
+// is made up.
+func Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id := c.GetHeader(Header)
+		if !valid(id) {
+			id = generate()
+		}
+		c.Header(Header, id)
+		c.Request = c.Request.WithContext(NewContext(c.Request.Context(), id))
+		c.Next()
+	}
+}
+
+func valid(id string) bool {
+	if id == "" || len(id) > maxLength {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		if id[i] < '!' || id[i] > '~' {
+			return false
+		}
+	}
+	return true
+}
+
+func generate() string {
+	b := make([]byte, 16)
+	// crypto/rand does not fail on supported platforms.
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
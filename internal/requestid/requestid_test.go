@@ -0,0 +1,50 @@
+package requestid
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(Middleware())
+	router.GET("/", func(c *gin.Context) {
+		c.String(http.StatusOK, FromContext(c.Request.Context()))
+	})
+
+	tests := []struct {
+		TestCase string
+		Header   string
+		Kept     bool
+	}{
+		{TestCase: "Client ID is kept", Header: "req-42", Kept: true},
+		{TestCase: "Missing ID is generated"},
+		{TestCase: "ID with spaces is replaced", Header: "req 42"},
+		{TestCase: "Overlong ID is replaced", Header: strings.Repeat("a", 129)},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.TestCase, func(t *testing.T) {
+			req, _ := http.NewRequest(http.MethodGet, "/", nil)
+			if tc.Header != "" {
+				req.Header.Set(Header, tc.Header)
+			}
+			rr := httptest.NewRecorder()
+			router.ServeHTTP(rr, req)
+
+			id := rr.Body.String()
+			assert.Equal(t, id, rr.Header().Get(Header))
+			if tc.Kept {
+				assert.Equal(t, tc.Header, id)
+			} else {
+				assert.Len(t, id, 32)
+			}
+		})
+	}
+}
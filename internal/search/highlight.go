@@ -0,0 +1,84 @@
+package search
+
+import (
+	"html"
+	"strings"
+)
+
+const (
+	// snippetWords is the number of words a snippet shows at most, unless
+	// a phrase match runs past it.
+	snippetWords = 24
+	// snippetLead is the number of words kept before the first match.
+	snippetLead = 6
+)
+
+// Highlight returns an HTML snippet of text with the matches of q wrapped in
+// <mark> tags. Long texts are cut around the first match, with an ellipsis
+// marking what was left out. The text itself is escaped.
+func Highlight(text string, q Query) string {
+	tokens := scan(text)
+	words := make([]string, len(tokens))
+	for i, tok := range tokens {
+		words[i] = tok.word
+	}
+
+	type span struct{ first, last int }
+	var spans []span
+	for i := 0; i < len(words); i++ {
+		n := 0
+		for _, term := range q.Terms {
+			if m := term.matchAt(words, i); m > n {
+				n = m
+			}
+		}
+		if n > 0 {
+			spans = append(spans, span{first: i, last: i + n - 1})
+			i += n - 1
+		}
+	}
+
+	first, last := 0, len(tokens)-1
+	if len(tokens) > snippetWords {
+		if len(spans) > 0 && spans[0].first > snippetLead {
+			first = spans[0].first - snippetLead
+		}
+		if first+snippetWords-1 < last {
+			last = first + snippetWords - 1
+		}
+	}
+
+	// A phrase match running past the end of the snippet is shown whole.
+	for _, s := range spans {
+		if s.first >= first && s.first <= last && s.last > last {
+			last = s.last
+		}
+	}
+
+	var b strings.Builder
+	start, end := 0, len(text)
+	if first > 0 {
+		start = tokens[first].start
+		b.WriteString("… ")
+	}
+	if last < len(tokens)-1 {
+		end = tokens[last].end
+	}
+	pos := start
+	for _, s := range spans {
+		if s.first < first || s.first > last {
+			continue
+		}
+		from, to := tokens[s.first].start, tokens[s.last].end
+		b.WriteString(html.EscapeString(text[pos:from]))
+		b.WriteString("<mark>")
+		b.WriteString(html.EscapeString(text[from:to]))
+		b.WriteString("</mark>")
+		pos = to
+	}
+	b.WriteString(html.EscapeString(text[pos:end]))
+	if end < len(text) {
+		b.WriteString(" …")
+	}
+	return b.String()
+}
@@ -0,0 +1,59 @@
+package search
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHighlight(t *testing.T) {
+	long := strings.Repeat("filler ", 20) + "the error page is broken " + strings.Repeat("tail ", 20)
+
+	tests := []struct {
+		TestCase string
+		Text     string
+		Query    string
+		Expected string
+	}{
+		{
+			TestCase: "Words",
+			Text:     "Fix the login page",
+			Query:    "login fix",
+			Expected: "<mark>Fix</mark> the <mark>login</mark> page",
+		},
+		{
+			TestCase: "Prefix",
+			Text:     "Deploy deployment scripts",
+			Query:    "deploy*",
+			Expected: "<mark>Deploy</mark> <mark>deployment</mark> scripts",
+		},
+		{
+			TestCase: "Phrase is marked as a whole",
+			Text:     "Page through the error, page",
+			Query:    `"error page"`,
+			Expected: "Page through the <mark>error, page</mark>",
+		},
+		{
+			TestCase: "Text is escaped",
+			Text:     "Escape <b>login</b> & more",
+			Query:    "login",
+			Expected: "Escape &lt;b&gt;<mark>login</mark>&lt;/b&gt; &amp; more",
+		},
+		{
+			TestCase: "Long text is cut around the first match",
+			Text:     long,
+			Query:    `"error page"`,
+			Expected: "… filler filler filler filler filler the <mark>error page</mark> is broken tail tail tail tail tail tail tail tail tail tail tail tail tail tail …",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.TestCase, func(t *testing.T) {
+			q, err := Parse(tc.Query)
+			require.NoError(t, err)
+			assert.Equal(t, tc.Expected, Highlight(tc.Text, q))
+		})
+	}
+}
@@ -0,0 +1,118 @@
+package search
+
+import (
+	"math"
+	"sort"
+	"strings"
+)
+
+// Index is an inverted index from words to the documents containing them.
+// It is not safe for concurrent use.
+type Index struct {
+	// postings counts how often each word occurs in each document.
+	postings map[string]map[int]int
+	// docs keeps the words of each document in order, for phrases and
+	// removal.
+	docs map[int][]string
+}
+
+// Hit is a document matching a query. Higher scores rank first.
+type Hit struct {
+	ID    int
+	Score float64
+}
+
+func NewIndex() *Index {
+	return &Index{
+		postings: make(map[string]map[int]int),
+		docs:     make(map[int][]string),
+	}
+}
+
+// Add indexes text as the document id, replacing what was indexed for it
+// before.
+func (ix *Index) Add(id int, text string) {
+	ix.Remove(id)
+	words := Tokenize(text)
+	ix.docs[id] = words
+	for _, word := range words {
+		if ix.postings[word] == nil {
+			ix.postings[word] = make(map[int]int)
+		}
+		ix.postings[word][id]++
+	}
+}
+
+func (ix *Index) Remove(id int) {
+	for _, word := range ix.docs[id] {
+		delete(ix.postings[word], id)
+		if len(ix.postings[word]) == 0 {
+			delete(ix.postings, word)
+		}
+	}
+	delete(ix.docs, id)
+}
+
+// Search returns the documents matching every term of q, best first. A
+// term adds its number of occurrences in a document, weighted by how rare
+// the term is across documents, and scores are divided by the square root
+// of the document length so that matches in short texts count more.
+func (ix *Index) Search(q Query) []Hit {
+	scores := make(map[int]float64)
+	for i, term := range q.Terms {
+		counts := ix.occurrences(term)
+		idf := math.Log(1 + float64(len(ix.docs))/float64(len(counts)+1))
+		next := make(map[int]float64, len(counts))
+		for id, n := range counts {
+			if score, ok := scores[id]; ok || i == 0 {
+				next[id] = score + float64(n)*idf
+			}
+		}
+		scores = next
+		if len(scores) == 0 {
+			return nil
+		}
+	}
+
+	hits := make([]Hit, 0, len(scores))
+	for id, score := range scores {
+		hits = append(hits, Hit{ID: id, Score: score / math.Sqrt(float64(len(ix.docs[id])))})
+	}
+	sort.Slice(hits, func(i, j int) bool {
+		if hits[i].Score != hits[j].Score {
+			return hits[i].Score > hits[j].Score
+		}
+		return hits[i].ID < hits[j].ID
+	})
+	return hits
+}
+
+// occurrences counts the matches of a term in each document containing it.
+func (ix *Index) occurrences(term Term) map[int]int {
+	counts := make(map[int]int)
+	switch {
+	case term.Prefix:
+		for word, docs := range ix.postings {
+			if strings.HasPrefix(word, term.Words[0]) {
+				for id, n := range docs {
+					counts[id] += n
+				}
+			}
+		}
+	case len(term.Words) == 1:
+		for id, n := range ix.postings[term.Words[0]] {
+			counts[id] = n
+		}
+	default:
+		// Only documents containing the first word can hold the phrase.
+		for id := range ix.postings[term.Words[0]] {
+			words := ix.docs[id]
+			for i := range words {
+				if term.matchAt(words, i) > 0 {
+					counts[id]++
+				}
+			}
+		}
+	}
+	return counts
+}
@@ -0,0 +1,60 @@
+package search
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIndexSearch(t *testing.T) {
+	ix := NewIndex()
+	ix.Add(1, "Fix login page")
+	ix.Add(2, "Write release notes for the login redesign")
+	ix.Add(3, "Deploy the deployment scripts")
+	ix.Add(4, "Page through the error page")
+	ix.Add(5, "Login")
+
+	tests := []struct {
+		TestCase string
+		Query    string
+		Expected []int
+	}{
+		{TestCase: "Shorter names rank first", Query: "login", Expected: []int{5, 1, 2}},
+		{TestCase: "All words must match", Query: "login page", Expected: []int{1}},
+		{TestCase: "Prefix", Query: "deploy*", Expected: []int{3}},
+		{TestCase: "Prefix counts every matching word", Query: "p*", Expected: []int{4, 1}},
+		{TestCase: "Phrase", Query: `"error page"`, Expected: []int{4}},
+		{TestCase: "Phrase words out of order", Query: `"page error"`, Expected: nil},
+		{TestCase: "Unknown word", Query: "login nothing", Expected: nil},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.TestCase, func(t *testing.T) {
+			q, err := Parse(tc.Query)
+			require.NoError(t, err)
+			var ids []int
+			for _, hit := range ix.Search(q) {
+				ids = append(ids, hit.ID)
+			}
+			assert.Equal(t, tc.Expected, ids)
+		})
+	}
+}
+
+func TestIndexReplaceAndRemove(t *testing.T) {
+	ix := NewIndex()
+	ix.Add(1, "Old name")
+	ix.Add(1, "New name")
+
+	q, _ := Parse("old")
+	assert.Empty(t, ix.Search(q))
+	q, _ = Parse("new")
+	assert.Len(t, ix.Search(q), 1)
+
+	ix.Remove(1)
+	q, _ = Parse("name")
+	assert.Empty(t, ix.Search(q))
+	assert.Empty(t, ix.postings)
+	assert.Empty(t, ix.docs)
+}
@@ -0,0 +1,127 @@
+// Package search parses full-text queries over task names, matches them with
+// an in-memory inverted index and highlights the matches.
+package search
+
+import (
+	"errors"
+	"strings"
+	"unicode"
+)
+
+var ErrEmptyQuery = errors.New("search query has no words")
+
+// Query matches the texts containing all of its terms.
+type Query struct {
+	Terms []Term
+}
+
+// Term is a word, a word prefix or a phrase of consecutive words.
+type Term struct {
+	Words []string
+	// Prefix matches every word starting with the only word of the term.
+	Prefix bool
+}
+
+// Parse reads a query such as `login fix* "error page"`. Words in double
+// quotes form a phrase, and a trailing * makes a word match as a prefix.
+// A word that is split by punctuation, such as e-mail, is read as a phrase.
+func Parse(q string) (Query, error) {
+	var query Query
+	for q != "" {
+		q = strings.TrimLeftFunc(q, unicode.IsSpace)
+		if q == "" {
+			break
+		}
+
+		var raw string
+		phrase := q[0] == '"'
+		if phrase {
+			end := strings.IndexByte(q[1:], '"')
+			if end < 0 {
+				raw, q = q[1:], ""
+			} else {
+				raw, q = q[1:end+1], q[end+2:]
+			}
+		} else {
+			end := strings.IndexFunc(q, unicode.IsSpace)
+			if end < 0 {
+				end = len(q)
+			}
+			raw, q = q[:end], q[end:]
+		}
+
+		words := Tokenize(raw)
+		if len(words) == 0 {
+			continue
+		}
+		query.Terms = append(query.Terms, Term{
+			Words:  words,
+			Prefix: !phrase && len(words) == 1 && strings.HasSuffix(raw, "*"),
+		})
+	}
+	if len(query.Terms) == 0 {
+		return Query{}, ErrEmptyQuery
+	}
+	return query, nil
+}
+
+// matchAt returns the number of words starting at words[i] that the term
+// matches, or 0.
+func (t Term) matchAt(words []string, i int) int {
+	if len(t.Words) == 1 && t.Prefix {
+		if strings.HasPrefix(words[i], t.Words[0]) {
+			return 1
+		}
+		return 0
+	}
+	if i+len(t.Words) > len(words) {
+		return 0
+	}
+	for j, word := range t.Words {
+		if words[i+j] != word {
+			return 0
+		}
+	}
+	return len(t.Words)
+}
+
+// Tokenize splits text into lower-case words of letters, digits and
+// underscores, which is how MySQL's full-text parser reads it.
+func Tokenize(text string) []string {
+	var words []string
+	for _, tok := range scan(text) {
+		words = append(words, tok.word)
+	}
+	return words
+}
+
+type token struct {
+	word       string
+	start, end int
+}
+
+// scan returns the words of text with their byte offsets.
+func scan(text string) []token {
+	var tokens []token
+	start := -1
+	for i, r := range text {
+		if isWordRune(r) {
+			if start < 0 {
+				start = i
+			}
+			continue
+		}
+		if start >= 0 {
+			tokens = append(tokens, token{word: strings.ToLower(text[start:i]), start: start, end: i})
+			start = -1
+		}
+	}
+	if start >= 0 {
+		tokens = append(tokens, token{word: strings.ToLower(text[start:]), start: start, end: len(text)})
+	}
+	return tokens
+}
+
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_'
+}
@@ -0,0 +1,62 @@
+package search
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		TestCase string
+		Input    string
+		Expected Query
+		Error    error
+	}{
+		{
+			TestCase: "Words",
+			Input:    "Login  BUG",
+			Expected: Query{Terms: []Term{{Words: []string{"login"}}, {Words: []string{"bug"}}}},
+		},
+		{
+			TestCase: "Prefix",
+			Input:    "deploy*",
+			Expected: Query{Terms: []Term{{Words: []string{"deploy"}, Prefix: true}}},
+		},
+		{
+			TestCase: "Phrase",
+			Input:    `fix "error page"`,
+			Expected: Query{Terms: []Term{{Words: []string{"fix"}}, {Words: []string{"error", "page"}}}},
+		},
+		{
+			TestCase: "Unterminated phrase runs to the end",
+			Input:    `"release notes`,
+			Expected: Query{Terms: []Term{{Words: []string{"release", "notes"}}}},
+		},
+		{
+			TestCase: "Punctuated word is a phrase",
+			Input:    "e-mail*",
+			Expected: Query{Terms: []Term{{Words: []string{"e", "mail"}}}},
+		},
+		{
+			TestCase: "Operators are not passed through",
+			Input:    "+urgent -later (report)",
+			Expected: Query{Terms: []Term{{Words: []string{"urgent"}}, {Words: []string{"later"}}, {Words: []string{"report"}}}},
+		},
+		{TestCase: "Empty", Input: "  ", Error: ErrEmptyQuery},
+		{TestCase: "Only punctuation", Input: `* "" -`, Error: ErrEmptyQuery},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.TestCase, func(t *testing.T) {
+			query, err := Parse(tc.Input)
+			assert.Equal(t, tc.Error, err)
+			assert.Equal(t, tc.Expected, query)
+		})
+	}
+}
+
+func TestTokenize(t *testing.T) {
+	assert.Equal(t, []string{"fix", "snake_case", "in", "größe", "v2"}, Tokenize("Fix snake_case in Größe, v2!"))
+	assert.Nil(t, Tokenize(" -- "))
+}
@@ -0,0 +1,21 @@
+package apikey
+
+import (
+	"context"
+
+	"task-api/internal/domain/apikey"
+)
+
+type Service interface {
+	// ListKeys returns every key of the caller's tenant, revoked ones
+	// included, without secrets.
+	ListKeys(ctx context.Context) ([]apikey.Key, error)
+	// IssueKey creates a key bound to the caller's tenant and returns it
+	// with its secret, which is not stored and cannot be shown again.
+	IssueKey(ctx context.Context, name string, scopes []string) (apikey.Issued, error)
+	// RevokeKey stops a key of the caller's tenant from working. Revoking
+	// it again is a no-op.
+	RevokeKey(ctx context.Context, id int) (apikey.Key, error)
+	// Verify returns the key behind a secret and records that it was used.
+	Verify(secret string) (apikey.Key, error)
+}
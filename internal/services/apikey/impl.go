@@ -0,0 +1,150 @@
+package apikey
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"task-api/internal/domain/apikey"
+	"task-api/internal/tenant"
+)
+
+const (
+	// secretBytes is the amount of randomness in a secret.
+	secretBytes = 32
+	// prefixLength is the length of the start of a secret kept to tell
+	// keys apart, SecretPrefix included.
+	prefixLength = 11
+	// lastUsedPrecision limits how often the last use of a key is written.
+	lastUsedPrecision = time.Minute
+)
+
+type apiKeyService struct {
+	repo apikey.Repository
+	now  func() time.Time
+}
+
+func NewAPIKeyService(repo apikey.Repository) Service {
+	return &apiKeyService{repo: repo, now: time.Now}
+}
+
+func (s *apiKeyService) ListKeys(ctx context.Context) ([]apikey.Key, error) {
+	all, err := s.repo.GetAll()
+	if err != nil {
+		return nil, err
+	}
+	tenantID := tenant.FromContext(ctx)
+	var keys []apikey.Key
+	for _, k := range all {
+		if k.TenantID == tenantID {
+			keys = append(keys, k)
+		}
+	}
+	return keys, nil
+}
+
+func (s *apiKeyService) IssueKey(ctx context.Context, name string, scopes []string) (apikey.Issued, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return apikey.Issued{}, fmt.Errorf("%w: name is required", apikey.ErrInvalidKey)
+	}
+	scopes, err := normalizeScopes(scopes)
+	if err != nil {
+		return apikey.Issued{}, err
+	}
+
+	random := make([]byte, secretBytes)
+	if _, err := rand.Read(random); err != nil {
+		return apikey.Issued{}, err
+	}
+	secret := apikey.SecretPrefix + base64.RawURLEncoding.EncodeToString(random)
+	k, err := s.repo.Create(apikey.Key{
+		TenantID:  tenant.FromContext(ctx),
+		Name:      name,
+		Prefix:    secret[:prefixLength],
+		Hash:      hash(secret),
+		Scopes:    scopes,
+		CreatedAt: s.now().UTC().Truncate(time.Second),
+	})
+	if err != nil {
+		return apikey.Issued{}, err
+	}
+	return apikey.Issued{Key: k, Secret: secret}, nil
+}
+
+// RevokeKey answers apikey.ErrNotFound for the keys of other tenants.
+func (s *apiKeyService) RevokeKey(ctx context.Context, id int) (apikey.Key, error) {
+	k, err := s.repo.GetByID(id)
+	if err != nil {
+		return apikey.Key{}, err
+	}
+	if k.TenantID != tenant.FromContext(ctx) {
+		return apikey.Key{}, apikey.ErrNotFound
+	}
+	if k.Revoked() {
+		return k, nil
+	}
+	revokedAt := s.now().UTC().Truncate(time.Second)
+	if err := s.repo.Revoke(id, revokedAt); err != nil {
+		return apikey.Key{}, err
+	}
+	k.RevokedAt = &revokedAt
+	return k, nil
+}
+
+// Verify writes the last use at most once per lastUsedPrecision, and a
+// failure to write it does not fail the request.
+func (s *apiKeyService) Verify(secret string) (apikey.Key, error) {
+	k, err := s.repo.GetByHash(hash(secret))
+	if err != nil {
+		return apikey.Key{}, err
+	}
+	if k.Revoked() {
+		return apikey.Key{}, apikey.ErrRevoked
+	}
+	now := s.now().UTC().Truncate(time.Second)
+	if k.LastUsedAt == nil || now.Sub(*k.LastUsedAt) >= lastUsedPrecision {
+		if err := s.repo.Touch(k.ID, now); err != nil {
+			log.Printf("apikey: recording use of key %d: %v", k.ID, err)
+		} else {
+			k.LastUsedAt = &now
+		}
+	}
+	return k, nil
+}
+
+// normalizeScopes checks the scopes of a new key and puts them into the
+// order of apikey.Scopes without duplicates.
+func normalizeScopes(scopes []string) ([]string, error) {
+	requested := make(map[string]bool)
+	for _, scope := range scopes {
+		requested[scope] = true
+	}
+	var normalized []string
+	for _, scope := range apikey.Scopes {
+		if requested[scope] {
+			normalized = append(normalized, scope)
+			delete(requested, scope)
+		}
+	}
+	for scope := range requested {
+		return nil, fmt.Errorf("%w: unknown scope %q", apikey.ErrInvalidKey, scope)
+	}
+	if len(normalized) == 0 {
+		return nil, fmt.Errorf("%w: at least one scope is required", apikey.ErrInvalidKey)
+	}
+	return normalized, nil
+}
+
+// hash is the SHA-256 of a secret. Secrets carry enough randomness that a
+// fast hash suffices and keys can be looked up by it.
+func hash(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,152 @@
+package apikey
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"task-api/internal/domain/apikey"
+	"task-api/internal/infrastructure/persistence/memory"
+	"task-api/internal/mocks"
+	"task-api/internal/tenant"
+)
+
+var testNow = time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+
+func newTestService(repo apikey.Repository) *apiKeyService {
+	s := NewAPIKeyService(repo).(*apiKeyService)
+	s.now = func() time.Time { return testNow }
+	return s
+}
+
+func Test_IssueKey(t *testing.T) {
+	service := newTestService(memory.NewInMemoryAPIKeyRepository())
+
+	tests := []struct {
+		TestCase string
+		Name     string
+		Scopes   []string
+		Expected []string
+		Error    string
+	}{
+		{
+			TestCase: "Scopes are ordered and deduplicated",
+			Name:     " CI ",
+			Scopes:   []string{apikey.ScopeTasksWrite, apikey.ScopeTasksRead, apikey.ScopeTasksWrite},
+			Expected: []string{apikey.ScopeTasksRead, apikey.ScopeTasksWrite},
+		},
+		{TestCase: "Missing name", Name: " ", Scopes: []string{apikey.ScopeTasksRead}, Error: "invalid api key: name is required"},
+		{TestCase: "Missing scopes", Name: "CI", Error: "invalid api key: at least one scope is required"},
+		{TestCase: "Unknown scope", Name: "CI", Scopes: []string{"tasks:admin"}, Error: `invalid api key: unknown scope "tasks:admin"`},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.TestCase, func(t *testing.T) {
+			issued, err := service.IssueKey(context.Background(), tc.Name, tc.Scopes)
+			if tc.Error != "" {
+				assert.EqualError(t, err, tc.Error)
+				assert.ErrorIs(t, err, apikey.ErrInvalidKey)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, "CI", issued.Key.Name)
+			assert.Equal(t, tc.Expected, issued.Key.Scopes)
+			assert.Equal(t, testNow, issued.Key.CreatedAt)
+			assert.Equal(t, tenant.Default, issued.Key.TenantID)
+			assert.True(t, strings.HasPrefix(issued.Secret, apikey.SecretPrefix))
+			assert.Equal(t, issued.Secret[:prefixLength], issued.Key.Prefix)
+			assert.NotContains(t, issued.Key.Hash, issued.Secret)
+		})
+	}
+}
+
+func Test_VerifyAndRevoke(t *testing.T) {
+	service := newTestService(memory.NewInMemoryAPIKeyRepository())
+	ctx := context.Background()
+	issued, err := service.IssueKey(ctx, "Cron", []string{apikey.ScopeTasksRead})
+	require.NoError(t, err)
+	other, err := service.IssueKey(ctx, "CI", []string{apikey.ScopeTasksRead})
+	require.NoError(t, err)
+	assert.NotEqual(t, issued.Secret, other.Secret)
+
+	k, err := service.Verify(issued.Secret)
+	assert.NoError(t, err)
+	assert.Equal(t, issued.Key.ID, k.ID)
+	assert.Equal(t, &testNow, k.LastUsedAt)
+
+	// Uses within lastUsedPrecision keep the recorded time.
+	firstUse := testNow
+	testNow = firstUse.Add(30 * time.Second)
+	k, err = service.Verify(issued.Secret)
+	assert.NoError(t, err)
+	assert.Equal(t, &firstUse, k.LastUsedAt)
+
+	testNow = firstUse.Add(2 * time.Minute)
+	k, err = service.Verify(issued.Secret)
+	assert.NoError(t, err)
+	assert.Equal(t, &testNow, k.LastUsedAt)
+
+	_, err = service.Verify(issued.Secret + "x")
+	assert.Equal(t, apikey.ErrNotFound, err)
+
+	revoked, err := service.RevokeKey(ctx, issued.Key.ID)
+	assert.NoError(t, err)
+	assert.True(t, revoked.Revoked())
+	_, err = service.Verify(issued.Secret)
+	assert.Equal(t, apikey.ErrRevoked, err)
+
+	testNow = testNow.Add(time.Hour)
+	again, err := service.RevokeKey(ctx, issued.Key.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, revoked.RevokedAt, again.RevokedAt)
+
+	_, err = service.RevokeKey(ctx, 99)
+	assert.Equal(t, apikey.ErrNotFound, err)
+
+	_, err = service.Verify(other.Secret)
+	assert.NoError(t, err)
+}
+
+func Test_KeysOfTenant(t *testing.T) {
+	service := newTestService(memory.NewInMemoryAPIKeyRepository())
+	acme := tenant.NewContext(context.Background(), "acme")
+	globex := tenant.NewContext(context.Background(), "globex")
+
+	issued, err := service.IssueKey(acme, "CI", []string{apikey.ScopeTasksRead})
+	require.NoError(t, err)
+	assert.Equal(t, "acme", issued.Key.TenantID)
+
+	keys, err := service.ListKeys(acme)
+	assert.NoError(t, err)
+	assert.Len(t, keys, 1)
+	keys, err = service.ListKeys(globex)
+	assert.NoError(t, err)
+	assert.Empty(t, keys)
+
+	_, err = service.RevokeKey(globex, issued.Key.ID)
+	assert.Equal(t, apikey.ErrNotFound, err)
+	k, err := service.Verify(issued.Secret)
+	assert.NoError(t, err)
+	assert.Equal(t, "acme", k.TenantID)
+}
+
+func Test_VerifyIgnoresTouchFailures(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockAPIKeyRepository(ctrl)
+	service := newTestService(mockRepo)
+
+	mockRepo.EXPECT().GetByHash(hash("tk_secret")).Return(apikey.Key{ID: 1, Scopes: []string{apikey.ScopeTasksRead}}, nil)
+	mockRepo.EXPECT().Touch(1, testNow).Return(errors.New("storage unavailable"))
+
+	k, err := service.Verify("tk_secret")
+	assert.NoError(t, err)
+	assert.Nil(t, k.LastUsedAt)
+}
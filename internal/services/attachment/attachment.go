@@ -0,0 +1,23 @@
+package attachment
+
+import (
+	"context"
+	"io"
+
+	"task-api/internal/domain/attachment"
+	"task-api/internal/domain/task"
+)
+
+// Service reaches attachments through their task, so only the attachments of
+// tasks in the tenant of the context can be read or changed.
+type Service interface {
+	ListAttachments(ctx context.Context, taskID int) ([]attachment.Attachment, error)
+	// Upload stores the content read from r as a new attachment of a task.
+	Upload(ctx context.Context, taskID int, uploader, filename string, r io.Reader) (attachment.Attachment, error)
+	// Open returns an attachment together with its content, which the caller
+	// must close.
+	Open(ctx context.Context, taskID, id int) (attachment.Attachment, io.ReadSeekCloser, error)
+	DeleteAttachment(ctx context.Context, taskID, id int) error
+	// TaskDeleted removes the attachments of a deleted task and their blobs.
+	task.DeleteHook
+}
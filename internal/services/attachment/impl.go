@@ -0,0 +1,233 @@
+package attachment
+
+import (
+	"bufio"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"task-api/internal/config"
+	"task-api/internal/domain/attachment"
+	"task-api/internal/domain/role"
+	"task-api/internal/domain/task"
+)
+
+// sniffLength is the number of leading bytes http.DetectContentType looks at.
+const sniffLength = 512
+
+const maxFilenameLength = 255
+
+type attachmentService struct {
+	repo         attachment.Repository
+	tasks        task.Repository
+	policy       role.Policy
+	blobs        attachment.BlobStore
+	maxSize      int64
+	allowedTypes []string
+	now          func() time.Time
+}
+
+func NewAttachmentService(repo attachment.Repository, tasks task.Repository, policy role.Policy, blobs attachment.BlobStore, cfg config.AttachmentConfig) Service {
+	return &attachmentService{
+		repo:         repo,
+		tasks:        tasks,
+		policy:       policy,
+		blobs:        blobs,
+		maxSize:      cfg.MaxSize,
+		allowedTypes: cfg.AllowedTypes,
+		now:          time.Now,
+	}
+}
+
+func (s *attachmentService) ListAttachments(ctx context.Context, taskID int) ([]attachment.Attachment, error) {
+	if err := s.authorize(ctx, role.ActionRead, taskID); err != nil {
+		return nil, err
+	}
+	attachments, err := s.repo.ListByTask(taskID)
+	if err != nil {
+		return nil, err
+	}
+	if attachments == nil {
+		attachments = []attachment.Attachment{}
+	}
+	return attachments, nil
+}
+
+// Upload detects the content type from the content itself rather than
+// trusting the client, and streams the content into the blob store while
+// hashing it, so the upload is never held in memory as a whole.
+func (s *attachmentService) Upload(ctx context.Context, taskID int, uploader, filename string, r io.Reader) (attachment.Attachment, error) {
+	if err := s.authorize(ctx, role.ActionUpdate, taskID); err != nil {
+		return attachment.Attachment{}, err
+	}
+
+	buffered := bufio.NewReaderSize(r, sniffLength)
+	head, err := buffered.Peek(sniffLength)
+	if err != nil && err != io.EOF {
+		return attachment.Attachment{}, err
+	}
+	if len(head) == 0 {
+		return attachment.Attachment{}, attachment.ErrEmpty
+	}
+	contentType := http.DetectContentType(head)
+	if !s.allowed(contentType) {
+		return attachment.Attachment{}, fmt.Errorf("%w: %s", attachment.ErrTypeNotAllowed, contentType)
+	}
+
+	key, err := newKey(taskID)
+	if err != nil {
+		return attachment.Attachment{}, err
+	}
+	hash := sha256.New()
+	// Reading one byte past the limit tells an upload of exactly the
+	// maximum size apart from a larger one.
+	limited := &io.LimitedReader{R: io.TeeReader(buffered, hash), N: s.maxSize + 1}
+	size, err := s.blobs.Put(key, limited)
+	if err != nil {
+		return attachment.Attachment{}, err
+	}
+	if size > s.maxSize {
+		s.deleteBlob(key)
+		return attachment.Attachment{}, attachment.ErrTooLarge
+	}
+
+	created, err := s.repo.Create(attachment.Attachment{
+		TaskID:      taskID,
+		Filename:    cleanFilename(filename),
+		ContentType: contentType,
+		Size:        size,
+		Checksum:    hex.EncodeToString(hash.Sum(nil)),
+		Uploader:    uploader,
+		CreatedAt:   s.now().UTC(),
+		StorageKey:  key,
+	})
+	if err != nil {
+		s.deleteBlob(key)
+		return attachment.Attachment{}, err
+	}
+	return created, nil
+}
+
+func (s *attachmentService) Open(ctx context.Context, taskID, id int) (attachment.Attachment, io.ReadSeekCloser, error) {
+	a, err := s.get(ctx, role.ActionRead, taskID, id)
+	if err != nil {
+		return attachment.Attachment{}, nil, err
+	}
+	content, err := s.blobs.Open(a.StorageKey)
+	if err != nil {
+		return attachment.Attachment{}, nil, err
+	}
+	return a, content, nil
+}
+
+func (s *attachmentService) DeleteAttachment(ctx context.Context, taskID, id int) error {
+	a, err := s.get(ctx, role.ActionUpdate, taskID, id)
+	if err != nil {
+		return err
+	}
+	if err := s.repo.Delete(id); err != nil {
+		return err
+	}
+	return s.blobs.Delete(a.StorageKey)
+}
+
+func (s *attachmentService) TaskDeleted(id int) error {
+	attachments, err := s.repo.ListByTask(id)
+	if err != nil {
+		return err
+	}
+	for _, a := range attachments {
+		if err := s.blobs.Delete(a.StorageKey); err != nil {
+			return err
+		}
+		if err := s.repo.Delete(a.ID); err != nil && err != attachment.ErrNotFound {
+			return err
+		}
+	}
+	return nil
+}
+
+// authorize loads the task and checks the caller's role on its project.
+// Uploading and deleting attachments count as updating the task.
+func (s *attachmentService) authorize(ctx context.Context, action role.Action, taskID int) error {
+	t, err := s.tasks.GetByID(ctx, taskID)
+	if err != nil {
+		return err
+	}
+	return s.policy.Authorize(ctx, action, t.ProjectID)
+}
+
+// get loads an attachment and makes sure it belongs to the given task, the
+// task to the tenant of ctx, and that the caller may act on it.
+func (s *attachmentService) get(ctx context.Context, action role.Action, taskID, id int) (attachment.Attachment, error) {
+	if err := s.authorize(ctx, action, taskID); err != nil {
+		return attachment.Attachment{}, err
+	}
+	a, err := s.repo.GetByID(id)
+	if err != nil {
+		return attachment.Attachment{}, err
+	}
+	if a.TaskID != taskID {
+		return attachment.Attachment{}, attachment.ErrNotFound
+	}
+	return a, nil
+}
+
+// allowed matches a detected content type against the configured types,
+// which may end in "/*" to allow a whole family. No configured types allows
+// everything.
+func (s *attachmentService) allowed(contentType string) bool {
+	if len(s.allowedTypes) == 0 {
+		return true
+	}
+	mediaType := contentType
+	if i := strings.IndexByte(mediaType, ';'); i >= 0 {
+		mediaType = mediaType[:i]
+	}
+	mediaType = strings.TrimSpace(mediaType)
+	for _, allowed := range s.allowedTypes {
+		if allowed == mediaType {
+			return true
+		}
+		if strings.HasSuffix(allowed, "/*") && strings.HasPrefix(mediaType, strings.TrimSuffix(allowed, "*")) {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *attachmentService) deleteBlob(key string) {
+	if err := s.blobs.Delete(key); err != nil {
+		log.Printf("attachment: failed to delete blob %s: %v", key, err)
+	}
+}
+
+func newKey(taskID int) (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("tasks/%d/%s", taskID, hex.EncodeToString(b)), nil
+}
+
+// cleanFilename keeps only the last element of the client supplied name,
+// whichever path separator the client used.
+func cleanFilename(name string) string {
+	name = filepath.Base(strings.ReplaceAll(name, "\\", "/"))
+	name = strings.TrimSpace(name)
+	if name == "." || name == "/" || name == "" {
+		return "attachment"
+	}
+	if len(name) > maxFilenameLength {
+		name = name[len(name)-maxFilenameLength:]
+	}
+	return name
+}
@@ -0,0 +1,335 @@
+package attachment
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"task-api/internal/config"
+	"task-api/internal/domain/attachment"
+	"task-api/internal/domain/role"
+	"task-api/internal/domain/task"
+	"task-api/internal/infrastructure/blob"
+	"task-api/internal/mocks"
+)
+
+var testNow = time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+
+var pngHeader = []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR")
+
+// allowAll lets every caller do anything, for the tests that are not about
+// roles.
+type allowAll struct{}
+
+func (allowAll) Authorize(context.Context, role.Action, int) error {
+	return nil
+}
+
+func newTestService(t *testing.T, repo attachment.Repository, tasks task.Repository, policy role.Policy, allowedTypes []string) (*attachmentService, attachment.BlobStore) {
+	cfg := config.AttachmentConfig{Dir: t.TempDir(), MaxSize: 16, AllowedTypes: allowedTypes}
+	blobs, err := blob.NewFilesystemStore(cfg)
+	assert.NoError(t, err)
+	s := NewAttachmentService(repo, tasks, policy, blobs, cfg).(*attachmentService)
+	s.now = func() time.Time { return testNow }
+	return s, blobs
+}
+
+func Test_Upload(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockAttachmentRepository(ctrl)
+	mockTasks := mocks.NewMockRepository(ctrl)
+	service, blobs := newTestService(t, mockRepo, mockTasks, allowAll{}, []string{"text/plain", "image/*"})
+
+	tests := []struct {
+		TestCase string
+		Filename string
+		Content  []byte
+		Expected attachment.Attachment
+		Error    error
+		Setup    func()
+	}{
+		{
+			TestCase: "Upload text",
+			Filename: `C:\Users\alice\notes.txt`,
+			Content:  []byte("hello"),
+			Expected: attachment.Attachment{
+				TaskID:      1,
+				Filename:    "notes.txt",
+				ContentType: "text/plain; charset=utf-8",
+				Size:        5,
+				Checksum:    "2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824",
+				Uploader:    "alice",
+				CreatedAt:   testNow,
+			},
+			Setup: func() {
+				mockTasks.EXPECT().GetByID(gomock.Any(), 1).Return(task.Info{ID: 1}, nil)
+				mockRepo.EXPECT().Create(gomock.Any()).DoAndReturn(func(a attachment.Attachment) (attachment.Attachment, error) {
+					return a, nil
+				})
+			},
+		},
+		{
+			TestCase: "Content type is sniffed, not taken from the name",
+			Filename: "../../screenshot.txt",
+			Content:  pngHeader,
+			Expected: attachment.Attachment{
+				TaskID:      1,
+				Filename:    "screenshot.txt",
+				ContentType: "image/png",
+				Size:        int64(len(pngHeader)),
+				Checksum:    "02a3e298f1533f62558c58e4c70edcab9af5a50d62d925fd5390942020fb0fb8",
+				Uploader:    "alice",
+				CreatedAt:   testNow,
+			},
+			Setup: func() {
+				mockTasks.EXPECT().GetByID(gomock.Any(), 1).Return(task.Info{ID: 1}, nil)
+				mockRepo.EXPECT().Create(gomock.Any()).DoAndReturn(func(a attachment.Attachment) (attachment.Attachment, error) {
+					return a, nil
+				})
+			},
+		},
+		{
+			TestCase: "Type not allowed",
+			Filename: "doc.pdf",
+			Content:  []byte("%PDF-1.7\n"),
+			Error:    attachment.ErrTypeNotAllowed,
+			Setup: func() {
+				mockTasks.EXPECT().GetByID(gomock.Any(), 1).Return(task.Info{ID: 1}, nil)
+			},
+		},
+		{
+			TestCase: "Too large",
+			Filename: "big.txt",
+			Content:  bytes.Repeat([]byte("a"), 17),
+			Error:    attachment.ErrTooLarge,
+			Setup: func() {
+				mockTasks.EXPECT().GetByID(gomock.Any(), 1).Return(task.Info{ID: 1}, nil)
+			},
+		},
+		{
+			TestCase: "Empty",
+			Filename: "empty.txt",
+			Error:    attachment.ErrEmpty,
+			Setup: func() {
+				mockTasks.EXPECT().GetByID(gomock.Any(), 1).Return(task.Info{ID: 1}, nil)
+			},
+		},
+		{
+			TestCase: "Task not found",
+			Filename: "notes.txt",
+			Content:  []byte("hello"),
+			Error:    task.ErrNotFound,
+			Setup: func() {
+				mockTasks.EXPECT().GetByID(gomock.Any(), 1).Return(task.Info{}, task.ErrNotFound)
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.TestCase, func(t *testing.T) {
+			tc.Setup()
+			result, err := service.Upload(context.Background(), 1, "alice", tc.Filename, bytes.NewReader(tc.Content))
+			if tc.Error != nil {
+				assert.ErrorIs(t, err, tc.Error)
+				return
+			}
+			assert.NoError(t, err)
+
+			content, err := blobs.Open(result.StorageKey)
+			assert.NoError(t, err)
+			stored, _ := io.ReadAll(content)
+			content.Close()
+			assert.Equal(t, tc.Content, stored)
+			assert.True(t, strings.HasPrefix(result.StorageKey, "tasks/1/"))
+
+			result.StorageKey = ""
+			assert.Equal(t, tc.Expected, result)
+		})
+	}
+}
+
+func Test_OpenAndDeleteAttachment(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockAttachmentRepository(ctrl)
+	mockTasks := mocks.NewMockRepository(ctrl)
+	service, blobs := newTestService(t, mockRepo, mockTasks, allowAll{}, nil)
+	_, err := blobs.Put("tasks/1/abc", strings.NewReader("hello"))
+	assert.NoError(t, err)
+	existing := attachment.Attachment{ID: 3, TaskID: 1, StorageKey: "tasks/1/abc"}
+
+	tests := []struct {
+		TestCase string
+		Run      func() error
+		Error    error
+		Setup    func()
+	}{
+		{
+			TestCase: "Open through another task",
+			Run: func() error {
+				_, _, err := service.Open(context.Background(), 2, 3)
+				return err
+			},
+			Error: attachment.ErrNotFound,
+			Setup: func() {
+				mockTasks.EXPECT().GetByID(gomock.Any(), 2).Return(task.Info{ID: 2}, nil)
+				mockRepo.EXPECT().GetByID(3).Return(existing, nil)
+			},
+		},
+		{
+			TestCase: "Open an attachment of a task in another tenant",
+			Run: func() error {
+				_, _, err := service.Open(context.Background(), 1, 3)
+				return err
+			},
+			Error: task.ErrNotFound,
+			Setup: func() {
+				mockTasks.EXPECT().GetByID(gomock.Any(), 1).Return(task.Info{}, task.ErrNotFound)
+			},
+		},
+		{
+			TestCase: "Open",
+			Run: func() error {
+				_, content, err := service.Open(context.Background(), 1, 3)
+				if err != nil {
+					return err
+				}
+				defer content.Close()
+				b, _ := io.ReadAll(content)
+				assert.Equal(t, "hello", string(b))
+				return nil
+			},
+			Setup: func() {
+				mockTasks.EXPECT().GetByID(gomock.Any(), 1).Return(task.Info{ID: 1}, nil)
+				mockRepo.EXPECT().GetByID(3).Return(existing, nil)
+			},
+		},
+		{
+			TestCase: "Delete removes the blob",
+			Run: func() error {
+				if err := service.DeleteAttachment(context.Background(), 1, 3); err != nil {
+					return err
+				}
+				_, err := blobs.Open("tasks/1/abc")
+				return err
+			},
+			Error: attachment.ErrBlobNotFound,
+			Setup: func() {
+				mockTasks.EXPECT().GetByID(gomock.Any(), 1).Return(task.Info{ID: 1}, nil)
+				mockRepo.EXPECT().GetByID(3).Return(existing, nil)
+				mockRepo.EXPECT().Delete(3).Return(nil)
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.TestCase, func(t *testing.T) {
+			tc.Setup()
+			assert.Equal(t, tc.Error, tc.Run())
+		})
+	}
+}
+
+func Test_TaskDeleted(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockAttachmentRepository(ctrl)
+	mockTasks := mocks.NewMockRepository(ctrl)
+	service, blobs := newTestService(t, mockRepo, mockTasks, allowAll{}, nil)
+	for _, key := range []string{"tasks/1/a", "tasks/1/b"} {
+		_, err := blobs.Put(key, strings.NewReader("x"))
+		assert.NoError(t, err)
+	}
+
+	mockRepo.EXPECT().ListByTask(1).Return([]attachment.Attachment{
+		{ID: 1, TaskID: 1, StorageKey: "tasks/1/a"},
+		{ID: 2, TaskID: 1, StorageKey: "tasks/1/b"},
+	}, nil)
+	mockRepo.EXPECT().Delete(1).Return(nil)
+	mockRepo.EXPECT().Delete(2).Return(nil)
+
+	assert.NoError(t, service.TaskDeleted(1))
+	for _, key := range []string{"tasks/1/a", "tasks/1/b"} {
+		_, err := blobs.Open(key)
+		assert.Equal(t, attachment.ErrBlobNotFound, err)
+	}
+}
+
+func Test_Authorization(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockAttachmentRepository(ctrl)
+	mockTasks := mocks.NewMockRepository(ctrl)
+	mockPolicy := mocks.NewMockPolicy(ctrl)
+	service, blobs := newTestService(t, mockRepo, mockTasks, mockPolicy, nil)
+	_, err := blobs.Put("tasks/1/abc", strings.NewReader("hello"))
+	assert.NoError(t, err)
+
+	ctx := context.Background()
+	denied := fmt.Errorf("%w: viewer may not update tasks", role.ErrForbidden)
+
+	tests := []struct {
+		TestCase string
+		Run      func() error
+		Error    error
+		Setup    func()
+	}{
+		{
+			TestCase: "Viewers may not upload",
+			Run: func() error {
+				_, err := service.Upload(ctx, 1, "alice", "notes.txt", strings.NewReader("hello"))
+				return err
+			},
+			Error: denied,
+			Setup: func() {
+				mockTasks.EXPECT().GetByID(gomock.Any(), 1).Return(task.Info{ID: 1, ProjectID: 4}, nil)
+				mockPolicy.EXPECT().Authorize(ctx, role.ActionUpdate, 4).Return(denied)
+			},
+		},
+		{
+			TestCase: "Denied delete keeps the blob",
+			Run: func() error {
+				if err := service.DeleteAttachment(ctx, 1, 3); err != denied {
+					return err
+				}
+				_, err := blobs.Open("tasks/1/abc")
+				return err
+			},
+			Setup: func() {
+				mockTasks.EXPECT().GetByID(gomock.Any(), 1).Return(task.Info{ID: 1, ProjectID: 4}, nil)
+				mockPolicy.EXPECT().Authorize(ctx, role.ActionUpdate, 4).Return(denied)
+			},
+		},
+		{
+			TestCase: "Downloads need read access",
+			Run: func() error {
+				_, _, err := service.Open(ctx, 1, 3)
+				return err
+			},
+			Error: denied,
+			Setup: func() {
+				mockTasks.EXPECT().GetByID(gomock.Any(), 1).Return(task.Info{ID: 1, ProjectID: 4}, nil)
+				mockPolicy.EXPECT().Authorize(ctx, role.ActionRead, 4).Return(denied)
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.TestCase, func(t *testing.T) {
+			tc.Setup()
+			assert.Equal(t, tc.Error, tc.Run())
+		})
+	}
+}
@@ -0,0 +1,14 @@
+package audit
+
+import (
+	"context"
+
+	"task-api/internal/domain/audit"
+)
+
+type Service interface {
+	audit.Recorder
+	// GetRecords returns the records of the caller's tenant matching f,
+	// oldest first.
+	GetRecords(ctx context.Context, f audit.Filter) ([]audit.Record, error)
+}
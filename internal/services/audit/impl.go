@@ -0,0 +1,108 @@
+package audit
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"task-api/internal/auth"
+	"task-api/internal/domain/audit"
+	"task-api/internal/domain/task"
+	"task-api/internal/requestid"
+	"task-api/internal/tenant"
+)
+
+type auditService struct {
+	repo audit.Repository
+	now  func() time.Time
+}
+
+func NewAuditService(repo audit.Repository) Service {
+	return &auditService{repo: repo, now: time.Now}
+}
+
+// Record takes the tenant, actor and request ID from ctx. Times are kept to the
+// microsecond, which is what MySQL stores.
+func (s *auditService) Record(ctx context.Context, op audit.Operation, before, after *task.Info) error {
+	changes, err := diff(before, after)
+	if err != nil {
+		return err
+	}
+	rec := audit.Record{
+		TenantID:  tenant.FromContext(ctx),
+		Time:      s.now().UTC().Truncate(time.Microsecond),
+		RequestID: requestid.FromContext(ctx),
+		Operation: op,
+		Changes:   changes,
+	}
+	if identity, ok := auth.FromContext(ctx); ok {
+		rec.Actor = identity.Subject
+	}
+	if after != nil {
+		rec.TaskID = after.ID
+	} else if before != nil {
+		rec.TaskID = before.ID
+	}
+	_, err = s.repo.Append(ctx, rec)
+	return err
+}
+
+// GetRecords only returns the records of the tenant carried by ctx, whatever
+// f says.
+func (s *auditService) GetRecords(ctx context.Context, f audit.Filter) ([]audit.Record, error) {
+	if f.TaskID < 0 {
+		return nil, fmt.Errorf("%w: task_id must be positive", audit.ErrInvalidFilter)
+	}
+	if !f.From.IsZero() && !f.To.IsZero() && f.From.After(f.To) {
+		return nil, fmt.Errorf("%w: from is after to", audit.ErrInvalidFilter)
+	}
+	f.TenantID = tenant.FromContext(ctx)
+	return s.repo.Find(f)
+}
+
+// diff compares the stored fields of two versions of a task by their JSON
+// values. Progress and labels are computed on read and left out, and so is
+// the ID, which the record carries.
+func diff(before, after *task.Info) (map[string]audit.Change, error) {
+	was, err := fields(before)
+	if err != nil {
+		return nil, err
+	}
+	is, err := fields(after)
+	if err != nil {
+		return nil, err
+	}
+	changes := make(map[string]audit.Change)
+	for field, value := range was {
+		if !bytes.Equal(value, is[field]) {
+			changes[field] = audit.Change{Before: value, After: is[field]}
+		}
+	}
+	for field, value := range is {
+		if _, ok := was[field]; !ok {
+			changes[field] = audit.Change{After: value}
+		}
+	}
+	return changes, nil
+}
+
+func fields(t *task.Info) (map[string]json.RawMessage, error) {
+	if t == nil {
+		return nil, nil
+	}
+	stored := *t
+	stored.Progress = nil
+	stored.Labels = nil
+	data, err := json.Marshal(stored)
+	if err != nil {
+		return nil, err
+	}
+	var values map[string]json.RawMessage
+	if err := json.Unmarshal(data, &values); err != nil {
+		return nil, err
+	}
+	delete(values, "id")
+	return values, nil
+}
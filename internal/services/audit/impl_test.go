@@ -0,0 +1,167 @@
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"task-api/internal/auth"
+	"task-api/internal/domain/audit"
+	"task-api/internal/domain/task"
+	"task-api/internal/infrastructure/persistence/memory"
+	"task-api/internal/mocks"
+	"task-api/internal/requestid"
+	"task-api/internal/tenant"
+)
+
+var testNow = time.Date(2026, 3, 1, 9, 0, 0, 123456789, time.UTC)
+
+func newTestService(repo audit.Repository) *auditService {
+	s := NewAuditService(repo).(*auditService)
+	s.now = func() time.Time { return testNow }
+	return s
+}
+
+func Test_Record(t *testing.T) {
+	ctx := requestid.NewContext(auth.NewContext(context.Background(), auth.Identity{Subject: "alice"}), "req-1")
+	assignee := 2
+	draft := task.Info{ID: 7, Name: "Draft", ProjectID: 1, Progress: &task.Progress{Done: 1, Total: 2}, Labels: []string{"ops"}}
+	final := task.Info{ID: 7, Name: "Final", Status: task.StatusDone, ProjectID: 1, AssigneeID: &assignee}
+
+	tests := []struct {
+		TestCase  string
+		Operation audit.Operation
+		Before    *task.Info
+		After     *task.Info
+		Expected  string
+	}{
+		{
+			TestCase:  "Create lists the fields set",
+			Operation: audit.OperationCreate,
+			After:     &draft,
+			Expected: `{"name":{"before":null,"after":"Draft"},"status":{"before":null,"after":0},
+				"project_id":{"before":null,"after":1}}`,
+		},
+		{
+			TestCase:  "Update lists the fields changed",
+			Operation: audit.OperationUpdate,
+			Before:    &draft,
+			After:     &final,
+			Expected: `{"name":{"before":"Draft","after":"Final"},"status":{"before":0,"after":1},
+				"assignee_id":{"before":null,"after":2}}`,
+		},
+		{
+			TestCase:  "Update without changes",
+			Operation: audit.OperationUpdate,
+			Before:    &final,
+			After:     &final,
+			Expected:  `{}`,
+		},
+		{
+			TestCase:  "Delete lists the fields the task had",
+			Operation: audit.OperationDelete,
+			Before:    &final,
+			Expected: `{"name":{"before":"Final","after":null},"status":{"before":1,"after":null},
+				"project_id":{"before":1,"after":null},"assignee_id":{"before":2,"after":null}}`,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.TestCase, func(t *testing.T) {
+			repo := memory.NewInMemoryAuditRepository()
+			service := newTestService(repo)
+
+			err := service.Record(ctx, tc.Operation, tc.Before, tc.After)
+			assert.NoError(t, err)
+
+			records, err := service.GetRecords(ctx, audit.Filter{})
+			assert.NoError(t, err)
+			if assert.Len(t, records, 1) {
+				rec := records[0]
+				assert.Equal(t, 7, rec.TaskID)
+				assert.Equal(t, "alice", rec.Actor)
+				assert.Equal(t, "req-1", rec.RequestID)
+				assert.Equal(t, testNow.Truncate(time.Microsecond), rec.Time)
+				assert.Equal(t, tc.Operation, rec.Operation)
+				changes, err := json.Marshal(rec.Changes)
+				assert.NoError(t, err)
+				assert.JSONEq(t, tc.Expected, string(changes))
+			}
+		})
+	}
+}
+
+func Test_RecordFails(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mocks.NewMockAuditRepository(ctrl)
+	repo.EXPECT().Append(gomock.Any(), gomock.Any()).Return(audit.Record{}, errors.New("disk full"))
+
+	err := newTestService(repo).Record(context.Background(), audit.OperationDelete, &task.Info{ID: 1}, nil)
+	assert.EqualError(t, err, "disk full")
+}
+
+func Test_GetRecords(t *testing.T) {
+	repo := memory.NewInMemoryAuditRepository()
+	service := newTestService(repo)
+	_, err := repo.Append(context.Background(), audit.Record{TenantID: tenant.Default, TaskID: 1, Actor: "alice", Time: testNow})
+	assert.NoError(t, err)
+	_, err = repo.Append(context.Background(), audit.Record{TenantID: tenant.Default, TaskID: 2, Actor: "bob", Time: testNow})
+	assert.NoError(t, err)
+
+	tests := []struct {
+		TestCase string
+		Filter   audit.Filter
+		Expected int
+		Error    string
+	}{
+		{TestCase: "Filter by actor", Filter: audit.Filter{Actor: "bob"}, Expected: 1},
+		{TestCase: "Negative task", Filter: audit.Filter{TaskID: -1}, Error: "invalid audit filter: task_id must be positive"},
+		{
+			TestCase: "From after to",
+			Filter:   audit.Filter{From: testNow, To: testNow.Add(-time.Hour)},
+			Error:    "invalid audit filter: from is after to",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.TestCase, func(t *testing.T) {
+			records, err := service.GetRecords(context.Background(), tc.Filter)
+			if tc.Error != "" {
+				assert.EqualError(t, err, tc.Error)
+				assert.ErrorIs(t, err, audit.ErrInvalidFilter)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Len(t, records, tc.Expected)
+		})
+	}
+}
+
+func Test_GetRecordsOfTenant(t *testing.T) {
+	service := newTestService(memory.NewInMemoryAuditRepository())
+	alice := auth.NewContext(context.Background(), auth.Identity{Subject: "alice"})
+	acme := tenant.NewContext(alice, "acme")
+	globex := tenant.NewContext(alice, "globex")
+
+	err := service.Record(acme, audit.OperationCreate, nil, &task.Info{ID: 7, Name: "Draft", ProjectID: 1})
+	assert.NoError(t, err)
+
+	records, err := service.GetRecords(acme, audit.Filter{TaskID: 7})
+	assert.NoError(t, err)
+	assert.Len(t, records, 1)
+
+	// Not even asking for the task by ID reaches another tenant's history.
+	records, err = service.GetRecords(globex, audit.Filter{TaskID: 7})
+	assert.NoError(t, err)
+	assert.Empty(t, records)
+	records, err = service.GetRecords(globex, audit.Filter{TenantID: "acme"})
+	assert.NoError(t, err)
+	assert.Empty(t, records)
+}
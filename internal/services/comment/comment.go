@@ -0,0 +1,22 @@
+package comment
+
+import (
+	"context"
+
+	"task-api/internal/domain/comment"
+	"task-api/internal/domain/task"
+)
+
+// Service reaches comments through their task, so only the comments of tasks
+// in the tenant of the context can be read or changed.
+type Service interface {
+	// TaskDeleted removes the comments of a deleted task.
+	task.DeleteHook
+
+	ListComments(ctx context.Context, taskID int, parentID *int, offset, limit int) (comment.Page, error)
+	CreateComment(ctx context.Context, c comment.Comment) (comment.Comment, error)
+	// UpdateComment changes the body of a comment written by author.
+	UpdateComment(ctx context.Context, author string, c comment.Comment) (comment.Comment, error)
+	// DeleteComment removes a comment written by author and its replies.
+	DeleteComment(ctx context.Context, author string, taskID, id int) error
+}
@@ -0,0 +1,158 @@
+package comment
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"task-api/internal/domain/comment"
+	"task-api/internal/domain/role"
+	"task-api/internal/domain/task"
+)
+
+const (
+	DefaultPageSize = 20
+	MaxPageSize     = 100
+	maxBodyLength   = 10000
+)
+
+type commentService struct {
+	repo   comment.Repository
+	tasks  task.Repository
+	policy role.Policy
+	now    func() time.Time
+}
+
+func NewCommentService(repo comment.Repository, tasks task.Repository, policy role.Policy) Service {
+	return &commentService{repo: repo, tasks: tasks, policy: policy, now: time.Now}
+}
+
+func (s *commentService) ListComments(ctx context.Context, taskID int, parentID *int, offset, limit int) (comment.Page, error) {
+	if err := s.authorize(ctx, role.ActionRead, taskID); err != nil {
+		return comment.Page{}, err
+	}
+	if parentID != nil {
+		if _, err := s.get(taskID, *parentID); err != nil {
+			return comment.Page{}, err
+		}
+	}
+
+	if offset < 0 {
+		offset = 0
+	}
+	if limit <= 0 {
+		limit = DefaultPageSize
+	}
+	if limit > MaxPageSize {
+		limit = MaxPageSize
+	}
+
+	items, total, err := s.repo.List(taskID, parentID, offset, limit)
+	if err != nil {
+		return comment.Page{}, err
+	}
+	if items == nil {
+		items = []comment.Comment{}
+	}
+	return comment.Page{Items: items, Total: total, Offset: offset, Limit: limit}, nil
+}
+
+func (s *commentService) CreateComment(ctx context.Context, c comment.Comment) (comment.Comment, error) {
+	body, err := validateBody(c.Body)
+	if err != nil {
+		return comment.Comment{}, err
+	}
+	if err := s.authorize(ctx, role.ActionUpdate, c.TaskID); err != nil {
+		return comment.Comment{}, err
+	}
+	if c.ParentID != nil {
+		if _, err := s.get(c.TaskID, *c.ParentID); err != nil {
+			if err == comment.ErrNotFound {
+				return comment.Comment{}, comment.ErrParentNotFound
+			}
+			return comment.Comment{}, err
+		}
+	}
+
+	now := s.now().UTC()
+	c.ID = 0
+	c.Body = body
+	c.ReplyCount = 0
+	c.CreatedAt = now
+	c.UpdatedAt = now
+	return s.repo.Create(c)
+}
+
+func (s *commentService) UpdateComment(ctx context.Context, author string, c comment.Comment) (comment.Comment, error) {
+	body, err := validateBody(c.Body)
+	if err != nil {
+		return comment.Comment{}, err
+	}
+	if err := s.authorize(ctx, role.ActionUpdate, c.TaskID); err != nil {
+		return comment.Comment{}, err
+	}
+	existing, err := s.get(c.TaskID, c.ID)
+	if err != nil {
+		return comment.Comment{}, err
+	}
+	if existing.Author != author {
+		return comment.Comment{}, comment.ErrForbidden
+	}
+
+	existing.Body = body
+	existing.UpdatedAt = s.now().UTC()
+	return s.repo.Update(existing)
+}
+
+func (s *commentService) DeleteComment(ctx context.Context, author string, taskID, id int) error {
+	if err := s.authorize(ctx, role.ActionUpdate, taskID); err != nil {
+		return err
+	}
+	existing, err := s.get(taskID, id)
+	if err != nil {
+		return err
+	}
+	if existing.Author != author {
+		return comment.ErrForbidden
+	}
+	return s.repo.Delete(id)
+}
+
+func (s *commentService) TaskDeleted(id int) error {
+	return s.repo.DeleteByTask(id)
+}
+
+// authorize loads the task and checks the caller's role on its project.
+// Writing comments counts as updating the task, so viewers can only read.
+func (s *commentService) authorize(ctx context.Context, action role.Action, taskID int) error {
+	t, err := s.tasks.GetByID(ctx, taskID)
+	if err != nil {
+		return err
+	}
+	return s.policy.Authorize(ctx, action, t.ProjectID)
+}
+
+// get loads a comment and makes sure it belongs to the given task, so
+// comments cannot be reached through the URL of another task.
+func (s *commentService) get(taskID, id int) (comment.Comment, error) {
+	c, err := s.repo.GetByID(id)
+	if err != nil {
+		return comment.Comment{}, err
+	}
+	if c.TaskID != taskID {
+		return comment.Comment{}, comment.ErrNotFound
+	}
+	return c, nil
+}
+
+func validateBody(body string) (string, error) {
+	body = strings.TrimSpace(body)
+	if body == "" {
+		return "", fmt.Errorf("%w: body is required", comment.ErrInvalidComment)
+	}
+	if len(body) > maxBodyLength {
+		return "", fmt.Errorf("%w: body is longer than %d characters", comment.ErrInvalidComment, maxBodyLength)
+	}
+	return body, nil
+}
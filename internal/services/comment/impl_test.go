@@ -0,0 +1,311 @@
+package comment
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"task-api/internal/domain/comment"
+	"task-api/internal/domain/role"
+	"task-api/internal/domain/task"
+	"task-api/internal/mocks"
+)
+
+var testNow = time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+
+// allowAll lets every caller do anything, for the tests that are not about
+// roles.
+type allowAll struct{}
+
+func (allowAll) Authorize(context.Context, role.Action, int) error {
+	return nil
+}
+
+func newTestService(repo comment.Repository, tasks task.Repository, policy role.Policy) *commentService {
+	s := NewCommentService(repo, tasks, policy).(*commentService)
+	s.now = func() time.Time { return testNow }
+	return s
+}
+
+func Test_CreateComment(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockCommentRepository(ctrl)
+	mockTasks := mocks.NewMockRepository(ctrl)
+	service := newTestService(mockRepo, mockTasks, allowAll{})
+	parentID := 3
+
+	tests := []struct {
+		TestCase string
+		Input    comment.Comment
+		Error    string
+		Setup    func()
+	}{
+		{
+			TestCase: "Create top-level comment",
+			Input:    comment.Comment{TaskID: 1, Author: "alice", Body: " Looks good "},
+			Setup: func() {
+				mockTasks.EXPECT().GetByID(gomock.Any(), 1).Return(task.Info{ID: 1}, nil)
+				mockRepo.EXPECT().Create(comment.Comment{TaskID: 1, Author: "alice", Body: "Looks good", CreatedAt: testNow, UpdatedAt: testNow}).
+					Return(comment.Comment{ID: 1}, nil)
+			},
+		},
+		{
+			TestCase: "Create reply",
+			Input:    comment.Comment{TaskID: 1, ParentID: &parentID, Author: "bob", Body: "Thanks"},
+			Setup: func() {
+				mockTasks.EXPECT().GetByID(gomock.Any(), 1).Return(task.Info{ID: 1}, nil)
+				mockRepo.EXPECT().GetByID(3).Return(comment.Comment{ID: 3, TaskID: 1}, nil)
+				mockRepo.EXPECT().Create(gomock.Any()).Return(comment.Comment{ID: 4}, nil)
+			},
+		},
+		{
+			TestCase: "Reply to a comment of another task",
+			Input:    comment.Comment{TaskID: 1, ParentID: &parentID, Author: "bob", Body: "Thanks"},
+			Error:    "parent comment not found",
+			Setup: func() {
+				mockTasks.EXPECT().GetByID(gomock.Any(), 1).Return(task.Info{ID: 1}, nil)
+				mockRepo.EXPECT().GetByID(3).Return(comment.Comment{ID: 3, TaskID: 2}, nil)
+			},
+		},
+		{
+			TestCase: "Empty body",
+			Input:    comment.Comment{TaskID: 1, Author: "alice", Body: "  "},
+			Error:    "invalid comment: body is required",
+			Setup:    func() {},
+		},
+		{
+			TestCase: "Missing task",
+			Input:    comment.Comment{TaskID: 9, Author: "alice", Body: "Hello"},
+			Error:    "task not found",
+			Setup: func() {
+				mockTasks.EXPECT().GetByID(gomock.Any(), 9).Return(task.Info{}, task.ErrNotFound)
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.TestCase, func(t *testing.T) {
+			tc.Setup()
+			_, err := service.CreateComment(context.Background(), tc.Input)
+			if tc.Error != "" {
+				assert.EqualError(t, err, tc.Error)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func Test_UpdateAndDeleteComment(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockCommentRepository(ctrl)
+	mockTasks := mocks.NewMockRepository(ctrl)
+	service := newTestService(mockRepo, mockTasks, allowAll{})
+	existing := comment.Comment{ID: 5, TaskID: 1, Author: "alice", Body: "Draft", CreatedAt: testNow.Add(-time.Hour), UpdatedAt: testNow.Add(-time.Hour)}
+
+	tests := []struct {
+		TestCase string
+		Run      func() error
+		Error    error
+		Setup    func()
+	}{
+		{
+			TestCase: "Author edits their comment",
+			Run: func() error {
+				_, err := service.UpdateComment(context.Background(), "alice", comment.Comment{ID: 5, TaskID: 1, Body: "Final"})
+				return err
+			},
+			Setup: func() {
+				mockTasks.EXPECT().GetByID(gomock.Any(), 1).Return(task.Info{ID: 1}, nil)
+				mockRepo.EXPECT().GetByID(5).Return(existing, nil)
+				edited := existing
+				edited.Body = "Final"
+				edited.UpdatedAt = testNow
+				mockRepo.EXPECT().Update(edited).Return(edited, nil)
+			},
+		},
+		{
+			TestCase: "Someone else edits the comment",
+			Run: func() error {
+				_, err := service.UpdateComment(context.Background(), "bob", comment.Comment{ID: 5, TaskID: 1, Body: "Hijacked"})
+				return err
+			},
+			Error: comment.ErrForbidden,
+			Setup: func() {
+				mockTasks.EXPECT().GetByID(gomock.Any(), 1).Return(task.Info{ID: 1}, nil)
+				mockRepo.EXPECT().GetByID(5).Return(existing, nil)
+			},
+		},
+		{
+			TestCase: "Edit through another task",
+			Run: func() error {
+				_, err := service.UpdateComment(context.Background(), "alice", comment.Comment{ID: 5, TaskID: 2, Body: "Final"})
+				return err
+			},
+			Error: comment.ErrNotFound,
+			Setup: func() {
+				mockTasks.EXPECT().GetByID(gomock.Any(), 2).Return(task.Info{ID: 2}, nil)
+				mockRepo.EXPECT().GetByID(5).Return(existing, nil)
+			},
+		},
+		{
+			TestCase: "Edit a comment of a task in another tenant",
+			Run: func() error {
+				_, err := service.UpdateComment(context.Background(), "alice", comment.Comment{ID: 5, TaskID: 1, Body: "Final"})
+				return err
+			},
+			Error: task.ErrNotFound,
+			Setup: func() {
+				mockTasks.EXPECT().GetByID(gomock.Any(), 1).Return(task.Info{}, task.ErrNotFound)
+			},
+		},
+		{
+			TestCase: "Someone else deletes the comment",
+			Run:      func() error { return service.DeleteComment(context.Background(), "bob", 1, 5) },
+			Error:    comment.ErrForbidden,
+			Setup: func() {
+				mockTasks.EXPECT().GetByID(gomock.Any(), 1).Return(task.Info{ID: 1}, nil)
+				mockRepo.EXPECT().GetByID(5).Return(existing, nil)
+			},
+		},
+		{
+			TestCase: "Author deletes their comment",
+			Run:      func() error { return service.DeleteComment(context.Background(), "alice", 1, 5) },
+			Setup: func() {
+				mockTasks.EXPECT().GetByID(gomock.Any(), 1).Return(task.Info{ID: 1}, nil)
+				mockRepo.EXPECT().GetByID(5).Return(existing, nil)
+				mockRepo.EXPECT().Delete(5).Return(nil)
+			},
+		},
+		{
+			TestCase: "Task deleted",
+			Run:      func() error { return service.TaskDeleted(1) },
+			Setup: func() {
+				mockRepo.EXPECT().DeleteByTask(1).Return(nil)
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.TestCase, func(t *testing.T) {
+			tc.Setup()
+			assert.Equal(t, tc.Error, tc.Run())
+		})
+	}
+}
+
+func Test_ListComments(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockCommentRepository(ctrl)
+	mockTasks := mocks.NewMockRepository(ctrl)
+	service := newTestService(mockRepo, mockTasks, allowAll{})
+
+	tests := []struct {
+		TestCase string
+		Offset   int
+		Limit    int
+		Expected comment.Page
+		Setup    func()
+	}{
+		{
+			TestCase: "Default page size",
+			Offset:   0,
+			Limit:    0,
+			Expected: comment.Page{Items: []comment.Comment{}, Total: 0, Offset: 0, Limit: DefaultPageSize},
+			Setup: func() {
+				mockTasks.EXPECT().GetByID(gomock.Any(), 1).Return(task.Info{ID: 1}, nil)
+				mockRepo.EXPECT().List(1, nil, 0, DefaultPageSize).Return(nil, 0, nil)
+			},
+		},
+		{
+			TestCase: "Page size is capped",
+			Offset:   40,
+			Limit:    1000,
+			Expected: comment.Page{Items: []comment.Comment{{ID: 41}}, Total: 41, Offset: 40, Limit: MaxPageSize},
+			Setup: func() {
+				mockTasks.EXPECT().GetByID(gomock.Any(), 1).Return(task.Info{ID: 1}, nil)
+				mockRepo.EXPECT().List(1, nil, 40, MaxPageSize).Return([]comment.Comment{{ID: 41}}, 41, nil)
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.TestCase, func(t *testing.T) {
+			tc.Setup()
+			page, err := service.ListComments(context.Background(), 1, nil, tc.Offset, tc.Limit)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.Expected, page)
+		})
+	}
+}
+
+func Test_Authorization(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockCommentRepository(ctrl)
+	mockTasks := mocks.NewMockRepository(ctrl)
+	mockPolicy := mocks.NewMockPolicy(ctrl)
+	service := newTestService(mockRepo, mockTasks, mockPolicy)
+
+	ctx := context.Background()
+	denied := fmt.Errorf("%w: viewer may not update tasks", role.ErrForbidden)
+
+	tests := []struct {
+		TestCase string
+		Run      func() error
+		Error    error
+		Setup    func()
+	}{
+		{
+			TestCase: "Viewers read comments",
+			Run: func() error {
+				_, err := service.ListComments(ctx, 1, nil, 0, 0)
+				return err
+			},
+			Setup: func() {
+				mockTasks.EXPECT().GetByID(gomock.Any(), 1).Return(task.Info{ID: 1, ProjectID: 4}, nil)
+				mockPolicy.EXPECT().Authorize(ctx, role.ActionRead, 4).Return(nil)
+				mockRepo.EXPECT().List(1, nil, 0, DefaultPageSize).Return(nil, 0, nil)
+			},
+		},
+		{
+			TestCase: "Viewers may not comment",
+			Run: func() error {
+				_, err := service.CreateComment(ctx, comment.Comment{TaskID: 1, Author: "alice", Body: "Hello"})
+				return err
+			},
+			Error: denied,
+			Setup: func() {
+				mockTasks.EXPECT().GetByID(gomock.Any(), 1).Return(task.Info{ID: 1, ProjectID: 4}, nil)
+				mockPolicy.EXPECT().Authorize(ctx, role.ActionUpdate, 4).Return(denied)
+			},
+		},
+		{
+			TestCase: "Denied delete leaves the comment alone",
+			Run:      func() error { return service.DeleteComment(ctx, "alice", 1, 5) },
+			Error:    denied,
+			Setup: func() {
+				mockTasks.EXPECT().GetByID(gomock.Any(), 1).Return(task.Info{ID: 1, ProjectID: 4}, nil)
+				mockPolicy.EXPECT().Authorize(ctx, role.ActionUpdate, 4).Return(denied)
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.TestCase, func(t *testing.T) {
+			tc.Setup()
+			assert.Equal(t, tc.Error, tc.Run())
+		})
+	}
+}
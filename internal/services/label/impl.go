@@ -0,0 +1,67 @@
+package label
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"task-api/internal/domain/label"
+)
+
+const maxNameLength = 64
+
+var colorPattern = regexp.MustCompile(`^#[0-9a-fA-F]{6}$`)
+
+type labelService struct {
+	repo label.Repository
+}
+
+func NewLabelService(repo label.Repository) Service {
+	return &labelService{repo: repo}
+}
+
+func (s *labelService) GetAllLabels(ctx context.Context) ([]label.Label, error) {
+	return s.repo.GetAll(ctx)
+}
+
+func (s *labelService) GetLabel(ctx context.Context, id int) (label.Label, error) {
+	return s.repo.GetByID(ctx, id)
+}
+
+func (s *labelService) CreateLabel(ctx context.Context, l label.Label) (label.Label, error) {
+	l, err := normalize(l)
+	if err != nil {
+		return label.Label{}, err
+	}
+	return s.repo.Create(ctx, l)
+}
+
+func (s *labelService) UpdateLabel(ctx context.Context, l label.Label) (label.Label, error) {
+	l, err := normalize(l)
+	if err != nil {
+		return label.Label{}, err
+	}
+	return s.repo.Update(ctx, l)
+}
+
+func (s *labelService) DeleteLabel(ctx context.Context, id int) error {
+	return s.repo.Delete(ctx, id)
+}
+
+// normalize trims the name and checks it can be used in a comma-separated
+// label filter.
+func normalize(l label.Label) (label.Label, error) {
+	l.Name = strings.TrimSpace(l.Name)
+	switch {
+	case l.Name == "":
+		return label.Label{}, fmt.Errorf("%w: name is required", label.ErrInvalidLabel)
+	case len(l.Name) > maxNameLength:
+		return label.Label{}, fmt.Errorf("%w: name is longer than %d characters", label.ErrInvalidLabel, maxNameLength)
+	case strings.Contains(l.Name, ","):
+		return label.Label{}, fmt.Errorf("%w: name must not contain commas", label.ErrInvalidLabel)
+	case l.Color != "" && !colorPattern.MatchString(l.Color):
+		return label.Label{}, fmt.Errorf("%w: color must look like #rrggbb", label.ErrInvalidLabel)
+	}
+	return l, nil
+}
@@ -0,0 +1,89 @@
+package label
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"task-api/internal/domain/label"
+	"task-api/internal/mocks"
+)
+
+func Test_CreateLabel(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockLabelRepository(ctrl)
+	service := NewLabelService(mockRepo)
+
+	tests := []struct {
+		TestCase string
+		Input    label.Label
+		Expected label.Label
+		Error    string
+		Setup    func()
+	}{
+		{
+			TestCase: "Create label with trimmed name",
+			Input:    label.Label{Name: " backend ", Color: "#00ff00"},
+			Expected: label.Label{ID: 1, Name: "backend", Color: "#00ff00"},
+			Setup: func() {
+				mockRepo.EXPECT().Create(gomock.Any(), label.Label{Name: "backend", Color: "#00ff00"}).Return(label.Label{ID: 1, Name: "backend", Color: "#00ff00"}, nil)
+			},
+		},
+		{
+			TestCase: "Create label without name",
+			Input:    label.Label{Name: "  "},
+			Error:    "invalid label: name is required",
+			Setup:    func() {},
+		},
+		{
+			TestCase: "Create label with comma",
+			Input:    label.Label{Name: "a,b"},
+			Error:    "invalid label: name must not contain commas",
+			Setup:    func() {},
+		},
+		{
+			TestCase: "Create label with invalid color",
+			Input:    label.Label{Name: "urgent", Color: "red"},
+			Error:    "invalid label: color must look like #rrggbb",
+			Setup:    func() {},
+		},
+		{
+			TestCase: "Create duplicate label",
+			Input:    label.Label{Name: "urgent"},
+			Error:    "label name already exists",
+			Setup: func() {
+				mockRepo.EXPECT().Create(gomock.Any(), label.Label{Name: "urgent"}).Return(label.Label{}, label.ErrDuplicateName)
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.TestCase, func(t *testing.T) {
+			tc.Setup()
+			result, err := service.CreateLabel(context.Background(), tc.Input)
+			if tc.Error != "" {
+				assert.EqualError(t, err, tc.Error)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.Expected, result)
+		})
+	}
+}
+
+func Test_UpdateLabel(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockLabelRepository(ctrl)
+	service := NewLabelService(mockRepo)
+
+	mockRepo.EXPECT().Update(gomock.Any(), label.Label{ID: 9, Name: "urgent"}).Return(label.Label{}, label.ErrNotFound)
+
+	_, err := service.UpdateLabel(context.Background(), label.Label{ID: 9, Name: "urgent"})
+	assert.Equal(t, label.ErrNotFound, err)
+}
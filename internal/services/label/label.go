@@ -0,0 +1,15 @@
+package label
+
+import (
+	"context"
+
+	"task-api/internal/domain/label"
+)
+
+type Service interface {
+	GetAllLabels(ctx context.Context) ([]label.Label, error)
+	GetLabel(ctx context.Context, id int) (label.Label, error)
+	CreateLabel(ctx context.Context, l label.Label) (label.Label, error)
+	UpdateLabel(ctx context.Context, l label.Label) (label.Label, error)
+	DeleteLabel(ctx context.Context, id int) error
+}
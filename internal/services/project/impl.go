@@ -0,0 +1,161 @@
+package project
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"task-api/internal/domain/project"
+	"task-api/internal/domain/role"
+	"task-api/internal/domain/task"
+)
+
+const (
+	maxNameLength        = 255
+	maxDescriptionLength = 10000
+)
+
+type projectService struct {
+	repo   project.Repository
+	tasks  task.Repository
+	policy role.Policy
+	now    func() time.Time
+}
+
+// NewProjectService checks every call against the caller's role: viewers
+// read projects, while creating, editing and archiving them takes an editor
+// or admin.
+func NewProjectService(repo project.Repository, tasks task.Repository, policy role.Policy) Service {
+	return &projectService{repo: repo, tasks: tasks, policy: policy, now: time.Now}
+}
+
+func (s *projectService) GetAllProjects(ctx context.Context, includeArchived bool) ([]project.Project, error) {
+	if err := s.policy.Authorize(ctx, role.ActionRead, 0); err != nil {
+		return nil, err
+	}
+	projects, err := s.repo.GetAll(includeArchived)
+	if err != nil {
+		return nil, err
+	}
+	counts, err := s.tasks.CountByProject(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for i := range projects {
+		projects[i] = withCounts(projects[i], counts)
+	}
+	return projects, nil
+}
+
+func (s *projectService) GetProject(ctx context.Context, id int) (project.Project, error) {
+	if err := s.policy.Authorize(ctx, role.ActionRead, id); err != nil {
+		return project.Project{}, err
+	}
+	p, err := s.repo.GetByID(id)
+	if err != nil {
+		return project.Project{}, err
+	}
+	return s.withCounts(ctx, p)
+}
+
+func (s *projectService) CreateProject(ctx context.Context, p project.Project) (project.Project, error) {
+	if err := s.policy.Authorize(ctx, role.ActionCreate, 0); err != nil {
+		return project.Project{}, err
+	}
+	p, err := normalize(p)
+	if err != nil {
+		return project.Project{}, err
+	}
+	p.ID = 0
+	p.ArchivedAt = nil
+	return s.repo.Create(p)
+}
+
+func (s *projectService) UpdateProject(ctx context.Context, p project.Project) (project.Project, error) {
+	if err := s.policy.Authorize(ctx, role.ActionUpdate, p.ID); err != nil {
+		return project.Project{}, err
+	}
+	p, err := normalize(p)
+	if err != nil {
+		return project.Project{}, err
+	}
+	existing, err := s.repo.GetByID(p.ID)
+	if err != nil {
+		return project.Project{}, err
+	}
+	existing.Name = p.Name
+	existing.Description = p.Description
+	return s.save(ctx, existing)
+}
+
+func (s *projectService) ArchiveProject(ctx context.Context, id int) (project.Project, error) {
+	if err := s.policy.Authorize(ctx, role.ActionUpdate, id); err != nil {
+		return project.Project{}, err
+	}
+	if id == project.DefaultProjectID {
+		return project.Project{}, project.ErrDefaultProject
+	}
+	p, err := s.repo.GetByID(id)
+	if err != nil {
+		return project.Project{}, err
+	}
+	if p.Archived() {
+		return s.withCounts(ctx, p)
+	}
+	now := s.now().UTC()
+	p.ArchivedAt = &now
+	return s.save(ctx, p)
+}
+
+func (s *projectService) UnarchiveProject(ctx context.Context, id int) (project.Project, error) {
+	if err := s.policy.Authorize(ctx, role.ActionUpdate, id); err != nil {
+		return project.Project{}, err
+	}
+	p, err := s.repo.GetByID(id)
+	if err != nil {
+		return project.Project{}, err
+	}
+	if !p.Archived() {
+		return s.withCounts(ctx, p)
+	}
+	p.ArchivedAt = nil
+	return s.save(ctx, p)
+}
+
+func (s *projectService) save(ctx context.Context, p project.Project) (project.Project, error) {
+	updated, err := s.repo.Update(p)
+	if err != nil {
+		return project.Project{}, err
+	}
+	return s.withCounts(ctx, updated)
+}
+
+func (s *projectService) withCounts(ctx context.Context, p project.Project) (project.Project, error) {
+	counts, err := s.tasks.CountByProject(ctx)
+	if err != nil {
+		return project.Project{}, err
+	}
+	return withCounts(p, counts), nil
+}
+
+func withCounts(p project.Project, counts map[int]task.Progress) project.Project {
+	c := counts[p.ID]
+	p.TaskCount = c.Total
+	p.DoneCount = c.Done
+	return p
+}
+
+func normalize(p project.Project) (project.Project, error) {
+	p.Name = strings.TrimSpace(p.Name)
+	p.Description = strings.TrimSpace(p.Description)
+	switch {
+	case p.Name == "":
+		return project.Project{}, fmt.Errorf("%w: name is required", project.ErrInvalidProject)
+	case len(p.Name) > maxNameLength:
+		return project.Project{}, fmt.Errorf("%w: name is longer than %d characters", project.ErrInvalidProject, maxNameLength)
+	case len(p.Description) > maxDescriptionLength:
+		return project.Project{}, fmt.Errorf("%w: description is longer than %d characters", project.ErrInvalidProject, maxDescriptionLength)
+	}
+	return p, nil
+}
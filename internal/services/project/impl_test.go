@@ -0,0 +1,230 @@
+package project
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"task-api/internal/domain/project"
+	"task-api/internal/domain/role"
+	"task-api/internal/domain/task"
+	"task-api/internal/mocks"
+)
+
+var testNow = time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+
+// allowAll lets every caller do anything, for the tests that are not about
+// roles.
+type allowAll struct{}
+
+func (allowAll) Authorize(context.Context, role.Action, int) error {
+	return nil
+}
+
+func newTestService(repo project.Repository, tasks task.Repository, policy role.Policy) *projectService {
+	s := NewProjectService(repo, tasks, policy).(*projectService)
+	s.now = func() time.Time { return testNow }
+	return s
+}
+
+func Test_GetAllProjects(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockProjectRepository(ctrl)
+	mockTasks := mocks.NewMockRepository(ctrl)
+	service := newTestService(mockRepo, mockTasks, allowAll{})
+
+	mockRepo.EXPECT().GetAll(false).Return([]project.Project{{ID: 1, Name: "Default"}, {ID: 2, Name: "Website"}}, nil)
+	mockTasks.EXPECT().CountByProject(gomock.Any()).Return(map[int]task.Progress{2: {Total: 3, Done: 1}}, nil)
+
+	projects, err := service.GetAllProjects(context.Background(), false)
+	assert.NoError(t, err)
+	assert.Equal(t, []project.Project{
+		{ID: 1, Name: "Default"},
+		{ID: 2, Name: "Website", TaskCount: 3, DoneCount: 1},
+	}, projects)
+}
+
+func Test_CreateProject(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockProjectRepository(ctrl)
+	service := newTestService(mockRepo, mocks.NewMockRepository(ctrl), allowAll{})
+
+	tests := []struct {
+		TestCase string
+		Input    project.Project
+		Error    string
+		Setup    func()
+	}{
+		{
+			TestCase: "Create project with trimmed name",
+			Input:    project.Project{Name: " Website ", ArchivedAt: &testNow},
+			Setup: func() {
+				mockRepo.EXPECT().Create(project.Project{Name: "Website"}).Return(project.Project{ID: 2, Name: "Website"}, nil)
+			},
+		},
+		{
+			TestCase: "Create project without name",
+			Input:    project.Project{Name: " "},
+			Error:    "invalid project: name is required",
+			Setup:    func() {},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.TestCase, func(t *testing.T) {
+			tc.Setup()
+			_, err := service.CreateProject(context.Background(), tc.Input)
+			if tc.Error != "" {
+				assert.EqualError(t, err, tc.Error)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func Test_ArchiveProject(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockProjectRepository(ctrl)
+	mockTasks := mocks.NewMockRepository(ctrl)
+	service := newTestService(mockRepo, mockTasks, allowAll{})
+
+	tests := []struct {
+		TestCase string
+		Run      func() (project.Project, error)
+		Expected project.Project
+		Error    error
+		Setup    func()
+	}{
+		{
+			TestCase: "Archive",
+			Run:      func() (project.Project, error) { return service.ArchiveProject(context.Background(), 2) },
+			Expected: project.Project{ID: 2, Name: "Website", ArchivedAt: &testNow, TaskCount: 1},
+			Setup: func() {
+				mockRepo.EXPECT().GetByID(2).Return(project.Project{ID: 2, Name: "Website"}, nil)
+				mockRepo.EXPECT().Update(project.Project{ID: 2, Name: "Website", ArchivedAt: &testNow}).
+					Return(project.Project{ID: 2, Name: "Website", ArchivedAt: &testNow}, nil)
+				mockTasks.EXPECT().CountByProject(gomock.Any()).Return(map[int]task.Progress{2: {Total: 1}}, nil)
+			},
+		},
+		{
+			TestCase: "Archive the default project",
+			Run: func() (project.Project, error) {
+				return service.ArchiveProject(context.Background(), project.DefaultProjectID)
+			},
+			Error: project.ErrDefaultProject,
+			Setup: func() {},
+		},
+		{
+			TestCase: "Unarchive",
+			Run:      func() (project.Project, error) { return service.UnarchiveProject(context.Background(), 2) },
+			Expected: project.Project{ID: 2, Name: "Website"},
+			Setup: func() {
+				mockRepo.EXPECT().GetByID(2).Return(project.Project{ID: 2, Name: "Website", ArchivedAt: &testNow}, nil)
+				mockRepo.EXPECT().Update(project.Project{ID: 2, Name: "Website"}).Return(project.Project{ID: 2, Name: "Website"}, nil)
+				mockTasks.EXPECT().CountByProject(gomock.Any()).Return(nil, nil)
+			},
+		},
+		{
+			TestCase: "Archive missing project",
+			Run:      func() (project.Project, error) { return service.ArchiveProject(context.Background(), 9) },
+			Error:    project.ErrNotFound,
+			Setup: func() {
+				mockRepo.EXPECT().GetByID(9).Return(project.Project{}, project.ErrNotFound)
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.TestCase, func(t *testing.T) {
+			tc.Setup()
+			result, err := tc.Run()
+			assert.Equal(t, tc.Error, err)
+			assert.Equal(t, tc.Expected, result)
+		})
+	}
+}
+
+func Test_Authorization(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockProjectRepository(ctrl)
+	mockTasks := mocks.NewMockRepository(ctrl)
+	mockPolicy := mocks.NewMockPolicy(ctrl)
+	service := newTestService(mockRepo, mockTasks, mockPolicy)
+
+	ctx := context.Background()
+	denied := fmt.Errorf("%w: viewer may not update tasks in project 2", role.ErrForbidden)
+
+	tests := []struct {
+		TestCase string
+		Run      func() error
+		Error    error
+		Setup    func()
+	}{
+		{
+			TestCase: "Viewers may not create projects",
+			Run: func() error {
+				_, err := service.CreateProject(ctx, project.Project{Name: "Website"})
+				return err
+			},
+			Error: denied,
+			Setup: func() {
+				mockPolicy.EXPECT().Authorize(ctx, role.ActionCreate, 0).Return(denied)
+			},
+		},
+		{
+			TestCase: "Denied archive leaves the project alone",
+			Run: func() error {
+				_, err := service.ArchiveProject(ctx, 2)
+				return err
+			},
+			Error: denied,
+			Setup: func() {
+				mockPolicy.EXPECT().Authorize(ctx, role.ActionUpdate, 2).Return(denied)
+			},
+		},
+		{
+			TestCase: "Editors archive projects",
+			Run: func() error {
+				_, err := service.ArchiveProject(ctx, 2)
+				return err
+			},
+			Setup: func() {
+				mockPolicy.EXPECT().Authorize(ctx, role.ActionUpdate, 2).Return(nil)
+				mockRepo.EXPECT().GetByID(2).Return(project.Project{ID: 2, Name: "Website"}, nil)
+				mockRepo.EXPECT().Update(gomock.Any()).Return(project.Project{ID: 2, Name: "Website", ArchivedAt: &testNow}, nil)
+				mockTasks.EXPECT().CountByProject(gomock.Any()).Return(map[int]task.Progress{}, nil)
+			},
+		},
+		{
+			TestCase: "Reading a project needs read access to it",
+			Run: func() error {
+				_, err := service.GetProject(ctx, 2)
+				return err
+			},
+			Error: denied,
+			Setup: func() {
+				mockPolicy.EXPECT().Authorize(ctx, role.ActionRead, 2).Return(denied)
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.TestCase, func(t *testing.T) {
+			tc.Setup()
+			assert.Equal(t, tc.Error, tc.Run())
+		})
+	}
+}
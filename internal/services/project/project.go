@@ -0,0 +1,21 @@
+package project
+
+import (
+	"context"
+
+	"task-api/internal/domain/project"
+)
+
+// Service manages the projects shared by all tenants. Their task counts only
+// count the tasks of the tenant of the context.
+type Service interface {
+	GetAllProjects(ctx context.Context, includeArchived bool) ([]project.Project, error)
+	GetProject(ctx context.Context, id int) (project.Project, error)
+	CreateProject(ctx context.Context, p project.Project) (project.Project, error)
+	// UpdateProject changes the name and description of a project.
+	UpdateProject(ctx context.Context, p project.Project) (project.Project, error)
+	// ArchiveProject archives a project together with its tasks, which
+	// drop out of the task lists and can no longer be changed.
+	ArchiveProject(ctx context.Context, id int) (project.Project, error)
+	UnarchiveProject(ctx context.Context, id int) (project.Project, error)
+}
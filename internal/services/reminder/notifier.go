@@ -0,0 +1,33 @@
+package reminder
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"task-api/internal/domain/reminder"
+	"task-api/internal/domain/task"
+)
+
+// Notifier delivers reminders. Returning an error makes the scheduler try
+// the same reminder again on its next run.
+type Notifier interface {
+	Notify(ctx context.Context, r reminder.Reminder, t task.Info) error
+}
+
+// LogNotifier writes reminders to the server log. It leaves task names out,
+// as they may hold customer data.
+type LogNotifier struct{}
+
+func NewLogNotifier() Notifier {
+	return LogNotifier{}
+}
+
+func (LogNotifier) Notify(_ context.Context, r reminder.Reminder, t task.Info) error {
+	if r.Window < 0 {
+		log.Printf("reminder: task %d is overdue since %s", t.ID, r.DueAt.Format(time.RFC3339))
+		return nil
+	}
+	log.Printf("reminder: task %d is due at %s", t.ID, r.DueAt.Format(time.RFC3339))
+	return nil
+}
@@ -0,0 +1,154 @@
+package reminder
+
+import (
+	"context"
+	"log"
+	"sort"
+	"time"
+
+	"task-api/internal/config"
+	"task-api/internal/domain/project"
+	"task-api/internal/domain/reminder"
+	"task-api/internal/domain/task"
+	"task-api/internal/tenant"
+)
+
+// Scheduler periodically reminds of pending tasks that are due soon or
+// overdue. Every reminder is claimed in the repository before it is sent,
+// so it goes out once per task, due date and window, across restarts too.
+type Scheduler struct {
+	repo     reminder.Repository
+	tasks    task.Repository
+	projects project.Repository
+	notifier Notifier
+	// windows are sorted from the most to the least urgent.
+	windows  []time.Duration
+	interval time.Duration
+	now      func() time.Time
+}
+
+func NewScheduler(repo reminder.Repository, tasks task.Repository, projects project.Repository, notifier Notifier,
+	cfg config.ReminderConfig) *Scheduler {
+	windows := append([]time.Duration(nil), cfg.Windows...)
+	sort.Slice(windows, func(i, j int) bool { return windows[i] < windows[j] })
+	if cfg.PollInterval <= 0 {
+		cfg.PollInterval = time.Minute
+	}
+	return &Scheduler{
+		repo:     repo,
+		tasks:    tasks,
+		projects: projects,
+		notifier: notifier,
+		windows:  windows,
+		interval: cfg.PollInterval,
+		now:      time.Now,
+	}
+}
+
+// Run sends reminders every poll interval until ctx is cancelled.
+func (s *Scheduler) Run(ctx context.Context) {
+	if len(s.windows) == 0 {
+		return
+	}
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		s.RunOnce(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RunOnce sends the reminders that are due now. A task is reminded for the
+// most urgent window it is in; windows it skipped, for instance because it
+// was created late or the server was down, are not caught up on.
+func (s *Scheduler) RunOnce(ctx context.Context) {
+	if len(s.windows) == 0 {
+		return
+	}
+	now := s.now().UTC()
+	tasks, err := s.dueBefore(ctx, now.Add(s.windows[len(s.windows)-1]))
+	if err != nil {
+		log.Printf("reminder: failed to list due tasks: %v", err)
+		return
+	}
+	archived, err := s.archivedProjects()
+	if err != nil {
+		log.Printf("reminder: failed to list projects: %v", err)
+		return
+	}
+
+	for _, t := range tasks {
+		if archived[t.ProjectID] {
+			continue
+		}
+		window, ok := s.window(*t.DueAt, now)
+		if !ok {
+			continue
+		}
+		r := reminder.Reminder{TaskID: t.ID, Window: window, DueAt: t.DueAt.UTC(), SentAt: now}
+		if err := s.repo.Claim(r); err != nil {
+			if err != reminder.ErrAlreadySent {
+				log.Printf("reminder: failed to record reminder for task %d: %v", t.ID, err)
+			}
+			continue
+		}
+		if err := s.notifier.Notify(tenant.NewContext(ctx, t.TenantID), r, t); err != nil {
+			log.Printf("reminder: failed to send reminder for task %d: %v", t.ID, err)
+			if err := s.repo.Release(r); err != nil {
+				log.Printf("reminder: failed to release reminder for task %d: %v", t.ID, err)
+			}
+		}
+	}
+}
+
+// dueBefore lists the pending tasks due before a time in every tenant, since
+// the task repository only lists the tasks of one tenant at a time.
+func (s *Scheduler) dueBefore(ctx context.Context, before time.Time) ([]task.Info, error) {
+	tenants, err := s.tasks.Tenants(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var due []task.Info
+	for _, id := range tenants {
+		tasks, err := s.tasks.GetDueBefore(tenant.NewContext(ctx, id), before)
+		if err != nil {
+			return nil, err
+		}
+		due = append(due, tasks...)
+	}
+	return due, nil
+}
+
+// TaskDeleted forgets the reminders sent for a deleted task.
+func (s *Scheduler) TaskDeleted(id int) error {
+	return s.repo.DeleteByTask(id)
+}
+
+// window returns the most urgent window that has opened for a due date.
+func (s *Scheduler) window(dueAt, now time.Time) (time.Duration, bool) {
+	for _, window := range s.windows {
+		if !now.Before(dueAt.Add(-window)) {
+			return window, true
+		}
+	}
+	return 0, false
+}
+
+func (s *Scheduler) archivedProjects() (map[int]bool, error) {
+	projects, err := s.projects.GetAll(true)
+	if err != nil {
+		return nil, err
+	}
+	archived := make(map[int]bool)
+	for _, p := range projects {
+		if p.Archived() {
+			archived[p.ID] = true
+		}
+	}
+	return archived, nil
+}
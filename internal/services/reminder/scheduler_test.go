@@ -0,0 +1,164 @@
+package reminder
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"task-api/internal/config"
+	"task-api/internal/domain/project"
+	"task-api/internal/domain/reminder"
+	"task-api/internal/domain/task"
+	"task-api/internal/infrastructure/persistence/memory"
+	"task-api/internal/tenant"
+)
+
+type sent struct {
+	TaskID int
+	Window time.Duration
+}
+
+type fakeNotifier struct {
+	sent    []sent
+	tenants []string
+	err     error
+}
+
+func (n *fakeNotifier) Notify(ctx context.Context, r reminder.Reminder, t task.Info) error {
+	if n.err != nil {
+		return n.err
+	}
+	n.sent = append(n.sent, sent{TaskID: t.ID, Window: r.Window})
+	n.tenants = append(n.tenants, tenant.FromContext(ctx))
+	return nil
+}
+
+func TestScheduler(t *testing.T) {
+	tasks := memory.NewInMemoryTaskRepository()
+	projects := memory.NewInMemoryProjectRepository()
+	reminders := memory.NewInMemoryReminderRepository()
+	notifier := &fakeNotifier{}
+	cfg := config.ReminderConfig{Windows: []time.Duration{0, 24 * time.Hour, -24 * time.Hour}}
+
+	now := time.Date(2024, 3, 20, 9, 0, 0, 0, time.UTC)
+	newScheduler := func() *Scheduler {
+		s := NewScheduler(reminders, tasks, projects, notifier, cfg)
+		s.now = func() time.Time { return now }
+		return s
+	}
+	scheduler := newScheduler()
+
+	ctx := context.Background()
+	dueAt := time.Date(2024, 3, 22, 9, 0, 0, 0, time.UTC)
+	report, _ := tasks.Create(ctx, task.Info{Name: "Report", DueAt: &dueAt})
+	_, _ = tasks.Create(ctx, task.Info{Name: "Done", Status: task.StatusDone, DueAt: &dueAt})
+	_, _ = tasks.Create(ctx, task.Info{Name: "Someday"})
+	legacy, _ := projects.Create(project.Project{Name: "Legacy"})
+	archivedAt := now
+	legacy.ArchivedAt = &archivedAt
+	_, _ = projects.Update(legacy)
+	_, _ = tasks.Create(ctx, task.Info{Name: "Archived", ProjectID: legacy.ID, DueAt: &dueAt})
+
+	tests := []struct {
+		TestCase string
+		Setup    func()
+		Expected []sent
+	}{
+		{
+			TestCase: "Nothing before the first window",
+			Setup:    func() {},
+			Expected: nil,
+		},
+		{
+			TestCase: "A day before the due date",
+			Setup:    func() { now = dueAt.Add(-23 * time.Hour) },
+			Expected: []sent{{TaskID: report.ID, Window: 24 * time.Hour}},
+		},
+		{
+			TestCase: "Each reminder is sent once",
+			Setup:    func() { now = now.Add(time.Hour) },
+			Expected: nil,
+		},
+		{
+			TestCase: "Restarts do not repeat reminders",
+			Setup:    func() { scheduler = newScheduler() },
+			Expected: nil,
+		},
+		{
+			TestCase: "Failed reminders are retried",
+			Setup: func() {
+				now = dueAt
+				notifier.err = errors.New("smtp unavailable")
+				scheduler.RunOnce(context.Background())
+				notifier.err = nil
+			},
+			Expected: []sent{{TaskID: report.ID, Window: 0}},
+		},
+		{
+			TestCase: "Skipped windows are not caught up on",
+			Setup: func() {
+				dueAt := now.Add(-48 * time.Hour)
+				report.DueAt = &dueAt
+				_, _ = tasks.Update(ctx, report)
+			},
+			Expected: []sent{{TaskID: report.ID, Window: -24 * time.Hour}},
+		},
+		{
+			TestCase: "A moved due date is reminded of again",
+			Setup: func() {
+				dueAt := now.Add(12 * time.Hour)
+				report.DueAt = &dueAt
+				_, _ = tasks.Update(ctx, report)
+			},
+			Expected: []sent{{TaskID: report.ID, Window: 24 * time.Hour}},
+		},
+		{
+			TestCase: "Completed tasks are left alone",
+			Setup: func() {
+				now = now.Add(12 * time.Hour)
+				report.Status = task.StatusDone
+				_, _ = tasks.Update(ctx, report)
+			},
+			Expected: nil,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.TestCase, func(t *testing.T) {
+			tc.Setup()
+			notifier.sent = nil
+			scheduler.RunOnce(context.Background())
+			assert.Equal(t, tc.Expected, notifier.sent)
+		})
+	}
+}
+
+func TestSchedulerRemindsEveryTenant(t *testing.T) {
+	tasks := memory.NewInMemoryTaskRepository()
+	notifier := &fakeNotifier{}
+	scheduler := NewScheduler(memory.NewInMemoryReminderRepository(), tasks, memory.NewInMemoryProjectRepository(),
+		notifier, config.ReminderConfig{Windows: []time.Duration{0}})
+	now := time.Date(2024, 3, 20, 9, 0, 0, 0, time.UTC)
+	scheduler.now = func() time.Time { return now }
+
+	dueAt := now.Add(-time.Hour)
+	_, _ = tasks.Create(context.Background(), task.Info{Name: "Report", DueAt: &dueAt})
+	_, _ = tasks.Create(tenant.NewContext(context.Background(), "acme"), task.Info{Name: "Invoice", DueAt: &dueAt})
+
+	scheduler.RunOnce(context.Background())
+	assert.Len(t, notifier.sent, 2)
+	assert.ElementsMatch(t, []string{"acme", tenant.Default}, notifier.tenants)
+}
+
+func TestSchedulerForgetsDeletedTasks(t *testing.T) {
+	reminders := memory.NewInMemoryReminderRepository()
+	scheduler := NewScheduler(reminders, nil, nil, &fakeNotifier{}, config.ReminderConfig{})
+	r := reminder.Reminder{TaskID: 1, DueAt: time.Date(2024, 3, 22, 9, 0, 0, 0, time.UTC)}
+
+	assert.NoError(t, reminders.Claim(r))
+	assert.NoError(t, scheduler.TaskDeleted(1))
+	assert.NoError(t, reminders.Claim(r))
+}
@@ -0,0 +1,48 @@
+package role
+
+import (
+	"fmt"
+
+	"task-api/internal/domain/project"
+	"task-api/internal/domain/role"
+	"task-api/internal/domain/user"
+)
+
+type roleService struct {
+	repo     role.Repository
+	users    user.Repository
+	projects project.Repository
+}
+
+func NewRoleService(repo role.Repository, users user.Repository, projects project.Repository) Service {
+	return &roleService{repo: repo, users: users, projects: projects}
+}
+
+func (s *roleService) GetRoles(userID int) ([]role.Assignment, error) {
+	if _, err := s.users.GetByID(userID); err != nil {
+		return nil, err
+	}
+	return s.repo.GetByUser(userID)
+}
+
+func (s *roleService) AssignRole(a role.Assignment) (role.Assignment, error) {
+	if !a.Role.Valid() {
+		return role.Assignment{}, fmt.Errorf("%w: %q is not one of viewer, editor and admin", role.ErrInvalidRole, a.Role)
+	}
+	if _, err := s.users.GetByID(a.UserID); err != nil {
+		return role.Assignment{}, err
+	}
+	if a.ProjectID != 0 {
+		if _, err := s.projects.GetByID(a.ProjectID); err != nil {
+			return role.Assignment{}, err
+		}
+	}
+	if err := s.repo.Set(a); err != nil {
+		return role.Assignment{}, err
+	}
+	return a, nil
+}
+
+func (s *roleService) UnassignRole(userID, projectID int) error {
+	return s.repo.Delete(userID, projectID)
+}
@@ -0,0 +1,84 @@
+package role
+
+import (
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"task-api/internal/domain/project"
+	"task-api/internal/domain/role"
+	"task-api/internal/domain/user"
+	"task-api/internal/mocks"
+)
+
+func Test_AssignRole(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockRoleRepository(ctrl)
+	mockUsers := mocks.NewMockUserRepository(ctrl)
+	mockProjects := mocks.NewMockProjectRepository(ctrl)
+	service := NewRoleService(mockRepo, mockUsers, mockProjects)
+
+	tests := []struct {
+		TestCase string
+		Input    role.Assignment
+		Setup    func()
+		Error    string
+	}{
+		{
+			TestCase: "Global role",
+			Input:    role.Assignment{UserID: 1, Role: role.Editor},
+			Setup: func() {
+				mockUsers.EXPECT().GetByID(1).Return(user.User{ID: 1, Username: "bob"}, nil)
+				mockRepo.EXPECT().Set(role.Assignment{UserID: 1, Role: role.Editor}).Return(nil)
+			},
+		},
+		{
+			TestCase: "Project role",
+			Input:    role.Assignment{UserID: 1, ProjectID: 2, Role: role.Admin},
+			Setup: func() {
+				mockUsers.EXPECT().GetByID(1).Return(user.User{ID: 1, Username: "bob"}, nil)
+				mockProjects.EXPECT().GetByID(2).Return(project.Project{ID: 2}, nil)
+				mockRepo.EXPECT().Set(role.Assignment{UserID: 1, ProjectID: 2, Role: role.Admin}).Return(nil)
+			},
+		},
+		{
+			TestCase: "Unknown role",
+			Input:    role.Assignment{UserID: 1, Role: "owner"},
+			Setup:    func() {},
+			Error:    `invalid role: "owner" is not one of viewer, editor and admin`,
+		},
+		{
+			TestCase: "Unknown user",
+			Input:    role.Assignment{UserID: 9, Role: role.Viewer},
+			Setup: func() {
+				mockUsers.EXPECT().GetByID(9).Return(user.User{}, user.ErrNotFound)
+			},
+			Error: "user not found",
+		},
+		{
+			TestCase: "Unknown project",
+			Input:    role.Assignment{UserID: 1, ProjectID: 9, Role: role.Viewer},
+			Setup: func() {
+				mockUsers.EXPECT().GetByID(1).Return(user.User{ID: 1, Username: "bob"}, nil)
+				mockProjects.EXPECT().GetByID(9).Return(project.Project{}, project.ErrNotFound)
+			},
+			Error: "project not found",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.TestCase, func(t *testing.T) {
+			tc.Setup()
+			result, err := service.AssignRole(tc.Input)
+			if tc.Error != "" {
+				assert.EqualError(t, err, tc.Error)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.Input, result)
+		})
+	}
+}
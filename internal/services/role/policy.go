@@ -0,0 +1,84 @@
+package role
+
+import (
+	"context"
+	"fmt"
+
+	"task-api/internal/auth"
+	"task-api/internal/config"
+	"task-api/internal/domain/apikey"
+	"task-api/internal/domain/role"
+	"task-api/internal/domain/user"
+)
+
+// policy finds the role of the caller in the context:
+//   - the admin subjects of the auth config are admins everywhere;
+//   - API keys are editors with the tasks:write scope and viewers without;
+//   - users have their role in the project, else their global role;
+//   - everyone else has the configured default role.
+type policy struct {
+	repo        role.Repository
+	users       user.Repository
+	admins      map[string]bool
+	defaultRole role.Role
+}
+
+func NewPolicy(repo role.Repository, users user.Repository, cfg config.RoleConfig, authCfg config.AuthConfig) role.Policy {
+	admins := make(map[string]bool, len(authCfg.AdminSubjects))
+	for _, subject := range authCfg.AdminSubjects {
+		admins[subject] = true
+	}
+	return &policy{repo: repo, users: users, admins: admins, defaultRole: cfg.DefaultRole}
+}
+
+func (p *policy) Authorize(ctx context.Context, action role.Action, projectID int) error {
+	identity, ok := auth.FromContext(ctx)
+	if !ok {
+		return fmt.Errorf("%w: authentication required to %s tasks", role.ErrForbidden, action)
+	}
+	r, err := p.roleOf(identity, projectID)
+	if err != nil {
+		return err
+	}
+	if r.Allows(action) {
+		return nil
+	}
+	if projectID != 0 {
+		return fmt.Errorf("%w: %s may not %s tasks in project %d", role.ErrForbidden, r, action, projectID)
+	}
+	return fmt.Errorf("%w: %s may not %s tasks", role.ErrForbidden, r, action)
+}
+
+func (p *policy) roleOf(identity auth.Identity, projectID int) (role.Role, error) {
+	if identity.Scopes != nil {
+		if identity.Allows(apikey.ScopeTasksWrite) {
+			return role.Editor, nil
+		}
+		return role.Viewer, nil
+	}
+	if p.admins[identity.Subject] {
+		return role.Admin, nil
+	}
+
+	u, err := p.users.GetByUsername(identity.Subject)
+	if err == user.ErrNotFound {
+		return p.defaultRole, nil
+	}
+	if err != nil {
+		return "", err
+	}
+	assignments, err := p.repo.GetByUser(u.ID)
+	if err != nil {
+		return "", err
+	}
+	r := p.defaultRole
+	for _, a := range assignments {
+		switch {
+		case a.ProjectID == 0:
+			r = a.Role
+		case a.ProjectID == projectID:
+			return a.Role, nil
+		}
+	}
+	return r, nil
+}
@@ -0,0 +1,71 @@
+package role
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"task-api/internal/auth"
+	"task-api/internal/config"
+	"task-api/internal/domain/apikey"
+	"task-api/internal/domain/role"
+	"task-api/internal/domain/user"
+	"task-api/internal/infrastructure/persistence/memory"
+)
+
+func TestPolicy(t *testing.T) {
+	roles := memory.NewInMemoryRoleRepository()
+	users := memory.NewInMemoryUserRepository()
+	bob, _ := users.Create(user.User{Username: "bob"})
+	carol, _ := users.Create(user.User{Username: "carol"})
+	_ = roles.Set(role.Assignment{UserID: bob.ID, Role: role.Editor})
+	_ = roles.Set(role.Assignment{UserID: bob.ID, ProjectID: 2, Role: role.Viewer})
+	_ = roles.Set(role.Assignment{UserID: carol.ID, ProjectID: 3, Role: role.Admin})
+
+	policy := NewPolicy(roles, users, config.RoleConfig{DefaultRole: role.Viewer}, config.AuthConfig{AdminSubjects: []string{"root"}})
+
+	as := func(identity auth.Identity) context.Context {
+		return auth.NewContext(context.Background(), identity)
+	}
+
+	tests := []struct {
+		TestCase  string
+		Context   context.Context
+		Action    role.Action
+		ProjectID int
+		Error     string
+	}{
+		{TestCase: "Anonymous", Context: context.Background(), Action: role.ActionRead,
+			Error: "forbidden: authentication required to read tasks"},
+		{TestCase: "Admin subject deletes", Context: as(auth.Identity{Subject: "root"}), Action: role.ActionDelete, ProjectID: 1},
+		{TestCase: "Global editor updates", Context: as(auth.Identity{Subject: "bob"}), Action: role.ActionUpdate, ProjectID: 1},
+		{TestCase: "Global editor cannot delete", Context: as(auth.Identity{Subject: "bob"}), Action: role.ActionDelete, ProjectID: 1,
+			Error: "forbidden: editor may not delete tasks in project 1"},
+		{TestCase: "Project role overrides the global one", Context: as(auth.Identity{Subject: "bob"}), Action: role.ActionUpdate, ProjectID: 2,
+			Error: "forbidden: viewer may not update tasks in project 2"},
+		{TestCase: "Project admin deletes", Context: as(auth.Identity{Subject: "carol"}), Action: role.ActionDelete, ProjectID: 3},
+		{TestCase: "Default role elsewhere", Context: as(auth.Identity{Subject: "carol"}), Action: role.ActionCreate, ProjectID: 1,
+			Error: "forbidden: viewer may not create tasks in project 1"},
+		{TestCase: "Across projects", Context: as(auth.Identity{Subject: "carol"}), Action: role.ActionRead},
+		{TestCase: "Subject without a user", Context: as(auth.Identity{Subject: "board"}), Action: role.ActionUpdate,
+			Error: "forbidden: viewer may not update tasks"},
+		{TestCase: "Writing API key", Context: as(auth.Identity{Subject: "apikey:1", Scopes: []string{apikey.ScopeTasksWrite}}), Action: role.ActionUpdate, ProjectID: 1},
+		{TestCase: "API keys never delete", Context: as(auth.Identity{Subject: "apikey:1", Scopes: []string{apikey.ScopeTasksWrite}}), Action: role.ActionDelete, ProjectID: 1,
+			Error: "forbidden: editor may not delete tasks in project 1"},
+		{TestCase: "Admin subjects lose their rights through an API key", Context: as(auth.Identity{Subject: "root", Scopes: []string{apikey.ScopeTasksRead}}), Action: role.ActionCreate, ProjectID: 1,
+			Error: "forbidden: viewer may not create tasks in project 1"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.TestCase, func(t *testing.T) {
+			err := policy.Authorize(tc.Context, tc.Action, tc.ProjectID)
+			if tc.Error == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.EqualError(t, err, tc.Error)
+			assert.ErrorIs(t, err, role.ErrForbidden)
+		})
+	}
+}
@@ -0,0 +1,12 @@
+package role
+
+import "task-api/internal/domain/role"
+
+type Service interface {
+	// GetRoles returns the assignments of a user, the global one first.
+	GetRoles(userID int) ([]role.Assignment, error)
+	// AssignRole gives a user a role in a project, or in every project
+	// when the assignment has none.
+	AssignRole(a role.Assignment) (role.Assignment, error)
+	UnassignRole(userID, projectID int) error
+}
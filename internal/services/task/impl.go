@@ -1,47 +1,920 @@
 package task
 
 import (
+	"context"
 	"errors"
+	"fmt"
+	"log"
+	"time"
 
+	"task-api/internal/config"
+	"task-api/internal/domain/audit"
+	"task-api/internal/domain/label"
+	"task-api/internal/domain/project"
+	"task-api/internal/domain/role"
 	"task-api/internal/domain/task"
+	"task-api/internal/domain/user"
+	"task-api/internal/events"
+	"task-api/internal/recurrence"
+	"task-api/internal/search"
+)
+
+const (
+	// maxOccurrences caps the dates GetOccurrences returns.
+	maxOccurrences = 100
+
+	DefaultSearchLimit = 20
+	MaxSearchLimit     = 100
 )
 
 type taskService struct {
-	repo task.Repository
+	repo           task.Repository
+	labels         label.Repository
+	users          user.Repository
+	projects       project.Repository
+	policy         role.Policy
+	recorder       audit.Recorder
+	tx             task.Transactor
+	publisher      events.Publisher
+	deleteChildren task.DeleteMode
+	hooks          []task.DeleteHook
 }
 
-func NewTaskService(repo task.Repository) Service {
-	return &taskService{repo: repo}
+func NewTaskService(repo task.Repository, labels label.Repository, users user.Repository, projects project.Repository,
+	policy role.Policy, recorder audit.Recorder, tx task.Transactor, publisher events.Publisher, cfg config.TaskConfig, hooks []task.DeleteHook) Service {
+	return &taskService{
+		repo:           repo,
+		labels:         labels,
+		users:          users,
+		projects:       projects,
+		policy:         policy,
+		recorder:       recorder,
+		tx:             tx,
+		publisher:      publisher,
+		deleteChildren: cfg.DeleteChildren,
+		hooks:          hooks,
+	}
 }
 
-func (s *taskService) GetAllTasks() ([]task.Info, error) {
-	return s.repo.GetAll()
+// GetAllTasks returns the tasks of all projects that are not archived and
+// that the caller may read.
+func (s *taskService) GetAllTasks(ctx context.Context) ([]task.Info, error) {
+	if err := s.policy.Authorize(ctx, role.ActionRead, 0); err != nil {
+		return nil, err
+	}
+	tasks, err := s.repo.GetAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+	fillProgress(tasks)
+	tasks, err = s.withoutArchived(tasks)
+	if err != nil {
+		return nil, err
+	}
+	tasks, err = s.withoutUnreadable(ctx, tasks)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.fillLabels(ctx, tasks); err != nil {
+		return nil, err
+	}
+	return tasks, nil
 }
 
-func (s *taskService) GetTaskByID(id int) (task.Info, error) {
-	return s.repo.GetByID(id)
+func (s *taskService) GetTasksByProject(ctx context.Context, projectID int) ([]task.Info, error) {
+	if err := s.policy.Authorize(ctx, role.ActionRead, projectID); err != nil {
+		return nil, err
+	}
+	if _, err := s.projects.GetByID(projectID); err != nil {
+		return nil, err
+	}
+	tasks, err := s.repo.GetByProject(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+	fillProgress(tasks)
+	if err := s.fillLabels(ctx, tasks); err != nil {
+		return nil, err
+	}
+	return tasks, nil
 }
 
-func (s *taskService) CreateTask(t task.Info) (task.Info, error) {
-	if t.Name == "" {
-		return task.Info{}, errors.New("task name is required")
+// GetTasksByLabels returns the tasks carrying any or all of the named
+// labels. Unknown names match nothing.
+func (s *taskService) GetTasksByLabels(ctx context.Context, names []string, match label.Match) ([]task.Info, error) {
+	if err := s.policy.Authorize(ctx, role.ActionRead, 0); err != nil {
+		return nil, err
+	}
+	if match != label.MatchAny && match != label.MatchAll {
+		return nil, label.ErrInvalidMatch
+	}
+
+	var labelIDs []int
+	seen := make(map[int]bool)
+	for _, name := range names {
+		l, err := s.labels.GetByName(ctx, name)
+		if err == label.ErrNotFound {
+			if match == label.MatchAll {
+				return nil, nil
+			}
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		if !seen[l.ID] {
+			seen[l.ID] = true
+			labelIDs = append(labelIDs, l.ID)
+		}
+	}
+	if len(labelIDs) == 0 {
+		return nil, nil
+	}
+
+	taskIDs, err := s.labels.FindTasks(ctx, labelIDs, match)
+	if err != nil {
+		return nil, err
+	}
+	wanted := make(map[int]bool, len(taskIDs))
+	for _, id := range taskIDs {
+		wanted[id] = true
+	}
+
+	tasks, err := s.GetAllTasks(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var filtered []task.Info
+	for _, t := range tasks {
+		if wanted[t.ID] {
+			filtered = append(filtered, t)
+		}
+	}
+	return filtered, nil
+}
+
+func (s *taskService) GetTaskByID(ctx context.Context, id int) (task.Info, error) {
+	t, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return task.Info{}, err
+	}
+	if err := s.policy.Authorize(ctx, role.ActionRead, t.ProjectID); err != nil {
+		return task.Info{}, err
+	}
+	children, err := s.repo.GetChildren(ctx, id)
+	if err != nil {
+		return task.Info{}, err
+	}
+	t.Progress = rollUp(children)
+	return s.withLabels(ctx, t)
+}
+
+func (s *taskService) GetChildren(ctx context.Context, id int, recursive bool) ([]task.Info, error) {
+	if _, err := s.readable(ctx, id); err != nil {
+		return nil, err
+	}
+	if recursive {
+		return s.repo.GetDescendants(ctx, id)
+	}
+	return s.repo.GetChildren(ctx, id)
+}
+
+func (s *taskService) CreateTask(ctx context.Context, t task.Info) (task.Info, error) {
+	if t.ProjectID == 0 {
+		t.ProjectID = project.DefaultProjectID
+	}
+	if err := s.policy.Authorize(ctx, role.ActionCreate, t.ProjectID); err != nil {
+		return task.Info{}, err
+	}
+	if err := validate(t); err != nil {
+		return task.Info{}, err
+	}
+	if err := s.checkProject(t.ProjectID); err != nil {
+		return task.Info{}, err
+	}
+	if err := s.checkParent(ctx, t); err != nil {
+		return task.Info{}, err
+	}
+	if err := s.checkAssignee(t.AssigneeID); err != nil {
+		return task.Info{}, err
+	}
+	if t.Recurrence != nil {
+		t.Recurrence.Start = *t.DueAt
+	}
+	t.Progress = nil
+	t.Labels = nil
+	created, err := s.create(ctx, t)
+	if err != nil {
+		return task.Info{}, err
+	}
+	s.publisher.Publish(events.TaskCreated, created)
+	return created, nil
+}
+
+// UpdateTask replaces a task. Leaving out the project keeps the task in its
+// current project; tasks of archived projects cannot be changed. Moving a
+// task takes the right to update tasks in both projects. Completing a
+// recurring task creates its next instance.
+func (s *taskService) UpdateTask(ctx context.Context, t task.Info) (task.Info, error) {
+	if err := validate(t); err != nil {
+		return task.Info{}, err
+	}
+	existing, err := s.updatable(ctx, t.ID)
+	if err != nil {
+		return task.Info{}, err
+	}
+	if err := s.checkProject(existing.ProjectID); err != nil {
+		return task.Info{}, err
+	}
+	if t.ProjectID == 0 {
+		t.ProjectID = existing.ProjectID
+	} else if t.ProjectID != existing.ProjectID {
+		if err := s.policy.Authorize(ctx, role.ActionUpdate, t.ProjectID); err != nil {
+			return task.Info{}, err
+		}
+		if err := s.checkProject(t.ProjectID); err != nil {
+			return task.Info{}, err
+		}
+	}
+	if err := s.checkParent(ctx, t); err != nil {
+		return task.Info{}, err
+	}
+	if err := s.checkAssignee(t.AssigneeID); err != nil {
+		return task.Info{}, err
+	}
+	if t.Status == task.StatusDone {
+		if err := s.checkBlockers(ctx, t.ID); err != nil {
+			return task.Info{}, err
+		}
+	}
+	var completed *task.Info
+	if t.Recurrence != nil {
+		t.Recurrence.Start = *t.DueAt
+		if old := existing.Recurrence; old != nil && old.Rule == t.Recurrence.Rule && old.Timezone == t.Recurrence.Timezone {
+			t.Recurrence.Start = old.Start
+		}
+		if existing.Status != task.StatusDone && t.Status == task.StatusDone {
+			// The next instance takes the recurrence over, so reopening and
+			// completing this one again does not repeat it.
+			done := t
+			completed = &done
+			t.Recurrence = nil
+		}
+	}
+	t.Progress = nil
+	t.Labels = nil
+	updated, err := s.update(ctx, existing, t)
+	if err != nil {
+		return task.Info{}, err
+	}
+	if completed != nil {
+		// The next instance is only created once the completion is stored,
+		// so a failed update leaves none behind. When creating it fails, the
+		// task is reopened so that retrying the completion creates it.
+		if err := s.createNext(ctx, *completed); err != nil {
+			existing.Progress = nil
+			existing.Labels = nil
+			if _, reopenErr := s.update(ctx, updated, existing); reopenErr != nil {
+				return task.Info{}, errors.Join(err, reopenErr)
+			}
+			return task.Info{}, err
+		}
+	}
+	return s.publishUpdated(ctx, updated)
+}
+
+// DeleteTask removes a task and, depending on the configured mode, either
+// its whole subtree or nothing else after moving its children up a level.
+// Every task deleted or moved is authorized in its own project before any
+// of them is changed, so a denied check leaves the whole subtree in place.
+func (s *taskService) DeleteTask(ctx context.Context, id int) error {
+	existing, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if err := s.policy.Authorize(ctx, role.ActionDelete, existing.ProjectID); err != nil {
+		return err
+	}
+
+	if s.deleteChildren == task.DeleteCascade {
+		descendants, err := s.repo.GetDescendants(ctx, id)
+		if err != nil {
+			return err
+		}
+		if err := s.authorizeEach(ctx, role.ActionDelete, descendants); err != nil {
+			return err
+		}
+		// Delete the deepest tasks first so no child outlives its parent.
+		for i := len(descendants) - 1; i >= 0; i-- {
+			if err := s.remove(ctx, descendants[i]); err != nil {
+				return err
+			}
+		}
+	} else {
+		children, err := s.repo.GetChildren(ctx, id)
+		if err != nil {
+			return err
+		}
+		if err := s.authorizeEach(ctx, role.ActionUpdate, children); err != nil {
+			return err
+		}
+		for _, child := range children {
+			moved := child
+			moved.ParentID = existing.ParentID
+			updated, err := s.update(ctx, child, moved)
+			if err != nil {
+				return err
+			}
+			if _, err := s.publishUpdated(ctx, updated); err != nil {
+				return err
+			}
+		}
+	}
+
+	return s.remove(ctx, existing)
+}
+
+func (s *taskService) remove(ctx context.Context, t task.Info) error {
+	err := s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		if err := s.repo.Delete(ctx, t.ID); err != nil {
+			return err
+		}
+		return s.record(ctx, audit.OperationDelete, &t, nil)
+	})
+	if err != nil {
+		return err
+	}
+	if err := s.labels.DetachAll(ctx, t.ID); err != nil {
+		return err
+	}
+	// The task is gone at this point, so a failing hook only leaves orphaned
+	// data behind and does not fail the delete.
+	for _, hook := range s.hooks {
+		if err := hook.TaskDeleted(t.ID); err != nil {
+			log.Printf("task: delete hook failed for task %d: %v", t.ID, err)
+		}
+	}
+	s.publisher.Publish(events.TaskDeleted, t)
+	return nil
+}
+
+func (s *taskService) AssignTask(ctx context.Context, id int, assigneeID *int) (task.Info, error) {
+	t, err := s.updatable(ctx, id)
+	if err != nil {
+		return task.Info{}, err
+	}
+	if err := s.checkProject(t.ProjectID); err != nil {
+		return task.Info{}, err
+	}
+	if err := s.checkAssignee(assigneeID); err != nil {
+		return task.Info{}, err
+	}
+	before := t
+	t.AssigneeID = assigneeID
+	updated, err := s.update(ctx, before, t)
+	if err != nil {
+		return task.Info{}, err
+	}
+	return s.publishUpdated(ctx, updated)
+}
+
+func (s *taskService) GetTasksByAssignee(ctx context.Context, userID int) ([]task.Info, error) {
+	if err := s.policy.Authorize(ctx, role.ActionRead, 0); err != nil {
+		return nil, err
+	}
+	if _, err := s.users.GetByID(userID); err != nil {
+		return nil, err
+	}
+	tasks, err := s.repo.GetByAssignee(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	tasks, err = s.withoutArchived(tasks)
+	if err != nil {
+		return nil, err
+	}
+	tasks, err = s.withoutUnreadable(ctx, tasks)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.fillLabels(ctx, tasks); err != nil {
+		return nil, err
+	}
+	return tasks, nil
+}
+
+func (s *taskService) AttachLabel(ctx context.Context, id, labelID int) (task.Info, error) {
+	t, err := s.updatable(ctx, id)
+	if err != nil {
+		return task.Info{}, err
+	}
+	if err := s.labels.Attach(ctx, id, labelID); err != nil {
+		return task.Info{}, err
+	}
+	return s.publishUpdated(ctx, t)
+}
+
+func (s *taskService) DetachLabel(ctx context.Context, id, labelID int) (task.Info, error) {
+	t, err := s.updatable(ctx, id)
+	if err != nil {
+		return task.Info{}, err
+	}
+	if err := s.labels.Detach(ctx, id, labelID); err != nil {
+		return task.Info{}, err
+	}
+	return s.publishUpdated(ctx, t)
+}
+
+// GetOccurrences previews at most maxOccurrences due dates, in the time zone
+// of the recurrence.
+func (s *taskService) GetOccurrences(ctx context.Context, id int, from, to time.Time) ([]time.Time, error) {
+	t, err := s.readable(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if t.Recurrence == nil {
+		return nil, task.ErrNotRecurring
+	}
+	series, err := seriesOf(t)
+	if err != nil {
+		return nil, err
+	}
+	return series.Between(from, to, maxOccurrences), nil
+}
+
+// SearchTasks leaves out the tasks of archived projects and of projects the
+// caller may not read, like GetAllTasks.
+func (s *taskService) SearchTasks(ctx context.Context, query string, limit int) ([]task.SearchResult, error) {
+	if err := s.policy.Authorize(ctx, role.ActionRead, 0); err != nil {
+		return nil, err
+	}
+	q, err := search.Parse(query)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", task.ErrInvalidSearch, err)
+	}
+	if limit <= 0 {
+		limit = DefaultSearchLimit
+	}
+	if limit > MaxSearchLimit {
+		limit = MaxSearchLimit
+	}
+
+	results, err := s.repo.Search(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+	archived, err := s.archivedProjects()
+	if err != nil {
+		return nil, err
+	}
+
+	canRead := s.projectReader(ctx)
+	var found []task.SearchResult
+	var tasks []task.Info
+	for _, result := range results {
+		if len(found) == limit {
+			break
+		}
+		if archived[result.Task.ProjectID] {
+			continue
+		}
+		if ok, err := canRead(result.Task.ProjectID); err != nil {
+			return nil, err
+		} else if !ok {
+			continue
+		}
+		result.Snippet = search.Highlight(result.Task.Name, q)
+		found = append(found, result)
+		tasks = append(tasks, result.Task)
+	}
+	if err := s.fillLabels(ctx, tasks); err != nil {
+		return nil, err
 	}
-	if t.Status != 0 && t.Status != 1 {
-		return task.Info{}, errors.New("invalid task status")
+	for i := range found {
+		found[i].Task = tasks[i]
 	}
-	return s.repo.Create(t)
+	return found, nil
 }
 
-func (s *taskService) UpdateTask(t task.Info) (task.Info, error) {
+// createNext creates the instance following a completed recurring task, due
+// at the next occurrence after it, unless the series has ended. Labels are
+// carried over.
+func (s *taskService) createNext(ctx context.Context, done task.Info) error {
+	series, err := seriesOf(done)
+	if err != nil {
+		return err
+	}
+	dueAt, ok := series.After(*done.DueAt)
+	if !ok {
+		return nil
+	}
+	dueAt = dueAt.UTC()
+	recurrence := *done.Recurrence
+	next, err := s.create(ctx, task.Info{
+		Name:       done.Name,
+		ProjectID:  done.ProjectID,
+		ParentID:   done.ParentID,
+		AssigneeID: done.AssigneeID,
+		DueAt:      &dueAt,
+		Recurrence: &recurrence,
+	})
+	if err != nil {
+		return err
+	}
+	labels, err := s.labels.GetTaskLabels(ctx, []int{done.ID})
+	if err != nil {
+		return err
+	}
+	for _, l := range labels[done.ID] {
+		if err := s.labels.Attach(ctx, next.ID, l.ID); err != nil {
+			return err
+		}
+	}
+	next, err = s.withLabels(ctx, next)
+	if err != nil {
+		return err
+	}
+	s.publisher.Publish(events.TaskCreated, next)
+	return nil
+}
+
+// create stores a new task along with its audit record, so that neither is
+// kept without the other.
+func (s *taskService) create(ctx context.Context, t task.Info) (task.Info, error) {
+	var created task.Info
+	err := s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		var err error
+		if created, err = s.repo.Create(ctx, t); err != nil {
+			return err
+		}
+		return s.record(ctx, audit.OperationCreate, nil, &created)
+	})
+	if err != nil {
+		return task.Info{}, err
+	}
+	return created, nil
+}
+
+// update stores t, which was before, along with its audit record.
+func (s *taskService) update(ctx context.Context, before, t task.Info) (task.Info, error) {
+	var updated task.Info
+	err := s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		var err error
+		if updated, err = s.repo.Update(ctx, t); err != nil {
+			return err
+		}
+		return s.record(ctx, audit.OperationUpdate, &before, &updated)
+	})
+	if err != nil {
+		return task.Info{}, err
+	}
+	return updated, nil
+}
+
+// record writes the audit record of a change in the transaction of ctx. A
+// change that cannot be recorded is rolled back.
+func (s *taskService) record(ctx context.Context, op audit.Operation, before, after *task.Info) error {
+	if err := s.recorder.Record(ctx, op, before, after); err != nil {
+		return fmt.Errorf("audit record of %s: %w", op, err)
+	}
+	return nil
+}
+
+// publishUpdated fills in the labels of a task that was just written and
+// publishes it as updated.
+func (s *taskService) publishUpdated(ctx context.Context, t task.Info) (task.Info, error) {
+	t, err := s.withLabels(ctx, t)
+	if err != nil {
+		return task.Info{}, err
+	}
+	s.publisher.Publish(events.TaskUpdated, t)
+	return t, nil
+}
+
+func (s *taskService) withLabels(ctx context.Context, t task.Info) (task.Info, error) {
+	tasks := []task.Info{t}
+	if err := s.fillLabels(ctx, tasks); err != nil {
+		return task.Info{}, err
+	}
+	return tasks[0], nil
+}
+
+func (s *taskService) fillLabels(ctx context.Context, tasks []task.Info) error {
+	ids := make([]int, len(tasks))
+	for i, t := range tasks {
+		ids[i] = t.ID
+	}
+	labels, err := s.labels.GetTaskLabels(ctx, ids)
+	if err != nil {
+		return err
+	}
+	for i := range tasks {
+		tasks[i].Labels = nil
+		for _, l := range labels[tasks[i].ID] {
+			tasks[i].Labels = append(tasks[i].Labels, l.Name)
+		}
+	}
+	return nil
+}
+
+func (s *taskService) GetBlockers(ctx context.Context, id int) ([]task.Info, error) {
+	if _, err := s.readable(ctx, id); err != nil {
+		return nil, err
+	}
+	return s.repo.GetBlockers(ctx, id)
+}
+
+// AddBlocker records that blockerID blocks id, refusing dependencies that
+// would close a cycle.
+func (s *taskService) AddBlocker(ctx context.Context, id, blockerID int) error {
+	if _, err := s.updatable(ctx, id); err != nil {
+		return err
+	}
+	if id == blockerID {
+		return task.ErrDependencyCycle
+	}
+	deps, err := s.repo.GetDependencies(ctx)
+	if err != nil {
+		return err
+	}
+	if task.Blocks(deps, id, blockerID) {
+		return task.ErrDependencyCycle
+	}
+	return s.repo.AddDependency(ctx, blockerID, id)
+}
+
+func (s *taskService) RemoveBlocker(ctx context.Context, id, blockerID int) error {
+	if _, err := s.updatable(ctx, id); err != nil {
+		return err
+	}
+	return s.repo.RemoveDependency(ctx, blockerID, id)
+}
+
+func (s *taskService) GetExecutionOrder(ctx context.Context) ([]task.Info, error) {
+	if err := s.policy.Authorize(ctx, role.ActionRead, 0); err != nil {
+		return nil, err
+	}
+	tasks, err := s.repo.GetAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+	tasks, err = s.withoutUnreadable(ctx, tasks)
+	if err != nil {
+		return nil, err
+	}
+	deps, err := s.repo.GetDependencies(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return task.TopologicalOrder(tasks, deps)
+}
+
+// readable returns a task the caller may read.
+func (s *taskService) readable(ctx context.Context, id int) (task.Info, error) {
+	t, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return task.Info{}, err
+	}
+	if err := s.policy.Authorize(ctx, role.ActionRead, t.ProjectID); err != nil {
+		return task.Info{}, err
+	}
+	return t, nil
+}
+
+// projectReader reports whether the caller may read the tasks of a project,
+// asking the policy once per project. Only ErrForbidden means no.
+func (s *taskService) projectReader(ctx context.Context) func(projectID int) (bool, error) {
+	allowed := make(map[int]bool)
+	return func(projectID int) (bool, error) {
+		if ok, seen := allowed[projectID]; seen {
+			return ok, nil
+		}
+		err := s.policy.Authorize(ctx, role.ActionRead, projectID)
+		if err != nil && !errors.Is(err, role.ErrForbidden) {
+			return false, err
+		}
+		allowed[projectID] = err == nil
+		return err == nil, nil
+	}
+}
+
+// withoutUnreadable drops the tasks of the projects the caller may not read,
+// as a role in a project overrides the global role checked up front.
+func (s *taskService) withoutUnreadable(ctx context.Context, tasks []task.Info) ([]task.Info, error) {
+	canRead := s.projectReader(ctx)
+	readable := make([]task.Info, 0, len(tasks))
+	for _, t := range tasks {
+		ok, err := canRead(t.ProjectID)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			readable = append(readable, t)
+		}
+	}
+	if len(readable) == len(tasks) {
+		return tasks, nil
+	}
+	return readable, nil
+}
+
+// authorizeEach authorizes action in the project of every task, failing on
+// the first denial.
+func (s *taskService) authorizeEach(ctx context.Context, action role.Action, tasks []task.Info) error {
+	checked := make(map[int]bool)
+	for _, t := range tasks {
+		if checked[t.ProjectID] {
+			continue
+		}
+		if err := s.policy.Authorize(ctx, action, t.ProjectID); err != nil {
+			return err
+		}
+		checked[t.ProjectID] = true
+	}
+	return nil
+}
+
+// updatable returns a task the caller may update.
+func (s *taskService) updatable(ctx context.Context, id int) (task.Info, error) {
+	t, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return task.Info{}, err
+	}
+	if err := s.policy.Authorize(ctx, role.ActionUpdate, t.ProjectID); err != nil {
+		return task.Info{}, err
+	}
+	return t, nil
+}
+
+// checkBlockers fails with ErrBlocked while any blocker of the task is open.
+func (s *taskService) checkBlockers(ctx context.Context, id int) error {
+	blockers, err := s.repo.GetBlockers(ctx, id)
+	if err != nil {
+		return err
+	}
+	for _, b := range blockers {
+		if b.Status != task.StatusDone {
+			return task.ErrBlocked
+		}
+	}
+	return nil
+}
+
+func validate(t task.Info) error {
 	if t.Name == "" {
-		return task.Info{}, errors.New("task name is required")
+		return task.ErrNameRequired
+	}
+	if t.Status != task.StatusPending && t.Status != task.StatusDone {
+		return task.ErrInvalidStatus
+	}
+	if t.Recurrence != nil {
+		if t.DueAt == nil {
+			return fmt.Errorf("%w: a recurring task needs a due date", task.ErrInvalidRecurrence)
+		}
+		if _, err := seriesOf(t); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// seriesOf expands the recurrence of a task in its time zone.
+func seriesOf(t task.Info) (recurrence.Series, error) {
+	rule, err := recurrence.Parse(t.Recurrence.Rule)
+	if err != nil {
+		return recurrence.Series{}, fmt.Errorf("%w: %v", task.ErrInvalidRecurrence, err)
+	}
+	loc, err := time.LoadLocation(t.Recurrence.Timezone)
+	if err != nil || t.Recurrence.Timezone == "Local" {
+		return recurrence.Series{}, fmt.Errorf("%w: unknown time zone %q", task.ErrInvalidRecurrence, t.Recurrence.Timezone)
+	}
+	return recurrence.NewSeries(rule, t.Recurrence.Start.In(loc)), nil
+}
+
+// checkParent makes sure the parent exists and that the new parent is not
+// the task itself or one of its descendants, which would create a cycle.
+func (s *taskService) checkParent(ctx context.Context, t task.Info) error {
+	if t.ParentID == nil {
+		return nil
+	}
+	if t.ID != 0 && *t.ParentID == t.ID {
+		return task.ErrParentCycle
+	}
+	if _, err := s.repo.GetByID(ctx, *t.ParentID); err != nil {
+		if err == task.ErrNotFound {
+			return task.ErrParentNotFound
+		}
+		return err
 	}
-	if t.Status != 0 && t.Status != 1 {
-		return task.Info{}, errors.New("invalid task status")
+	if t.ID == 0 {
+		return nil
 	}
-	return s.repo.Update(t)
+
+	descendants, err := s.repo.GetDescendants(ctx, t.ID)
+	if err != nil {
+		return err
+	}
+	for _, d := range descendants {
+		if d.ID == *t.ParentID {
+			return task.ErrParentCycle
+		}
+	}
+	return nil
+}
+
+// checkProject makes sure tasks are only created in or moved to existing
+// projects that are not archived.
+func (s *taskService) checkProject(id int) error {
+	p, err := s.projects.GetByID(id)
+	if err != nil {
+		if err == project.ErrNotFound {
+			return task.ErrProjectNotFound
+		}
+		return err
+	}
+	if p.Archived() {
+		return project.ErrArchived
+	}
+	return nil
+}
+
+// withoutArchived drops the tasks of archived projects.
+func (s *taskService) withoutArchived(tasks []task.Info) ([]task.Info, error) {
+	archived, err := s.archivedProjects()
+	if err != nil {
+		return nil, err
+	}
+	if len(archived) == 0 {
+		return tasks, nil
+	}
+	var active []task.Info
+	for _, t := range tasks {
+		if !archived[t.ProjectID] {
+			active = append(active, t)
+		}
+	}
+	return active, nil
+}
+
+func (s *taskService) archivedProjects() (map[int]bool, error) {
+	projects, err := s.projects.GetAll(true)
+	if err != nil {
+		return nil, err
+	}
+	archived := make(map[int]bool)
+	for _, p := range projects {
+		if p.Archived() {
+			archived[p.ID] = true
+		}
+	}
+	return archived, nil
+}
+
+// checkAssignee makes sure a task is only assigned to an existing user.
+func (s *taskService) checkAssignee(assigneeID *int) error {
+	if assigneeID == nil {
+		return nil
+	}
+	if _, err := s.users.GetByID(*assigneeID); err != nil {
+		if err == user.ErrNotFound {
+			return task.ErrAssigneeNotFound
+		}
+		return err
+	}
+	return nil
 }
 
-func (s *taskService) DeleteTask(id int) error {
-	return s.repo.Delete(id)
+// fillProgress rolls up the completion of the children found among tasks.
+func fillProgress(tasks []task.Info) {
+	progress := make(map[int]*task.Progress)
+	for _, t := range tasks {
+		if t.ParentID == nil {
+			continue
+		}
+		p, ok := progress[*t.ParentID]
+		if !ok {
+			p = &task.Progress{}
+			progress[*t.ParentID] = p
+		}
+		p.Total++
+		if t.Status == task.StatusDone {
+			p.Done++
+		}
+	}
+	for i := range tasks {
+		tasks[i].Progress = progress[tasks[i].ID]
+	}
+}
+
+func rollUp(children []task.Info) *task.Progress {
+	if len(children) == 0 {
+		return nil
+	}
+	p := &task.Progress{Total: len(children)}
+	for _, c := range children {
+		if c.Status == task.StatusDone {
+			p.Done++
+		}
+	}
+	return p
 }
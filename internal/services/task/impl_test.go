@@ -1,22 +1,58 @@
 package task
 
 import (
+	"context"
 	"errors"
+	"fmt"
 	"testing"
+	"time"
 
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
 
+	"task-api/internal/config"
+	"task-api/internal/domain/audit"
+	"task-api/internal/domain/label"
+	"task-api/internal/domain/project"
+	"task-api/internal/domain/role"
 	"task-api/internal/domain/task"
+	"task-api/internal/domain/user"
+	"task-api/internal/events"
 	"task-api/internal/mocks"
 )
 
+// allowAll lets every caller do anything, for the tests that are not about
+// roles.
+type allowAll struct{}
+
+func (allowAll) Authorize(context.Context, role.Action, int) error {
+	return nil
+}
+
+// discard drops audit records, for the tests that are not about auditing.
+type discard struct{}
+
+func (discard) Record(context.Context, audit.Operation, *task.Info, *task.Info) error {
+	return nil
+}
+
+// direct runs work without a transaction, with the context it is given.
+type direct struct{}
+
+func (direct) WithinTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
 func Test_CreateTask(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
 	mockRepo := mocks.NewMockRepository(ctrl)
-	service := NewTaskService(mockRepo)
+	mockLabels := mocks.NewMockLabelRepository(ctrl)
+	mockUsers := mocks.NewMockUserRepository(ctrl)
+	mockProjects := mocks.NewMockProjectRepository(ctrl)
+	allowProjects(mockProjects)
+	service := NewTaskService(mockRepo, mockLabels, mockUsers, mockProjects, allowAll{}, discard{}, direct{}, events.NewBroker(16), config.TaskConfig{DeleteChildren: task.DeleteReparent}, nil)
 
 	tests := []struct {
 		TestCase string
@@ -31,21 +67,21 @@ func Test_CreateTask(t *testing.T) {
 			Expected: task.Info{ID: 1, Name: "Valid Task", Status: 0},
 			Error:    nil,
 			Setup: func() {
-				mockRepo.EXPECT().Create(task.Info{Name: "Valid Task", Status: 0}).Return(task.Info{ID: 1, Name: "Valid Task", Status: 0}, nil)
+				mockRepo.EXPECT().Create(gomock.Any(), task.Info{Name: "Valid Task", Status: 0, ProjectID: project.DefaultProjectID}).Return(task.Info{ID: 1, Name: "Valid Task", Status: 0}, nil)
 			},
 		},
 		{
 			TestCase: "Create task with empty name",
 			Input:    task.Info{Name: "", Status: 0},
 			Expected: task.Info{},
-			Error:    errors.New("task name is required"),
+			Error:    task.ErrNameRequired,
 			Setup:    func() {},
 		},
 		{
 			TestCase: "Create task with invalid status",
 			Input:    task.Info{Name: "Invalid Status Task", Status: 2},
 			Expected: task.Info{},
-			Error:    errors.New("invalid task status"),
+			Error:    task.ErrInvalidStatus,
 			Setup:    func() {},
 		},
 	}
@@ -53,7 +89,7 @@ func Test_CreateTask(t *testing.T) {
 	for _, tc := range tests {
 		t.Run(tc.TestCase, func(t *testing.T) {
 			tc.Setup()
-			result, err := service.CreateTask(tc.Input)
+			result, err := service.CreateTask(context.Background(), tc.Input)
 			if tc.Error != nil {
 				assert.Equal(t, tc.Error, err)
 			} else {
@@ -69,7 +105,11 @@ func Test_GetAllTasks(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockRepo := mocks.NewMockRepository(ctrl)
-	service := NewTaskService(mockRepo)
+	mockLabels := mocks.NewMockLabelRepository(ctrl)
+	mockUsers := mocks.NewMockUserRepository(ctrl)
+	mockProjects := mocks.NewMockProjectRepository(ctrl)
+	allowProjects(mockProjects)
+	service := NewTaskService(mockRepo, mockLabels, mockUsers, mockProjects, allowAll{}, discard{}, direct{}, events.NewBroker(16), config.TaskConfig{DeleteChildren: task.DeleteReparent}, nil)
 
 	tests := []struct {
 		TestCase string
@@ -85,10 +125,11 @@ func Test_GetAllTasks(t *testing.T) {
 			},
 			Error: nil,
 			Setup: func() {
-				mockRepo.EXPECT().GetAll().Return([]task.Info{
+				mockRepo.EXPECT().GetAll(gomock.Any()).Return([]task.Info{
 					{ID: 1, Name: "Test Task 1", Status: 0},
 					{ID: 2, Name: "Test Task 2", Status: 1},
 				}, nil)
+				mockLabels.EXPECT().GetTaskLabels(gomock.Any(), []int{1, 2}).Return(nil, nil)
 			},
 		},
 	}
@@ -96,7 +137,7 @@ func Test_GetAllTasks(t *testing.T) {
 	for _, tc := range tests {
 		t.Run(tc.TestCase, func(t *testing.T) {
 			tc.Setup()
-			result, err := service.GetAllTasks()
+			result, err := service.GetAllTasks(context.Background())
 			if tc.Error != nil {
 				assert.Equal(t, tc.Error, err)
 			} else {
@@ -112,7 +153,11 @@ func Test_GetTaskByID(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockRepo := mocks.NewMockRepository(ctrl)
-	service := NewTaskService(mockRepo)
+	mockLabels := mocks.NewMockLabelRepository(ctrl)
+	mockUsers := mocks.NewMockUserRepository(ctrl)
+	mockProjects := mocks.NewMockProjectRepository(ctrl)
+	allowProjects(mockProjects)
+	service := NewTaskService(mockRepo, mockLabels, mockUsers, mockProjects, allowAll{}, discard{}, direct{}, events.NewBroker(16), config.TaskConfig{DeleteChildren: task.DeleteReparent}, nil)
 
 	tests := []struct {
 		TestCase string
@@ -127,7 +172,32 @@ func Test_GetTaskByID(t *testing.T) {
 			Expected: task.Info{ID: 1, Name: "Test Task", Status: 0},
 			Error:    nil,
 			Setup: func() {
-				mockRepo.EXPECT().GetByID(1).Return(task.Info{ID: 1, Name: "Test Task", Status: 0}, nil)
+				mockRepo.EXPECT().GetByID(gomock.Any(), 1).Return(task.Info{ID: 1, Name: "Test Task", Status: 0}, nil)
+				mockRepo.EXPECT().GetChildren(gomock.Any(), 1).Return(nil, nil)
+				mockLabels.EXPECT().GetTaskLabels(gomock.Any(), []int{1}).Return(nil, nil)
+			},
+		},
+		{
+			TestCase: "Get task by ID with subtask progress",
+			ID:       2,
+			Expected: task.Info{ID: 2, Name: "Parent", Status: 0, Progress: &task.Progress{Done: 1, Total: 2}},
+			Error:    nil,
+			Setup: func() {
+				mockRepo.EXPECT().GetByID(gomock.Any(), 2).Return(task.Info{ID: 2, Name: "Parent", Status: 0}, nil)
+				mockRepo.EXPECT().GetChildren(gomock.Any(), 2).Return([]task.Info{
+					{ID: 3, Name: "Child 1", Status: 1, ParentID: intPtr(2)},
+					{ID: 4, Name: "Child 2", Status: 0, ParentID: intPtr(2)},
+				}, nil)
+				mockLabels.EXPECT().GetTaskLabels(gomock.Any(), []int{2}).Return(nil, nil)
+			},
+		},
+		{
+			TestCase: "Get missing task",
+			ID:       9,
+			Expected: task.Info{},
+			Error:    task.ErrNotFound,
+			Setup: func() {
+				mockRepo.EXPECT().GetByID(gomock.Any(), 9).Return(task.Info{}, task.ErrNotFound)
 			},
 		},
 	}
@@ -135,7 +205,7 @@ func Test_GetTaskByID(t *testing.T) {
 	for _, tc := range tests {
 		t.Run(tc.TestCase, func(t *testing.T) {
 			tc.Setup()
-			result, err := service.GetTaskByID(tc.ID)
+			result, err := service.GetTaskByID(context.Background(), tc.ID)
 			if tc.Error != nil {
 				assert.Equal(t, tc.Error, err)
 			} else {
@@ -151,7 +221,11 @@ func Test_UpdateTask(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockRepo := mocks.NewMockRepository(ctrl)
-	service := NewTaskService(mockRepo)
+	mockLabels := mocks.NewMockLabelRepository(ctrl)
+	mockUsers := mocks.NewMockUserRepository(ctrl)
+	mockProjects := mocks.NewMockProjectRepository(ctrl)
+	allowProjects(mockProjects)
+	service := NewTaskService(mockRepo, mockLabels, mockUsers, mockProjects, allowAll{}, discard{}, direct{}, events.NewBroker(16), config.TaskConfig{DeleteChildren: task.DeleteReparent}, nil)
 
 	tests := []struct {
 		TestCase string
@@ -166,21 +240,24 @@ func Test_UpdateTask(t *testing.T) {
 			Expected: task.Info{ID: 1, Name: "Updated Task", Status: 1},
 			Error:    nil,
 			Setup: func() {
-				mockRepo.EXPECT().Update(task.Info{ID: 1, Name: "Updated Task", Status: 1}).Return(task.Info{ID: 1, Name: "Updated Task", Status: 1}, nil)
+				mockRepo.EXPECT().GetByID(gomock.Any(), 1).Return(task.Info{ID: 1, Name: "Task", ProjectID: project.DefaultProjectID}, nil)
+				mockRepo.EXPECT().GetBlockers(gomock.Any(), 1).Return(nil, nil)
+				mockRepo.EXPECT().Update(gomock.Any(), task.Info{ID: 1, Name: "Updated Task", Status: 1, ProjectID: project.DefaultProjectID}).Return(task.Info{ID: 1, Name: "Updated Task", Status: 1}, nil)
+				mockLabels.EXPECT().GetTaskLabels(gomock.Any(), []int{1}).Return(nil, nil)
 			},
 		},
 		{
 			TestCase: "Update task with empty name",
 			Input:    task.Info{ID: 1, Name: "", Status: 1},
 			Expected: task.Info{},
-			Error:    errors.New("task name is required"),
+			Error:    task.ErrNameRequired,
 			Setup:    func() {},
 		},
 		{
 			TestCase: "Update task with invalid status",
 			Input:    task.Info{ID: 1, Name: "Invalid Status Task", Status: 2},
 			Expected: task.Info{},
-			Error:    errors.New("invalid task status"),
+			Error:    task.ErrInvalidStatus,
 			Setup:    func() {},
 		},
 	}
@@ -188,7 +265,7 @@ func Test_UpdateTask(t *testing.T) {
 	for _, tc := range tests {
 		t.Run(tc.TestCase, func(t *testing.T) {
 			tc.Setup()
-			result, err := service.UpdateTask(tc.Input)
+			result, err := service.UpdateTask(context.Background(), tc.Input)
 			if tc.Error != nil {
 				assert.Equal(t, tc.Error, err)
 			} else {
@@ -204,7 +281,11 @@ func Test_DeleteTask(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockRepo := mocks.NewMockRepository(ctrl)
-	service := NewTaskService(mockRepo)
+	mockLabels := mocks.NewMockLabelRepository(ctrl)
+	mockUsers := mocks.NewMockUserRepository(ctrl)
+	mockProjects := mocks.NewMockProjectRepository(ctrl)
+	allowProjects(mockProjects)
+	service := NewTaskService(mockRepo, mockLabels, mockUsers, mockProjects, allowAll{}, discard{}, direct{}, events.NewBroker(16), config.TaskConfig{DeleteChildren: task.DeleteReparent}, nil)
 
 	tests := []struct {
 		TestCase string
@@ -217,7 +298,10 @@ func Test_DeleteTask(t *testing.T) {
 			ID:       1,
 			Error:    nil,
 			Setup: func() {
-				mockRepo.EXPECT().Delete(1).Return(nil)
+				mockRepo.EXPECT().GetByID(gomock.Any(), 1).Return(task.Info{ID: 1, Name: "Test Task", Status: 0}, nil)
+				mockRepo.EXPECT().GetChildren(gomock.Any(), 1).Return(nil, nil)
+				mockRepo.EXPECT().Delete(gomock.Any(), 1).Return(nil)
+				mockLabels.EXPECT().DetachAll(gomock.Any(), 1).Return(nil)
 			},
 		},
 	}
@@ -225,7 +309,7 @@ func Test_DeleteTask(t *testing.T) {
 	for _, tc := range tests {
 		t.Run(tc.TestCase, func(t *testing.T) {
 			tc.Setup()
-			err := service.DeleteTask(tc.ID)
+			err := service.DeleteTask(context.Background(), tc.ID)
 			if tc.Error != nil {
 				assert.Equal(t, tc.Error, err)
 			} else {
@@ -234,3 +318,1030 @@ func Test_DeleteTask(t *testing.T) {
 		})
 	}
 }
+
+func Test_PublishEvents(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockRepository(ctrl)
+	mockLabels := mocks.NewMockLabelRepository(ctrl)
+	mockUsers := mocks.NewMockUserRepository(ctrl)
+	mockProjects := mocks.NewMockProjectRepository(ctrl)
+	allowProjects(mockProjects)
+	broker := events.NewBroker(16)
+	service := NewTaskService(mockRepo, mockLabels, mockUsers, mockProjects, allowAll{}, discard{}, direct{}, broker, config.TaskConfig{DeleteChildren: task.DeleteReparent}, nil)
+	_, sub := broker.Subscribe(0)
+	defer sub.Close()
+
+	tests := []struct {
+		TestCase string
+		Expected []events.Type
+		Run      func()
+	}{
+		{
+			TestCase: "Create, update and delete publish events",
+			Expected: []events.Type{events.TaskCreated, events.TaskUpdated, events.TaskDeleted},
+			Run: func() {
+				mockRepo.EXPECT().Create(gomock.Any(), task.Info{Name: "Task", Status: 0, ProjectID: project.DefaultProjectID}).
+					Return(task.Info{ID: 1, Name: "Task", Status: 0, ProjectID: project.DefaultProjectID}, nil)
+				mockRepo.EXPECT().GetByID(gomock.Any(), 1).Return(task.Info{ID: 1, Name: "Task", Status: 0, ProjectID: project.DefaultProjectID}, nil)
+				mockRepo.EXPECT().GetBlockers(gomock.Any(), 1).Return(nil, nil)
+				mockRepo.EXPECT().Update(gomock.Any(), task.Info{ID: 1, Name: "Task", Status: 1, ProjectID: project.DefaultProjectID}).
+					Return(task.Info{ID: 1, Name: "Task", Status: 1, ProjectID: project.DefaultProjectID}, nil)
+				mockLabels.EXPECT().GetTaskLabels(gomock.Any(), []int{1}).Return(nil, nil)
+				mockRepo.EXPECT().GetByID(gomock.Any(), 1).Return(task.Info{ID: 1, Name: "Task", Status: 1, ProjectID: project.DefaultProjectID}, nil)
+				mockRepo.EXPECT().GetChildren(gomock.Any(), 1).Return(nil, nil)
+				mockRepo.EXPECT().Delete(gomock.Any(), 1).Return(nil)
+				mockLabels.EXPECT().DetachAll(gomock.Any(), 1).Return(nil)
+
+				_, _ = service.CreateTask(context.Background(), task.Info{Name: "Task", Status: 0})
+				_, _ = service.UpdateTask(context.Background(), task.Info{ID: 1, Name: "Task", Status: 1})
+				_ = service.DeleteTask(context.Background(), 1)
+			},
+		},
+		{
+			TestCase: "Failed writes publish nothing",
+			Expected: nil,
+			Run: func() {
+				mockRepo.EXPECT().GetByID(gomock.Any(), 2).Return(task.Info{ID: 2, Name: "Task", ProjectID: project.DefaultProjectID}, nil)
+				mockRepo.EXPECT().GetBlockers(gomock.Any(), 2).Return(nil, nil)
+				mockRepo.EXPECT().Update(gomock.Any(), task.Info{ID: 2, Name: "Task", Status: 1, ProjectID: project.DefaultProjectID}).Return(task.Info{}, errors.New("storage unavailable"))
+
+				_, _ = service.UpdateTask(context.Background(), task.Info{ID: 2, Name: "Task", Status: 1})
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.TestCase, func(t *testing.T) {
+			tc.Run()
+
+			var published []events.Type
+			for len(sub.C) > 0 {
+				published = append(published, (<-sub.C).Type)
+			}
+			assert.Equal(t, tc.Expected, published)
+		})
+	}
+}
+
+func Test_TaskParent(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockRepository(ctrl)
+	mockLabels := mocks.NewMockLabelRepository(ctrl)
+	mockUsers := mocks.NewMockUserRepository(ctrl)
+	mockProjects := mocks.NewMockProjectRepository(ctrl)
+	allowProjects(mockProjects)
+	service := NewTaskService(mockRepo, mockLabels, mockUsers, mockProjects, allowAll{}, discard{}, direct{}, events.NewBroker(16), config.TaskConfig{DeleteChildren: task.DeleteReparent}, nil)
+
+	tests := []struct {
+		TestCase string
+		Input    task.Info
+		Error    error
+		Setup    func()
+	}{
+		{
+			TestCase: "Create subtask of existing parent",
+			Input:    task.Info{Name: "Child", ParentID: intPtr(1)},
+			Error:    nil,
+			Setup: func() {
+				mockRepo.EXPECT().GetByID(gomock.Any(), 1).Return(task.Info{ID: 1, Name: "Parent"}, nil)
+				mockRepo.EXPECT().Create(gomock.Any(), task.Info{Name: "Child", ParentID: intPtr(1), ProjectID: project.DefaultProjectID}).Return(task.Info{ID: 2, Name: "Child", ParentID: intPtr(1)}, nil)
+			},
+		},
+		{
+			TestCase: "Create subtask of missing parent",
+			Input:    task.Info{Name: "Child", ParentID: intPtr(9)},
+			Error:    task.ErrParentNotFound,
+			Setup: func() {
+				mockRepo.EXPECT().GetByID(gomock.Any(), 9).Return(task.Info{}, task.ErrNotFound)
+			},
+		},
+		{
+			TestCase: "Task cannot be its own parent",
+			Input:    task.Info{ID: 1, Name: "Task", ParentID: intPtr(1)},
+			Error:    task.ErrParentCycle,
+			Setup: func() {
+				mockRepo.EXPECT().GetByID(gomock.Any(), 1).Return(task.Info{ID: 1, Name: "Task", ProjectID: project.DefaultProjectID}, nil)
+			},
+		},
+		{
+			TestCase: "Task cannot move under its descendant",
+			Input:    task.Info{ID: 1, Name: "Task", ParentID: intPtr(3)},
+			Error:    task.ErrParentCycle,
+			Setup: func() {
+				mockRepo.EXPECT().GetByID(gomock.Any(), 1).Return(task.Info{ID: 1, Name: "Task", ProjectID: project.DefaultProjectID}, nil)
+				mockRepo.EXPECT().GetByID(gomock.Any(), 3).Return(task.Info{ID: 3, Name: "Grandchild", ParentID: intPtr(2)}, nil)
+				mockRepo.EXPECT().GetDescendants(gomock.Any(), 1).Return([]task.Info{
+					{ID: 2, Name: "Child", ParentID: intPtr(1)},
+					{ID: 3, Name: "Grandchild", ParentID: intPtr(2)},
+				}, nil)
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.TestCase, func(t *testing.T) {
+			tc.Setup()
+			var err error
+			if tc.Input.ID == 0 {
+				_, err = service.CreateTask(context.Background(), tc.Input)
+			} else {
+				_, err = service.UpdateTask(context.Background(), tc.Input)
+			}
+			assert.Equal(t, tc.Error, err)
+		})
+	}
+}
+
+func Test_DeleteTaskChildren(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockRepository(ctrl)
+	mockLabels := mocks.NewMockLabelRepository(ctrl)
+	mockUsers := mocks.NewMockUserRepository(ctrl)
+	mockProjects := mocks.NewMockProjectRepository(ctrl)
+	allowProjects(mockProjects)
+	// Any service implementing task.DeleteHook will do.
+	mockHook := mocks.NewMockCommentService(ctrl)
+
+	tests := []struct {
+		TestCase string
+		Mode     task.DeleteMode
+		Setup    func()
+	}{
+		{
+			TestCase: "Reparent moves children to the grandparent",
+			Mode:     task.DeleteReparent,
+			Setup: func() {
+				mockRepo.EXPECT().GetByID(gomock.Any(), 2).Return(task.Info{ID: 2, Name: "Middle", ParentID: intPtr(1)}, nil)
+				mockRepo.EXPECT().GetChildren(gomock.Any(), 2).Return([]task.Info{{ID: 3, Name: "Leaf", ParentID: intPtr(2)}}, nil)
+				mockRepo.EXPECT().Update(gomock.Any(), task.Info{ID: 3, Name: "Leaf", ParentID: intPtr(1)}).Return(task.Info{ID: 3, Name: "Leaf", ParentID: intPtr(1)}, nil)
+				mockRepo.EXPECT().Delete(gomock.Any(), 2).Return(nil)
+				mockLabels.EXPECT().GetTaskLabels(gomock.Any(), []int{3}).Return(nil, nil)
+				mockLabels.EXPECT().DetachAll(gomock.Any(), 2).Return(nil)
+				mockHook.EXPECT().TaskDeleted(2).Return(nil)
+			},
+		},
+		{
+			TestCase: "Cascade deletes the subtree deepest first",
+			Mode:     task.DeleteCascade,
+			Setup: func() {
+				mockRepo.EXPECT().GetByID(gomock.Any(), 2).Return(task.Info{ID: 2, Name: "Middle", ParentID: intPtr(1)}, nil)
+				mockRepo.EXPECT().GetDescendants(gomock.Any(), 2).Return([]task.Info{
+					{ID: 3, Name: "Leaf", ParentID: intPtr(2)},
+					{ID: 4, Name: "Deeper", ParentID: intPtr(3)},
+				}, nil)
+				gomock.InOrder(
+					mockRepo.EXPECT().Delete(gomock.Any(), 4).Return(nil),
+					mockRepo.EXPECT().Delete(gomock.Any(), 3).Return(nil),
+					mockRepo.EXPECT().Delete(gomock.Any(), 2).Return(nil),
+				)
+				mockLabels.EXPECT().DetachAll(gomock.Any(), gomock.Any()).Return(nil).Times(3)
+				mockHook.EXPECT().TaskDeleted(4).Return(nil)
+				mockHook.EXPECT().TaskDeleted(3).Return(nil)
+				// A failing hook does not fail the delete.
+				mockHook.EXPECT().TaskDeleted(2).Return(errors.New("storage unavailable"))
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.TestCase, func(t *testing.T) {
+			tc.Setup()
+			service := NewTaskService(mockRepo, mockLabels, mockUsers, mockProjects, allowAll{}, discard{}, direct{}, events.NewBroker(16), config.TaskConfig{DeleteChildren: tc.Mode}, []task.DeleteHook{mockHook})
+			assert.NoError(t, service.DeleteTask(context.Background(), 2))
+		})
+	}
+}
+
+func Test_Blockers(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockRepository(ctrl)
+	mockLabels := mocks.NewMockLabelRepository(ctrl)
+	mockUsers := mocks.NewMockUserRepository(ctrl)
+	mockProjects := mocks.NewMockProjectRepository(ctrl)
+	allowProjects(mockProjects)
+	service := NewTaskService(mockRepo, mockLabels, mockUsers, mockProjects, allowAll{}, discard{}, direct{}, events.NewBroker(16), config.TaskConfig{DeleteChildren: task.DeleteReparent}, nil)
+
+	tests := []struct {
+		TestCase string
+		Run      func() error
+		Error    error
+		Setup    func()
+	}{
+		{
+			TestCase: "Add blocker",
+			Run:      func() error { return service.AddBlocker(context.Background(), 2, 1) },
+			Error:    nil,
+			Setup: func() {
+				mockRepo.EXPECT().GetByID(gomock.Any(), 2).Return(task.Info{ID: 2, ProjectID: project.DefaultProjectID}, nil)
+				mockRepo.EXPECT().GetDependencies(gomock.Any()).Return([]task.Dependency{{BlockerID: 3, BlockedID: 2}}, nil)
+				mockRepo.EXPECT().AddDependency(gomock.Any(), 1, 2).Return(nil)
+			},
+		},
+		{
+			TestCase: "Task cannot block itself",
+			Run:      func() error { return service.AddBlocker(context.Background(), 1, 1) },
+			Error:    task.ErrDependencyCycle,
+			Setup: func() {
+				mockRepo.EXPECT().GetByID(gomock.Any(), 1).Return(task.Info{ID: 1, ProjectID: project.DefaultProjectID}, nil)
+			},
+		},
+		{
+			TestCase: "Transitive cycle is refused",
+			Run:      func() error { return service.AddBlocker(context.Background(), 1, 3) },
+			Error:    task.ErrDependencyCycle,
+			Setup: func() {
+				mockRepo.EXPECT().GetByID(gomock.Any(), 1).Return(task.Info{ID: 1, ProjectID: project.DefaultProjectID}, nil)
+				mockRepo.EXPECT().GetDependencies(gomock.Any()).Return([]task.Dependency{{BlockerID: 1, BlockedID: 2}, {BlockerID: 2, BlockedID: 3}}, nil)
+			},
+		},
+		{
+			TestCase: "Cannot complete a task with open blockers",
+			Run: func() error {
+				_, err := service.UpdateTask(context.Background(), task.Info{ID: 2, Name: "Build", Status: task.StatusDone})
+				return err
+			},
+			Error: task.ErrBlocked,
+			Setup: func() {
+				mockRepo.EXPECT().GetByID(gomock.Any(), 2).Return(task.Info{ID: 2, Name: "Build", ProjectID: project.DefaultProjectID}, nil)
+				mockRepo.EXPECT().GetBlockers(gomock.Any(), 2).Return([]task.Info{
+					{ID: 1, Name: "Design", Status: task.StatusDone},
+					{ID: 3, Name: "Review", Status: task.StatusPending},
+				}, nil)
+			},
+		},
+		{
+			TestCase: "Complete a task whose blockers are done",
+			Run: func() error {
+				_, err := service.UpdateTask(context.Background(), task.Info{ID: 2, Name: "Build", Status: task.StatusDone})
+				return err
+			},
+			Error: nil,
+			Setup: func() {
+				mockRepo.EXPECT().GetByID(gomock.Any(), 2).Return(task.Info{ID: 2, Name: "Build", ProjectID: project.DefaultProjectID}, nil)
+				mockRepo.EXPECT().GetBlockers(gomock.Any(), 2).Return([]task.Info{{ID: 1, Name: "Design", Status: task.StatusDone}}, nil)
+				mockRepo.EXPECT().Update(gomock.Any(), task.Info{ID: 2, Name: "Build", Status: task.StatusDone, ProjectID: project.DefaultProjectID}).
+					Return(task.Info{ID: 2, Name: "Build", Status: task.StatusDone, ProjectID: project.DefaultProjectID}, nil)
+				mockLabels.EXPECT().GetTaskLabels(gomock.Any(), []int{2}).Return(nil, nil)
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.TestCase, func(t *testing.T) {
+			tc.Setup()
+			assert.Equal(t, tc.Error, tc.Run())
+		})
+	}
+}
+
+func Test_GetExecutionOrder(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockRepository(ctrl)
+	mockLabels := mocks.NewMockLabelRepository(ctrl)
+	mockUsers := mocks.NewMockUserRepository(ctrl)
+	mockProjects := mocks.NewMockProjectRepository(ctrl)
+	allowProjects(mockProjects)
+	service := NewTaskService(mockRepo, mockLabels, mockUsers, mockProjects, allowAll{}, discard{}, direct{}, events.NewBroker(16), config.TaskConfig{DeleteChildren: task.DeleteReparent}, nil)
+
+	mockRepo.EXPECT().GetAll(gomock.Any()).Return([]task.Info{{ID: 2, Name: "Ship"}, {ID: 1, Name: "Build"}}, nil)
+	mockRepo.EXPECT().GetDependencies(gomock.Any()).Return([]task.Dependency{{BlockerID: 2, BlockedID: 1}}, nil)
+
+	order, err := service.GetExecutionOrder(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, []task.Info{{ID: 2, Name: "Ship"}, {ID: 1, Name: "Build"}}, order)
+}
+
+func Test_Labels(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockRepository(ctrl)
+	mockLabels := mocks.NewMockLabelRepository(ctrl)
+	mockUsers := mocks.NewMockUserRepository(ctrl)
+	mockProjects := mocks.NewMockProjectRepository(ctrl)
+	allowProjects(mockProjects)
+	service := NewTaskService(mockRepo, mockLabels, mockUsers, mockProjects, allowAll{}, discard{}, direct{}, events.NewBroker(16), config.TaskConfig{DeleteChildren: task.DeleteReparent}, nil)
+
+	backend := label.Label{ID: 1, Name: "backend"}
+	urgent := label.Label{ID: 2, Name: "urgent"}
+	allTasks := []task.Info{{ID: 1, Name: "API"}, {ID: 2, Name: "UI"}, {ID: 3, Name: "Hotfix"}}
+
+	tests := []struct {
+		TestCase string
+		Names    []string
+		Match    label.Match
+		Expected []task.Info
+		Error    error
+		Setup    func()
+	}{
+		{
+			TestCase: "Any of the labels",
+			Names:    []string{"backend", "urgent", "unknown"},
+			Match:    label.MatchAny,
+			Expected: []task.Info{
+				{ID: 1, Name: "API", Labels: []string{"backend"}},
+				{ID: 3, Name: "Hotfix", Labels: []string{"backend", "urgent"}},
+			},
+			Setup: func() {
+				mockLabels.EXPECT().GetByName(gomock.Any(), "backend").Return(backend, nil)
+				mockLabels.EXPECT().GetByName(gomock.Any(), "urgent").Return(urgent, nil)
+				mockLabels.EXPECT().GetByName(gomock.Any(), "unknown").Return(label.Label{}, label.ErrNotFound)
+				mockLabels.EXPECT().FindTasks(gomock.Any(), []int{1, 2}, label.MatchAny).Return([]int{1, 3}, nil)
+				mockRepo.EXPECT().GetAll(gomock.Any()).Return(allTasks, nil)
+				mockLabels.EXPECT().GetTaskLabels(gomock.Any(), []int{1, 2, 3}).Return(map[int][]label.Label{
+					1: {backend},
+					3: {backend, urgent},
+				}, nil)
+			},
+		},
+		{
+			TestCase: "All of the labels with an unknown one matches nothing",
+			Names:    []string{"backend", "unknown"},
+			Match:    label.MatchAll,
+			Expected: nil,
+			Setup: func() {
+				mockLabels.EXPECT().GetByName(gomock.Any(), "backend").Return(backend, nil)
+				mockLabels.EXPECT().GetByName(gomock.Any(), "unknown").Return(label.Label{}, label.ErrNotFound)
+			},
+		},
+		{
+			TestCase: "Invalid match",
+			Names:    []string{"backend"},
+			Match:    "some",
+			Error:    label.ErrInvalidMatch,
+			Setup:    func() {},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.TestCase, func(t *testing.T) {
+			tc.Setup()
+			result, err := service.GetTasksByLabels(context.Background(), tc.Names, tc.Match)
+			if tc.Error != nil {
+				assert.Equal(t, tc.Error, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.Expected, result)
+		})
+	}
+}
+
+func Test_AttachLabel(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockRepository(ctrl)
+	mockLabels := mocks.NewMockLabelRepository(ctrl)
+	mockUsers := mocks.NewMockUserRepository(ctrl)
+	mockProjects := mocks.NewMockProjectRepository(ctrl)
+	allowProjects(mockProjects)
+	service := NewTaskService(mockRepo, mockLabels, mockUsers, mockProjects, allowAll{}, discard{}, direct{}, events.NewBroker(16), config.TaskConfig{DeleteChildren: task.DeleteReparent}, nil)
+
+	mockRepo.EXPECT().GetByID(gomock.Any(), 1).Return(task.Info{ID: 1, Name: "API"}, nil)
+	mockLabels.EXPECT().Attach(gomock.Any(), 1, 2).Return(nil)
+	mockLabels.EXPECT().GetTaskLabels(gomock.Any(), []int{1}).Return(map[int][]label.Label{1: {{ID: 2, Name: "urgent"}}}, nil)
+
+	result, err := service.AttachLabel(context.Background(), 1, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, task.Info{ID: 1, Name: "API", Labels: []string{"urgent"}}, result)
+
+	mockRepo.EXPECT().GetByID(gomock.Any(), 1).Return(task.Info{ID: 1, Name: "API"}, nil)
+	mockLabels.EXPECT().Detach(gomock.Any(), 1, 3).Return(label.ErrNotAttached)
+
+	_, err = service.DetachLabel(context.Background(), 1, 3)
+	assert.Equal(t, label.ErrNotAttached, err)
+}
+
+func Test_AssignTask(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockRepository(ctrl)
+	mockLabels := mocks.NewMockLabelRepository(ctrl)
+	mockUsers := mocks.NewMockUserRepository(ctrl)
+	mockProjects := mocks.NewMockProjectRepository(ctrl)
+	allowProjects(mockProjects)
+	service := NewTaskService(mockRepo, mockLabels, mockUsers, mockProjects, allowAll{}, discard{}, direct{}, events.NewBroker(16), config.TaskConfig{DeleteChildren: task.DeleteReparent}, nil)
+
+	tests := []struct {
+		TestCase   string
+		AssigneeID *int
+		Expected   task.Info
+		Error      error
+		Setup      func()
+	}{
+		{
+			TestCase:   "Assign to an existing user",
+			AssigneeID: intPtr(7),
+			Expected:   task.Info{ID: 1, Name: "API", ProjectID: project.DefaultProjectID, AssigneeID: intPtr(7)},
+			Setup: func() {
+				mockRepo.EXPECT().GetByID(gomock.Any(), 1).Return(task.Info{ID: 1, Name: "API", ProjectID: project.DefaultProjectID}, nil)
+				mockUsers.EXPECT().GetByID(7).Return(user.User{ID: 7, Username: "alice"}, nil)
+				mockRepo.EXPECT().Update(gomock.Any(), task.Info{ID: 1, Name: "API", ProjectID: project.DefaultProjectID, AssigneeID: intPtr(7)}).Return(task.Info{ID: 1, Name: "API", ProjectID: project.DefaultProjectID, AssigneeID: intPtr(7)}, nil)
+				mockLabels.EXPECT().GetTaskLabels(gomock.Any(), []int{1}).Return(nil, nil)
+			},
+		},
+		{
+			TestCase:   "Assign to an unknown user",
+			AssigneeID: intPtr(9),
+			Error:      task.ErrAssigneeNotFound,
+			Setup: func() {
+				mockRepo.EXPECT().GetByID(gomock.Any(), 1).Return(task.Info{ID: 1, Name: "API", ProjectID: project.DefaultProjectID}, nil)
+				mockUsers.EXPECT().GetByID(9).Return(user.User{}, user.ErrNotFound)
+			},
+		},
+		{
+			TestCase: "Unassign",
+			Expected: task.Info{ID: 1, Name: "API", ProjectID: project.DefaultProjectID},
+			Setup: func() {
+				mockRepo.EXPECT().GetByID(gomock.Any(), 1).Return(task.Info{ID: 1, Name: "API", ProjectID: project.DefaultProjectID, AssigneeID: intPtr(7)}, nil)
+				mockRepo.EXPECT().Update(gomock.Any(), task.Info{ID: 1, Name: "API", ProjectID: project.DefaultProjectID}).Return(task.Info{ID: 1, Name: "API", ProjectID: project.DefaultProjectID}, nil)
+				mockLabels.EXPECT().GetTaskLabels(gomock.Any(), []int{1}).Return(nil, nil)
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.TestCase, func(t *testing.T) {
+			tc.Setup()
+			result, err := service.AssignTask(context.Background(), 1, tc.AssigneeID)
+			assert.Equal(t, tc.Error, err)
+			assert.Equal(t, tc.Expected, result)
+		})
+	}
+}
+
+func Test_CreateTaskWithAssignee(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockRepository(ctrl)
+	mockUsers := mocks.NewMockUserRepository(ctrl)
+	mockProjects := mocks.NewMockProjectRepository(ctrl)
+	allowProjects(mockProjects)
+	service := NewTaskService(mockRepo, mocks.NewMockLabelRepository(ctrl), mockUsers, mockProjects, allowAll{}, discard{}, direct{}, events.NewBroker(16), config.TaskConfig{DeleteChildren: task.DeleteReparent}, nil)
+
+	mockUsers.EXPECT().GetByID(9).Return(user.User{}, user.ErrNotFound)
+
+	_, err := service.CreateTask(context.Background(), task.Info{Name: "API", AssigneeID: intPtr(9)})
+	assert.Equal(t, task.ErrAssigneeNotFound, err)
+}
+
+func Test_GetTasksByAssignee(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockRepository(ctrl)
+	mockLabels := mocks.NewMockLabelRepository(ctrl)
+	mockUsers := mocks.NewMockUserRepository(ctrl)
+	mockProjects := mocks.NewMockProjectRepository(ctrl)
+	allowProjects(mockProjects)
+	service := NewTaskService(mockRepo, mockLabels, mockUsers, mockProjects, allowAll{}, discard{}, direct{}, events.NewBroker(16), config.TaskConfig{DeleteChildren: task.DeleteReparent}, nil)
+
+	mockUsers.EXPECT().GetByID(7).Return(user.User{ID: 7, Username: "alice"}, nil)
+	mockRepo.EXPECT().GetByAssignee(gomock.Any(), 7).Return([]task.Info{{ID: 1, Name: "API", AssigneeID: intPtr(7)}}, nil)
+	mockLabels.EXPECT().GetTaskLabels(gomock.Any(), []int{1}).Return(map[int][]label.Label{1: {{ID: 2, Name: "urgent"}}}, nil)
+
+	tasks, err := service.GetTasksByAssignee(context.Background(), 7)
+	assert.NoError(t, err)
+	assert.Equal(t, []task.Info{{ID: 1, Name: "API", AssigneeID: intPtr(7), Labels: []string{"urgent"}}}, tasks)
+
+	mockUsers.EXPECT().GetByID(8).Return(user.User{}, user.ErrNotFound)
+
+	_, err = service.GetTasksByAssignee(context.Background(), 8)
+	assert.Equal(t, user.ErrNotFound, err)
+}
+
+func Test_Projects(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockRepository(ctrl)
+	mockLabels := mocks.NewMockLabelRepository(ctrl)
+	mockProjects := mocks.NewMockProjectRepository(ctrl)
+	service := NewTaskService(mockRepo, mockLabels, mocks.NewMockUserRepository(ctrl), mockProjects, allowAll{}, discard{}, direct{}, events.NewBroker(16), config.TaskConfig{DeleteChildren: task.DeleteReparent}, nil)
+
+	archivedAt := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	archived := project.Project{ID: 2, Name: "Legacy", ArchivedAt: &archivedAt}
+
+	tests := []struct {
+		TestCase string
+		Run      func() (interface{}, error)
+		Expected interface{}
+		Error    error
+		Setup    func()
+	}{
+		{
+			TestCase: "Create in an unknown project",
+			Run: func() (interface{}, error) {
+				return service.CreateTask(context.Background(), task.Info{Name: "Task", ProjectID: 9})
+			},
+			Expected: task.Info{},
+			Error:    task.ErrProjectNotFound,
+			Setup: func() {
+				mockProjects.EXPECT().GetByID(9).Return(project.Project{}, project.ErrNotFound)
+			},
+		},
+		{
+			TestCase: "Tasks of archived projects are read-only",
+			Run: func() (interface{}, error) {
+				return service.UpdateTask(context.Background(), task.Info{ID: 1, Name: "Renamed"})
+			},
+			Expected: task.Info{},
+			Error:    project.ErrArchived,
+			Setup: func() {
+				mockRepo.EXPECT().GetByID(gomock.Any(), 1).Return(task.Info{ID: 1, Name: "Task", ProjectID: 2}, nil)
+				mockProjects.EXPECT().GetByID(2).Return(archived, nil)
+			},
+		},
+		{
+			TestCase: "Tasks of archived projects are hidden",
+			Run: func() (interface{}, error) {
+				return service.GetAllTasks(context.Background())
+			},
+			Expected: []task.Info{{ID: 1, Name: "Task", ProjectID: project.DefaultProjectID}},
+			Setup: func() {
+				mockRepo.EXPECT().GetAll(gomock.Any()).Return([]task.Info{
+					{ID: 1, Name: "Task", ProjectID: project.DefaultProjectID},
+					{ID: 2, Name: "Old", ProjectID: 2},
+				}, nil)
+				mockProjects.EXPECT().GetAll(true).Return([]project.Project{{ID: project.DefaultProjectID, Name: "Default"}, archived}, nil)
+				mockLabels.EXPECT().GetTaskLabels(gomock.Any(), []int{1}).Return(nil, nil)
+			},
+		},
+		{
+			TestCase: "List the tasks of a project",
+			Run: func() (interface{}, error) {
+				return service.GetTasksByProject(context.Background(), 2)
+			},
+			Expected: []task.Info{{ID: 2, Name: "Old", ProjectID: 2}},
+			Setup: func() {
+				mockProjects.EXPECT().GetByID(2).Return(archived, nil)
+				mockRepo.EXPECT().GetByProject(gomock.Any(), 2).Return([]task.Info{{ID: 2, Name: "Old", ProjectID: 2}}, nil)
+				mockLabels.EXPECT().GetTaskLabels(gomock.Any(), []int{2}).Return(nil, nil)
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.TestCase, func(t *testing.T) {
+			tc.Setup()
+			result, err := tc.Run()
+			assert.Equal(t, tc.Error, err)
+			assert.Equal(t, tc.Expected, result)
+		})
+	}
+}
+
+func Test_Recurrence(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockRepository(ctrl)
+	mockLabels := mocks.NewMockLabelRepository(ctrl)
+	mockProjects := mocks.NewMockProjectRepository(ctrl)
+	allowProjects(mockProjects)
+	service := NewTaskService(mockRepo, mockLabels, mocks.NewMockUserRepository(ctrl), mockProjects, allowAll{}, discard{}, direct{}, events.NewBroker(16), config.TaskConfig{DeleteChildren: task.DeleteReparent}, nil)
+
+	// Mondays at 09:00 in Berlin, starting before DST begins.
+	monday := time.Date(2024, 3, 25, 8, 0, 0, 0, time.UTC)
+	nextMonday := time.Date(2024, 4, 1, 7, 0, 0, 0, time.UTC)
+	weekly := func(rule string) *task.Recurrence {
+		return &task.Recurrence{Rule: rule, Timezone: "Europe/Berlin", Start: monday}
+	}
+	errStorage := errors.New("storage unavailable")
+
+	tests := []struct {
+		TestCase string
+		Run      func() (task.Info, error)
+		Expected task.Info
+		Error    error
+		Setup    func()
+	}{
+		{
+			TestCase: "Recurrence needs a due date",
+			Run: func() (task.Info, error) {
+				return service.CreateTask(context.Background(), task.Info{Name: "Report", Recurrence: weekly("FREQ=WEEKLY")})
+			},
+			Error: task.ErrInvalidRecurrence,
+			Setup: func() {},
+		},
+		{
+			TestCase: "Invalid rule",
+			Run: func() (task.Info, error) {
+				return service.CreateTask(context.Background(), task.Info{Name: "Report", DueAt: &monday, Recurrence: weekly("FREQ=HOURLY")})
+			},
+			Error: task.ErrInvalidRecurrence,
+			Setup: func() {},
+		},
+		{
+			TestCase: "Unknown time zone",
+			Run: func() (task.Info, error) {
+				return service.CreateTask(context.Background(), task.Info{Name: "Report", DueAt: &monday, Recurrence: &task.Recurrence{Rule: "FREQ=WEEKLY", Timezone: "Mars/Olympus"}})
+			},
+			Error: task.ErrInvalidRecurrence,
+			Setup: func() {},
+		},
+		{
+			TestCase: "The series starts at the due date",
+			Run: func() (task.Info, error) {
+				return service.CreateTask(context.Background(), task.Info{Name: "Report", DueAt: &monday, Recurrence: &task.Recurrence{Rule: "FREQ=WEEKLY", Timezone: "Europe/Berlin"}})
+			},
+			Expected: task.Info{ID: 1, Name: "Report", ProjectID: project.DefaultProjectID, DueAt: &monday, Recurrence: weekly("FREQ=WEEKLY")},
+			Setup: func() {
+				created := task.Info{Name: "Report", ProjectID: project.DefaultProjectID, DueAt: &monday, Recurrence: weekly("FREQ=WEEKLY")}
+				mockRepo.EXPECT().Create(gomock.Any(), created).DoAndReturn(func(_ context.Context, t task.Info) (task.Info, error) {
+					t.ID = 1
+					return t, nil
+				})
+			},
+		},
+		{
+			TestCase: "Completing an instance creates the next one",
+			Run: func() (task.Info, error) {
+				return service.UpdateTask(context.Background(), task.Info{ID: 1, Name: "Report", Status: task.StatusDone, DueAt: &monday, Recurrence: weekly("FREQ=WEEKLY")})
+			},
+			Expected: task.Info{ID: 1, Name: "Report", Status: task.StatusDone, ProjectID: project.DefaultProjectID, DueAt: &monday},
+			Setup: func() {
+				mockRepo.EXPECT().GetByID(gomock.Any(), 1).Return(task.Info{ID: 1, Name: "Report", ProjectID: project.DefaultProjectID, DueAt: &monday, Recurrence: weekly("FREQ=WEEKLY")}, nil)
+				mockRepo.EXPECT().GetBlockers(gomock.Any(), 1).Return(nil, nil)
+				mockRepo.EXPECT().Create(gomock.Any(), task.Info{Name: "Report", ProjectID: project.DefaultProjectID, DueAt: &nextMonday, Recurrence: weekly("FREQ=WEEKLY")}).
+					Return(task.Info{ID: 2, Name: "Report", ProjectID: project.DefaultProjectID, DueAt: &nextMonday, Recurrence: weekly("FREQ=WEEKLY")}, nil)
+				mockLabels.EXPECT().GetTaskLabels(gomock.Any(), []int{1}).Return(map[int][]label.Label{1: {{ID: 3, Name: "reports"}}}, nil)
+				mockLabels.EXPECT().Attach(gomock.Any(), 2, 3).Return(nil)
+				mockLabels.EXPECT().GetTaskLabels(gomock.Any(), []int{2}).Return(map[int][]label.Label{2: {{ID: 3, Name: "reports"}}}, nil)
+				mockRepo.EXPECT().Update(gomock.Any(), task.Info{ID: 1, Name: "Report", Status: task.StatusDone, ProjectID: project.DefaultProjectID, DueAt: &monday}).
+					Return(task.Info{ID: 1, Name: "Report", Status: task.StatusDone, ProjectID: project.DefaultProjectID, DueAt: &monday}, nil)
+				mockLabels.EXPECT().GetTaskLabels(gomock.Any(), []int{1}).Return(nil, nil)
+			},
+		},
+		{
+			TestCase: "No next instance when the completion is not stored",
+			Run: func() (task.Info, error) {
+				return service.UpdateTask(context.Background(), task.Info{ID: 1, Name: "Report", Status: task.StatusDone, DueAt: &monday, Recurrence: weekly("FREQ=WEEKLY")})
+			},
+			Error: errStorage,
+			Setup: func() {
+				mockRepo.EXPECT().GetByID(gomock.Any(), 1).Return(task.Info{ID: 1, Name: "Report", ProjectID: project.DefaultProjectID, DueAt: &monday, Recurrence: weekly("FREQ=WEEKLY")}, nil)
+				mockRepo.EXPECT().GetBlockers(gomock.Any(), 1).Return(nil, nil)
+				mockRepo.EXPECT().Update(gomock.Any(), task.Info{ID: 1, Name: "Report", Status: task.StatusDone, ProjectID: project.DefaultProjectID, DueAt: &monday}).
+					Return(task.Info{}, errStorage)
+			},
+		},
+		{
+			TestCase: "The task is reopened when the next instance cannot be created",
+			Run: func() (task.Info, error) {
+				return service.UpdateTask(context.Background(), task.Info{ID: 1, Name: "Report", Status: task.StatusDone, DueAt: &monday, Recurrence: weekly("FREQ=WEEKLY")})
+			},
+			Error: errStorage,
+			Setup: func() {
+				open := task.Info{ID: 1, Name: "Report", ProjectID: project.DefaultProjectID, DueAt: &monday, Recurrence: weekly("FREQ=WEEKLY")}
+				mockRepo.EXPECT().GetByID(gomock.Any(), 1).Return(open, nil)
+				mockRepo.EXPECT().GetBlockers(gomock.Any(), 1).Return(nil, nil)
+				gomock.InOrder(
+					mockRepo.EXPECT().Update(gomock.Any(), task.Info{ID: 1, Name: "Report", Status: task.StatusDone, ProjectID: project.DefaultProjectID, DueAt: &monday}).
+						Return(task.Info{ID: 1, Name: "Report", Status: task.StatusDone, ProjectID: project.DefaultProjectID, DueAt: &monday}, nil),
+					mockRepo.EXPECT().Create(gomock.Any(), gomock.Any()).Return(task.Info{}, errStorage),
+					mockRepo.EXPECT().Update(gomock.Any(), open).Return(open, nil),
+				)
+			},
+		},
+		{
+			TestCase: "Completing the last instance ends the series",
+			Run: func() (task.Info, error) {
+				return service.UpdateTask(context.Background(), task.Info{ID: 1, Name: "Report", Status: task.StatusDone, DueAt: &monday, Recurrence: weekly("FREQ=WEEKLY;COUNT=1")})
+			},
+			Expected: task.Info{ID: 1, Name: "Report", Status: task.StatusDone, ProjectID: project.DefaultProjectID, DueAt: &monday},
+			Setup: func() {
+				mockRepo.EXPECT().GetByID(gomock.Any(), 1).Return(task.Info{ID: 1, Name: "Report", ProjectID: project.DefaultProjectID, DueAt: &monday, Recurrence: weekly("FREQ=WEEKLY;COUNT=1")}, nil)
+				mockRepo.EXPECT().GetBlockers(gomock.Any(), 1).Return(nil, nil)
+				mockRepo.EXPECT().Update(gomock.Any(), task.Info{ID: 1, Name: "Report", Status: task.StatusDone, ProjectID: project.DefaultProjectID, DueAt: &monday}).
+					Return(task.Info{ID: 1, Name: "Report", Status: task.StatusDone, ProjectID: project.DefaultProjectID, DueAt: &monday}, nil)
+				mockLabels.EXPECT().GetTaskLabels(gomock.Any(), []int{1}).Return(nil, nil)
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.TestCase, func(t *testing.T) {
+			tc.Setup()
+			result, err := tc.Run()
+			assert.ErrorIs(t, err, tc.Error)
+			assert.Equal(t, tc.Expected, result)
+		})
+	}
+}
+
+func Test_GetOccurrences(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockRepository(ctrl)
+	service := NewTaskService(mockRepo, mocks.NewMockLabelRepository(ctrl), mocks.NewMockUserRepository(ctrl), mocks.NewMockProjectRepository(ctrl), allowAll{}, discard{}, direct{}, events.NewBroker(16), config.TaskConfig{DeleteChildren: task.DeleteReparent}, nil)
+
+	start := time.Date(2024, 3, 25, 8, 0, 0, 0, time.UTC)
+	mockRepo.EXPECT().GetByID(gomock.Any(), 1).Return(task.Info{ID: 1, Name: "Report", DueAt: &start,
+		Recurrence: &task.Recurrence{Rule: "FREQ=WEEKLY", Timezone: "Europe/Berlin", Start: start}}, nil)
+
+	occurrences, err := service.GetOccurrences(context.Background(), 1, start, time.Date(2024, 4, 8, 0, 0, 0, 0, time.UTC))
+	assert.NoError(t, err)
+	var formatted []string
+	for _, o := range occurrences {
+		formatted = append(formatted, o.Format(time.RFC3339))
+	}
+	assert.Equal(t, []string{"2024-03-25T09:00:00+01:00", "2024-04-01T09:00:00+02:00"}, formatted)
+
+	mockRepo.EXPECT().GetByID(gomock.Any(), 2).Return(task.Info{ID: 2, Name: "Once"}, nil)
+
+	_, err = service.GetOccurrences(context.Background(), 2, start, time.Time{})
+	assert.Equal(t, task.ErrNotRecurring, err)
+}
+
+func Test_SearchTasks(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockRepository(ctrl)
+	mockLabels := mocks.NewMockLabelRepository(ctrl)
+	mockProjects := mocks.NewMockProjectRepository(ctrl)
+	service := NewTaskService(mockRepo, mockLabels, mocks.NewMockUserRepository(ctrl), mockProjects, allowAll{}, discard{}, direct{}, events.NewBroker(16), config.TaskConfig{DeleteChildren: task.DeleteReparent}, nil)
+
+	archivedAt := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	found := []task.SearchResult{
+		{Task: task.Info{ID: 1, Name: "Fix login", ProjectID: 1}, Score: 3},
+		{Task: task.Info{ID: 2, Name: "Old login page", ProjectID: 2}, Score: 2},
+		{Task: task.Info{ID: 3, Name: "Login <b>copy</b>", ProjectID: 1}, Score: 1},
+	}
+
+	tests := []struct {
+		TestCase string
+		Query    string
+		Limit    int
+		Setup    func()
+		Expected []task.SearchResult
+		Error    error
+	}{
+		{
+			TestCase: "Archived projects are left out",
+			Query:    "login",
+			Setup: func() {
+				mockRepo.EXPECT().Search(gomock.Any(), gomock.Any()).Return(found, nil)
+				mockProjects.EXPECT().GetAll(true).Return([]project.Project{{ID: 1}, {ID: 2, ArchivedAt: &archivedAt}}, nil)
+				mockLabels.EXPECT().GetTaskLabels(gomock.Any(), []int{1, 3}).Return(map[int][]label.Label{1: {{ID: 7, Name: "auth"}}}, nil)
+			},
+			Expected: []task.SearchResult{
+				{Task: task.Info{ID: 1, Name: "Fix login", ProjectID: 1, Labels: []string{"auth"}}, Score: 3, Snippet: "Fix <mark>login</mark>"},
+				{Task: task.Info{ID: 3, Name: "Login <b>copy</b>", ProjectID: 1}, Score: 1, Snippet: "<mark>Login</mark> &lt;b&gt;copy&lt;/b&gt;"},
+			},
+		},
+		{
+			TestCase: "Limit",
+			Query:    "login",
+			Limit:    1,
+			Setup: func() {
+				mockRepo.EXPECT().Search(gomock.Any(), gomock.Any()).Return(found, nil)
+				mockProjects.EXPECT().GetAll(true).Return([]project.Project{{ID: 1}, {ID: 2}}, nil)
+				mockLabels.EXPECT().GetTaskLabels(gomock.Any(), []int{1}).Return(nil, nil)
+			},
+			Expected: []task.SearchResult{
+				{Task: task.Info{ID: 1, Name: "Fix login", ProjectID: 1}, Score: 3, Snippet: "Fix <mark>login</mark>"},
+			},
+		},
+		{
+			TestCase: "Query without words",
+			Query:    " * ",
+			Setup:    func() {},
+			Error:    task.ErrInvalidSearch,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.TestCase, func(t *testing.T) {
+			tc.Setup()
+			results, err := service.SearchTasks(context.Background(), tc.Query, tc.Limit)
+			if tc.Error != nil {
+				assert.ErrorIs(t, err, tc.Error)
+				assert.True(t, task.IsInvalid(err))
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.Expected, results)
+		})
+	}
+}
+
+// allowProjects lets every task live in the active default project, for
+// tests that are not about projects.
+func Test_Authorization(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockRepository(ctrl)
+	mockLabels := mocks.NewMockLabelRepository(ctrl)
+	mockProjects := mocks.NewMockProjectRepository(ctrl)
+	mockPolicy := mocks.NewMockPolicy(ctrl)
+	allowProjects(mockProjects)
+	service := NewTaskService(mockRepo, mockLabels, mocks.NewMockUserRepository(ctrl), mockProjects, mockPolicy, discard{}, direct{}, events.NewBroker(16), config.TaskConfig{DeleteChildren: task.DeleteReparent}, nil)
+	cascading := NewTaskService(mockRepo, mockLabels, mocks.NewMockUserRepository(ctrl), mockProjects, mockPolicy, discard{}, direct{}, events.NewBroker(16), config.TaskConfig{DeleteChildren: task.DeleteCascade}, nil)
+
+	ctx := context.Background()
+	denied := fmt.Errorf("%w: viewer may not update tasks", role.ErrForbidden)
+	visible := task.Info{ID: 1, Name: "Visible", ProjectID: project.DefaultProjectID}
+	hidden := task.Info{ID: 2, Name: "Hidden", ProjectID: 7}
+
+	tests := []struct {
+		TestCase string
+		Run      func() error
+		Error    error
+		Setup    func()
+	}{
+		{
+			TestCase: "Tasks are created in the default project",
+			Run: func() error {
+				_, err := service.CreateTask(ctx, task.Info{Name: "Task"})
+				return err
+			},
+			Setup: func() {
+				mockPolicy.EXPECT().Authorize(ctx, role.ActionCreate, project.DefaultProjectID).Return(nil)
+				mockRepo.EXPECT().Create(gomock.Any(), task.Info{Name: "Task", ProjectID: project.DefaultProjectID}).Return(task.Info{ID: 1, Name: "Task", ProjectID: project.DefaultProjectID}, nil)
+			},
+		},
+		{
+			TestCase: "Denied delete leaves the task alone",
+			Run:      func() error { return service.DeleteTask(ctx, 1) },
+			Error:    denied,
+			Setup: func() {
+				mockRepo.EXPECT().GetByID(gomock.Any(), 1).Return(task.Info{ID: 1, Name: "Task", ProjectID: 4}, nil)
+				mockPolicy.EXPECT().Authorize(ctx, role.ActionDelete, 4).Return(denied)
+			},
+		},
+		{
+			TestCase: "Moving a task needs both projects",
+			Run: func() error {
+				_, err := service.UpdateTask(ctx, task.Info{ID: 1, Name: "Task", ProjectID: 5})
+				return err
+			},
+			Error: denied,
+			Setup: func() {
+				mockRepo.EXPECT().GetByID(gomock.Any(), 1).Return(task.Info{ID: 1, Name: "Task", ProjectID: project.DefaultProjectID}, nil)
+				mockPolicy.EXPECT().Authorize(ctx, role.ActionUpdate, project.DefaultProjectID).Return(nil)
+				mockPolicy.EXPECT().Authorize(ctx, role.ActionUpdate, 5).Return(denied)
+			},
+		},
+		{
+			TestCase: "A denied subtask keeps the whole subtree",
+			Run:      func() error { return cascading.DeleteTask(ctx, 1) },
+			Error:    denied,
+			Setup: func() {
+				mockRepo.EXPECT().GetByID(gomock.Any(), 1).Return(task.Info{ID: 1, Name: "Task", ProjectID: 4}, nil)
+				mockPolicy.EXPECT().Authorize(ctx, role.ActionDelete, 4).Return(nil).Times(2)
+				mockRepo.EXPECT().GetDescendants(gomock.Any(), 1).Return([]task.Info{
+					{ID: 2, Name: "Child", ProjectID: 4, ParentID: intPtr(1)},
+					{ID: 3, Name: "Grandchild", ProjectID: 6, ParentID: intPtr(2)},
+				}, nil)
+				mockPolicy.EXPECT().Authorize(ctx, role.ActionDelete, 6).Return(denied)
+			},
+		},
+		{
+			TestCase: "Lists leave out the projects the caller may not read",
+			Run: func() error {
+				tasks, err := service.GetAllTasks(ctx)
+				assert.Equal(t, []task.Info{visible}, tasks)
+				return err
+			},
+			Setup: func() {
+				mockPolicy.EXPECT().Authorize(ctx, role.ActionRead, 0).Return(nil)
+				mockRepo.EXPECT().GetAll(gomock.Any()).Return([]task.Info{visible, hidden}, nil)
+				mockPolicy.EXPECT().Authorize(ctx, role.ActionRead, project.DefaultProjectID).Return(nil)
+				mockPolicy.EXPECT().Authorize(ctx, role.ActionRead, 7).Return(denied)
+				mockLabels.EXPECT().GetTaskLabels(gomock.Any(), []int{1}).Return(nil, nil)
+			},
+		},
+		{
+			TestCase: "Lists are authorized across projects",
+			Run: func() error {
+				_, err := service.GetExecutionOrder(ctx)
+				return err
+			},
+			Error: denied,
+			Setup: func() {
+				mockPolicy.EXPECT().Authorize(ctx, role.ActionRead, 0).Return(denied)
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.TestCase, func(t *testing.T) {
+			tc.Setup()
+			assert.Equal(t, tc.Error, tc.Run())
+		})
+	}
+}
+
+func Test_Audit(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockRepository(ctrl)
+	mockLabels := mocks.NewMockLabelRepository(ctrl)
+	mockProjects := mocks.NewMockProjectRepository(ctrl)
+	mockRecorder := mocks.NewMockRecorder(ctrl)
+	allowProjects(mockProjects)
+	service := NewTaskService(mockRepo, mockLabels, mocks.NewMockUserRepository(ctrl), mockProjects, allowAll{}, mockRecorder, direct{}, events.NewBroker(16), config.TaskConfig{DeleteChildren: task.DeleteReparent}, nil)
+
+	ctx := context.Background()
+	draft := task.Info{ID: 1, Name: "Draft", ProjectID: project.DefaultProjectID}
+	final := task.Info{ID: 1, Name: "Final", ProjectID: project.DefaultProjectID}
+	child := task.Info{ID: 2, Name: "Child", ProjectID: project.DefaultProjectID, ParentID: intPtr(1)}
+	orphan := task.Info{ID: 2, Name: "Child", ProjectID: project.DefaultProjectID}
+
+	tests := []struct {
+		TestCase string
+		Run      func() error
+		Setup    func()
+		Error    error
+	}{
+		{
+			TestCase: "Create",
+			Run: func() error {
+				_, err := service.CreateTask(ctx, task.Info{Name: "Draft"})
+				return err
+			},
+			Setup: func() {
+				mockRepo.EXPECT().Create(gomock.Any(), task.Info{Name: "Draft", ProjectID: project.DefaultProjectID}).Return(draft, nil)
+				mockRecorder.EXPECT().Record(ctx, audit.OperationCreate, nil, &draft).Return(nil)
+			},
+		},
+		{
+			TestCase: "Update records the task before and after",
+			Run: func() error {
+				_, err := service.UpdateTask(ctx, task.Info{ID: 1, Name: "Final"})
+				return err
+			},
+			Setup: func() {
+				mockRepo.EXPECT().GetByID(gomock.Any(), 1).Return(draft, nil)
+				mockRepo.EXPECT().Update(gomock.Any(), final).Return(final, nil)
+				mockRecorder.EXPECT().Record(ctx, audit.OperationUpdate, &draft, &final).Return(nil)
+				mockLabels.EXPECT().GetTaskLabels(gomock.Any(), []int{1}).Return(nil, nil)
+			},
+		},
+		{
+			TestCase: "Delete records moved children too",
+			Run:      func() error { return service.DeleteTask(ctx, 1) },
+			Setup: func() {
+				mockRepo.EXPECT().GetByID(gomock.Any(), 1).Return(final, nil)
+				mockRepo.EXPECT().GetChildren(gomock.Any(), 1).Return([]task.Info{child}, nil)
+				mockRepo.EXPECT().Update(gomock.Any(), orphan).Return(orphan, nil)
+				mockRecorder.EXPECT().Record(ctx, audit.OperationUpdate, &child, &orphan).Return(nil)
+				mockLabels.EXPECT().GetTaskLabels(gomock.Any(), []int{2}).Return(nil, nil)
+				mockRepo.EXPECT().Delete(gomock.Any(), 1).Return(nil)
+				mockLabels.EXPECT().DetachAll(gomock.Any(), 1).Return(nil)
+				mockRecorder.EXPECT().Record(ctx, audit.OperationDelete, &final, nil).Return(nil)
+			},
+		},
+		{
+			TestCase: "A failing record fails the change",
+			Run: func() error {
+				_, err := service.CreateTask(ctx, task.Info{Name: "Draft"})
+				return err
+			},
+			Setup: func() {
+				mockRepo.EXPECT().Create(gomock.Any(), task.Info{Name: "Draft", ProjectID: project.DefaultProjectID}).Return(draft, nil)
+				mockRecorder.EXPECT().Record(ctx, audit.OperationCreate, nil, &draft).Return(errors.New("disk full"))
+			},
+			Error: errors.New("audit record of create: disk full"),
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.TestCase, func(t *testing.T) {
+			tc.Setup()
+			err := tc.Run()
+			if tc.Error != nil {
+				assert.EqualError(t, err, tc.Error.Error())
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func allowProjects(mockProjects *mocks.MockProjectRepository) {
+	mockProjects.EXPECT().GetByID(project.DefaultProjectID).Return(project.Project{ID: project.DefaultProjectID, Name: "Default"}, nil).AnyTimes()
+	mockProjects.EXPECT().GetAll(true).Return([]project.Project{{ID: project.DefaultProjectID, Name: "Default"}}, nil).AnyTimes()
+}
+
+func intPtr(v int) *int {
+	return &v
+}
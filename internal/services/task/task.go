@@ -1,11 +1,41 @@
 package task
 
-import "task-api/internal/domain/task"
+import (
+	"context"
+	"time"
 
+	"task-api/internal/domain/label"
+	"task-api/internal/domain/task"
+)
+
+// Service consults the role policy before every operation, which denies
+// callers without an identity in ctx.
 type Service interface {
-	GetAllTasks() ([]task.Info, error)
-	GetTaskByID(id int) (task.Info, error)
-	CreateTask(t task.Info) (task.Info, error)
-	UpdateTask(t task.Info) (task.Info, error)
-	DeleteTask(id int) error
+	GetAllTasks(ctx context.Context) ([]task.Info, error)
+	GetTasksByLabels(ctx context.Context, names []string, match label.Match) ([]task.Info, error)
+	// GetTasksByProject returns the tasks of a project, archived or not.
+	GetTasksByProject(ctx context.Context, projectID int) ([]task.Info, error)
+	GetTaskByID(ctx context.Context, id int) (task.Info, error)
+	GetChildren(ctx context.Context, id int, recursive bool) ([]task.Info, error)
+	CreateTask(ctx context.Context, t task.Info) (task.Info, error)
+	UpdateTask(ctx context.Context, t task.Info) (task.Info, error)
+	DeleteTask(ctx context.Context, id int) error
+	GetBlockers(ctx context.Context, id int) ([]task.Info, error)
+	AddBlocker(ctx context.Context, id, blockerID int) error
+	RemoveBlocker(ctx context.Context, id, blockerID int) error
+	// GetExecutionOrder returns every task ordered so that each one comes
+	// after the tasks blocking it.
+	GetExecutionOrder(ctx context.Context) ([]task.Info, error)
+	AttachLabel(ctx context.Context, id, labelID int) (task.Info, error)
+	DetachLabel(ctx context.Context, id, labelID int) (task.Info, error)
+	// AssignTask assigns a task to a user, or unassigns it when assigneeID
+	// is nil.
+	AssignTask(ctx context.Context, id int, assigneeID *int) (task.Info, error)
+	GetTasksByAssignee(ctx context.Context, userID int) ([]task.Info, error)
+	// GetOccurrences previews the due dates of a recurring task from from
+	// on, before to unless to is zero.
+	GetOccurrences(ctx context.Context, id int, from, to time.Time) ([]time.Time, error)
+	// SearchTasks returns up to limit tasks whose names match a full-text
+	// query, most relevant first.
+	SearchTasks(ctx context.Context, query string, limit int) ([]task.SearchResult, error)
 }
@@ -0,0 +1,64 @@
+package user
+
+import (
+	"fmt"
+	"net/mail"
+	"regexp"
+	"strings"
+
+	"task-api/internal/domain/user"
+)
+
+const maxNameLength = 255
+
+var usernamePattern = regexp.MustCompile(`^[A-Za-z0-9._-]{1,64}$`)
+
+type userService struct {
+	repo user.Repository
+}
+
+func NewUserService(repo user.Repository) Service {
+	return &userService{repo: repo}
+}
+
+func (s *userService) GetAllUsers() ([]user.User, error) {
+	return s.repo.GetAll()
+}
+
+func (s *userService) GetUser(id int) (user.User, error) {
+	return s.repo.GetByID(id)
+}
+
+func (s *userService) GetUserByUsername(username string) (user.User, error) {
+	return s.repo.GetByUsername(username)
+}
+
+func (s *userService) CreateUser(u user.User) (user.User, error) {
+	u, err := normalize(u)
+	if err != nil {
+		return user.User{}, err
+	}
+	return s.repo.Create(u)
+}
+
+// normalize trims the user's fields and checks that the username can be
+// used as a token subject.
+func normalize(u user.User) (user.User, error) {
+	u.Username = strings.TrimSpace(u.Username)
+	u.Name = strings.TrimSpace(u.Name)
+	u.Email = strings.TrimSpace(u.Email)
+	switch {
+	case u.Username == "":
+		return user.User{}, fmt.Errorf("%w: username is required", user.ErrInvalidUser)
+	case !usernamePattern.MatchString(u.Username):
+		return user.User{}, fmt.Errorf("%w: username may only contain letters, digits, dots, dashes and underscores, up to 64 characters", user.ErrInvalidUser)
+	case len(u.Name) > maxNameLength:
+		return user.User{}, fmt.Errorf("%w: name is longer than %d characters", user.ErrInvalidUser, maxNameLength)
+	}
+	if u.Email != "" {
+		if addr, err := mail.ParseAddress(u.Email); err != nil || addr.Address != u.Email {
+			return user.User{}, fmt.Errorf("%w: invalid email address", user.ErrInvalidUser)
+		}
+	}
+	return u, nil
+}
@@ -0,0 +1,76 @@
+package user
+
+import (
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"task-api/internal/domain/user"
+	"task-api/internal/mocks"
+)
+
+func Test_CreateUser(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockUserRepository(ctrl)
+	service := NewUserService(mockRepo)
+
+	tests := []struct {
+		TestCase string
+		Input    user.User
+		Expected user.User
+		Error    string
+		Setup    func()
+	}{
+		{
+			TestCase: "Create user with trimmed fields",
+			Input:    user.User{Username: " alice ", Name: " Alice ", Email: "alice@example.com"},
+			Expected: user.User{ID: 1, Username: "alice", Name: "Alice", Email: "alice@example.com"},
+			Setup: func() {
+				mockRepo.EXPECT().Create(user.User{Username: "alice", Name: "Alice", Email: "alice@example.com"}).
+					Return(user.User{ID: 1, Username: "alice", Name: "Alice", Email: "alice@example.com"}, nil)
+			},
+		},
+		{
+			TestCase: "Create user without username",
+			Input:    user.User{Name: "Nobody"},
+			Error:    "invalid user: username is required",
+			Setup:    func() {},
+		},
+		{
+			TestCase: "Create user with spaces in the username",
+			Input:    user.User{Username: "alice smith"},
+			Error:    "invalid user: username may only contain letters, digits, dots, dashes and underscores, up to 64 characters",
+			Setup:    func() {},
+		},
+		{
+			TestCase: "Create user with invalid email",
+			Input:    user.User{Username: "alice", Email: "Alice <alice@example.com>"},
+			Error:    "invalid user: invalid email address",
+			Setup:    func() {},
+		},
+		{
+			TestCase: "Create duplicate user",
+			Input:    user.User{Username: "alice"},
+			Error:    "username already exists",
+			Setup: func() {
+				mockRepo.EXPECT().Create(user.User{Username: "alice"}).Return(user.User{}, user.ErrDuplicateUsername)
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.TestCase, func(t *testing.T) {
+			tc.Setup()
+			result, err := service.CreateUser(tc.Input)
+			if tc.Error != "" {
+				assert.EqualError(t, err, tc.Error)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.Expected, result)
+		})
+	}
+}
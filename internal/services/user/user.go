@@ -0,0 +1,10 @@
+package user
+
+import "task-api/internal/domain/user"
+
+type Service interface {
+	GetAllUsers() ([]user.User, error)
+	GetUser(id int) (user.User, error)
+	GetUserByUsername(username string) (user.User, error)
+	CreateUser(u user.User) (user.User, error)
+}
@@ -0,0 +1,284 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"task-api/internal/config"
+	"task-api/internal/domain/webhook"
+	"task-api/internal/events"
+)
+
+const maxResponseError = 512
+
+// Dispatcher turns task events into webhook deliveries and sends them. Every
+// delivery is persisted before it is attempted, so pending deliveries and
+// scheduled retries survive restarts and are picked up by the poller.
+type Dispatcher struct {
+	repo   webhook.Repository
+	broker *events.Broker
+	cfg    config.WebhookConfig
+	client *http.Client
+	now    func() time.Time
+	jitter func(time.Duration) time.Duration
+
+	jobs   chan int
+	mu     sync.Mutex
+	queued map[int]bool
+}
+
+func NewDispatcher(repo webhook.Repository, broker *events.Broker, cfg config.WebhookConfig) *Dispatcher {
+	if cfg.Workers <= 0 {
+		cfg.Workers = 1
+	}
+	if cfg.MaxAttempts <= 0 {
+		cfg.MaxAttempts = 1
+	}
+	if cfg.PollInterval <= 0 {
+		cfg.PollInterval = time.Second
+	}
+	return &Dispatcher{
+		repo:   repo,
+		broker: broker,
+		cfg:    cfg,
+		client: &http.Client{Timeout: cfg.Timeout},
+		now:    time.Now,
+		jitter: func(d time.Duration) time.Duration {
+			return time.Duration(rand.Int63n(int64(d) + 1))
+		},
+		jobs:   make(chan int, 256),
+		queued: make(map[int]bool),
+	}
+}
+
+// Run consumes task events and sends deliveries until ctx is cancelled.
+// Events stop when the broker is closed, and the deliveries already
+// recorded are still sent.
+func (d *Dispatcher) Run(ctx context.Context) {
+	var wg sync.WaitGroup
+	for i := 0; i < d.cfg.Workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			d.work(ctx)
+		}()
+	}
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		d.poll(ctx)
+	}()
+
+	d.consume(ctx)
+	wg.Wait()
+}
+
+// Enqueue schedules a pending delivery for an immediate attempt. When the
+// queue is full the delivery stays pending and the poller retries it.
+func (d *Dispatcher) Enqueue(id int) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.queued[id] {
+		return
+	}
+	select {
+	case d.jobs <- id:
+		d.queued[id] = true
+	default:
+	}
+}
+
+func (d *Dispatcher) consume(ctx context.Context) {
+	var lastID uint64
+	for {
+		backlog, sub := d.broker.Subscribe(lastID)
+		for _, event := range backlog {
+			d.handleEvent(event)
+			lastID = event.ID
+		}
+
+		dropped := false
+		for !dropped {
+			select {
+			case <-ctx.Done():
+				sub.Close()
+				return
+			case event, ok := <-sub.C:
+				if !ok {
+					// A closed broker has nothing more to send.
+					if errors.Is(sub.Err(), events.ErrClosed) {
+						return
+					}
+					// Dropped for falling behind: resubscribe and replay
+					// what was missed from the broker buffer.
+					dropped = true
+					continue
+				}
+				d.handleEvent(event)
+				lastID = event.ID
+			}
+		}
+	}
+}
+
+func (d *Dispatcher) handleEvent(event events.Event) {
+	subscriptions, err := d.repo.GetAll()
+	if err != nil {
+		log.Printf("webhook: failed to load subscriptions for event %d: %v", event.ID, err)
+		return
+	}
+
+	payload, err := json.Marshal(event)
+	if err != nil {
+		log.Printf("webhook: failed to encode event %d: %v", event.ID, err)
+		return
+	}
+
+	now := d.now().UTC()
+	for _, s := range subscriptions {
+		// Subscriptions only hear of the tasks of their own tenant.
+		if s.TenantID != event.Task.TenantID || !s.Accepts(string(event.Type)) {
+			continue
+		}
+		delivery, err := d.repo.CreateDelivery(webhook.Delivery{
+			SubscriptionID: s.ID,
+			EventID:        event.ID,
+			EventType:      string(event.Type),
+			Payload:        payload,
+			Status:         webhook.DeliveryPending,
+			NextAttemptAt:  now,
+			CreatedAt:      now,
+		})
+		if err != nil {
+			log.Printf("webhook: failed to record delivery of event %d to subscription %d: %v", event.ID, s.ID, err)
+			continue
+		}
+		d.Enqueue(delivery.ID)
+	}
+}
+
+func (d *Dispatcher) poll(ctx context.Context) {
+	ticker := time.NewTicker(d.cfg.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			due, err := d.repo.ListDueDeliveries(d.now().UTC())
+			if err != nil {
+				log.Printf("webhook: failed to list due deliveries: %v", err)
+				continue
+			}
+			for _, delivery := range due {
+				d.Enqueue(delivery.ID)
+			}
+		}
+	}
+}
+
+func (d *Dispatcher) work(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case id := <-d.jobs:
+			d.attempt(ctx, id)
+			d.mu.Lock()
+			delete(d.queued, id)
+			d.mu.Unlock()
+		}
+	}
+}
+
+func (d *Dispatcher) attempt(ctx context.Context, id int) {
+	delivery, err := d.repo.GetDelivery(id)
+	if err != nil {
+		log.Printf("webhook: failed to load delivery %d: %v", id, err)
+		return
+	}
+	if delivery.Status != webhook.DeliveryPending {
+		return
+	}
+
+	subscription, err := d.repo.GetByID(delivery.SubscriptionID)
+	if err != nil {
+		delivery.Status = webhook.DeliveryFailed
+		delivery.LastError = err.Error()
+		d.save(delivery)
+		return
+	}
+
+	delivery.Attempts++
+	delivery.ResponseCode, err = d.send(ctx, subscription, delivery)
+	switch {
+	case err == nil:
+		delivery.Status = webhook.DeliverySucceeded
+		delivery.LastError = ""
+	case delivery.Attempts >= d.cfg.MaxAttempts:
+		delivery.Status = webhook.DeliveryFailed
+		delivery.LastError = err.Error()
+	default:
+		delivery.LastError = err.Error()
+		delivery.NextAttemptAt = d.now().UTC().Add(d.Backoff(delivery.Attempts))
+	}
+	d.save(delivery)
+}
+
+func (d *Dispatcher) save(delivery webhook.Delivery) {
+	if _, err := d.repo.UpdateDelivery(delivery); err != nil {
+		log.Printf("webhook: failed to update delivery %d: %v", delivery.ID, err)
+	}
+}
+
+func (d *Dispatcher) send(ctx context.Context, subscription webhook.Subscription, delivery webhook.Delivery) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, subscription.URL, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "Task-API-Webhooks/1.0")
+	req.Header.Set(EventHeader, delivery.EventType)
+	req.Header.Set(DeliveryHeader, strconv.Itoa(delivery.ID))
+	req.Header.Set(SignatureHeader, Sign(subscription.Secret, d.now(), delivery.Payload))
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, maxResponseError))
+		return resp.StatusCode, fmt.Errorf("unexpected status %d: %s", resp.StatusCode, bytes.TrimSpace(body))
+	}
+	_, _ = io.Copy(io.Discard, resp.Body)
+	return resp.StatusCode, nil
+}
+
+// Backoff returns the delay before the retry following the given attempt:
+// exponential growth from BackoffBase capped at BackoffMax, with the upper
+// half randomised so failing receivers are not hit in lockstep.
+func (d *Dispatcher) Backoff(attempt int) time.Duration {
+	delay := d.cfg.BackoffBase
+	for i := 1; i < attempt && delay < d.cfg.BackoffMax; i++ {
+		delay *= 2
+	}
+	if delay > d.cfg.BackoffMax {
+		delay = d.cfg.BackoffMax
+	}
+	half := delay / 2
+	return half + d.jitter(delay-half)
+}
@@ -0,0 +1,174 @@
+package webhook
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"task-api/internal/config"
+	"task-api/internal/domain/task"
+	"task-api/internal/domain/webhook"
+	"task-api/internal/events"
+	"task-api/internal/infrastructure/persistence/memory"
+)
+
+func TestDispatcher_Backoff(t *testing.T) {
+	d := NewDispatcher(nil, nil, config.WebhookConfig{BackoffBase: time.Second, BackoffMax: 10 * time.Second})
+
+	tests := []struct {
+		TestCase string
+		Attempt  int
+		Min      time.Duration
+		Max      time.Duration
+	}{
+		{TestCase: "First retry", Attempt: 1, Min: 500 * time.Millisecond, Max: time.Second},
+		{TestCase: "Exponential growth", Attempt: 3, Min: 2 * time.Second, Max: 4 * time.Second},
+		{TestCase: "Capped at the maximum", Attempt: 10, Min: 5 * time.Second, Max: 10 * time.Second},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.TestCase, func(t *testing.T) {
+			d.jitter = func(time.Duration) time.Duration { return 0 }
+			assert.Equal(t, tc.Min, d.Backoff(tc.Attempt))
+			d.jitter = func(max time.Duration) time.Duration { return max }
+			assert.Equal(t, tc.Max, d.Backoff(tc.Attempt))
+		})
+	}
+}
+
+func TestDispatcher_Attempt(t *testing.T) {
+	var failures atomic.Int32
+	var lastSignature atomic.Value
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		lastSignature.Store(Verify("secret", r.Header.Get(SignatureHeader), body, time.Now(), time.Minute))
+		if failures.Add(-1) >= 0 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	repo := memory.NewInMemoryWebhookRepository()
+	now := time.Now().UTC()
+	d := NewDispatcher(repo, events.NewBroker(1), config.WebhookConfig{MaxAttempts: 2, BackoffBase: time.Minute, BackoffMax: time.Hour})
+	d.now = func() time.Time { return now }
+	d.jitter = func(time.Duration) time.Duration { return 0 }
+
+	sub, _ := repo.Create(webhook.Subscription{URL: server.URL, Secret: "secret", Active: true, EventTypes: []string{"task.updated"}})
+	_, _ = repo.Create(webhook.Subscription{URL: server.URL, Secret: "secret", Active: false})
+	_, _ = repo.Create(webhook.Subscription{URL: server.URL, Secret: "secret", Active: true, EventTypes: []string{"task.deleted"}})
+
+	tests := []struct {
+		TestCase string
+		Failures int32
+		Attempts int
+		Expected webhook.DeliveryStatus
+		Code     int
+		NextAt   time.Time
+	}{
+		{TestCase: "Success on the first attempt", Failures: 0, Attempts: 1, Expected: webhook.DeliverySucceeded, Code: http.StatusNoContent, NextAt: now},
+		{TestCase: "Failure schedules a retry", Failures: 1, Attempts: 1, Expected: webhook.DeliveryPending, Code: http.StatusServiceUnavailable, NextAt: now.Add(30 * time.Second)},
+		{TestCase: "Failure after the last attempt", Failures: 2, Attempts: 2, Expected: webhook.DeliveryFailed, Code: http.StatusServiceUnavailable, NextAt: now.Add(30 * time.Second)},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.TestCase, func(t *testing.T) {
+			failures.Store(tc.Failures)
+			before, _ := repo.ListDeliveries(sub.ID)
+
+			d.handleEvent(events.Event{ID: 1, Type: events.TaskUpdated, Task: task.Info{ID: 1, Name: "Task"}})
+			deliveries, _ := repo.ListDeliveries(sub.ID)
+			assert.Len(t, deliveries, len(before)+1)
+			id := deliveries[len(deliveries)-1].ID
+
+			for i := 0; i < tc.Attempts; i++ {
+				d.attempt(context.Background(), id)
+			}
+
+			delivery, err := repo.GetDelivery(id)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.Expected, delivery.Status)
+			assert.Equal(t, tc.Attempts, delivery.Attempts)
+			assert.Equal(t, tc.Code, delivery.ResponseCode)
+			assert.Equal(t, tc.NextAt, delivery.NextAttemptAt)
+			assert.Equal(t, true, lastSignature.Load())
+		})
+	}
+}
+
+func TestDispatcher_HandleEventOfTenant(t *testing.T) {
+	repo := memory.NewInMemoryWebhookRepository()
+	d := NewDispatcher(repo, events.NewBroker(1), config.WebhookConfig{})
+	ours, _ := repo.Create(webhook.Subscription{URL: "https://acme.example.com/hook", Active: true, TenantID: "acme"})
+	theirs, _ := repo.Create(webhook.Subscription{URL: "https://globex.example.com/hook", Active: true, TenantID: "globex"})
+
+	d.handleEvent(events.Event{ID: 1, Type: events.TaskCreated, Task: task.Info{ID: 1, Name: "Task", TenantID: "acme"}})
+
+	deliveries, _ := repo.ListDeliveries(ours.ID)
+	assert.Len(t, deliveries, 1)
+	deliveries, _ = repo.ListDeliveries(theirs.ID)
+	assert.Empty(t, deliveries)
+}
+
+func TestDispatcher_Run(t *testing.T) {
+	received := make(chan string, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received <- r.Header.Get(EventHeader)
+	}))
+	defer server.Close()
+
+	repo := memory.NewInMemoryWebhookRepository()
+	broker := events.NewBroker(16)
+	d := NewDispatcher(repo, broker, config.WebhookConfig{MaxAttempts: 3, PollInterval: 10 * time.Millisecond})
+	_, _ = repo.Create(webhook.Subscription{URL: server.URL, Secret: "secret", Active: true})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		d.Run(ctx)
+		close(done)
+	}()
+
+	// Wait for the dispatcher to subscribe before publishing.
+	time.Sleep(20 * time.Millisecond)
+	broker.Publish(events.TaskCreated, task.Info{ID: 1, Name: "Task"})
+
+	select {
+	case eventType := <-received:
+		assert.Equal(t, "task.created", eventType)
+	case <-time.After(time.Second):
+		t.Fatal("webhook was not delivered")
+	}
+
+	cancel()
+	<-done
+}
+
+func TestDispatcher_RunAfterBrokerClosed(t *testing.T) {
+	broker := events.NewBroker(16)
+	d := NewDispatcher(memory.NewInMemoryWebhookRepository(), broker, config.WebhookConfig{})
+
+	done := make(chan struct{})
+	go func() {
+		d.consume(context.Background())
+		close(done)
+	}()
+
+	// Wait for the dispatcher to subscribe before closing.
+	time.Sleep(20 * time.Millisecond)
+	broker.Close()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("dispatcher kept consuming a closed broker")
+	}
+}
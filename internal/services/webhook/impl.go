@@ -0,0 +1,165 @@
+package webhook
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"net/url"
+	"time"
+
+	"task-api/internal/domain/webhook"
+	"task-api/internal/events"
+	"task-api/internal/tenant"
+)
+
+type webhookService struct {
+	repo       webhook.Repository
+	dispatcher *Dispatcher
+}
+
+func NewWebhookService(repo webhook.Repository, dispatcher *Dispatcher) Service {
+	return &webhookService{repo: repo, dispatcher: dispatcher}
+}
+
+func (s *webhookService) GetAllSubscriptions(ctx context.Context) ([]webhook.Subscription, error) {
+	subscriptions, err := s.repo.GetAll()
+	if err != nil {
+		return nil, err
+	}
+	tenantID := tenant.FromContext(ctx)
+	var result []webhook.Subscription
+	for _, sub := range subscriptions {
+		if sub.TenantID == tenantID {
+			result = append(result, sub)
+		}
+	}
+	return result, nil
+}
+
+func (s *webhookService) GetSubscription(ctx context.Context, id int) (webhook.Subscription, error) {
+	return s.get(ctx, id)
+}
+
+func (s *webhookService) CreateSubscription(ctx context.Context, sub webhook.Subscription) (webhook.Subscription, error) {
+	if err := validateSubscription(sub); err != nil {
+		return webhook.Subscription{}, err
+	}
+	if sub.Secret == "" {
+		secret, err := generateSecret()
+		if err != nil {
+			return webhook.Subscription{}, err
+		}
+		sub.Secret = secret
+	}
+	sub.CreatedAt = time.Now().UTC()
+	sub.TenantID = tenant.FromContext(ctx)
+	return s.repo.Create(sub)
+}
+
+func (s *webhookService) UpdateSubscription(ctx context.Context, sub webhook.Subscription) (webhook.Subscription, error) {
+	if err := validateSubscription(sub); err != nil {
+		return webhook.Subscription{}, err
+	}
+	existing, err := s.get(ctx, sub.ID)
+	if err != nil {
+		return webhook.Subscription{}, err
+	}
+	if sub.Secret == "" {
+		sub.Secret = existing.Secret
+	}
+	sub.CreatedAt = existing.CreatedAt
+	sub.TenantID = existing.TenantID
+	return s.repo.Update(sub)
+}
+
+func (s *webhookService) DeleteSubscription(ctx context.Context, id int) error {
+	if _, err := s.get(ctx, id); err != nil {
+		return err
+	}
+	return s.repo.Delete(id)
+}
+
+func (s *webhookService) ListDeliveries(ctx context.Context, subscriptionID int, status webhook.DeliveryStatus) ([]webhook.Delivery, error) {
+	if _, err := s.get(ctx, subscriptionID); err != nil {
+		return nil, err
+	}
+	deliveries, err := s.repo.ListDeliveries(subscriptionID)
+	if err != nil || status == "" {
+		return deliveries, err
+	}
+
+	var filtered []webhook.Delivery
+	for _, d := range deliveries {
+		if d.Status == status {
+			filtered = append(filtered, d)
+		}
+	}
+	return filtered, nil
+}
+
+// ReplayDelivery sends the payload of an earlier delivery again as a new
+// delivery, leaving the original record untouched.
+func (s *webhookService) ReplayDelivery(ctx context.Context, subscriptionID, deliveryID int) (webhook.Delivery, error) {
+	if _, err := s.get(ctx, subscriptionID); err != nil {
+		return webhook.Delivery{}, err
+	}
+	original, err := s.repo.GetDelivery(deliveryID)
+	if err != nil {
+		return webhook.Delivery{}, err
+	}
+	if original.SubscriptionID != subscriptionID {
+		return webhook.Delivery{}, webhook.ErrDeliveryNotFound
+	}
+
+	now := time.Now().UTC()
+	replay, err := s.repo.CreateDelivery(webhook.Delivery{
+		SubscriptionID: original.SubscriptionID,
+		EventID:        original.EventID,
+		EventType:      original.EventType,
+		Payload:        original.Payload,
+		Status:         webhook.DeliveryPending,
+		NextAttemptAt:  now,
+		CreatedAt:      now,
+	})
+	if err != nil {
+		return webhook.Delivery{}, err
+	}
+	s.dispatcher.Enqueue(replay.ID)
+	return replay, nil
+}
+
+// get loads a subscription of the tenant in ctx.
+func (s *webhookService) get(ctx context.Context, id int) (webhook.Subscription, error) {
+	sub, err := s.repo.GetByID(id)
+	if err != nil {
+		return webhook.Subscription{}, err
+	}
+	if sub.TenantID != tenant.FromContext(ctx) {
+		return webhook.Subscription{}, webhook.ErrNotFound
+	}
+	return sub, nil
+}
+
+func validateSubscription(sub webhook.Subscription) error {
+	u, err := url.Parse(sub.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("%w: url must be an absolute http or https url", webhook.ErrInvalidSubscription)
+	}
+	for _, t := range sub.EventTypes {
+		switch events.Type(t) {
+		case events.TaskCreated, events.TaskUpdated, events.TaskDeleted:
+		default:
+			return fmt.Errorf("%w: unknown event type %q", webhook.ErrInvalidSubscription, t)
+		}
+	}
+	return nil
+}
+
+func generateSecret() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,256 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"task-api/internal/config"
+	"task-api/internal/domain/webhook"
+	"task-api/internal/events"
+	"task-api/internal/mocks"
+	"task-api/internal/tenant"
+)
+
+func newTestService(repo webhook.Repository) Service {
+	return NewWebhookService(repo, NewDispatcher(repo, events.NewBroker(1), config.WebhookConfig{}))
+}
+
+func Test_CreateSubscription(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockWebhookRepository(ctrl)
+	service := newTestService(mockRepo)
+
+	tests := []struct {
+		TestCase string
+		Input    webhook.Subscription
+		Error    string
+		Setup    func()
+	}{
+		{
+			TestCase: "Create with generated secret",
+			Input:    webhook.Subscription{URL: "https://example.com/hook", EventTypes: []string{"task.created"}, Active: true},
+			Setup: func() {
+				mockRepo.EXPECT().Create(gomock.Any()).DoAndReturn(func(s webhook.Subscription) (webhook.Subscription, error) {
+					assert.Len(t, s.Secret, 64)
+					assert.False(t, s.CreatedAt.IsZero())
+					assert.Equal(t, "acme", s.TenantID)
+					s.ID = 1
+					return s, nil
+				})
+			},
+		},
+		{
+			TestCase: "Create with relative url",
+			Input:    webhook.Subscription{URL: "/hook"},
+			Error:    "invalid webhook subscription: url must be an absolute http or https url",
+			Setup:    func() {},
+		},
+		{
+			TestCase: "Create with unknown event type",
+			Input:    webhook.Subscription{URL: "https://example.com/hook", EventTypes: []string{"task.archived"}},
+			Error:    `invalid webhook subscription: unknown event type "task.archived"`,
+			Setup:    func() {},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.TestCase, func(t *testing.T) {
+			tc.Setup()
+			_, err := service.CreateSubscription(tenant.NewContext(context.Background(), "acme"), tc.Input)
+			if tc.Error != "" {
+				assert.EqualError(t, err, tc.Error)
+				assert.ErrorIs(t, err, webhook.ErrInvalidSubscription)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func Test_GetAllSubscriptions(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockWebhookRepository(ctrl)
+	service := newTestService(mockRepo)
+
+	mockRepo.EXPECT().GetAll().Return([]webhook.Subscription{
+		{ID: 1, URL: "https://default.example.com/hook", TenantID: tenant.Default},
+		{ID: 2, URL: "https://acme.example.com/hook", TenantID: "acme"},
+	}, nil)
+
+	result, err := service.GetAllSubscriptions(tenant.NewContext(context.Background(), "acme"))
+	assert.NoError(t, err)
+	assert.Equal(t, []webhook.Subscription{{ID: 2, URL: "https://acme.example.com/hook", TenantID: "acme"}}, result)
+}
+
+func Test_UpdateSubscription(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockWebhookRepository(ctrl)
+	service := newTestService(mockRepo)
+
+	tests := []struct {
+		TestCase string
+		Input    webhook.Subscription
+		Expected webhook.Subscription
+		Setup    func()
+	}{
+		{
+			TestCase: "Keep the existing secret when none is given",
+			Input:    webhook.Subscription{ID: 1, URL: "https://example.com/new", Active: false},
+			Expected: webhook.Subscription{ID: 1, URL: "https://example.com/new", Secret: "old", Active: false, TenantID: tenant.Default},
+			Setup: func() {
+				mockRepo.EXPECT().GetByID(1).Return(webhook.Subscription{ID: 1, URL: "https://example.com/hook", Secret: "old", Active: true, TenantID: tenant.Default}, nil)
+				mockRepo.EXPECT().Update(webhook.Subscription{ID: 1, URL: "https://example.com/new", Secret: "old", Active: false, TenantID: tenant.Default}).
+					Return(webhook.Subscription{ID: 1, URL: "https://example.com/new", Secret: "old", Active: false, TenantID: tenant.Default}, nil)
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.TestCase, func(t *testing.T) {
+			tc.Setup()
+			result, err := service.UpdateSubscription(context.Background(), tc.Input)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.Expected, result)
+		})
+	}
+}
+
+func Test_ListDeliveries(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockWebhookRepository(ctrl)
+	service := newTestService(mockRepo)
+
+	deliveries := []webhook.Delivery{
+		{ID: 1, SubscriptionID: 1, Status: webhook.DeliverySucceeded},
+		{ID: 2, SubscriptionID: 1, Status: webhook.DeliveryFailed},
+	}
+
+	tests := []struct {
+		TestCase string
+		Status   webhook.DeliveryStatus
+		Expected []webhook.Delivery
+		Error    error
+		Setup    func()
+	}{
+		{
+			TestCase: "List all deliveries",
+			Expected: deliveries,
+			Setup: func() {
+				mockRepo.EXPECT().GetByID(1).Return(webhook.Subscription{ID: 1, TenantID: tenant.Default}, nil)
+				mockRepo.EXPECT().ListDeliveries(1).Return(deliveries, nil)
+			},
+		},
+		{
+			TestCase: "List failed deliveries",
+			Status:   webhook.DeliveryFailed,
+			Expected: []webhook.Delivery{deliveries[1]},
+			Setup: func() {
+				mockRepo.EXPECT().GetByID(1).Return(webhook.Subscription{ID: 1, TenantID: tenant.Default}, nil)
+				mockRepo.EXPECT().ListDeliveries(1).Return(deliveries, nil)
+			},
+		},
+		{
+			TestCase: "Unknown subscription",
+			Error:    webhook.ErrNotFound,
+			Setup: func() {
+				mockRepo.EXPECT().GetByID(1).Return(webhook.Subscription{}, webhook.ErrNotFound)
+			},
+		},
+		{
+			TestCase: "Subscription of another tenant",
+			Error:    webhook.ErrNotFound,
+			Setup: func() {
+				mockRepo.EXPECT().GetByID(1).Return(webhook.Subscription{ID: 1, TenantID: "acme"}, nil)
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.TestCase, func(t *testing.T) {
+			tc.Setup()
+			result, err := service.ListDeliveries(context.Background(), 1, tc.Status)
+			assert.Equal(t, tc.Error, err)
+			assert.Equal(t, tc.Expected, result)
+		})
+	}
+}
+
+func Test_ReplayDelivery(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockWebhookRepository(ctrl)
+	service := newTestService(mockRepo)
+
+	original := webhook.Delivery{
+		ID:             3,
+		SubscriptionID: 1,
+		EventID:        9,
+		EventType:      "task.updated",
+		Payload:        json.RawMessage(`{"id":9}`),
+		Status:         webhook.DeliveryFailed,
+		Attempts:       8,
+		LastError:      "unexpected status 500",
+	}
+
+	tests := []struct {
+		TestCase       string
+		SubscriptionID int
+		Error          error
+		Setup          func()
+	}{
+		{
+			TestCase:       "Replay as a new pending delivery",
+			SubscriptionID: 1,
+			Setup: func() {
+				mockRepo.EXPECT().GetByID(1).Return(webhook.Subscription{ID: 1, TenantID: tenant.Default}, nil)
+				mockRepo.EXPECT().GetDelivery(3).Return(original, nil)
+				mockRepo.EXPECT().CreateDelivery(gomock.Any()).DoAndReturn(func(d webhook.Delivery) (webhook.Delivery, error) {
+					assert.Equal(t, webhook.DeliveryPending, d.Status)
+					assert.Equal(t, 0, d.Attempts)
+					assert.Equal(t, original.Payload, d.Payload)
+					assert.Equal(t, original.EventID, d.EventID)
+					d.ID = 4
+					return d, nil
+				})
+			},
+		},
+		{
+			TestCase:       "Delivery of another subscription",
+			SubscriptionID: 2,
+			Error:          webhook.ErrDeliveryNotFound,
+			Setup: func() {
+				mockRepo.EXPECT().GetByID(2).Return(webhook.Subscription{ID: 2, TenantID: tenant.Default}, nil)
+				mockRepo.EXPECT().GetDelivery(3).Return(original, nil)
+			},
+		},
+		{
+			TestCase:       "Delivery of a subscription of another tenant",
+			SubscriptionID: 1,
+			Error:          webhook.ErrNotFound,
+			Setup: func() {
+				mockRepo.EXPECT().GetByID(1).Return(webhook.Subscription{ID: 1, TenantID: "acme"}, nil)
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.TestCase, func(t *testing.T) {
+			tc.Setup()
+			_, err := service.ReplayDelivery(context.Background(), tc.SubscriptionID, 3)
+			assert.Equal(t, tc.Error, err)
+		})
+	}
+}
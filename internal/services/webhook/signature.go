@@ -0,0 +1,56 @@
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	SignatureHeader = "X-Webhook-Signature"
+	EventHeader     = "X-Webhook-Event"
+	DeliveryHeader  = "X-Webhook-Delivery"
+)
+
+// Sign returns the signature header value for a payload sent at the given
+// time: "t=<unix seconds>,v1=<hex HMAC-SHA256 of "<t>.<payload>">". Binding the
+// timestamp into the MAC lets receivers reject replayed requests.
+func Sign(secret string, timestamp time.Time, payload []byte) string {
+	t := strconv.FormatInt(timestamp.Unix(), 10)
+	return fmt.Sprintf("t=%s,v1=%s", t, computeMAC(secret, t, payload))
+}
+
+// Verify checks a signature header produced by Sign and rejects signatures
+// older than tolerance.
+func Verify(secret, header string, payload []byte, now time.Time, tolerance time.Duration) bool {
+	var t, v1 string
+	for _, part := range strings.Split(header, ",") {
+		key, value, _ := strings.Cut(part, "=")
+		switch key {
+		case "t":
+			t = value
+		case "v1":
+			v1 = value
+		}
+	}
+	unix, err := strconv.ParseInt(t, 10, 64)
+	if err != nil || v1 == "" {
+		return false
+	}
+	if age := now.Sub(time.Unix(unix, 0)); age > tolerance || age < -tolerance {
+		return false
+	}
+	return hmac.Equal([]byte(v1), []byte(computeMAC(secret, t, payload)))
+}
+
+func computeMAC(secret, timestamp string, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(payload)
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,37 @@
+package webhook
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSignature(t *testing.T) {
+	sentAt := time.Unix(1767225600, 0)
+	payload := []byte(`{"id":1}`)
+	header := Sign("secret", sentAt, payload)
+
+	assert.Equal(t, "t=1767225600,v1=", header[:16])
+
+	tests := []struct {
+		TestCase string
+		Secret   string
+		Header   string
+		Payload  []byte
+		Now      time.Time
+		Expected bool
+	}{
+		{TestCase: "Valid signature", Secret: "secret", Header: header, Payload: payload, Now: sentAt.Add(time.Minute), Expected: true},
+		{TestCase: "Wrong secret", Secret: "other", Header: header, Payload: payload, Now: sentAt, Expected: false},
+		{TestCase: "Tampered payload", Secret: "secret", Header: header, Payload: []byte(`{"id":2}`), Now: sentAt, Expected: false},
+		{TestCase: "Expired timestamp", Secret: "secret", Header: header, Payload: payload, Now: sentAt.Add(time.Hour), Expected: false},
+		{TestCase: "Malformed header", Secret: "secret", Header: "garbage", Payload: payload, Now: sentAt, Expected: false},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.TestCase, func(t *testing.T) {
+			assert.Equal(t, tc.Expected, Verify(tc.Secret, tc.Header, tc.Payload, tc.Now, 5*time.Minute))
+		})
+	}
+}
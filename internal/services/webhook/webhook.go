@@ -0,0 +1,19 @@
+package webhook
+
+import (
+	"context"
+
+	"task-api/internal/domain/webhook"
+)
+
+// Service manages the subscriptions of the tenant in the context; those of
+// other tenants are not found.
+type Service interface {
+	GetAllSubscriptions(ctx context.Context) ([]webhook.Subscription, error)
+	GetSubscription(ctx context.Context, id int) (webhook.Subscription, error)
+	CreateSubscription(ctx context.Context, s webhook.Subscription) (webhook.Subscription, error)
+	UpdateSubscription(ctx context.Context, s webhook.Subscription) (webhook.Subscription, error)
+	DeleteSubscription(ctx context.Context, id int) error
+	ListDeliveries(ctx context.Context, subscriptionID int, status webhook.DeliveryStatus) ([]webhook.Delivery, error)
+	ReplayDelivery(ctx context.Context, subscriptionID, deliveryID int) (webhook.Delivery, error)
+}
@@ -0,0 +1,88 @@
+// Package tenant keeps the teams sharing a deployment apart. Every request
+// belongs to a tenant, and the task repositories only ever reach the tasks
+// of the tenant in the context they are given.
+package tenant
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"task-api/internal/auth"
+	"task-api/internal/config"
+)
+
+const (
+	// Default is the tenant of requests that name none, and of work done
+	// outside requests.
+	Default = "default"
+	Header  = "X-Tenant-ID"
+	// maxLength matches the tenant_id column.
+	maxLength = 64
+)
+
+type contextKey struct{}
+
+// NewContext returns a copy of ctx carrying the tenant.
+func NewContext(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, contextKey{}, id)
+}
+
+// FromContext returns the tenant in ctx, or Default.
+func FromContext(ctx context.Context) string {
+	if id, ok := ctx.Value(contextKey{}).(string); ok {
+		return id
+	}
+	return Default
+}
+
+// Valid reports whether id is a tenant ID: up to 64 letters, digits,
+// hyphens and underscores.
+func Valid(id string) bool {
+	if id == "" || len(id) > maxLength {
+		return false
+	}
+	for _, r := range id {
+		if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '-' || r == '_') {
+			return false
+		}
+	}
+	return true
+}
+
+// Middleware puts the tenant of a request into its context: the one its
+// caller is bound to, and the X-Tenant-ID header may only repeat it.
+// Requests without a caller only reach public paths and belong to Default.
+// It runs after auth.Identify.
+func Middleware(cfg config.AuthConfig) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		header := c.GetHeader(Header)
+		if header != "" && !Valid(header) {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "invalid tenant " + header})
+			return
+		}
+		id := Default
+		if identity, ok := auth.FromContext(c.Request.Context()); ok {
+			id = Of(identity, cfg)
+			if header != "" && header != id {
+				c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "caller is not bound to tenant " + header})
+				return
+			}
+		}
+		c.Request = c.Request.WithContext(NewContext(c.Request.Context(), id))
+		c.Next()
+	}
+}
+
+// Of returns the tenant a caller is bound to: the one of its JWT or API key,
+// else the one cfg binds its subject to, else Default.
+func Of(identity auth.Identity, cfg config.AuthConfig) string {
+	if identity.Tenant != "" {
+		return identity.Tenant
+	}
+	if id, ok := cfg.SubjectTenants[identity.Subject]; ok {
+		return id
+	}
+	return Default
+}
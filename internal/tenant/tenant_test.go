@@ -0,0 +1,92 @@
+package tenant
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+
+	"task-api/internal/auth"
+	"task-api/internal/config"
+)
+
+func TestMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(func(c *gin.Context) {
+		if subject := c.GetHeader("X-Subject"); subject != "" {
+			identity := auth.Identity{Subject: subject, Tenant: c.GetHeader("X-Claim")}
+			c.Request = c.Request.WithContext(auth.NewContext(c.Request.Context(), identity))
+		}
+	}, Middleware(config.AuthConfig{SubjectTenants: map[string]string{"cert:billing": "acme"}}))
+	router.GET("/", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"tenant": FromContext(c.Request.Context())})
+	})
+
+	tests := []struct {
+		TestCase string
+		Subject  string
+		Claim    string
+		Header   string
+		Status   int
+		Expected string
+	}{
+		{TestCase: "No caller", Status: http.StatusOK, Expected: `{"tenant":"default"}`},
+		{TestCase: "No caller ignores the header", Header: "acme", Status: http.StatusOK, Expected: `{"tenant":"default"}`},
+		{TestCase: "Unbound caller", Subject: "alice", Status: http.StatusOK, Expected: `{"tenant":"default"}`},
+		{
+			TestCase: "Unbound caller naming a tenant",
+			Subject:  "alice",
+			Header:   "acme",
+			Status:   http.StatusForbidden,
+			Expected: `{"error":"caller is not bound to tenant acme"}`,
+		},
+		{TestCase: "Claim", Subject: "alice", Claim: "acme", Status: http.StatusOK, Expected: `{"tenant":"acme"}`},
+		{TestCase: "Header repeating the claim", Subject: "alice", Claim: "acme", Header: "acme", Status: http.StatusOK, Expected: `{"tenant":"acme"}`},
+		{
+			TestCase: "Header contradicting the claim",
+			Subject:  "alice",
+			Claim:    "acme",
+			Header:   "globex",
+			Status:   http.StatusForbidden,
+			Expected: `{"error":"caller is not bound to tenant globex"}`,
+		},
+		{TestCase: "Subject bound by the configuration", Subject: "cert:billing", Status: http.StatusOK, Expected: `{"tenant":"acme"}`},
+		{
+			TestCase: "Header contradicting the configuration",
+			Subject:  "cert:billing",
+			Header:   "globex",
+			Status:   http.StatusForbidden,
+			Expected: `{"error":"caller is not bound to tenant globex"}`,
+		},
+		{TestCase: "Invalid header", Subject: "alice", Header: "acme corp", Status: http.StatusBadRequest, Expected: `{"error":"invalid tenant acme corp"}`},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.TestCase, func(t *testing.T) {
+			req, _ := http.NewRequest(http.MethodGet, "/", nil)
+			if tc.Subject != "" {
+				req.Header.Set("X-Subject", tc.Subject)
+			}
+			if tc.Claim != "" {
+				req.Header.Set("X-Claim", tc.Claim)
+			}
+			if tc.Header != "" {
+				req.Header.Set(Header, tc.Header)
+			}
+			rr := httptest.NewRecorder()
+			router.ServeHTTP(rr, req)
+
+			assert.Equal(t, tc.Status, rr.Code)
+			assert.JSONEq(t, tc.Expected, rr.Body.String())
+		})
+	}
+}
+
+func TestFromContext(t *testing.T) {
+	assert.Equal(t, Default, FromContext(context.Background()))
+	assert.Equal(t, "acme", FromContext(NewContext(context.Background(), "acme")))
+}
@@ -0,0 +1,96 @@
+-- Upgrades a database created from the original tasks table with webhooks,
+-- subtasks, dependencies, labels, comments, attachments, users and
+-- assignment, which the later upgrades build on. New databases get the same
+-- schema from init.sql.
+USE TaskDB;
+CREATE TABLE IF NOT EXISTS webhook_subscriptions (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    url VARCHAR(2048) NOT NULL,
+    event_types VARCHAR(255) NOT NULL DEFAULT '',
+    secret VARCHAR(255) NOT NULL,
+    active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at DATETIME NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS webhook_deliveries (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    subscription_id INT NOT NULL,
+    event_id BIGINT UNSIGNED NOT NULL,
+    event_type VARCHAR(64) NOT NULL,
+    payload JSON NOT NULL,
+    status VARCHAR(16) NOT NULL,
+    attempts INT NOT NULL DEFAULT 0,
+    response_code INT NOT NULL DEFAULT 0,
+    last_error TEXT NOT NULL,
+    next_attempt_at DATETIME NOT NULL,
+    created_at DATETIME NOT NULL,
+    INDEX idx_webhook_deliveries_subscription (subscription_id),
+    INDEX idx_webhook_deliveries_due (status, next_attempt_at),
+    FOREIGN KEY (subscription_id) REFERENCES webhook_subscriptions (id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS users (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    username VARCHAR(64) NOT NULL UNIQUE,
+    name VARCHAR(255) NOT NULL DEFAULT '',
+    email VARCHAR(255) NOT NULL DEFAULT ''
+);
+
+ALTER TABLE tasks
+    ADD COLUMN parent_id INT NULL AFTER status,
+    ADD COLUMN assignee_id INT NULL AFTER parent_id,
+    ADD INDEX idx_tasks_parent (parent_id),
+    ADD INDEX idx_tasks_assignee (assignee_id),
+    ADD FOREIGN KEY (parent_id) REFERENCES tasks (id) ON DELETE SET NULL,
+    ADD FOREIGN KEY (assignee_id) REFERENCES users (id) ON DELETE SET NULL;
+
+CREATE TABLE IF NOT EXISTS task_dependencies (
+    blocker_id INT NOT NULL,
+    blocked_id INT NOT NULL,
+    PRIMARY KEY (blocker_id, blocked_id),
+    INDEX idx_task_dependencies_blocked (blocked_id),
+    FOREIGN KEY (blocker_id) REFERENCES tasks (id) ON DELETE CASCADE,
+    FOREIGN KEY (blocked_id) REFERENCES tasks (id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS labels (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    name VARCHAR(64) NOT NULL,
+    color VARCHAR(16) NOT NULL DEFAULT '',
+    UNIQUE KEY uq_labels_name (name)
+);
+
+CREATE TABLE IF NOT EXISTS task_labels (
+    task_id INT NOT NULL,
+    label_id INT NOT NULL,
+    PRIMARY KEY (task_id, label_id),
+    INDEX idx_task_labels_label (label_id),
+    FOREIGN KEY (task_id) REFERENCES tasks (id) ON DELETE CASCADE,
+    FOREIGN KEY (label_id) REFERENCES labels (id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS comments (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    task_id INT NOT NULL,
+    parent_id INT NULL,
+    author VARCHAR(255) NOT NULL,
+    body TEXT NOT NULL,
+    created_at DATETIME NOT NULL,
+    updated_at DATETIME NOT NULL,
+    INDEX idx_comments_thread (task_id, parent_id, id),
+    FOREIGN KEY (task_id) REFERENCES tasks (id) ON DELETE CASCADE,
+    FOREIGN KEY (parent_id) REFERENCES comments (id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS attachments (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    task_id INT NOT NULL,
+    filename VARCHAR(255) NOT NULL,
+    content_type VARCHAR(255) NOT NULL,
+    size BIGINT NOT NULL,
+    checksum CHAR(64) NOT NULL,
+    uploader VARCHAR(255) NOT NULL,
+    created_at DATETIME NOT NULL,
+    storage_key VARCHAR(255) NOT NULL,
+    INDEX idx_attachments_task (task_id)
+);
@@ -0,0 +1,18 @@
+-- Upgrades a database created before projects existed. New databases get
+-- the same schema from init.sql.
+USE TaskDB;
+CREATE TABLE IF NOT EXISTS projects (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    description TEXT NOT NULL,
+    archived_at DATETIME NULL
+);
+
+INSERT INTO projects (id, name, description) VALUES (1, 'Default', '')
+    ON DUPLICATE KEY UPDATE id = id;
+
+-- Existing tasks end up in the default project through the column default.
+ALTER TABLE tasks
+    ADD COLUMN project_id INT NOT NULL DEFAULT 1 AFTER status,
+    ADD INDEX idx_tasks_project (project_id),
+    ADD FOREIGN KEY (project_id) REFERENCES projects (id);
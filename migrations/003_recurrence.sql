@@ -0,0 +1,8 @@
+-- Adds due dates and recurrence rules to a database created before they
+-- existed. New databases get the same schema from init.sql.
+USE TaskDB;
+ALTER TABLE tasks
+    ADD COLUMN due_at DATETIME NULL AFTER assignee_id,
+    ADD COLUMN recurrence_rule VARCHAR(255) NULL AFTER due_at,
+    ADD COLUMN recurrence_timezone VARCHAR(64) NOT NULL DEFAULT '' AFTER recurrence_rule,
+    ADD COLUMN recurrence_start DATETIME NULL AFTER recurrence_timezone;
@@ -0,0 +1,12 @@
+-- Adds the due date index and the sent reminders to a database created
+-- before reminders existed. New databases get the same schema from init.sql.
+USE TaskDB;
+ALTER TABLE tasks ADD INDEX idx_tasks_due (due_at);
+
+CREATE TABLE IF NOT EXISTS reminders (
+    task_id INT NOT NULL,
+    window_seconds INT NOT NULL,
+    due_at DATETIME NOT NULL,
+    sent_at DATETIME NOT NULL,
+    PRIMARY KEY (task_id, window_seconds, due_at)
+);
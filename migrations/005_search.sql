@@ -0,0 +1,4 @@
+-- Adds the full-text index behind task search to a database created before
+-- search existed. New databases get the same schema from init.sql.
+USE TaskDB;
+ALTER TABLE tasks ADD FULLTEXT INDEX idx_tasks_name (name);
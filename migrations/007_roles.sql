@@ -0,0 +1,10 @@
+-- Adds role assignments to a database created before they existed. New
+-- databases get the same schema from init.sql.
+USE TaskDB;
+CREATE TABLE IF NOT EXISTS role_assignments (
+    user_id INT NOT NULL,
+    project_id INT NOT NULL DEFAULT 0,
+    role VARCHAR(16) NOT NULL,
+    PRIMARY KEY (user_id, project_id),
+    FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE
+);
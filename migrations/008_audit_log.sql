@@ -0,0 +1,23 @@
+-- Adds the audit log to a database created before it existed. New databases
+-- get the same schema from init.sql.
+USE TaskDB;
+CREATE TABLE IF NOT EXISTS audit_log (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    task_id INT NOT NULL,
+    actor VARCHAR(255) NOT NULL,
+    created_at DATETIME(6) NOT NULL,
+    request_id VARCHAR(128) NOT NULL DEFAULT '',
+    operation VARCHAR(16) NOT NULL,
+    changes JSON NOT NULL,
+    INDEX idx_audit_log_task (task_id, created_at),
+    INDEX idx_audit_log_actor (actor, created_at),
+    INDEX idx_audit_log_time (created_at)
+);
+
+DROP TRIGGER IF EXISTS audit_log_no_update;
+CREATE TRIGGER audit_log_no_update BEFORE UPDATE ON audit_log
+FOR EACH ROW SIGNAL SQLSTATE '45000' SET MESSAGE_TEXT = 'audit_log is append-only';
+
+DROP TRIGGER IF EXISTS audit_log_no_delete;
+CREATE TRIGGER audit_log_no_delete BEFORE DELETE ON audit_log
+FOR EACH ROW SIGNAL SQLSTATE '45000' SET MESSAGE_TEXT = 'audit_log is append-only';
@@ -0,0 +1,7 @@
+-- Adds tenants to a database created before they existed. Existing tasks
+-- belong to the default tenant. New databases get the same schema from
+-- init.sql.
+USE TaskDB;
+ALTER TABLE tasks
+    ADD COLUMN tenant_id VARCHAR(64) NOT NULL DEFAULT 'default' AFTER id,
+    ADD INDEX idx_tasks_tenant (tenant_id);
@@ -0,0 +1,9 @@
+-- Makes room for task names sealed under a key of the keyring, and for the
+-- ID of that key. Existing names stay in plain text until they are updated
+-- or sealed by the re-encryption job. New databases get the same schema
+-- from init.sql.
+USE TaskDB;
+ALTER TABLE tasks
+    MODIFY COLUMN name VARCHAR(2048) NOT NULL,
+    ADD COLUMN name_key_id VARCHAR(64) NULL AFTER name,
+    ADD INDEX idx_tasks_name_key (name_key_id);
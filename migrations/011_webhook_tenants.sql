@@ -0,0 +1,7 @@
+-- Adds tenants to the webhook subscriptions of a database created before
+-- they had one. Existing subscriptions belong to the default tenant and
+-- only receive its events. New databases get the same schema from init.sql.
+USE TaskDB;
+ALTER TABLE webhook_subscriptions
+    ADD COLUMN tenant_id VARCHAR(64) NOT NULL DEFAULT 'default' AFTER id,
+    ADD INDEX idx_webhook_subscriptions_tenant (tenant_id);
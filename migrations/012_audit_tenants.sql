@@ -0,0 +1,15 @@
+-- Adds tenants to the audit log of a database created before it had them.
+-- Records take the tenant of their task; those of tasks deleted since
+-- belong to the default tenant. New databases get the same schema from
+-- init.sql.
+USE TaskDB;
+ALTER TABLE audit_log
+    ADD COLUMN tenant_id VARCHAR(64) NOT NULL DEFAULT 'default' AFTER id,
+    ADD INDEX idx_audit_log_tenant (tenant_id, created_at);
+
+-- The log is append-only, so the trigger refusing updates is lifted for
+-- the backfill alone.
+DROP TRIGGER IF EXISTS audit_log_no_update;
+UPDATE audit_log a JOIN tasks t ON t.id = a.task_id SET a.tenant_id = t.tenant_id;
+CREATE TRIGGER audit_log_no_update BEFORE UPDATE ON audit_log
+FOR EACH ROW SIGNAL SQLSTATE '45000' SET MESSAGE_TEXT = 'audit_log is append-only';
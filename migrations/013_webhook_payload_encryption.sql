@@ -0,0 +1,7 @@
+-- Makes room for the ID of the key webhook delivery payloads are sealed
+-- under. Existing payloads stay in plain text until they are sealed by the
+-- re-encryption job. New databases get the same schema from init.sql.
+USE TaskDB;
+ALTER TABLE webhook_deliveries
+    ADD COLUMN payload_key_id VARCHAR(64) NULL AFTER payload,
+    ADD INDEX idx_webhook_deliveries_payload_key (payload_key_id);
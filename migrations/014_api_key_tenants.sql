@@ -0,0 +1,6 @@
+-- Binds the API keys of a database created before they had a tenant to one.
+-- Existing keys belong to the default tenant and only reach its tasks. New
+-- databases get the same schema from init.sql.
+USE TaskDB;
+ALTER TABLE api_keys
+    ADD COLUMN tenant_id VARCHAR(64) NOT NULL DEFAULT 'default' AFTER id;
@@ -0,0 +1,23 @@
+-- Gives the labels of a database created before they had a tenant one.
+-- Existing labels belong to the default tenant; those attached to tasks of
+-- other tenants are copied into each of them, and their tasks are moved to
+-- the copies. New databases get the same schema from init.sql.
+USE TaskDB;
+ALTER TABLE labels
+    ADD COLUMN tenant_id VARCHAR(64) NOT NULL DEFAULT 'default' AFTER id,
+    DROP INDEX uq_labels_name,
+    ADD UNIQUE KEY uq_labels_tenant_name (tenant_id, name);
+
+INSERT INTO labels (tenant_id, name, color)
+SELECT DISTINCT t.tenant_id, l.name, l.color
+FROM task_labels tl
+JOIN tasks t ON t.id = tl.task_id
+JOIN labels l ON l.id = tl.label_id
+WHERE t.tenant_id <> l.tenant_id;
+
+UPDATE task_labels tl
+JOIN tasks t ON t.id = tl.task_id
+JOIN labels l ON l.id = tl.label_id
+JOIN labels copy ON copy.tenant_id = t.tenant_id AND copy.name = l.name
+SET tl.label_id = copy.id
+WHERE t.tenant_id <> l.tenant_id;
@@ -2,10 +2,49 @@
 CREATE DATABASE IF NOT EXISTS TaskDB;
 -- Move on TaskDB and create table
 USE TaskDB;
-CREATE TABLE IF NOT EXISTS tasks (
+CREATE TABLE IF NOT EXISTS users (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    username VARCHAR(64) NOT NULL UNIQUE,
+    name VARCHAR(255) NOT NULL DEFAULT '',
+    email VARCHAR(255) NOT NULL DEFAULT ''
+);
+
+-- Project 1 is the default project tasks are created in when none is given.
+CREATE TABLE IF NOT EXISTS projects (
     id INT AUTO_INCREMENT PRIMARY KEY,
     name VARCHAR(255) NOT NULL,
-    status INT NOT NULL
+    description TEXT NOT NULL,
+    archived_at DATETIME NULL
+);
+
+INSERT INTO projects (id, name, description) VALUES (1, 'Default', '');
+
+CREATE TABLE IF NOT EXISTS tasks (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    tenant_id VARCHAR(64) NOT NULL DEFAULT 'default',
+    -- A name with a name_key_id is sealed under that key of the keyring, in
+    -- base64, which takes up to 2048 characters for 255 of plain text.
+    name VARCHAR(2048) NOT NULL,
+    name_key_id VARCHAR(64) NULL,
+    status INT NOT NULL,
+    project_id INT NOT NULL DEFAULT 1,
+    parent_id INT NULL,
+    assignee_id INT NULL,
+    -- A NULL recurrence_rule means the task does not recur.
+    due_at DATETIME NULL,
+    recurrence_rule VARCHAR(255) NULL,
+    recurrence_timezone VARCHAR(64) NOT NULL DEFAULT '',
+    recurrence_start DATETIME NULL,
+    INDEX idx_tasks_tenant (tenant_id),
+    INDEX idx_tasks_project (project_id),
+    INDEX idx_tasks_parent (parent_id),
+    INDEX idx_tasks_assignee (assignee_id),
+    INDEX idx_tasks_due (due_at),
+    INDEX idx_tasks_name_key (name_key_id),
+    FULLTEXT INDEX idx_tasks_name (name),
+    FOREIGN KEY (project_id) REFERENCES projects (id),
+    FOREIGN KEY (parent_id) REFERENCES tasks (id) ON DELETE SET NULL,
+    FOREIGN KEY (assignee_id) REFERENCES users (id) ON DELETE SET NULL
 );
 
 -- Insert some test data
@@ -18,4 +57,146 @@ INSERT INTO tasks (name, status) VALUES ('Task 6', 1);
 INSERT INTO tasks (name, status) VALUES ('Task 7', 0);
 INSERT INTO tasks (name, status) VALUES ('Task 8', 1);
 INSERT INTO tasks (name, status) VALUES ('Task 9', 0);
-INSERT INTO tasks (name, status) VALUES ('Task 10', 1);
\ No newline at end of file
+INSERT INTO tasks (name, status) VALUES ('Task 10', 1);
+
+CREATE TABLE IF NOT EXISTS task_dependencies (
+    blocker_id INT NOT NULL,
+    blocked_id INT NOT NULL,
+    PRIMARY KEY (blocker_id, blocked_id),
+    INDEX idx_task_dependencies_blocked (blocked_id),
+    FOREIGN KEY (blocker_id) REFERENCES tasks (id) ON DELETE CASCADE,
+    FOREIGN KEY (blocked_id) REFERENCES tasks (id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS labels (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    tenant_id VARCHAR(64) NOT NULL DEFAULT 'default',
+    name VARCHAR(64) NOT NULL,
+    color VARCHAR(16) NOT NULL DEFAULT '',
+    UNIQUE KEY uq_labels_tenant_name (tenant_id, name)
+);
+
+CREATE TABLE IF NOT EXISTS task_labels (
+    task_id INT NOT NULL,
+    label_id INT NOT NULL,
+    PRIMARY KEY (task_id, label_id),
+    INDEX idx_task_labels_label (label_id),
+    FOREIGN KEY (task_id) REFERENCES tasks (id) ON DELETE CASCADE,
+    FOREIGN KEY (label_id) REFERENCES labels (id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS comments (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    task_id INT NOT NULL,
+    parent_id INT NULL,
+    author VARCHAR(255) NOT NULL,
+    body TEXT NOT NULL,
+    created_at DATETIME NOT NULL,
+    updated_at DATETIME NOT NULL,
+    INDEX idx_comments_thread (task_id, parent_id, id),
+    FOREIGN KEY (task_id) REFERENCES tasks (id) ON DELETE CASCADE,
+    FOREIGN KEY (parent_id) REFERENCES comments (id) ON DELETE CASCADE
+);
+
+-- Reminders that went out, keyed so each one is sent once per task, due date
+-- and window.
+CREATE TABLE IF NOT EXISTS reminders (
+    task_id INT NOT NULL,
+    window_seconds INT NOT NULL,
+    due_at DATETIME NOT NULL,
+    sent_at DATETIME NOT NULL,
+    PRIMARY KEY (task_id, window_seconds, due_at)
+);
+
+-- Attachments are not cascaded from tasks: their blobs are removed by the
+-- application when a task is deleted, which needs the rows to find them.
+CREATE TABLE IF NOT EXISTS attachments (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    task_id INT NOT NULL,
+    filename VARCHAR(255) NOT NULL,
+    content_type VARCHAR(255) NOT NULL,
+    size BIGINT NOT NULL,
+    checksum CHAR(64) NOT NULL,
+    uploader VARCHAR(255) NOT NULL,
+    created_at DATETIME NOT NULL,
+    storage_key VARCHAR(255) NOT NULL,
+    INDEX idx_attachments_task (task_id)
+);
+
+CREATE TABLE IF NOT EXISTS webhook_subscriptions (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    tenant_id VARCHAR(64) NOT NULL DEFAULT 'default',
+    url VARCHAR(2048) NOT NULL,
+    event_types VARCHAR(255) NOT NULL DEFAULT '',
+    secret VARCHAR(255) NOT NULL,
+    active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at DATETIME NOT NULL,
+    INDEX idx_webhook_subscriptions_tenant (tenant_id)
+);
+
+CREATE TABLE IF NOT EXISTS webhook_deliveries (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    subscription_id INT NOT NULL,
+    event_id BIGINT UNSIGNED NOT NULL,
+    event_type VARCHAR(64) NOT NULL,
+    payload JSON NOT NULL,
+    payload_key_id VARCHAR(64) NULL,
+    status VARCHAR(16) NOT NULL,
+    attempts INT NOT NULL DEFAULT 0,
+    response_code INT NOT NULL DEFAULT 0,
+    last_error TEXT NOT NULL,
+    next_attempt_at DATETIME NOT NULL,
+    created_at DATETIME NOT NULL,
+    INDEX idx_webhook_deliveries_subscription (subscription_id),
+    INDEX idx_webhook_deliveries_due (status, next_attempt_at),
+    INDEX idx_webhook_deliveries_payload_key (payload_key_id),
+    FOREIGN KEY (subscription_id) REFERENCES webhook_subscriptions (id) ON DELETE CASCADE
+);
+
+-- API keys are stored as the SHA-256 hash of their secret.
+CREATE TABLE IF NOT EXISTS api_keys (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    tenant_id VARCHAR(64) NOT NULL DEFAULT 'default',
+    name VARCHAR(255) NOT NULL,
+    prefix VARCHAR(16) NOT NULL,
+    key_hash CHAR(64) NOT NULL UNIQUE,
+    scopes VARCHAR(255) NOT NULL,
+    created_at DATETIME NOT NULL,
+    last_used_at DATETIME NULL,
+    revoked_at DATETIME NULL
+);
+
+-- Project 0 holds the role a user has in every project without a role of its
+-- own.
+CREATE TABLE IF NOT EXISTS role_assignments (
+    user_id INT NOT NULL,
+    project_id INT NOT NULL DEFAULT 0,
+    role VARCHAR(16) NOT NULL,
+    PRIMARY KEY (user_id, project_id),
+    FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE
+);
+
+-- Every change of a task, for audits. Records outlive their tasks and the
+-- triggers keep them from being changed or removed.
+CREATE TABLE IF NOT EXISTS audit_log (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    tenant_id VARCHAR(64) NOT NULL DEFAULT 'default',
+    task_id INT NOT NULL,
+    actor VARCHAR(255) NOT NULL,
+    created_at DATETIME(6) NOT NULL,
+    request_id VARCHAR(128) NOT NULL DEFAULT '',
+    operation VARCHAR(16) NOT NULL,
+    changes JSON NOT NULL,
+    INDEX idx_audit_log_tenant (tenant_id, created_at),
+    INDEX idx_audit_log_task (task_id, created_at),
+    INDEX idx_audit_log_actor (actor, created_at),
+    INDEX idx_audit_log_time (created_at)
+);
+
+DROP TRIGGER IF EXISTS audit_log_no_update;
+CREATE TRIGGER audit_log_no_update BEFORE UPDATE ON audit_log
+FOR EACH ROW SIGNAL SQLSTATE '45000' SET MESSAGE_TEXT = 'audit_log is append-only';
+
+DROP TRIGGER IF EXISTS audit_log_no_delete;
+CREATE TRIGGER audit_log_no_delete BEFORE DELETE ON audit_log
+FOR EACH ROW SIGNAL SQLSTATE '45000' SET MESSAGE_TEXT = 'audit_log is append-only';